@@ -0,0 +1,153 @@
+// tilt-sync-agent applies Tilt live updates inside a container, without
+// needing `tar` or a shell in the image.
+//
+// When a live_update uses sync_agent(), Tilt injects the agent into the pod.
+// An init container runs
+//
+//	tilt-sync-agent install /.tilt-sync-agent/tilt-sync-agent
+//
+// to copy the binary into an emptyDir volume, and Tilt wraps the container's
+// command with it:
+//
+//	/.tilt-sync-agent/tilt-sync-agent --port 8473 --token-file /.tilt-sync-agent-token/token -- /app/server
+//
+// The image is built from scripts/tilt-sync-agent.Dockerfile.
+//
+// The agent only listens on the container's loopback interface, which
+// Tilt reaches over a port-forward. Every request must carry the token in
+// the token file, which Tilt mounts from a Secret. The agent re-reads the
+// file on every request, so Tilt can rotate the token without restarting
+// the container.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/tilt-dev/tilt/internal/syncagent"
+)
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "install" {
+		if len(os.Args) != 3 {
+			log.Fatalf("Usage: %s install DEST", os.Args[0])
+		}
+		err := install(os.Args[2])
+		if err != nil {
+			log.Fatalf("tilt-sync-agent: %v", err)
+		}
+		return
+	}
+
+	port := flag.Int("port", syncagent.DefaultPort, "Port to serve the sync API on")
+	host := flag.String("host", "127.0.0.1", "Host to serve the sync API on. Tilt connects over a port-forward, which reaches the container's loopback interface")
+	root := flag.String("root", "/", "Directory that synced paths are relative to")
+	token := flag.String("token", "", fmt.Sprintf("Token that every request must send (default $%s)", syncagent.EnvToken))
+	tokenFile := flag.String("token-file", "", "File to read the token from on every request. Overrides --token")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] [-- command [args...]]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "       %s install DEST\n\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "Serves Tilt live updates, and optionally runs a command alongside.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var tokenSource syncagent.TokenSource
+	if *tokenFile != "" {
+		tokenSource = syncagent.FileToken(*tokenFile)
+	} else {
+		if *token == "" {
+			*token = os.Getenv(syncagent.EnvToken)
+		}
+		if *token == "" {
+			log.Fatalf("tilt-sync-agent: no token. Pass --token-file, --token, or $%s", syncagent.EnvToken)
+		}
+		tokenSource = syncagent.StaticToken(syncagent.Token(*token))
+	}
+
+	l, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		log.Fatalf("tilt-sync-agent: %v", err)
+	}
+
+	server := &http.Server{Handler: syncagent.NewServer(*root, tokenSource).Handler()}
+	go func() {
+		err := server.Serve(l)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("tilt-sync-agent: %v", err)
+		}
+	}()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		select {}
+	}
+	os.Exit(runChild(args))
+}
+
+// Runs the wrapped command, forwarding signals to it, and returns its exit code.
+func runChild(args []string) int {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Start()
+	if err != nil {
+		log.Printf("tilt-sync-agent: %v", err)
+		return syncagent.ExitCodeNotFound
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		for sig := range sigs {
+			_ = cmd.Process.Signal(sig)
+		}
+	}()
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		log.Printf("tilt-sync-agent: %v", err)
+		return 1
+	}
+	return 0
+}
+
+// Copies this binary to dest, so that an init container can share it with
+// the app container through an emptyDir volume.
+func install(dest string) error {
+	src, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o755)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
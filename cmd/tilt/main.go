@@ -4,6 +4,7 @@ import (
 	_ "expvar"
 
 	"github.com/tilt-dev/tilt/internal/cli"
+	"github.com/tilt-dev/tilt/internal/kustomize"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -12,6 +13,8 @@ var version string
 var date string
 
 func main() {
+	kustomize.RunRendererIfRequested()
+
 	cli.SetTiltInfo(model.TiltBuild{
 		Version: version,
 		Date:    date,
@@ -0,0 +1,105 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCLI_RegistryPrune(t *testing.T) {
+	f := newDCFixture(t, "registry_prune")
+
+	host := f.startRegistry()
+	repo := host + "/registry-prune-app"
+	f.tilt.Environ["REGISTRY_PRUNE_REPO"] = repo
+
+	// Three builds from the "main" branch, with the moving "main" tag
+	// on the oldest one, and a release that Tilt didn't tag.
+	f.pushImage(repo, "1", "main-0000000a", "main")
+	f.pushImage(repo, "2", "main-0000000b")
+	f.pushImage(repo, "3", "main-0000000c")
+	f.pushImage(repo, "4", "v1.2.3")
+
+	// Give the images time to age past --max-age.
+	time.Sleep(2 * time.Second)
+
+	var outBuf strings.Builder
+	c := f.tilt.cmd(f.ctx, []string{"registry-prune", "--max-age=1s"}, io.MultiWriter(&outBuf, os.Stdout))
+	t.Logf("Running command: %s", c.String())
+	require.NoError(t, c.Run(), "Error running `tilt registry-prune`")
+
+	assert.Equal(t, []string{"main-0000000c", "v1.2.3"}, f.registryTags(host, "registry-prune-app"))
+}
+
+// Starts a registry that allows deletes, and returns its host.
+func (f *dcFixture) startRegistry() string {
+	out, err := f.dockerCmdOutput([]string{"run", "-d", "--rm", "-p", "127.0.0.1::5000",
+		"-e", "REGISTRY_STORAGE_DELETE_ENABLED=true", "registry:2"})
+	require.NoError(f.t, err, "starting registry: %s", out)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	id := lines[len(lines)-1]
+	f.t.Cleanup(func() {
+		_, _ = f.dockerCmdOutput([]string{"rm", "-f", id})
+	})
+
+	out, err = f.dockerCmdOutput([]string{"port", id, "5000/tcp"})
+	require.NoError(f.t, err, "reading registry port: %s", out)
+	host := strings.TrimSpace(strings.Split(out, "\n")[0])
+
+	require.Eventually(f.t, func() bool {
+		resp, err := http.Get(fmt.Sprintf("http://%s/v2/", host))
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 30*time.Second, 200*time.Millisecond, "registry never came up")
+	return host
+}
+
+// Builds a distinct image with the label Tilt adds to its builds,
+// and pushes it with each tag.
+func (f *dcFixture) pushImage(repo string, content string, tags ...string) {
+	dockerfile := fmt.Sprintf("FROM busybox\nRUN echo %s > /content\n", content)
+	ref := repo + ":" + tags[0]
+	out := &bytes.Buffer{}
+	cmd := f.dockerCmd([]string{"build", "--label", "dev.tilt.gc=true", "-t", ref, "-"}, out)
+	cmd.Stdin = strings.NewReader(dockerfile)
+	require.NoError(f.t, cmd.Run(), "building %s: %s", ref, out)
+
+	for _, tag := range tags {
+		tagged := repo + ":" + tag
+		if tag != tags[0] {
+			out, err := f.dockerCmdOutput([]string{"tag", ref, tagged})
+			require.NoError(f.t, err, "tagging %s: %s", tagged, out)
+		}
+		out, err := f.dockerCmdOutput([]string{"push", tagged})
+		require.NoError(f.t, err, "pushing %s: %s", tagged, out)
+	}
+}
+
+func (f *dcFixture) registryTags(host, name string) []string {
+	resp, err := http.Get(fmt.Sprintf("http://%s/v2/%s/tags/list", host, name))
+	require.NoError(f.t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	var list struct {
+		Tags []string `json:"tags"`
+	}
+	require.NoError(f.t, json.NewDecoder(resp.Body).Decode(&list))
+	sort.Strings(list.Tags)
+	return list.Tags
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/distribution/reference"
-	dockerclient "github.com/moby/moby/client"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	ktypes "k8s.io/apimachinery/pkg/types"
@@ -154,18 +153,7 @@ func (b *CustomBuilder) Build(ctx context.Context, refs container.RefSet,
 
 	dig := digest.Digest(inspect.ID)
 
-	tag, err := digestAsTag(dig)
-	if err != nil {
-		return container.TaggedRefs{}, errors.Wrap(err, "custom_build")
-	}
-
-	taggedWithDigest, err := refs.AddTagSuffix(tag)
-	if err != nil {
-		return container.TaggedRefs{}, errors.Wrap(err, "custom_build")
-	}
-
-	// Docker client only needs to care about the localImage
-	_, err = b.dCli.ImageTag(ctx, dockerclient.ImageTagOptions{Source: dig.String(), Target: taggedWithDigest.LocalRef.String()})
+	taggedWithDigest, err := tagDigest(ctx, b.dCli, refs, dig, spec.TagPolicy, spec.Dir)
 	if err != nil {
 		return container.TaggedRefs{}, errors.Wrap(err, "custom_build")
 	}
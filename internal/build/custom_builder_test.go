@@ -3,6 +3,7 @@ package build
 import (
 	"context"
 	"fmt"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -77,6 +78,30 @@ func TestCustomBuildSuccessClusterRefWithCustomTag(t *testing.T) {
 	assert.Equal(f.t, container.MustParseNamed("registry:1234/gcr.io_foo_bar:tilt-11cd0eb38bc3ceb9"), refs.ClusterRef)
 }
 
+func TestCustomBuildTagPolicy(t *testing.T) {
+	f := newFakeCustomBuildFixture(t)
+	out, err := exec.Command("git", "init", f.Path()).CombinedOutput()
+	require.NoError(t, err, string(out))
+	out, err = exec.Command("git", "-C", f.Path(), "checkout", "-b", "feature/login").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	sha := digest.Digest("sha256:11cd0eb38bc3ceb958ffb2f9bd70be3fb317ce7d255c8a4c3f4af30e298aa1aab")
+	f.dCli.Images["gcr.io/foo/bar:tilt-build-1551202573"] = typesimage.InspectResponse{ID: string(sha)}
+	cb := f.customBuild("exit 0")
+	cb.CmdImageSpec.TagPolicy = &v1alpha1.ImageTagPolicy{
+		Template:       "{git_branch}-{digest8}",
+		AdditionalTags: []string{"{git_branch}-latest"},
+	}
+	refs, err := f.Build(refSetFromString("gcr.io/foo/bar"), cb, nil)
+	require.NoError(t, err)
+
+	assert.Equal(f.t, container.MustParseNamed("gcr.io/foo/bar:feature-login-11cd0eb3"), refs.LocalRef)
+	assert.Equal(f.t, container.MustParseNamed("gcr.io/foo/bar:feature-login-11cd0eb3"), refs.ClusterRef)
+	require.Len(f.t, refs.AdditionalLocalRefs, 1)
+	assert.Equal(f.t, "gcr.io/foo/bar:feature-login-latest", refs.AdditionalLocalRefs[0].String())
+	assert.Equal(f.t, "gcr.io/foo/bar:feature-login-latest", f.dCli.TagTarget)
+}
+
 func TestCustomBuildSuccessSkipsLocalDocker(t *testing.T) {
 	f := newFakeCustomBuildFixture(t)
 
@@ -17,7 +17,6 @@ import (
 	"github.com/moby/buildkit/session/filesync"
 	typesbuild "github.com/moby/moby/api/types/build"
 	"github.com/moby/moby/api/types/jsonstream"
-	dockerclient "github.com/moby/moby/client"
 	"github.com/moby/moby/client/pkg/jsonmessage"
 	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
@@ -87,24 +86,16 @@ func (d *DockerBuilder) DumpImageDeployRef(ctx context.Context, ref string) (ref
 	return tagged, nil
 }
 
-// Tag the digest with the given name and wm-tilt tag.
-func (d *DockerBuilder) TagRefs(ctx context.Context, refs container.RefSet, dig digest.Digest) (container.TaggedRefs, error) {
-	tag, err := digestAsTag(dig)
-	if err != nil {
-		return container.TaggedRefs{}, errors.Wrap(err, "TagImage")
+// Tag the digest with the given name and the tags from the tag policy.
+func (d *DockerBuilder) TagRefs(ctx context.Context, refs container.RefSet, dig digest.Digest, spec v1alpha1.DockerImageSpec) (container.TaggedRefs, error) {
+	dir := spec.Context
+	if dir == "-" {
+		dir = ""
 	}
-
-	tagged, err := refs.AddTagSuffix(tag)
+	tagged, err := tagDigest(ctx, d.dCli, refs, dig, spec.TagPolicy, dir)
 	if err != nil {
 		return container.TaggedRefs{}, errors.Wrap(err, "TagImage")
 	}
-
-	// Docker client only needs to care about the localImage
-	_, err = d.dCli.ImageTag(ctx, dockerclient.ImageTagOptions{Source: dig.String(), Target: tagged.LocalRef.String()})
-	if err != nil {
-		return container.TaggedRefs{}, errors.Wrap(err, "TagImage#ImageTag")
-	}
-
 	return tagged, nil
 }
 
@@ -113,12 +104,14 @@ func (d *DockerBuilder) TagRefs(ctx context.Context, refs container.RefSet, dig
 // TODO(nick) In the future, I would like us to be smarter about checking if the kubernetes cluster
 // we're running in has access to the given registry. And if it doesn't, we should either emit an
 // error, or push to a registry that kubernetes does have access to (e.g., a local registry).
-func (d *DockerBuilder) PushImage(ctx context.Context, ref reference.NamedTagged) error {
+//
+// Returns the digest of the pushed manifest, if the registry reported one.
+func (d *DockerBuilder) PushImage(ctx context.Context, ref reference.NamedTagged) (digest.Digest, error) {
 	l := logger.Get(ctx)
 
 	imagePushResponse, err := d.dCli.ImagePush(ctx, ref)
 	if err != nil {
-		return errors.Wrap(err, "PushImage#ImagePush")
+		return "", errors.Wrap(err, "PushImage#ImagePush")
 	}
 
 	defer func() {
@@ -128,12 +121,15 @@ func (d *DockerBuilder) PushImage(ctx context.Context, ref reference.NamedTagged
 		}
 	}()
 
-	_, _, err = readDockerOutput(ctx, imagePushResponse)
+	output, _, err := readDockerOutput(ctx, imagePushResponse)
 	if err != nil {
-		return errors.Wrapf(err, "pushing image %q", ref.Name())
+		return "", errors.Wrapf(err, "pushing image %q", ref.Name())
 	}
 
-	return nil
+	if output.aux == nil {
+		return "", nil
+	}
+	return getDigestFromPushAux(*output.aux), nil
 }
 
 func (d *DockerBuilder) ImageExists(ctx context.Context, ref reference.NamedTagged) (bool, error) {
@@ -196,7 +192,7 @@ func (d *DockerBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs
 		}
 	}
 
-	tagged, err := d.TagRefs(ctx, refs, digest)
+	tagged, err := d.TagRefs(ctx, refs, digest, spec)
 	if err != nil {
 		return container.TaggedRefs{}, stages, errors.Wrap(err, "docker tag")
 	}
@@ -534,6 +530,17 @@ func getDigestFromAux(aux json.RawMessage) (digest.Digest, error) {
 	return digest.Digest(id), nil
 }
 
+// The push result, e.g. {"Tag": "tilt-abc", "Digest": "sha256:...", "Size": 1234}
+func getDigestFromPushAux(aux json.RawMessage) digest.Digest {
+	var result struct {
+		Digest string
+	}
+	if err := json.Unmarshal(aux, &result); err != nil {
+		return ""
+	}
+	return digest.Digest(result.Digest)
+}
+
 func digestAsTag(d digest.Digest) (string, error) {
 	str := d.Encoded()
 	if len(str) < 16 {
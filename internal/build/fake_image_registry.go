@@ -0,0 +1,48 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/distribution/reference"
+)
+
+// FakeImageRegistry pretends that every image is in the registry,
+// except the ones marked missing.
+type FakeImageRegistry struct {
+	mu      sync.Mutex
+	missing map[string]bool
+	lookups int
+}
+
+var _ ImageRegistry = &FakeImageRegistry{}
+
+func NewFakeImageRegistry() *FakeImageRegistry {
+	return &FakeImageRegistry{missing: make(map[string]bool)}
+}
+
+func (r *FakeImageRegistry) SetMissing(ref reference.NamedTagged) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.missing[ref.String()] = true
+}
+
+// The number of images looked up so far.
+func (r *FakeImageRegistry) Lookups() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lookups
+}
+
+func (r *FakeImageRegistry) ManifestDigest(ctx context.Context, repo reference.Named, tag string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+
+	ref := fmt.Sprintf("%s:%s", repo.Name(), tag)
+	if r.missing[ref] {
+		return "", fmt.Errorf("manifest %s: 404 Not Found", ref)
+	}
+	return "sha256:fake", nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
 	"k8s.io/apimachinery/pkg/types"
 
 	"github.com/tilt-dev/clusterid"
@@ -14,20 +15,30 @@ import (
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+// ImageRegistry looks up images in the registry that a cluster pulls from.
+type ImageRegistry interface {
+	ManifestDigest(ctx context.Context, repo reference.Named, tag string) (string, error)
+}
+
 type ImageBuilder struct {
-	db    *DockerBuilder
-	custb *CustomBuilder
-	kl    KINDLoader
+	db       *DockerBuilder
+	custb    *CustomBuilder
+	il       ImageLoader
+	signer   ImageSigner
+	registry ImageRegistry
 }
 
-func NewImageBuilder(db *DockerBuilder, custb *CustomBuilder, kl KINDLoader) *ImageBuilder {
+func NewImageBuilder(db *DockerBuilder, custb *CustomBuilder, il ImageLoader, signer ImageSigner, registry ImageRegistry) *ImageBuilder {
 	return &ImageBuilder{
-		db:    db,
-		custb: custb,
-		kl:    kl,
+		db:       db,
+		custb:    custb,
+		il:       il,
+		signer:   signer,
+		registry: registry,
 	}
 }
 
@@ -44,7 +55,54 @@ func (ib *ImageBuilder) CanReuseRef(ctx context.Context, iTarget model.ImageTarg
 		"DockerBuild nor CustomBuild)", iTarget.ImageMapSpec.Selector)
 }
 
-// Build the image, and push it if necessary.
+// CanReuseBuiltImage checks whether an image that an earlier run of Tilt built
+// is still everywhere the cluster needs it, so that we can skip the build.
+//
+// Stricter than CanReuseRef: the image has to be in the local image store
+// (unless a custom_build() only pushes it), and in the registry that the
+// cluster pulls from. We can't look inside a cluster that we load images
+// into directly (like KIND without a registry), so those are always rebuilt.
+func (ib *ImageBuilder) CanReuseBuiltImage(ctx context.Context, iTarget model.ImageTarget, cluster *v1alpha1.Cluster, ref reference.NamedTagged) (bool, error) {
+	refs, err := iTarget.Refs(cluster)
+	if err != nil {
+		return false, err
+	}
+
+	inLocalDocker := true
+	if iTarget.IsCustomBuild() {
+		inLocalDocker = iTarget.CustomBuildInfo().OutputMode != v1alpha1.CmdImageOutputRemote
+	}
+	if inLocalDocker {
+		exists, err := ib.db.ImageExists(ctx, ref)
+		if err != nil || !exists {
+			return false, err
+		}
+	}
+
+	if isDockerCompose(cluster) ||
+		iTarget.ClusterNeeds() != v1alpha1.ClusterImageNeedsPush ||
+		ib.db.WillBuildToKubeContext(k8s.KubeContext(k8sConnStatus(cluster).Context)) {
+		// The cluster runs images from the local image store.
+		return true, nil
+	}
+
+	taggedRefs, err := refs.AddTagSuffix(ref.Tag())
+	if err != nil {
+		return false, err
+	}
+	if ib.shouldUseImageLoad(taggedRefs, cluster) {
+		return false, nil
+	}
+
+	_, err = ib.registry.ManifestDigest(ctx, ref, ref.Tag())
+	if err != nil {
+		logger.Get(ctx).Debugf("Not reusing %s: %v", container.FamiliarString(ref), err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Build the image, push it if necessary, and sign it if requested.
 //
 // Note that this function can return partial results on an error.
 //
@@ -56,17 +114,28 @@ func (ib *ImageBuilder) Build(ctx context.Context,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
 	ps *PipelineState) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, error) {
 	refs, stages, err := ib.buildOnly(ctx, iTarget, customBuildCmd, cluster, imageMaps, ps)
+	TimelineFrom(ctx).AddImageStages(stages)
 	if err != nil {
 		return refs, stages, err
 	}
 
-	pushStage := ib.push(ctx, refs, ps, iTarget, cluster)
+	pushStage, pushedDigest := ib.push(ctx, refs, ps, iTarget, cluster)
 	if pushStage != nil {
 		stages = append(stages, *pushStage)
+		TimelineFrom(ctx).AddImageStages([]v1alpha1.DockerImageStageStatus{*pushStage})
 	}
 
 	if pushStage != nil && pushStage.Error != "" {
-		err = errors.New(pushStage.Error)
+		return refs, stages, errors.New(pushStage.Error)
+	}
+
+	signStage := ib.sign(ctx, refs, pushedDigest, ps, iTarget, cluster)
+	if signStage != nil {
+		stages = append(stages, *signStage)
+		TimelineFrom(ctx).AddImageStages([]v1alpha1.DockerImageStageStatus{*signStage})
+		if signStage.Error != "" {
+			err = errors.New(signStage.Error)
+		}
 	}
 
 	return refs, stages, err
@@ -112,13 +181,12 @@ func (ib *ImageBuilder) buildOnly(ctx context.Context,
 }
 
 // Push the image if the cluster requires it.
-func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps *PipelineState, iTarget model.ImageTarget, cluster *v1alpha1.Cluster) *v1alpha1.DockerImageStageStatus {
+//
+// Returns the digest of the pushed manifest, if we pushed to a registry.
+func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps *PipelineState, iTarget model.ImageTarget, cluster *v1alpha1.Cluster) (*v1alpha1.DockerImageStageStatus, digest.Digest) {
 	// Skip the push phase entirely if we're on Docker Compose.
-	isDC := cluster != nil &&
-		cluster.Spec.Connection != nil &&
-		cluster.Spec.Connection.Docker != nil
-	if isDC {
-		return nil
+	if isDockerCompose(cluster) {
+		return nil, ""
 	}
 
 	// On Kubernetes, we count each push() as a stage, and need to print why
@@ -133,40 +201,47 @@ func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps
 
 	if cbSkip {
 		ps.Printf(ctx, "Skipping push: custom_build() configured to handle push itself")
-		return nil
+		return nil, ""
 	}
 
 	// We can also skip the push of the image if it isn't used
 	// in any k8s resources! (e.g., it's consumed by another image).
 	if iTarget.ClusterNeeds() != v1alpha1.ClusterImageNeedsPush {
 		ps.Printf(ctx, "Skipping push: base image does not need deploy")
-		return nil
+		return nil, ""
 	}
 
 	if ib.db.WillBuildToKubeContext(k8s.KubeContext(k8sConnStatus(cluster).Context)) {
 		ps.Printf(ctx, "Skipping push: building on cluster's container runtime")
-		return nil
+		return nil, ""
 	}
 
 	startTime := apis.NowMicro()
-	var err error
-	if ib.shouldUseKINDLoad(refs, cluster) {
-		ps.Printf(ctx, "Loading image to KIND")
-		err := ib.kl.LoadToKIND(ps.AttachLogger(ctx), cluster, refs.LocalRef)
+	if ib.shouldUseImageLoad(refs, cluster) {
+		stageName := ImageLoadStageName(clusterid.Product(k8sConnStatus(cluster).Product))
+		ps.Printf(ctx, "Loading image to cluster with %s", stageName)
+		err := ib.il.LoadImage(ps.AttachLogger(ctx), cluster, refs.LocalRef)
 		endTime := apis.NowMicro()
 		stage := &v1alpha1.DockerImageStageStatus{
-			Name:       "kind load",
+			Name:       stageName,
 			StartedAt:  &startTime,
 			FinishedAt: &endTime,
 		}
 		if err != nil {
-			stage.Error = fmt.Sprintf("Error loading image to KIND: %v", err)
+			stage.Error = fmt.Sprintf("Error loading image to cluster: %v", err)
 		}
-		return stage
+		return stage, ""
 	}
 
 	ps.Printf(ctx, "Pushing with Docker client")
-	err = ib.db.PushImage(ps.AttachLogger(ctx), refs.LocalRef)
+	pushedDigest, err := ib.db.PushImage(ps.AttachLogger(ctx), refs.LocalRef)
+	for _, ref := range refs.AdditionalLocalRefs {
+		if err != nil {
+			break
+		}
+		ps.Printf(ctx, "Pushing additional tag %s", container.FamiliarString(ref))
+		_, err = ib.db.PushImage(ps.AttachLogger(ctx), ref)
+	}
 
 	endTime := apis.NowMicro()
 	stage := &v1alpha1.DockerImageStageStatus{
@@ -177,18 +252,62 @@ func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps
 	if err != nil {
 		stage.Error = fmt.Sprintf("docker push: %v", err)
 	}
+	return stage, pushedDigest
+}
+
+// Sign the pushed image, if the build has a signing key.
+func (ib *ImageBuilder) sign(ctx context.Context, refs container.TaggedRefs, pushedDigest digest.Digest, ps *PipelineState, iTarget model.ImageTarget, cluster *v1alpha1.Cluster) *v1alpha1.DockerImageStageStatus {
+	db, ok := iTarget.BuildDetails.(model.DockerBuild)
+	if !ok || db.SigningKey == "" {
+		return nil
+	}
+
+	// Docker Compose never pushes, so there's no signing stage.
+	if isDockerCompose(cluster) {
+		logger.Get(ctx).Infof("Skipping signing %s: Docker Compose images aren't pushed to a registry",
+			container.FamiliarString(refs.LocalRef))
+		return nil
+	}
+
+	ps.StartPipelineStep(ctx, "Signing %s", container.FamiliarString(refs.LocalRef))
+	defer ps.EndPipelineStep(ctx)
+
+	// Signatures live in the registry next to the image, so there's
+	// nothing to sign if the image never went to a registry.
+	if pushedDigest == "" {
+		ps.Printf(ctx, "Skipping signing: image wasn't pushed to a registry")
+		return nil
+	}
+
+	startTime := apis.NowMicro()
+	err := ib.signer.SignImage(ps.AttachLogger(ctx), db.SigningKey, refs.LocalRef, pushedDigest)
+	endTime := apis.NowMicro()
+	stage := &v1alpha1.DockerImageStageStatus{
+		Name:       "cosign sign",
+		StartedAt:  &startTime,
+		FinishedAt: &endTime,
+	}
+	if err != nil {
+		stage.Error = fmt.Sprintf("Error signing image: %v", err)
+	}
 	return stage
 }
 
-func (ib *ImageBuilder) shouldUseKINDLoad(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
-	isKIND := k8sConnStatus(cluster).Product == string(clusterid.ProductKIND)
-	if !isKIND {
+func isDockerCompose(cluster *v1alpha1.Cluster) bool {
+	return cluster != nil &&
+		cluster.Spec.Connection != nil &&
+		cluster.Spec.Connection.Docker != nil
+}
+
+func (ib *ImageBuilder) shouldUseImageLoad(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
+	product := clusterid.Product(k8sConnStatus(cluster).Product)
+	if ImageLoadStageName(product) == "" {
 		return false
 	}
 
-	// if we're using KIND and the image has a separate ref by which it's referred to
-	// in the cluster, that implies that we have a local registry in place, and should
-	// push to that instead of using KIND load.
+	// if the image has a separate ref by which it's referred to in the cluster,
+	// that implies that we have a local registry in place, and should
+	// push to that instead of loading the image directly.
 	if refs.LocalRef.String() != refs.ClusterRef.String() {
 		return false
 	}
@@ -0,0 +1,140 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/distribution/reference"
+
+	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// ImageLoader copies an image from the local Docker image store directly into
+// a cluster's container runtime, so that local clusters without a registry
+// can still run the images we build.
+type ImageLoader interface {
+	LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error
+}
+
+// A strategy for loading images into one kind of cluster.
+type imageLoadStrategy struct {
+	// A short description of the strategy, used as the name of the build stage.
+	name string
+
+	// The command that loads the image.
+	args func(clusterName string, ref reference.NamedTagged) []string
+
+	// When true, the command reads a `docker save` tarball of the image on stdin.
+	fromDockerSave bool
+}
+
+// containerd reads images from the k8s.io namespace.
+var ctrImportArgs = []string{"ctr", "-n", "k8s.io", "images", "import", "-"}
+
+var imageLoadStrategies = map[clusterid.Product]imageLoadStrategy{
+	clusterid.ProductKIND: {
+		name: "kind load",
+		args: func(clusterName string, ref reference.NamedTagged) []string {
+			// In Kind5, --name specifies the name of the cluster in the kubeconfig.
+			// In Kind6, the -name parameter is prefixed with 'kind-' before being written to/read from the kubeconfig
+			return []string{"kind", "load", "docker-image", ref.String(), "--name", strings.TrimPrefix(clusterName, "kind-")}
+		},
+	},
+	clusterid.ProductK3D: {
+		name: "k3d image import",
+		args: func(clusterName string, ref reference.NamedTagged) []string {
+			// k3d prefixes the cluster name with 'k3d-' in the kubeconfig.
+			return []string{"k3d", "image", "import", ref.String(), "--cluster", strings.TrimPrefix(clusterName, "k3d-")}
+		},
+	},
+	clusterid.ProductMinikube: {
+		name: "minikube image load",
+		args: func(clusterName string, ref reference.NamedTagged) []string {
+			// The minikube profile name is used as the cluster name in the kubeconfig.
+			return []string{"minikube", "image", "load", ref.String(), "--profile", clusterName}
+		},
+	},
+	clusterid.ProductMicroK8s: {
+		name:           "ctr images import",
+		fromDockerSave: true,
+		args: func(clusterName string, ref reference.NamedTagged) []string {
+			return append([]string{"microk8s"}, ctrImportArgs...)
+		},
+	},
+	clusterid.ProductColima: {
+		name:           "ctr images import",
+		fromDockerSave: true,
+		args: func(clusterName string, ref reference.NamedTagged) []string {
+			args := []string{"colima", "ssh"}
+			if profile := strings.TrimPrefix(clusterName, "colima-"); profile != clusterName {
+				args = append(args, "--profile", profile)
+			}
+			return append(append(args, "--", "sudo"), ctrImportArgs...)
+		},
+	},
+	clusterid.ProductRancherDesktop: {
+		name:           "ctr images import",
+		fromDockerSave: true,
+		args: func(clusterName string, ref reference.NamedTagged) []string {
+			return append([]string{"rdctl", "shell", "sudo"}, ctrImportArgs...)
+		},
+	},
+}
+
+// ImageLoadStageName returns the name of the build stage that loads images
+// into clusters of the given product, or "" if we don't know how to load
+// images into that product directly.
+func ImageLoadStageName(product clusterid.Product) string {
+	return imageLoadStrategies[product].name
+}
+
+type cmdImageLoader struct {
+}
+
+func NewImageLoader() ImageLoader {
+	return &cmdImageLoader{}
+}
+
+func (l *cmdImageLoader) LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+	k8sConn := k8sConnStatus(cluster)
+	strategy, ok := imageLoadStrategies[clusterid.Product(k8sConn.Product)]
+	if !ok {
+		return fmt.Errorf("don't know how to load images into %q clusters", k8sConn.Product)
+	}
+
+	args := strategy.args(k8sConn.Cluster, ref)
+	w := logger.NewMutexWriter(logger.Get(ctx).Writer(logger.InfoLvl))
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = w
+	cmd.Stderr = w
+	if !strategy.fromDockerSave {
+		return cmd.Run()
+	}
+
+	// Stream the image from `docker save` into the loader, without buffering the
+	// whole tarball on disk.
+	save := exec.CommandContext(ctx, "docker", "save", ref.String())
+	save.Stderr = w
+	pr, pw := io.Pipe()
+	save.Stdout = pw
+	cmd.Stdin = pr
+
+	if err := save.Start(); err != nil {
+		return fmt.Errorf("docker save: %v", err)
+	}
+	go func() {
+		_ = pw.CloseWithError(save.Wait())
+	}()
+
+	err := cmd.Run()
+	_ = pr.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %v", strings.Join(args[:2], " "), err)
+	}
+	return nil
+}
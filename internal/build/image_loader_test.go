@@ -0,0 +1,43 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/container"
+)
+
+func TestImageLoadStrategyArgs(t *testing.T) {
+	ref := container.MustParseNamedTagged("gcr.io/some-project/image:tilt-deadbeef")
+	for _, tc := range []struct {
+		product        clusterid.Product
+		cluster        string
+		expected       []string
+		fromDockerSave bool
+	}{
+		{clusterid.ProductKIND, "kind-dev", []string{"kind", "load", "docker-image", ref.String(), "--name", "dev"}, false},
+		{clusterid.ProductK3D, "k3d-k3s-default", []string{"k3d", "image", "import", ref.String(), "--cluster", "k3s-default"}, false},
+		{clusterid.ProductMinikube, "minikube", []string{"minikube", "image", "load", ref.String(), "--profile", "minikube"}, false},
+		{clusterid.ProductMicroK8s, "microk8s-cluster", []string{"microk8s", "ctr", "-n", "k8s.io", "images", "import", "-"}, true},
+		{clusterid.ProductColima, "colima", []string{"colima", "ssh", "--", "sudo", "ctr", "-n", "k8s.io", "images", "import", "-"}, true},
+		{clusterid.ProductColima, "colima-work", []string{"colima", "ssh", "--profile", "work", "--", "sudo", "ctr", "-n", "k8s.io", "images", "import", "-"}, true},
+		{clusterid.ProductRancherDesktop, "rancher-desktop", []string{"rdctl", "shell", "sudo", "ctr", "-n", "k8s.io", "images", "import", "-"}, true},
+	} {
+		t.Run(tc.cluster, func(t *testing.T) {
+			strategy, ok := imageLoadStrategies[tc.product]
+			if assert.True(t, ok) {
+				assert.Equal(t, tc.expected, strategy.args(tc.cluster, ref))
+				assert.Equal(t, tc.fromDockerSave, strategy.fromDockerSave)
+			}
+		})
+	}
+}
+
+func TestImageLoadStageName(t *testing.T) {
+	assert.Equal(t, "kind load", ImageLoadStageName(clusterid.ProductKIND))
+	assert.Equal(t, "ctr images import", ImageLoadStageName(clusterid.ProductColima))
+	assert.Equal(t, "", ImageLoadStageName(clusterid.ProductGKE))
+	assert.Equal(t, "", ImageLoadStageName(clusterid.ProductDockerDesktop))
+}
@@ -0,0 +1,50 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// ImageSigner signs an image that's been pushed to a registry,
+// so that the cluster never runs an unsigned image.
+type ImageSigner interface {
+	SignImage(ctx context.Context, key string, ref reference.Named, dig digest.Digest) error
+}
+
+// Signs images with the cosign CLI, which writes the signature
+// to the image's registry.
+type cosignSigner struct {
+}
+
+func NewImageSigner() ImageSigner {
+	return &cosignSigner{}
+}
+
+func (s *cosignSigner) SignImage(ctx context.Context, key string, ref reference.Named, dig digest.Digest) error {
+	// Always sign by digest, so that we sign exactly what we pushed,
+	// even if someone else moves the tag.
+	canonical, err := reference.WithDigest(reference.TrimNamed(ref), dig)
+	if err != nil {
+		return err
+	}
+
+	w := logger.NewMutexWriter(logger.Get(ctx).Writer(logger.InfoLvl))
+	cmd := exec.CommandContext(ctx, "cosign", "sign", "--key", key, "--yes", canonical.String())
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err = cmd.Run()
+	if errors.Is(err, exec.ErrNotFound) {
+		return fmt.Errorf("signing requires the cosign CLI (https://docs.sigstore.dev/cosign/system_config/installation/): %v", err)
+	}
+	if err != nil {
+		return fmt.Errorf("cosign sign: %v", err)
+	}
+	return nil
+}
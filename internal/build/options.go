@@ -12,19 +12,20 @@ import (
 
 func Options(archive io.Reader, spec v1alpha1.DockerImageSpec) docker.BuildOptions {
 	return docker.BuildOptions{
-		Context:     archive,
-		Dockerfile:  "Dockerfile",
-		Remove:      shouldRemoveImage(),
-		BuildArgs:   opts.ConvertKVStringsToMapWithNil(spec.Args),
-		Target:      spec.Target,
-		SSHSpecs:    spec.SSHAgentConfigs,
-		Network:     spec.Network,
-		ExtraTags:   spec.ExtraTags,
-		SecretSpecs: spec.Secrets,
-		CacheFrom:   spec.CacheFrom,
-		PullParent:  spec.Pull,
-		Platform:    spec.Platform,
-		ExtraHosts:  spec.ExtraHosts,
+		Context:      archive,
+		Dockerfile:   "Dockerfile",
+		Remove:       shouldRemoveImage(),
+		BuildArgs:    opts.ConvertKVStringsToMapWithNil(spec.Args),
+		Target:       spec.Target,
+		SSHSpecs:     spec.SSHAgentConfigs,
+		Network:      spec.Network,
+		ExtraTags:    spec.ExtraTags,
+		SecretSpecs:  spec.Secrets,
+		CacheFrom:    spec.CacheFrom,
+		PullParent:   spec.Pull,
+		Platform:     spec.Platform,
+		ExtraHosts:   spec.ExtraHosts,
+		Attestations: spec.Attestations,
 	}
 }
 
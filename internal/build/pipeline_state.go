@@ -6,7 +6,12 @@ import (
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type PipelineState struct {
@@ -15,6 +20,13 @@ type PipelineState struct {
 	curPipelineStart       time.Time
 	pipelineSteps          []PipelineStep
 	c                      Clock
+	timeline               *Timeline
+
+	// The span for the current pipeline step, if any.
+	stepSpan trace.Span
+
+	// The timeline entry for the current pipeline step.
+	stepID TimelineStepID
 }
 
 type PipelineStep struct {
@@ -43,6 +55,8 @@ func NewPipelineState(ctx context.Context, totalStepCount int, c Clock) *Pipelin
 		pipelineSteps:          []PipelineStep{},
 		curPipelineStart:       c.Now(),
 		c:                      c,
+		timeline:               TimelineFrom(ctx),
+		stepID:                 noTimelineStep,
 	}
 }
 
@@ -57,6 +71,13 @@ func NewPipelineState(ctx context.Context, totalStepCount int, c Clock) *Pipelin
 func (ps *PipelineState) End(ctx context.Context, err error) {
 	ps.curBuildStep = 0
 
+	// If a step failed, it may not have ended. If it did end,
+	// blame it for the failure anyway.
+	if err != nil && ps.stepSpan == nil && len(ps.pipelineSteps) > 0 {
+		ps.timeline.markFailed(ps.stepID, err)
+	}
+	ps.endStep(err, ps.c.Now())
+
 	if err != nil {
 		return
 	}
@@ -88,19 +109,42 @@ func (ps *PipelineState) curPipelineStep() PipelineStep {
 func (ps *PipelineState) StartPipelineStep(ctx context.Context, format string, a ...interface{}) {
 	l := logger.Get(ctx)
 	stepName := fmt.Sprintf(format, a...)
-	ps.pipelineSteps = append(ps.pipelineSteps, PipelineStep{
+	now := ps.c.Now()
+	ps.endStep(nil, now)
+	step := PipelineStep{
 		Name:      stepName,
-		StartTime: ps.c.Now(),
+		StartTime: now,
+	}
+	ps.pipelineSteps = append(ps.pipelineSteps, step)
+	ps.stepID = ps.timeline.Add(model.BuildStep{
+		Name:      stepName,
+		Type:      model.BuildStepTypePipeline,
+		StartTime: step.StartTime,
 	})
+	_, ps.stepSpan = tracer.Start(ctx, "pipeline.step",
+		attribute.String("step.name", stepName),
+		attribute.Int("step.index", ps.curPipelineIndex()))
 	line := logger.Blue(l).Sprintf("STEP %d/%d", ps.curPipelineIndex(), ps.totalPipelineStepCount)
 	l.Infof("%s — %s", line, stepName)
 	ps.curBuildStep = 1
 }
 
 func (ps *PipelineState) EndPipelineStep(ctx context.Context) {
-	elapsed := ps.c.Now().Sub(ps.curPipelineStep().StartTime)
+	now := ps.c.Now()
+	elapsed := now.Sub(ps.curPipelineStep().StartTime)
 	logger.Get(ctx).Infof("")
 	ps.pipelineSteps[len(ps.pipelineSteps)-1].Duration = elapsed
+	ps.endStep(nil, now)
+}
+
+// Ends the span and timeline entry for the current pipeline step, if it's still open.
+func (ps *PipelineState) endStep(err error, now time.Time) {
+	if ps.stepSpan == nil {
+		return
+	}
+	tracer.EndWithError(ps.stepSpan, err)
+	ps.stepSpan = nil
+	ps.timeline.finish(ps.stepID, now, err)
 }
 
 func (ps *PipelineState) StartBuildStep(ctx context.Context, format string, a ...interface{}) {
@@ -1,8 +1,89 @@
 package build
 
+import (
+	"context"
+	"fmt"
+
+	dockerclient "github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/git"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
 // The image tag prefix can be customized.
 //
 // This allows our integration tests to customize
 // the prefix so that they can write to a public
 // registry without interfering with each other.
 var ImageTagPrefix = "tilt-"
+
+// Renders the tags for an image built from dir.
+//
+// Returns the tag to deploy and any additional tags. Without a tag policy,
+// the tag is the ImageTagPrefix and the first 16 characters of the digest.
+func tagsForImage(policy *v1alpha1.ImageTagPolicy, dig digest.Digest, dir string) (string, []string, error) {
+	if policy == nil || policy.Template == "" {
+		tag, err := digestAsTag(dig)
+		return tag, nil, err
+	}
+
+	vars := container.TagTemplateVars{Digest: dig.Encoded()}
+	if dir != "" {
+		vars.GitBranch = git.CurrentBranch(dir)
+		vars.GitSHA = git.HeadCommit(dir)
+	}
+
+	tag, err := container.RenderTagTemplate(policy.Template, vars)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var additional []string
+	for _, tmpl := range policy.AdditionalTags {
+		t, err := container.RenderTagTemplate(tmpl, vars)
+		if err != nil {
+			return "", nil, err
+		}
+		if t != tag {
+			additional = append(additional, t)
+		}
+	}
+	return tag, additional, nil
+}
+
+// Tags the digest with the tags from the policy.
+//
+// The docker client only needs to care about the local refs.
+func tagDigest(ctx context.Context, dCli docker.Client, refs container.RefSet, dig digest.Digest,
+	policy *v1alpha1.ImageTagPolicy, dir string) (container.TaggedRefs, error) {
+	tag, additional, err := tagsForImage(policy, dig, dir)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	tagged, err := refs.AddTagSuffix(tag)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	_, err = dCli.ImageTag(ctx, dockerclient.ImageTagOptions{Source: dig.String(), Target: tagged.LocalRef.String()})
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+
+	for _, t := range additional {
+		extra, err := refs.AddTagSuffix(t)
+		if err != nil {
+			return container.TaggedRefs{}, err
+		}
+		_, err = dCli.ImageTag(ctx, dockerclient.ImageTagOptions{Source: dig.String(), Target: extra.LocalRef.String()})
+		if err != nil {
+			return container.TaggedRefs{}, fmt.Errorf("tagging %s: %v", container.FamiliarString(extra.LocalRef), err)
+		}
+		tagged.AdditionalLocalRefs = append(tagged.AdditionalLocalRefs, extra.LocalRef)
+	}
+	return tagged, nil
+}
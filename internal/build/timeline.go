@@ -0,0 +1,126 @@
+package build
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Timeline collects the steps of a single build, so that we can show
+// which step was slow.
+//
+// The BuildController attaches a Timeline to the build context, and
+// each part of the build pipeline adds its own steps. A nil Timeline
+// ignores all steps.
+type Timeline struct {
+	mu    sync.Mutex
+	steps []model.BuildStep
+}
+
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+type timelineContextKey struct{}
+
+func WithTimeline(ctx context.Context, t *Timeline) context.Context {
+	return context.WithValue(ctx, timelineContextKey{}, t)
+}
+
+// TimelineFrom returns the Timeline for the current build, or nil
+// if we're not in a build.
+func TimelineFrom(ctx context.Context) *Timeline {
+	t, _ := ctx.Value(timelineContextKey{}).(*Timeline)
+	return t
+}
+
+// TimelineStepID identifies a step in a Timeline, so that we can update
+// the step later even if other steps have the same name.
+type TimelineStepID int
+
+const noTimelineStep TimelineStepID = -1
+
+func (t *Timeline) Add(step model.BuildStep) TimelineStepID {
+	if t == nil {
+		return noTimelineStep
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.steps = append(t.steps, step)
+	return TimelineStepID(len(t.steps) - 1)
+}
+
+// Must hold the lock.
+func (t *Timeline) step(id TimelineStepID) *model.BuildStep {
+	if id < 0 || int(id) >= len(t.steps) {
+		return nil
+	}
+	return &t.steps[id]
+}
+
+// Sets the finish time of the step, if it hasn't finished yet.
+func (t *Timeline) finish(id TimelineStepID, finishTime time.Time, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	step := t.step(id)
+	if step == nil || !step.FinishTime.IsZero() {
+		return
+	}
+	step.FinishTime = finishTime
+	if err != nil {
+		step.Error = err.Error()
+	}
+}
+
+// Records an error on the step.
+func (t *Timeline) markFailed(id TimelineStepID, err error) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	step := t.step(id)
+	if step == nil {
+		return
+	}
+	step.Error = err.Error()
+}
+
+// AddImageStages adds the stages of an image build (as reported by Buildkit,
+// plus any stages that Tilt synthesized, like push).
+func (t *Timeline) AddImageStages(stages []v1alpha1.DockerImageStageStatus) {
+	for _, stage := range stages {
+		step := model.BuildStep{
+			Name:   stage.Name,
+			Type:   model.BuildStepTypeImageStage,
+			Cached: stage.Cached,
+			Error:  stage.Error,
+		}
+		if stage.StartedAt != nil {
+			step.StartTime = stage.StartedAt.Time
+		}
+		if stage.FinishedAt != nil {
+			step.FinishTime = stage.FinishedAt.Time
+		}
+		t.Add(step)
+	}
+}
+
+// Steps returns the steps in the order they were recorded.
+//
+// Each part of the pipeline records its steps as it finishes them, so this is
+// also the order that they ran in (though Buildkit stages may overlap).
+func (t *Timeline) Steps() []model.BuildStep {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]model.BuildStep{}, t.steps...)
+}
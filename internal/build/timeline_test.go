@@ -0,0 +1,135 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestTimelinePipelineSteps(t *testing.T) {
+	clock := &tickingClock{now: time.Unix(1000, 0)}
+	timeline := NewTimeline()
+	ctx := WithTimeline(logger.WithLogger(context.Background(), logger.NewTestLogger(io.Discard)), timeline)
+
+	ps := NewPipelineState(ctx, 2, clock)
+	ps.StartPipelineStep(ctx, "Building Dockerfile: [%s]", "foo")
+	ps.EndPipelineStep(ctx)
+	ps.StartPipelineStep(ctx, "Deploying")
+	ps.EndPipelineStep(ctx)
+	ps.End(ctx, nil)
+
+	assert.Equal(t, []model.BuildStep{
+		{
+			Name:       "Building Dockerfile: [foo]",
+			Type:       model.BuildStepTypePipeline,
+			StartTime:  time.Unix(1002, 0),
+			FinishTime: time.Unix(1003, 0),
+		},
+		{
+			Name:       "Deploying",
+			Type:       model.BuildStepTypePipeline,
+			StartTime:  time.Unix(1004, 0),
+			FinishTime: time.Unix(1005, 0),
+		},
+	}, timeline.Steps())
+}
+
+func TestTimelinePipelineStepFailed(t *testing.T) {
+	clock := &tickingClock{now: time.Unix(1000, 0)}
+	timeline := NewTimeline()
+	ctx := WithTimeline(logger.WithLogger(context.Background(), logger.NewTestLogger(io.Discard)), timeline)
+
+	ps := NewPipelineState(ctx, 2, clock)
+	ps.StartPipelineStep(ctx, "Deploying")
+	ps.EndPipelineStep(ctx)
+	ps.End(ctx, fmt.Errorf("oh noes"))
+
+	steps := timeline.Steps()
+	require.Len(t, steps, 1)
+	assert.Equal(t, "oh noes", steps[0].Error)
+	assert.False(t, steps[0].FinishTime.IsZero())
+}
+
+func TestTimelineStepsWithSameName(t *testing.T) {
+	clock := &tickingClock{now: time.Unix(1000, 0)}
+	timeline := NewTimeline()
+	ctx := WithTimeline(logger.WithLogger(context.Background(), logger.NewTestLogger(io.Discard)), timeline)
+
+	// Two pipelines that share a build timeline, with overlapping steps.
+	ps1 := NewPipelineState(ctx, 1, clock)
+	ps2 := NewPipelineState(ctx, 1, clock)
+	ps1.StartPipelineStep(ctx, "Deploying")
+	ps2.StartPipelineStep(ctx, "Deploying")
+	ps1.EndPipelineStep(ctx)
+	ps1.End(ctx, nil)
+	ps2.End(ctx, fmt.Errorf("oh noes"))
+
+	steps := timeline.Steps()
+	require.Len(t, steps, 2)
+	assert.Equal(t, time.Unix(1003, 0), steps[0].StartTime)
+	assert.Equal(t, time.Unix(1005, 0), steps[0].FinishTime)
+	assert.Equal(t, "", steps[0].Error)
+	assert.Equal(t, time.Unix(1004, 0), steps[1].StartTime)
+	assert.Equal(t, "oh noes", steps[1].Error)
+	assert.False(t, steps[1].FinishTime.IsZero())
+}
+
+func TestTimelineImageStages(t *testing.T) {
+	timeline := NewTimeline()
+	timeline.Add(model.BuildStep{
+		Name:      "Building Dockerfile: [foo]",
+		Type:      model.BuildStepTypePipeline,
+		StartTime: time.Unix(1000, 0),
+	})
+
+	// Buildkit reports stages after the build finishes.
+	loadStart := apis.NewMicroTime(time.Unix(1001, 0))
+	runStart := apis.NewMicroTime(time.Unix(1002, 0))
+	runFinish := apis.NewMicroTime(time.Unix(1005, 0))
+	timeline.AddImageStages([]v1alpha1.DockerImageStageStatus{
+		{Name: "[internal] load build context", StartedAt: &loadStart},
+		{Name: "[1/2] FROM alpine", Cached: true},
+		{Name: "[2/2] RUN make", StartedAt: &runStart, FinishedAt: &runFinish, Error: "exit code 2"},
+	})
+
+	steps := timeline.Steps()
+	require.Len(t, steps, 4)
+	assert.Equal(t, "[internal] load build context", steps[1].Name)
+	assert.Equal(t, model.BuildStepTypeImageStage, steps[1].Type)
+	assert.Equal(t, time.Unix(1001, 0), steps[1].StartTime)
+	assert.True(t, steps[2].Cached)
+	assert.Equal(t, model.BuildStep{
+		Name:       "[2/2] RUN make",
+		Type:       model.BuildStepTypeImageStage,
+		StartTime:  time.Unix(1002, 0),
+		FinishTime: time.Unix(1005, 0),
+		Error:      "exit code 2",
+	}, steps[3])
+}
+
+func TestTimelineNil(t *testing.T) {
+	var timeline *Timeline
+	timeline.Add(model.BuildStep{Name: "foo"})
+	assert.Nil(t, timeline.Steps())
+	assert.Nil(t, TimelineFrom(context.Background()))
+}
+
+// A clock that moves forward by a second every time you look at it.
+type tickingClock struct {
+	now time.Time
+}
+
+func (c *tickingClock) Now() time.Time {
+	c.now = c.now.Add(time.Second)
+	return c.now
+}
@@ -68,6 +68,7 @@ up-to-date in real-time. Think 'docker build && kubectl apply' or 'docker-compos
 	addCommand(rootCmd, &versionCmd{})
 	addCommand(rootCmd, &verifyInstallCmd{})
 	addCommand(rootCmd, &dockerPruneCmd{})
+	addCommand(rootCmd, &registryPruneCmd{})
 	addCommand(rootCmd, newArgsCmd(streams))
 	addCommand(rootCmd, newLogsCmd(streams))
 	addCommand(rootCmd, newDescribeCmd(streams))
@@ -84,6 +85,8 @@ up-to-date in real-time. Think 'docker build && kubectl apply' or 'docker-compos
 	addCommand(rootCmd, newEnableCmd())
 	addCommand(rootCmd, newDisableCmd())
 	addCommand(rootCmd, newTriggerCmd(streams))
+	addCommand(rootCmd, newDiffCmd(streams))
+	addCommand(rootCmd, newShareCmd())
 
 	rootCmd.AddCommand(analytics.NewCommand())
 	rootCmd.AddCommand(newDumpCmd(rootCmd, streams))
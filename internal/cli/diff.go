@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type diffCmd struct {
+	streams  genericclioptions.IOStreams
+	nameOnly bool
+}
+
+var _ tiltCmd = &diffCmd{}
+
+func newDiffCmd(streams genericclioptions.IOStreams) *diffCmd {
+	return &diffCmd{
+		streams: streams,
+	}
+}
+
+func (c *diffCmd) name() model.TiltSubcommand { return "diff" }
+
+func (c *diffCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "diff <resource-name>",
+		DisableFlagsInUseLine: true,
+		Short:                 "Show what the last Kubernetes apply changed for a resource",
+		Long: `Show what the last Kubernetes apply changed for a resource.
+
+Tilt computes the diff with a server-side dry-run before each apply,
+so the diff includes defaulting and admission webhooks.
+
+Dry-run diffs are opt-in. Enable them in your Tiltfile with:
+
+update_settings(k8s_dry_run_diff=True)
+`,
+		Args: cobra.ExactArgs(1),
+	}
+
+	addConnectServerFlags(cmd)
+	cmd.Flags().BoolVar(&c.nameOnly, "name-only", false, "Only print the objects and the fields that changed")
+	return cmd
+}
+
+func (c *diffCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	a.Incr("cmd.diff", make(engineanalytics.CmdTags))
+	defer a.Flush(time.Second)
+
+	ctrlclient, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	resourceName := args[0]
+	var ka v1alpha1.KubernetesApply
+	err = ctrlclient.Get(ctx, types.NamespacedName{Name: resourceName}, &ka)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("no Kubernetes apply found for resource %s. To see available resources, run:\ntilt get kubernetesapplys", resourceName)
+		}
+		return fmt.Errorf("looking up resource %s: %v", resourceName, err)
+	}
+
+	if !ka.Spec.DryRunDiff {
+		return fmt.Errorf("dry-run diffs are not enabled for resource %s. Enable them in your Tiltfile with:\nupdate_settings(k8s_dry_run_diff=True)", resourceName)
+	}
+
+	diff := ka.Status.Diff
+	if diff == nil {
+		return fmt.Errorf("resource %s has not been applied yet", resourceName)
+	}
+
+	printApplyDiff(c.streams.Out, diff, c.nameOnly)
+	return nil
+}
+
+func printApplyDiff(w io.Writer, diff *v1alpha1.KubernetesApplyDiff, nameOnly bool) {
+	if diff.Error != "" {
+		_, _ = fmt.Fprintf(w, "Dry-run failed: %s\n", diff.Error)
+		return
+	}
+
+	for _, obj := range diff.Objects {
+		name := obj.Name
+		if obj.Namespace != "" {
+			name = fmt.Sprintf("%s/%s", obj.Namespace, obj.Name)
+		}
+		_, _ = fmt.Fprintf(w, "%s %s: %s\n", obj.Kind, name, obj.Operation)
+
+		if nameOnly {
+			for _, f := range obj.ChangedFields {
+				_, _ = fmt.Fprintf(w, "  %s\n", f)
+			}
+			continue
+		}
+
+		if obj.Operation != v1alpha1.KubernetesApplyDiffOperationUnchanged {
+			_, _ = fmt.Fprint(w, obj.Diff)
+			if !strings.HasSuffix(obj.Diff, "\n") {
+				_, _ = fmt.Fprintln(w)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestDiff(t *testing.T) {
+	f := newServerFixture(t)
+
+	ka := &v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:       "fake-yaml",
+			DryRunDiff: true,
+		},
+	}
+	require.NoError(t, f.client.Create(f.ctx, ka))
+
+	ka.Status.Diff = &v1alpha1.KubernetesApplyDiff{
+		Objects: []v1alpha1.KubernetesApplyObjectDiff{
+			{
+				APIVersion:    "apps/v1",
+				Kind:          "Deployment",
+				Namespace:     "default",
+				Name:          "frontend",
+				Operation:     v1alpha1.KubernetesApplyDiffOperationUpdate,
+				ChangedFields: []string{"spec.replicas"},
+				Diff:          "--- live\n+++ dry-run\n-  replicas: 1\n+  replicas: 2\n",
+			},
+			{
+				APIVersion: "v1",
+				Kind:       "Service",
+				Namespace:  "default",
+				Name:       "frontend",
+				Operation:  v1alpha1.KubernetesApplyDiffOperationUnchanged,
+			},
+		},
+	}
+	require.NoError(t, f.client.Status().Update(f.ctx, ka))
+
+	out := bytes.NewBuffer(nil)
+	cmd := newDiffCmd(genericclioptions.IOStreams{Out: out})
+	cmd.register()
+	err := cmd.run(f.ctx, []string{"frontend"})
+	require.NoError(t, err)
+
+	assert.Equal(t, `Deployment default/frontend: update
+--- live
++++ dry-run
+-  replicas: 1
++  replicas: 2
+Service default/frontend: unchanged
+`, out.String())
+
+	out.Reset()
+	cmd.nameOnly = true
+	err = cmd.run(f.ctx, []string{"frontend"})
+	require.NoError(t, err)
+	assert.Equal(t, `Deployment default/frontend: update
+  spec.replicas
+Service default/frontend: unchanged
+`, out.String())
+}
+
+func TestDiffNotEnabled(t *testing.T) {
+	f := newServerFixture(t)
+
+	err := f.client.Create(f.ctx, &v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend"},
+		Spec:       v1alpha1.KubernetesApplySpec{YAML: "fake-yaml"},
+	})
+	require.NoError(t, err)
+
+	cmd := newDiffCmd(genericclioptions.IOStreams{Out: bytes.NewBuffer(nil)})
+	cmd.register()
+	err = cmd.run(f.ctx, []string{"frontend"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "update_settings(k8s_dry_run_diff=True)")
+
+	err = cmd.run(f.ctx, []string{"backend"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no Kubernetes apply found for resource backend")
+}
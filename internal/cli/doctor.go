@@ -72,6 +72,7 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 		printField("Host", host, nil)
 
 		version, err := clusterDocker.ServerVersion(ctx)
+		printField("Engine", docker.EngineName(version), err)
 		printField("Server Version", version.Version, err)
 		printField("API Version", version.APIVersion, err)
 
@@ -94,6 +95,7 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 			printField("Host", host, nil)
 
 			version, err := localDocker.ServerVersion(ctx)
+			printField("Engine", docker.EngineName(version), err)
 			printField("Server Version", version.Version, err)
 			printField("Version", version.APIVersion, err)
 
@@ -117,6 +119,7 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 		if composeBuild != "" {
 			composeField += fmt.Sprintf(" (build %s)", composeBuild)
 		}
+		printField("Compose", dcCli.Command(ctx), nil)
 		printField("Compose Version", composeField, nil)
 	}
 
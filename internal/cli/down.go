@@ -92,7 +92,18 @@ func (c *downCmd) down(ctx context.Context, downDeps DownDeps, args []string) er
 
 	sortedManifests := sortManifestsForDeletion(tlr.Manifests, tlr.EnabledManifests)
 
-	if err := deleteK8sEntities(ctx, sortedManifests, tlr.UpdateSettings, downDeps, c.deleteNamespaces); err != nil {
+	// Deleting the dev namespace deletes everything in it,
+	// so only do it when we're tearing down every resource.
+	deleteDevNamespace := false
+	if tlr.DevNamespace.DeleteOnDown {
+		if len(sortedManifests) == len(tlr.Manifests) {
+			deleteDevNamespace = true
+		} else {
+			logger.Get(ctx).Infof("Not deleting namespace %s because only some resources are enabled", tlr.DevNamespace.Name)
+		}
+	}
+
+	if err := deleteK8sEntities(ctx, sortedManifests, tlr.UpdateSettings, downDeps, c.deleteNamespaces, tlr.DevNamespace.Name, deleteDevNamespace); err != nil {
 		return err
 	}
 
@@ -178,7 +189,7 @@ func manifestsForNode(node *dependencyNode) []model.Manifest {
 	return append(manifests, node.manifest)
 }
 
-func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, updateSettings model.UpdateSettings, downDeps DownDeps, deleteNamespaces bool) error {
+func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, updateSettings model.UpdateSettings, downDeps DownDeps, deleteNamespaces bool, devNamespace k8s.Namespace, deleteDevNamespace bool) error {
 	kubeconfigWriter := downDeps.kubeconfigWriter
 	kClient := downDeps.kClient
 
@@ -204,6 +215,14 @@ func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, updateSe
 		}()
 	}
 
+	// Unnamespaced objects were deployed to the dev namespace.
+	if devNamespace != "" {
+		entities, err = k8s.InjectNamespace(ctx, kClient, entities, devNamespace)
+		if err != nil {
+			return errors.Wrap(err, "Injecting dev namespace")
+		}
+	}
+
 	entities, _, err = k8s.Filter(entities, func(e k8s.K8sEntity) (b bool, err error) {
 		downPolicy, exists := e.Annotations()["tilt.dev/down-policy"]
 		return !exists || downPolicy != "keep", nil
@@ -230,6 +249,11 @@ func deleteK8sEntities(ctx context.Context, manifests []model.Manifest, updateSe
 		}
 	}
 
+	// The dev namespace is deleted last, after everything in it.
+	if deleteDevNamespace {
+		entities = append(entities, k8s.NewNamespaceEntity(devNamespace.String()))
+	}
+
 	errs := []error{}
 	if len(entities) > 0 {
 		dCtx, cancel := context.WithTimeout(ctx, updateSettings.K8sUpsertTimeout())
@@ -19,6 +19,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
+	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
 	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -84,6 +85,42 @@ func TestDownDeletesNamespacesIfSpecified(t *testing.T) {
 	}
 }
 
+func TestDownDeletesDevNamespace(t *testing.T) {
+	f := newDownFixture(t)
+
+	tlr := newTiltfileLoadResult(newK8sManifest())
+	tlr.DevNamespace = k8scontext.DevNamespace{Name: "tilt-alice", DeleteOnDown: true}
+	f.tfl.Result = tlr
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+	require.Regexp(t, "(?s)name: sancho.*kind: Namespace.*name: tilt-alice", f.kCli.DeletedYaml)
+}
+
+func TestDownPreservesDevNamespaceByDefault(t *testing.T) {
+	f := newDownFixture(t)
+
+	tlr := newTiltfileLoadResult(newK8sManifest())
+	tlr.DevNamespace = k8scontext.DevNamespace{Name: "tilt-alice"}
+	f.tfl.Result = tlr
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+	require.Regexp(t, "(?s)name: sancho.*namespace: tilt-alice", f.kCli.DeletedYaml)
+	require.NotContains(t, f.kCli.DeletedYaml, "kind: Namespace")
+}
+
+func TestDownPreservesDevNamespaceWhenSomeResourcesDisabled(t *testing.T) {
+	f := newDownFixture(t)
+
+	tlr := newTiltfileLoadResult(newK8sConfigMapManifest("foo"), newK8sConfigMapManifest("bar"))
+	tlr.EnabledManifests = []model.ManifestName{"bar"}
+	tlr.DevNamespace = k8scontext.DevNamespace{Name: "tilt-alice", DeleteOnDown: true}
+	f.tfl.Result = tlr
+	err := f.cmd.down(f.ctx, f.deps, nil)
+	require.NoError(t, err)
+	require.Contains(t, f.kCli.DeletedYaml, "bar")
+	require.NotContains(t, f.kCli.DeletedYaml, "kind: Namespace")
+}
+
 func TestDownDeletesManifestsInReverseOrder(t *testing.T) {
 	f := newDownFixture(t)
 
@@ -101,7 +101,7 @@ func newServerFixture(t *testing.T) *serverFixture {
 	apiPort, err := strconv.Atoi(apiPortString)
 	require.NoError(t, err)
 	cfg, err := server.ProvideTiltServerOptions(ctx, model.TiltBuild{}, apiConnProvider,
-		"corgi-charge", testdata.CertKey(), server.APIServerPort(apiPort))
+		"corgi-charge", testdata.CertKey(), server.APIServerPort(apiPort), "")
 	require.NoError(t, err)
 
 	webListener, err := server.ProvideWebListener("localhost", model.WebPort(0))
@@ -126,7 +126,7 @@ func newServerFixture(t *testing.T) *serverFixture {
 	st.UnlockMutableState()
 
 	hudServer, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta,
-		server.NewWebsocketList(), client)
+		server.NewWebsocketList(), client, token.NewShareTokens(dir, model.ProvideAPIServerName(model.WebPort(webPort))))
 	require.NoError(t, err)
 
 	cfgAccess := server.ProvideConfigAccess(dir)
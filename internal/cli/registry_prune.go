@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/apis/tiltfile"
+	"github.com/tilt-dev/tilt/internal/engine/registryprune"
+	"github.com/tilt-dev/tilt/internal/registry"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type registryPruneCmd struct {
+	fileName   string
+	dryRun     bool
+	maxAge     time.Duration
+	keepRecent int
+
+	flags *pflag.FlagSet
+}
+
+func (c *registryPruneCmd) name() model.TiltSubcommand { return "registry-prune" }
+
+func (c *registryPruneCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry-prune",
+		Short: "Delete old images that Tilt pushed to remote registries",
+		Long: `Delete old images that Tilt pushed to remote registries.
+
+Looks up the registry repositories of the images in the Tiltfile, and deletes
+the tags that Tilt created (tagged "tilt-<digest>" or from an
+image_tag_policy() template with a digest, and labeled by Tilt's build)
+that are older than --max-age, except for the --keep-recent most recent builds
+of each image.
+
+Defaults come from registry_prune_settings() in the Tiltfile.
+
+The registry must allow deletes. For the reference registry, set
+REGISTRY_STORAGE_DELETE_ENABLED=true. Deleting tags doesn't free space
+until the registry runs garbage collection.
+`,
+		Example: "tilt registry-prune --dry-run\ntilt registry-prune --max-age=72h --keep-recent=3",
+	}
+
+	addTiltfileFlag(cmd, &c.fileName)
+	cmd.Flags().BoolVar(&c.dryRun, "dry-run", false, "print the images that would be deleted, without deleting them")
+	cmd.Flags().DurationVar(&c.maxAge, "max-age", 0,
+		fmt.Sprintf("delete images older than this (default from the Tiltfile, or %s)", model.RegistryPruneDefaultMaxAge))
+	cmd.Flags().IntVar(&c.keepRecent, "keep-recent", 0,
+		fmt.Sprintf("keep the N most recent builds of each image (default from the Tiltfile, or %d)", model.RegistryPruneDefaultKeepRecent))
+	c.flags = cmd.Flags()
+
+	return cmd
+}
+
+func (c *registryPruneCmd) run(ctx context.Context, args []string) error {
+	if c.keepRecent < 0 {
+		return fmt.Errorf("--keep-recent must not be negative")
+	}
+
+	a := analytics.Get(ctx)
+	a.Incr("cmd.registryPrune", map[string]string{"dryRun": fmt.Sprintf("%t", c.dryRun)})
+	defer a.Flush(time.Second)
+
+	if !logger.Get(ctx).Level().ShouldDisplay(logger.VerboseLvl) {
+		l := logger.NewLogger(logger.VerboseLvl, os.Stdout)
+		ctx = logger.WithLogger(ctx, l)
+	}
+
+	// The prune deps are the same as docker-prune's: a Tiltfile loader,
+	// and a Kubernetes client for resolving the local registry.
+	deps, err := wireDockerPrune(ctx, a, c.name())
+	if err != nil {
+		return err
+	}
+
+	tlr := deps.tfl.Load(ctx, ctrltiltfile.MainTiltfile(c.fileName, args), nil)
+	if tlr.Error != nil {
+		return tlr.Error
+	}
+
+	imgSelectors, err := resolveImageSelectors(ctx, deps.kCli, &tlr)
+	if err != nil {
+		return err
+	}
+
+	settings := tlr.RegistryPruneSettings
+	maxAge := settings.MaxAge
+	if c.maxAge != 0 {
+		maxAge = c.maxAge
+	}
+	keepRecent := settings.KeepRecent
+	if c.flags.Changed("keep-recent") {
+		keepRecent = c.keepRecent
+	}
+
+	repos := registryprune.ReposForSelectors(imgSelectors)
+	if len(repos) == 0 {
+		logger.Get(ctx).Infof("No images in the Tiltfile are pushed to a registry that Tilt can prune")
+		return nil
+	}
+
+	rp := registryprune.NewRegistryPruner(registry.ProvideClient())
+	tagPolicy := registryprune.TagPolicyForManifests(tlr.Manifests)
+	deleted := rp.Prune(ctx, maxAge, keepRecent, repos, tagPolicy, c.dryRun)
+	if len(deleted) == 0 {
+		logger.Get(ctx).Infof("No images to prune")
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/token"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const defaultSharePort = 10351
+
+type shareCmd struct {
+	bindHost   string
+	bindPort   int
+	expires    time.Duration
+	printToken bool
+}
+
+var _ tiltCmd = &shareCmd{}
+
+func newShareCmd() *shareCmd {
+	return &shareCmd{}
+}
+
+func (c *shareCmd) name() model.TiltSubcommand { return "share" }
+
+func (c *shareCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "share",
+		DisableFlagsInUseLine: true,
+		Short:                 "Share a read-only view of Tilt with your teammates",
+		Long: `Share a read-only view of a running Tilt with your teammates.
+
+Mints a read-only token, and serves the Tilt web UI behind it. Anyone with
+the printed link can view your resources and logs until the token expires,
+but can't trigger updates or change anything.
+
+By default, the shared view only listens on localhost (e.g., for an SSH tunnel
+or a port-forward). Use --bind to choose an interface your teammates can reach.
+
+Stops sharing when the token expires, or when you exit.
+
+# share for the next hour, on localhost
+tilt share
+
+# share for 15 minutes on a specific interface
+tilt share --expires=15m --bind=192.168.1.10
+
+# share on every interface
+tilt share --bind=0.0.0.0
+
+# print a read-only token for Prometheus to scrape /metrics with, valid for a week
+tilt share --print-token --expires=168h
+`,
+		Args: cobra.NoArgs,
+	}
+
+	addConnectServerFlags(cmd)
+	cmd.Flags().StringVar(&c.bindHost, "bind", "127.0.0.1", "Address to serve the shared view on. Set to 0.0.0.0 to share on every interface.")
+	cmd.Flags().IntVar(&c.bindPort, "bind-port", defaultSharePort, "Port to serve the shared view on")
+	cmd.Flags().DurationVar(&c.expires, "expires", time.Hour, "How long the read-only token is valid for")
+	cmd.Flags().BoolVar(&c.printToken, "print-token", false,
+		"Print a read-only token and exit, without serving anything. The token stays valid until it expires.")
+
+	return cmd
+}
+
+func (c *shareCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	cmdTags["expires_minutes"] = strconv.Itoa(int(c.expires.Minutes()))
+	a.Incr("cmd.share", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	dir, err := dirs.UseTiltDevDir()
+	if err != nil {
+		return err
+	}
+	shareTokens := token.NewShareTokens(dir, model.ProvideAPIServerName(provideWebPort()))
+
+	if c.printToken {
+		t, err := shareTokens.Mint(token.ScopeReadOnly, c.expires)
+		if err != nil {
+			return fmt.Errorf("minting token: %v", err)
+		}
+		fmt.Println(t.Token)
+		return nil
+	}
+
+	target, err := url.Parse(fmt.Sprintf("http://%s", apiHost()))
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", net.JoinHostPort(c.bindHost, strconv.Itoa(c.bindPort)))
+	if err != nil {
+		return fmt.Errorf("listening on %s:%d: %v", c.bindHost, c.bindPort, err)
+	}
+	defer func() { _ = l.Close() }()
+
+	t, err := shareTokens.Mint(token.ScopeReadOnly, c.expires)
+	if err != nil {
+		return fmt.Errorf("minting token: %v", err)
+	}
+	defer func() { _ = shareTokens.Revoke(t.Token) }()
+
+	ctx, cancel := context.WithDeadline(ctx, t.Expires)
+	defer cancel()
+
+	httpServer := &http.Server{
+		Handler: newShareProxy(target, shareTokens),
+
+		// blackhole any server errors
+		ErrorLog: log.New(io.Discard, "", 0),
+	}
+	go func() {
+		<-ctx.Done()
+		_ = httpServer.Close()
+	}()
+
+	shareURL := url.URL{
+		Scheme:   "http",
+		Host:     net.JoinHostPort(shareHost(c.bindHost), strconv.Itoa(c.bindPort)),
+		Path:     "/",
+		RawQuery: url.Values{shareTokenParam: []string{string(t.Token)}}.Encode(),
+	}
+	fmt.Printf("Sharing a read-only view of Tilt at %s\n", shareURL.String())
+	fmt.Printf("Link expires at %s. Press Ctrl-C to stop sharing.\n", t.Expires.Format(time.Kitchen))
+
+	err = httpServer.Serve(l)
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	fmt.Println("Stopped sharing")
+	return nil
+}
+
+// The query param that share links pass the token in.
+const shareTokenParam = "token"
+
+// newShareProxy forwards requests with a valid share token to the Tilt web server.
+//
+// Scripts and tools can pass the token in the X-Tilt-Token header or an
+// `Authorization: Bearer` header. Browsers get it from the share link: we
+// move the token from the link into the Tilt-Token cookie, and redirect to
+// the same page without it, so that the token doesn't stay in the address bar
+// or browser history.
+//
+// We always forward the token in the header, so that the Tilt web server
+// knows what scope it has (and gives the browser a cookie for the share token
+// rather than the session token).
+//
+// Requests without a valid share token never reach the Tilt web server.
+func newShareProxy(target *url.URL, shareTokens *token.ShareTokens) http.Handler {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromLink := false
+		candidate := r.Header.Get(server.TiltTokenHeaderName)
+		if candidate == "" {
+			candidate = server.BearerTokenFromRequest(r)
+		}
+		if candidate == "" {
+			cookie, err := r.Cookie(server.TiltTokenCookieName)
+			if err == nil {
+				candidate = cookie.Value
+			}
+		}
+		if candidate == "" {
+			candidate = r.URL.Query().Get(shareTokenParam)
+			fromLink = candidate != ""
+		}
+		if candidate == "" {
+			http.Error(w, "missing share token", http.StatusForbidden)
+			return
+		}
+
+		_, ok := shareTokens.Lookup(token.Token(candidate))
+		if !ok {
+			http.Error(w, "invalid or expired share token", http.StatusForbidden)
+			return
+		}
+
+		if fromLink {
+			// Share links are usually opened from another site (like a chat app),
+			// and browsers don't send strict cookies on a redirect from a
+			// cross-site navigation.
+			http.SetCookie(w, &http.Cookie{
+				Name:     server.TiltTokenCookieName,
+				Value:    candidate,
+				Path:     "/",
+				SameSite: http.SameSiteLaxMode,
+			})
+			u := *r.URL
+			q := u.Query()
+			q.Del(shareTokenParam)
+			u.RawQuery = q.Encode()
+			http.Redirect(w, r, u.RequestURI(), http.StatusFound)
+			return
+		}
+
+		r = r.Clone(r.Context())
+		removeCookie(r, server.TiltTokenCookieName)
+		r.Header.Del("Authorization")
+		r.Header.Set(server.TiltTokenHeaderName, candidate)
+		proxy.ServeHTTP(w, r)
+	})
+}
+
+func removeCookie(r *http.Request, name string) {
+	cookies := r.Cookies()
+	r.Header.Del("Cookie")
+	for _, c := range cookies {
+		if c.Name != name {
+			r.AddCookie(c)
+		}
+	}
+}
+
+// The host to print in the share link.
+func shareHost(bindHost string) string {
+	if bindHost != "" && bindHost != "0.0.0.0" && bindHost != "::" {
+		return bindHost
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "localhost"
+	}
+	return hostname
+}
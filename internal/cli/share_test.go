@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+
+	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/token"
+)
+
+func TestShareProxy(t *testing.T) {
+	var lastReq *http.Request
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastReq = r
+	}))
+	defer backend.Close()
+
+	target, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+	shareTokens := token.NewShareTokens(dirs.NewTiltDevDirAt(t.TempDir()), "tilt-default")
+	readOnly, err := shareTokens.Mint(token.ScopeReadOnly, time.Hour)
+	require.NoError(t, err)
+
+	proxy := newShareProxy(target, shareTokens)
+
+	// From the share link, which moves the token into a cookie.
+	rr := httptest.NewRecorder()
+	proxy.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/r/foo/overview?token="+string(readOnly.Token)+"&term=bar", nil))
+	require.Equal(t, http.StatusFound, rr.Code)
+	assert.Nil(t, lastReq, "share link should redirect without forwarding")
+	assert.Equal(t, "/r/foo/overview?term=bar", rr.Header().Get("Location"))
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, server.TiltTokenCookieName, cookies[0].Name)
+	assert.Equal(t, string(readOnly.Token), cookies[0].Value)
+
+	// From the header.
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set(server.TiltTokenHeaderName, string(readOnly.Token))
+	rr = httptest.NewRecorder()
+	proxy.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, string(readOnly.Token), lastReq.Header.Get(server.TiltTokenHeaderName))
+
+	// From a bearer token, which is forwarded in the header instead.
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer "+string(readOnly.Token))
+	rr = httptest.NewRecorder()
+	proxy.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, string(readOnly.Token), lastReq.Header.Get(server.TiltTokenHeaderName))
+	assert.Empty(t, lastReq.Header.Get("Authorization"))
+
+	// From the cookie, which shouldn't be forwarded.
+	req = httptest.NewRequest(http.MethodGet, "/api/view", nil)
+	req.AddCookie(&http.Cookie{Name: server.TiltTokenCookieName, Value: string(readOnly.Token)})
+	req.AddCookie(&http.Cookie{Name: "other", Value: "cookie"})
+	rr = httptest.NewRecorder()
+	proxy.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, string(readOnly.Token), lastReq.Header.Get(server.TiltTokenHeaderName))
+	_, err = lastReq.Cookie(server.TiltTokenCookieName)
+	assert.Error(t, err)
+	other, err := lastReq.Cookie("other")
+	require.NoError(t, err)
+	assert.Equal(t, "cookie", other.Value)
+}
+
+func TestShareProxyRejectsInvalidTokens(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Request should not have been forwarded: %s", r.URL)
+	}))
+	defer backend.Close()
+
+	target, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+	shareTokens := token.NewShareTokens(dirs.NewTiltDevDirAt(t.TempDir()), "tilt-default")
+	proxy := newShareProxy(target, shareTokens)
+
+	rr := httptest.NewRecorder()
+	proxy.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "missing share token")
+
+	rr = httptest.NewRecorder()
+	proxy.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/?token=session-token", nil))
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), "invalid or expired share token")
+}
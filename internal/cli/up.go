@@ -2,22 +2,28 @@ package cli
 
 import (
 	"context"
+	"crypto/sha256"
 	_ "embed"
 	"errors"
 	"fmt"
 	"log"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
 
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/controllers"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/engine/notify"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
+	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
@@ -52,6 +58,8 @@ type upCmd struct {
 	fileName             string
 	outputSnapshotOnExit string
 	disablePortForwards  bool
+	persistState         bool
+	notify               bool
 
 	legacy bool
 	stream bool
@@ -76,7 +84,8 @@ By default:
 2) Running with no Tiltfile args starts all services defined in the Tiltfile
 
 This default behavior does not apply if the Tiltfile uses config.parse or config.set_enabled_resources.
-In that case, see https://docs.tilt.dev/tiltfile_config.html and/or comments in your Tiltfile
+In that case, run tilt up -- --help to list the Tiltfile args, and see https://docs.tilt.dev/tiltfile_config.html
+and/or comments in your Tiltfile
 
 When you exit Tilt (using Ctrl+C), Kubernetes resources and Docker Compose resources continue running;
 you can use tilt down (https://docs.tilt.dev/cli/tilt_down.html) to delete these resources. Any long-running
@@ -90,6 +99,11 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	cmd.Flags().BoolVar(&c.stream, "stream", false, "If true, tilt will stream logs in the terminal.")
 	cmd.Flags().BoolVar(&c.disablePortForwards, "disable-port-forwards", false,
 		"Disable all Kubernetes port-forwards to the local machine.")
+	cmd.Flags().BoolVar(&c.persistState, "persist-state", false,
+		"If true, Tilt saves some of its state (like which resources are disabled, and the last image built for each docker_build) "+
+			"to disk, so that it survives a restart. Images whose inputs haven't changed aren't rebuilt on startup.")
+	cmd.Flags().BoolVar(&c.notify, "notify", false,
+		"If true, Tilt shows a desktop notification when a resource fails or recovers.")
 	cmd.Flags().BoolVar(&logActionsFlag, "logactions", false, "log all actions and state changes")
 	addStartServerFlags(cmd)
 	addDevServerFlags(cmd)
@@ -160,7 +174,12 @@ func (c *upCmd) run(ctx context.Context, args []string) error {
 		log.Printf("Tilt analytics disabled: %s", reason)
 	}
 
-	cmdUpDeps, err := wireCmdUp(ctx, a, cmdUpTags, "up", k8s.DisablePortForwardsFlag(c.disablePortForwards))
+	stateDir, err := c.persistentStateDir()
+	if err != nil {
+		return err
+	}
+
+	cmdUpDeps, err := wireCmdUp(ctx, a, cmdUpTags, "up", k8s.DisablePortForwardsFlag(c.disablePortForwards), stateDir, notify.DesktopFlag(c.notify))
 	if err != nil {
 		deferred.SetOutput(deferred.Original())
 		return err
@@ -196,6 +215,36 @@ func redirectLogs(ctx context.Context, l logger.Logger) context.Context {
 	return ctx
 }
 
+// The directory where the API server saves state for this Tiltfile,
+// if the user opted in with --persist-state.
+//
+// Each Tiltfile (and web port) gets its own directory, so that state
+// doesn't leak between projects.
+func (c *upCmd) persistentStateDir() (server.PersistentStateDir, error) {
+	if !c.persistState {
+		return "", nil
+	}
+
+	dir, err := dirs.UseTiltDevDir()
+	if err != nil {
+		return "", err
+	}
+
+	tiltfilePath, err := filepath.Abs(c.fileName)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256([]byte(tiltfilePath))
+	name := fmt.Sprintf("%s-%x", model.ProvideAPIServerName(provideWebPort()), hash[:6])
+	return server.PersistentStateDir(filepath.Join(dir.Root(), "state", name)), nil
+}
+
+// The API server persists ImageMaps whenever it has a state dir.
+func provideImageMapsPersisted(dir server.PersistentStateDir) buildcontrol.ImageMapsPersisted {
+	return buildcontrol.ImageMapsPersisted(dir != "")
+}
+
 func provideUpdateModeFlag() liveupdates.UpdateModeFlag {
 	return liveupdates.UpdateModeFlag(updateModeFlag)
 }
@@ -31,3 +31,23 @@ func TestHudEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestPersistentStateDir(t *testing.T) {
+	t.Setenv("TILT_DEV_DIR", t.TempDir())
+
+	stateDir := func(args ...string) string {
+		cmd := upCmd{}
+		c := cmd.register()
+		require.NoError(t, c.Flags().Parse(args))
+		dir, err := cmd.persistentStateDir()
+		require.NoError(t, err)
+		return string(dir)
+	}
+
+	require.Equal(t, "", stateDir())
+
+	a := stateDir("--persist-state", "-f", "a/Tiltfile")
+	require.NotEqual(t, "", a)
+	require.Equal(t, a, stateDir("--persist-state", "-f", "a/Tiltfile"))
+	require.NotEqual(t, a, stateDir("--persist-state", "-f", "b/Tiltfile"))
+}
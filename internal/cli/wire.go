@@ -43,6 +43,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/notify"
+	"github.com/tilt-dev/tilt/internal/engine/registryprune"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -54,7 +56,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/openurl"
+	"github.com/tilt-dev/tilt/internal/registry"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
 	"github.com/tilt-dev/tilt/internal/token"
@@ -102,6 +106,9 @@ var BaseWireSet = wire.NewSet(
 	k8swatch.NewEventWatchManager,
 	uisession.NewSubscriber,
 	uiresource.NewSubscriber,
+	metrics.NewSubscriber,
+	notify.NewController,
+	notify.NewDesktopController,
 	configs.NewConfigsController,
 	configs.NewTriggerQueueSubscriber,
 	telemetry.NewController,
@@ -113,6 +120,7 @@ var BaseWireSet = wire.NewSet(
 
 	build.ProvideClock,
 	provideClock,
+	provideImageMapsPersisted,
 	model.ProvideStartTime,
 	provideLogSource,
 	provideLogResources,
@@ -130,6 +138,9 @@ var BaseWireSet = wire.NewSet(
 	wire.Bind(new(store.Dispatcher), new(*store.Store)),
 
 	dockerprune.NewDockerPruner,
+	registry.ProvideClient,
+	wire.Bind(new(build.ImageRegistry), new(*registry.Client)),
+	registryprune.NewRegistryPruner,
 
 	provideTiltInfo,
 	engine.NewUpper,
@@ -137,6 +148,7 @@ var BaseWireSet = wire.NewSet(
 	engineanalytics.ProvideAnalyticsReporter,
 	provideUpdateModeFlag,
 	fsevent.ProvideWatcherMaker,
+	fsevent.ProvidePollWatcherMaker,
 	fsevent.ProvideTimerMaker,
 
 	controllers.WireSet,
@@ -160,8 +172,10 @@ var BaseWireSet = wire.NewSet(
 	dirs.UseTiltDevDir,
 	xdg.NewTiltDevBase,
 	token.GetOrCreateToken,
+	token.NewShareTokens,
 
-	build.NewKINDLoader,
+	build.NewImageLoader,
+	build.NewImageSigner,
 
 	wire.Value(feature.MainDefaults),
 )
@@ -187,7 +201,7 @@ func wireDockerPrune(ctx context.Context, analytics *analytics.TiltAnalytics, su
 }
 
 func wireCmdUp(ctx context.Context, analytics *analytics.TiltAnalytics, cmdTags engineanalytics.CmdTags, subcommand model.TiltSubcommand,
-	disablePortForwards k8s.DisablePortForwardsFlag) (CmdUpDeps, error) {
+	disablePortForwards k8s.DisablePortForwardsFlag, stateDir server.PersistentStateDir, desktopNotify notify.DesktopFlag) (CmdUpDeps, error) {
 	wire.Build(UpWireSet,
 		cloud.NewSnapshotter,
 		wire.Value(store.EngineModeUp),
@@ -209,6 +223,8 @@ func wireCmdCI(ctx context.Context, analytics *analytics.TiltAnalytics, subcomma
 		cloud.NewSnapshotter,
 		wire.Value(store.EngineModeCI),
 		wire.Value(k8s.DisablePortForwardsFlag(false)),
+		wire.Value(server.PersistentStateDir("")),
+		wire.Value(notify.DesktopFlag(false)),
 		wire.Value(engineanalytics.CmdTags(map[string]string{})),
 		wire.Struct(new(CmdCIDeps), "*"),
 	)
@@ -234,6 +250,7 @@ func wireCmdUpdog(ctx context.Context,
 		provideUpdogCmdSubscribers,
 		wire.Value(store.EngineModeCI),
 		wire.Value(k8s.DisablePortForwardsFlag(false)),
+		wire.Value(server.PersistentStateDir("")),
 		wire.Struct(new(CmdUpdogDeps), "*"))
 	return CmdUpdogDeps{}, nil
 }
@@ -192,4 +192,8 @@ type TaggedRefs struct {
 	//
 	// TODO(milas): Rename to ContainerRuntimeRef
 	ClusterRef reference.NamedTagged
+
+	// AdditionalLocalRefs are extra tags from the image's tag policy.
+	// They're pushed with LocalRef, but never deployed.
+	AdditionalLocalRefs []reference.NamedTagged
 }
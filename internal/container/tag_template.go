@@ -0,0 +1,214 @@
+package container
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The longest tag that a registry will accept.
+const maxTagLength = 128
+
+var tagTemplateVarRegex = regexp.MustCompile(`\{([^{}]*)\}`)
+var tagDigestVarRegex = regexp.MustCompile(`^digest([0-9]+)$`)
+var invalidTagCharRegex = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// The values that can be substituted into a tag template.
+type TagTemplateVars struct {
+	// The hex-encoded image digest, without the algorithm.
+	Digest string
+
+	// Empty if the build directory isn't in a git repo.
+	GitBranch string
+	GitSHA    string
+}
+
+// ValidateTagTemplate checks that a tag template only uses known variables.
+//
+// If requireDigest is true, the template must also reference the
+// digest, so that every build gets a new tag.
+func ValidateTagTemplate(tmpl string, requireDigest bool) error {
+	if strings.TrimSpace(tmpl) == "" {
+		return fmt.Errorf("tag template must not be empty")
+	}
+
+	hasDigest := false
+	for _, m := range tagTemplateVarRegex.FindAllStringSubmatch(tmpl, -1) {
+		name := m[1]
+		switch {
+		case name == "digest":
+			hasDigest = true
+		case tagDigestVarRegex.MatchString(name):
+			n, _ := strconv.Atoi(tagDigestVarRegex.FindStringSubmatch(name)[1])
+			if n < 1 {
+				return fmt.Errorf("tag template %q: {%s} must use at least 1 character of the digest", tmpl, name)
+			}
+			hasDigest = true
+		case name == "git_branch", name == "git_sha", name == "git_sha_short":
+		default:
+			return fmt.Errorf("tag template %q: unknown variable {%s}. "+
+				"Valid variables: {digest}, {digestN}, {git_branch}, {git_sha}, {git_sha_short}", tmpl, name)
+		}
+	}
+
+	if requireDigest && !hasDigest {
+		return fmt.Errorf("tag template %q must contain {digest} or {digestN}, so that each build has a unique tag", tmpl)
+	}
+	return nil
+}
+
+// RenderTagTemplate substitutes vars into a tag template.
+//
+// Characters that aren't allowed in a tag are replaced with "-"
+// (e.g., the slash in a branch name like feature/login).
+//
+// If the tag would be longer than a registry accepts, we shorten the git
+// branch, which is the only variable without a fixed length. We never cut
+// into the digest, because that's what makes each tag unique.
+func RenderTagTemplate(tmpl string, vars TagTemplateVars) (string, error) {
+	err := ValidateTagTemplate(tmpl, false)
+	if err != nil {
+		return "", err
+	}
+
+	vars.GitBranch = invalidTagCharRegex.ReplaceAllString(vars.GitBranch, "-")
+	result, err := renderTagTemplate(tmpl, vars)
+	if err != nil {
+		return "", err
+	}
+
+	branchCount := strings.Count(tmpl, "{git_branch}")
+	if len(result) > maxTagLength && branchCount > 0 {
+		excess := len(result) - maxTagLength
+		keep := len(vars.GitBranch) - (excess+branchCount-1)/branchCount
+		if keep < 0 {
+			keep = 0
+		}
+		vars.GitBranch = vars.GitBranch[:keep]
+		result, err = renderTagTemplate(tmpl, vars)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(result) > maxTagLength {
+		return "", fmt.Errorf("tag template %q rendered a tag longer than %d characters: %s",
+			tmpl, maxTagLength, result)
+	}
+	if result == "" {
+		return "", fmt.Errorf("tag template %q rendered an empty tag", tmpl)
+	}
+	return result, nil
+}
+
+func renderTagTemplate(tmpl string, vars TagTemplateVars) (string, error) {
+	var renderErr error
+	result := tagTemplateVarRegex.ReplaceAllStringFunc(tmpl, func(v string) string {
+		name := strings.Trim(v, "{}")
+		switch name {
+		case "digest":
+			return vars.Digest
+		case "git_branch":
+			return vars.GitBranch
+		case "git_sha":
+			return vars.GitSHA
+		case "git_sha_short":
+			if len(vars.GitSHA) > 7 {
+				return vars.GitSHA[:7]
+			}
+			return vars.GitSHA
+		}
+
+		n, _ := strconv.Atoi(tagDigestVarRegex.FindStringSubmatch(name)[1])
+		if n > len(vars.Digest) {
+			renderErr = fmt.Errorf("tag template %q: digest too short for {%s}: %s", tmpl, name, vars.Digest)
+			return ""
+		}
+		return vars.Digest[:n]
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+
+	result = invalidTagCharRegex.ReplaceAllString(result, "-")
+
+	// Tags can't start with a period or a dash. This usually
+	// happens when a git variable is empty.
+	return strings.TrimLeft(result, ".-"), nil
+}
+
+// TagTemplateRegexp returns a regexp that matches the tags that
+// RenderTagTemplate can produce from tmpl, for any vars.
+func TagTemplateRegexp(tmpl string) (*regexp.Regexp, error) {
+	err := ValidateTagTemplate(tmpl, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// RenderTagTemplate trims leading periods and dashes, which can eat
+	// whole parts of the template (e.g., an empty branch and the dash after it).
+	// So we match each part that the tag could start with, preceded
+	// by parts that could have been trimmed away.
+	type part struct {
+		pattern   string // matches the rendered part
+		start     string // matches the rendered part after trimming, or "" if it'd be trimmed away
+		trimmable bool   // whether the rendered part can be trimmed away completely
+	}
+	var parts []part
+	addLiteral := func(s string) {
+		if s == "" {
+			return
+		}
+		s = invalidTagCharRegex.ReplaceAllString(s, "-")
+		trimmed := strings.TrimLeft(s, ".-")
+		p := part{pattern: regexp.QuoteMeta(s), trimmable: trimmed == ""}
+		if trimmed != "" {
+			p.start = regexp.QuoteMeta(trimmed)
+		}
+		parts = append(parts, p)
+	}
+
+	last := 0
+	for _, m := range tagTemplateVarRegex.FindAllStringSubmatchIndex(tmpl, -1) {
+		addLiteral(tmpl[last:m[0]])
+		last = m[1]
+
+		name := tmpl[m[2]:m[3]]
+		switch name {
+		case "digest":
+			parts = append(parts, part{pattern: "[0-9a-f]+", start: "[0-9a-f]+"})
+		case "git_branch":
+			parts = append(parts, part{
+				pattern:   "[a-zA-Z0-9_.-]*",
+				start:     "[a-zA-Z0-9_][a-zA-Z0-9_.-]*",
+				trimmable: true,
+			})
+		case "git_sha", "git_sha_short":
+			parts = append(parts, part{pattern: "[0-9a-f]*", start: "[0-9a-f]+", trimmable: true})
+		default:
+			n := tagDigestVarRegex.FindStringSubmatch(name)[1]
+			p := fmt.Sprintf("[0-9a-f]{%s}", n)
+			parts = append(parts, part{pattern: p, start: p})
+		}
+	}
+	addLiteral(tmpl[last:])
+
+	var alternatives []string
+	for i, p := range parts {
+		if p.start != "" {
+			alt := p.start
+			for _, rest := range parts[i+1:] {
+				alt += rest.pattern
+			}
+			alternatives = append(alternatives, alt)
+		}
+		if !p.trimmable {
+			break
+		}
+	}
+	if len(alternatives) == 0 {
+		return nil, fmt.Errorf("tag template %q can only render an empty tag", tmpl)
+	}
+	return regexp.Compile("^(?:" + strings.Join(alternatives, "|") + ")$")
+}
@@ -0,0 +1,122 @@
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tagTestVars = TagTemplateVars{
+	Digest:    "cc5f4c463f81c55183d8d737ba2f0d30b3e6f3670dbe2da68f0aac168e93fbb1",
+	GitBranch: "feature/login",
+	GitSHA:    "3b18e512dba79e4c8300dd08aeb37f8e728b8dad",
+}
+
+func TestRenderTagTemplate(t *testing.T) {
+	for _, tc := range []struct {
+		tmpl     string
+		vars     TagTemplateVars
+		expected string
+	}{
+		{"{git_branch}-{git_sha_short}-{digest8}", tagTestVars, "feature-login-3b18e51-cc5f4c46"},
+		{"tilt-{digest16}", tagTestVars, "tilt-cc5f4c463f81c551"},
+		{"{git_sha}", tagTestVars, "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"},
+		{"{git_branch}-latest", tagTestVars, "feature-login-latest"},
+		{"{git_branch}-{digest8}", TagTemplateVars{Digest: tagTestVars.Digest}, "cc5f4c46"},
+	} {
+		t.Run(tc.tmpl, func(t *testing.T) {
+			actual, err := RenderTagTemplate(tc.tmpl, tc.vars)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestRenderTagTemplateErrors(t *testing.T) {
+	_, err := RenderTagTemplate("{git_branch}", TagTemplateVars{Digest: tagTestVars.Digest})
+	assert.EqualError(t, err, `tag template "{git_branch}" rendered an empty tag`)
+
+	_, err = RenderTagTemplate("{digest99}", TagTemplateVars{Digest: "cc5f"})
+	assert.EqualError(t, err, `tag template "{digest99}": digest too short for {digest99}: cc5f`)
+}
+
+func TestRenderTagTemplateLongBranch(t *testing.T) {
+	vars := tagTestVars
+	vars.GitBranch = "feature/" + strings.Repeat("x", 200)
+
+	actual, err := RenderTagTemplate("{git_branch}-{digest16}", vars)
+	require.NoError(t, err)
+	assert.Len(t, actual, maxTagLength)
+	assert.True(t, strings.HasPrefix(actual, "feature-xxx"), actual)
+	assert.True(t, strings.HasSuffix(actual, "-cc5f4c463f81c551"), actual)
+
+	actual, err = RenderTagTemplate("{git_branch}-{digest}-{git_branch}", vars)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(actual), maxTagLength)
+	assert.Contains(t, actual, "-"+tagTestVars.Digest+"-")
+}
+
+func TestRenderTagTemplateTooLong(t *testing.T) {
+	tmpl := strings.Repeat("x", 100) + "-{digest}"
+	_, err := RenderTagTemplate(tmpl, tagTestVars)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "longer than 128 characters")
+	}
+}
+
+func TestTagTemplateRegexp(t *testing.T) {
+	for _, tc := range []struct {
+		tmpl       string
+		vars       []TagTemplateVars
+		notMatched []string
+	}{
+		{
+			tmpl:       "{git_branch}-{git_sha_short}-{digest8}",
+			vars:       []TagTemplateVars{tagTestVars, {Digest: tagTestVars.Digest}, {Digest: tagTestVars.Digest, GitBranch: "-x"}},
+			notMatched: []string{"v1.2.3", "main-latest", "main-3b18e51-cc5f4c4", "main-3b18e51-cc5f4c463"},
+		},
+		{
+			tmpl:       "{git_branch}-latest",
+			vars:       []TagTemplateVars{tagTestVars},
+			notMatched: []string{"latest-main", "-latest"},
+		},
+		{
+			tmpl:       "tilt/{digest16}",
+			vars:       []TagTemplateVars{tagTestVars},
+			notMatched: []string{"tilt-cc5f4c46", "tilt/cc5f4c463f81c551"},
+		},
+	} {
+		t.Run(tc.tmpl, func(t *testing.T) {
+			re, err := TagTemplateRegexp(tc.tmpl)
+			require.NoError(t, err)
+			for _, vars := range tc.vars {
+				tag, err := RenderTagTemplate(tc.tmpl, vars)
+				require.NoError(t, err)
+				assert.True(t, re.MatchString(tag), "%s should match %s", re, tag)
+			}
+			for _, tag := range tc.notMatched {
+				assert.False(t, re.MatchString(tag), "%s should not match %s", re, tag)
+			}
+		})
+	}
+}
+
+func TestValidateTagTemplate(t *testing.T) {
+	assert.NoError(t, ValidateTagTemplate("{git_branch}-{digest8}", true))
+	assert.NoError(t, ValidateTagTemplate("{git_branch}-latest", false))
+
+	err := ValidateTagTemplate("{git_branch}-latest", true)
+	assert.EqualError(t, err, `tag template "{git_branch}-latest" must contain {digest} or {digestN}, so that each build has a unique tag`)
+
+	err = ValidateTagTemplate("{branch}-{digest}", true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "unknown variable {branch}")
+	}
+
+	err = ValidateTagTemplate("{digest0}", true)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "at least 1 character")
+	}
+}
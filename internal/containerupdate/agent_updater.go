@@ -0,0 +1,291 @@
+package containerupdate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/syncagent"
+	"github.com/tilt-dev/tilt/internal/tracer"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How long we wait for a new port-forward to the agent to pass a health check.
+const agentConnectTimeout = 5 * time.Second
+
+// How long we wait before trying to reach an agent again, after we couldn't
+// reach it. The agent may still be starting, so we back off up to a limit.
+const (
+	agentRetryInitialBackoff = 5 * time.Second
+	agentRetryMaxBackoff     = 2 * time.Minute
+)
+
+// AgentUpdater applies live updates through a sync agent running in the
+// container (see the syncagent package), reached over a port-forward.
+//
+// The port-forwards stay open between updates. If the agent can't be reached,
+// we fall back to the ExecUpdater.
+type AgentUpdater struct {
+	pfClient k8s.PortForwardClient
+	fallback *ExecUpdater
+	token    syncagent.Token
+	now      func() time.Time
+
+	mu sync.Mutex
+
+	// Open connections to agents.
+	conns map[agentKey]*agentConn
+
+	// Containers where we couldn't reach an agent. We don't retry these
+	// until their backoff expires, so that every update doesn't wait on
+	// a port-forward that won't work.
+	unavailable map[agentKey]agentRetry
+}
+
+type agentRetry struct {
+	retryAt time.Time
+	backoff time.Duration
+}
+
+type agentKey struct {
+	namespace k8s.Namespace
+	podID     k8s.PodID
+	port      int
+}
+
+type agentConn struct {
+	client *syncagent.Client
+	cancel context.CancelFunc
+}
+
+func NewAgentUpdater(kCli k8s.Client, fallback *ExecUpdater, token syncagent.Token) *AgentUpdater {
+	return &AgentUpdater{
+		pfClient:    kCli,
+		fallback:    fallback,
+		token:       token,
+		now:         time.Now,
+		conns:       make(map[agentKey]*agentConn),
+		unavailable: make(map[agentKey]agentRetry),
+	}
+}
+
+// ForPort returns a ContainerUpdater that talks to agents on the given container port.
+func (cu *AgentUpdater) ForPort(port int) ContainerUpdater {
+	return agentPortUpdater{cu: cu, port: port}
+}
+
+var _ ContainerHasher = agentPortUpdater{}
+
+type agentPortUpdater struct {
+	cu   *AgentUpdater
+	port int
+}
+
+func (u agentPortUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Container,
+	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
+	return u.cu.updateContainer(ctx, u.port, cInfo, archiveToCopy, filesToDelete, cmds, hotReload)
+}
+
+func (u agentPortUpdater) DownloadArchive(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string, w io.Writer) error {
+	return u.cu.downloadArchive(ctx, u.port, cInfo, containerPath, nil, w)
+}
+
+// HashFiles only works through the agent. If it can't be reached, the caller
+// should fall back to DownloadArchive, which can use exec.
+func (u agentPortUpdater) HashFiles(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string) (map[string]string, error) {
+	key := agentKey{namespace: cInfo.Namespace, podID: cInfo.PodID, port: u.port}
+	conn, err := u.cu.connect(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := conn.client.Hashes(ctx, containerPath)
+	if err != nil {
+		return nil, fmt.Errorf("hashing %s via sync agent: %w", containerPath, err)
+	}
+	return hashes, nil
+}
+
+func (u agentPortUpdater) DownloadFiles(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string, names []string, w io.Writer) error {
+	return u.cu.downloadArchive(ctx, u.port, cInfo, containerPath, names, w)
+}
+
+func (cu *AgentUpdater) downloadArchive(ctx context.Context, port int, cInfo liveupdates.Container,
+	containerPath string, names []string, w io.Writer) error {
+	key := agentKey{namespace: cInfo.Namespace, podID: cInfo.PodID, port: port}
+	conn, err := cu.connect(ctx, key)
+	if err != nil {
+		if len(names) > 0 {
+			return err
+		}
+		return cu.fallback.DownloadArchive(ctx, cInfo, containerPath, w)
+	}
+
+	r, err := conn.client.Archive(ctx, containerPath, names)
+	if err != nil {
+		return fmt.Errorf("copying %s via sync agent: %w", containerPath, err)
+	}
+	defer func() { _ = r.Close() }()
+	_, err = io.Copy(w, r)
+	if err != nil {
+		cu.disconnect(key, conn)
+		return fmt.Errorf("copying %s via sync agent: %w", containerPath, err)
+	}
+	return nil
+}
+
+func (cu *AgentUpdater) updateContainer(ctx context.Context, port int, cInfo liveupdates.Container,
+	archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error {
+	if !hotReload {
+		// The agent can't restart containers either, so let the ExecUpdater explain.
+		return cu.fallback.UpdateContainer(ctx, cInfo, archiveToCopy, filesToDelete, cmds, hotReload)
+	}
+
+	key := agentKey{namespace: cInfo.Namespace, podID: cInfo.PodID, port: port}
+	conn, err := cu.connect(ctx, key)
+	if err != nil {
+		logger.Get(ctx).Infof("Sync agent unavailable in pod %s on port %d (%v). Falling back to exec.",
+			cInfo.PodID, port, err)
+		return cu.fallback.UpdateContainer(ctx, cInfo, archiveToCopy, filesToDelete, cmds, hotReload)
+	}
+
+	err = conn.client.Sync(ctx, archiveToCopy, filesToDelete)
+	if err != nil {
+		cu.disconnect(key, conn)
+		return fmt.Errorf("syncing files via sync agent: %w", err)
+	}
+
+	l := logger.Get(ctx)
+	w := l.Writer(logger.InfoLvl)
+	for i, c := range cmds {
+		if !c.EchoOff {
+			l.Infof("[CMD %d/%d] %s", i+1, len(cmds), strings.Join(c.Argv, " "))
+		}
+		execCtx, span := startExecSpan(ctx, cInfo, c)
+		exitCode, err := conn.client.Run(execCtx, c.Argv, w)
+		if err == nil && exitCode != 0 {
+			err = NewExecError(c, exitCode)
+		}
+		tracer.EndWithError(span, err)
+		if err != nil {
+			if exitCode == -1 {
+				cu.disconnect(key, conn)
+			}
+			return fmt.Errorf(
+				"executing on container %s: %w",
+				cInfo.ContainerID.ShortStr(),
+				wrapRunStepError(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+// Returns an open connection to the agent, port-forwarding to it if necessary.
+func (cu *AgentUpdater) connect(ctx context.Context, key agentKey) (*agentConn, error) {
+	cu.mu.Lock()
+	defer cu.mu.Unlock()
+
+	if conn, ok := cu.conns[key]; ok {
+		return conn, nil
+	}
+	retry, wasUnavailable := cu.unavailable[key]
+	if wasUnavailable && cu.now().Before(retry.retryAt) {
+		return nil, fmt.Errorf("not reachable on an earlier update")
+	}
+
+	conn, err := cu.dial(ctx, key)
+	if err != nil {
+		backoff := agentRetryInitialBackoff
+		if wasUnavailable {
+			backoff = min(2*retry.backoff, agentRetryMaxBackoff)
+		}
+		cu.unavailable[key] = agentRetry{retryAt: cu.now().Add(backoff), backoff: backoff}
+		return nil, err
+	}
+	delete(cu.unavailable, key)
+	cu.conns[key] = conn
+	return conn, nil
+}
+
+// Forget closes any connections to agents in the pod, and forgets
+// whether they were reachable. Called when the pod goes away.
+func (cu *AgentUpdater) Forget(namespace k8s.Namespace, podID k8s.PodID) {
+	cu.mu.Lock()
+	defer cu.mu.Unlock()
+	for key := range cu.unavailable {
+		if key.namespace == namespace && key.podID == podID {
+			delete(cu.unavailable, key)
+		}
+	}
+	for key, conn := range cu.conns {
+		if key.namespace == namespace && key.podID == podID {
+			delete(cu.conns, key)
+			conn.cancel()
+		}
+	}
+}
+
+func (cu *AgentUpdater) dial(ctx context.Context, key agentKey) (*agentConn, error) {
+	// The port-forward outlives this update, so it can't use the update's context.
+	pfCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	pf, err := cu.pfClient.CreatePortForwarder(pfCtx, key.namespace, key.podID, 0, key.port, "127.0.0.1")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- pf.ForwardPorts()
+	}()
+
+	select {
+	case <-pf.ReadyCh():
+	case err := <-errCh:
+		cancel()
+		if err == nil {
+			err = fmt.Errorf("port-forward closed")
+		}
+		return nil, err
+	case <-ctx.Done():
+		cancel()
+		return nil, ctx.Err()
+	}
+
+	client := syncagent.NewClient(fmt.Sprintf("http://127.0.0.1:%d", pf.LocalPort()), cu.token)
+	healthCtx, healthCancel := context.WithTimeout(ctx, agentConnectTimeout)
+	defer healthCancel()
+	err = client.Healthy(healthCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	conn := &agentConn{client: client, cancel: cancel}
+	go func() {
+		// When the port-forward dies (e.g., because the pod went away),
+		// forget the connection.
+		<-errCh
+		cu.disconnect(key, conn)
+	}()
+	return conn, nil
+}
+
+func (cu *AgentUpdater) disconnect(key agentKey, conn *agentConn) {
+	cu.mu.Lock()
+	defer cu.mu.Unlock()
+	if cu.conns[key] == conn {
+		delete(cu.conns, key)
+	}
+	conn.cancel()
+}
@@ -0,0 +1,193 @@
+package containerupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/syncagent"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestAgentUpdaterSyncsAndRuns(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	f := newAgentFixture(t, true)
+
+	f.src.WriteFile("main.go", "package main")
+	archive := build.TarArchiveForPaths(f.ctx, []build.PathMapping{
+		{LocalPath: f.src.JoinPath("main.go"), ContainerPath: "/app/main.go"},
+	}, nil)
+	cmd := model.Cmd{Argv: []string{"sh", "-c", "echo built"}}
+	err := f.cu.ForPort(syncagent.DefaultPort).UpdateContainer(f.ctx, TestContainerInfo, archive, nil, []model.Cmd{cmd}, true)
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(filepath.Join(f.dst, "app", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main", string(contents))
+	assert.Empty(t, f.kCli.ExecCalls)
+
+	// The port-forward is reused on the next update.
+	err = f.cu.ForPort(syncagent.DefaultPort).UpdateContainer(f.ctx, TestContainerInfo, newTarReader(t), nil, []model.Cmd{cmd}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.pf.count())
+	assert.Equal(t, syncagent.DefaultPort, f.pf.remotePort)
+}
+
+func TestAgentUpdaterRunFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	f := newAgentFixture(t, true)
+
+	cmd := model.Cmd{Argv: []string{"sh", "-c", "exit 2"}}
+	err := f.cu.ForPort(syncagent.DefaultPort).UpdateContainer(f.ctx, TestContainerInfo, newTarReader(t), nil, []model.Cmd{cmd}, true)
+	require.Error(t, err)
+	assert.True(t, build.IsRunStepFailure(err))
+	assert.Contains(t, err.Error(), "failed with exit code: 2")
+}
+
+func TestAgentUpdaterFallsBackToExec(t *testing.T) {
+	f := newAgentFixture(t, false)
+
+	err := f.cu.ForPort(syncagent.DefaultPort).UpdateContainer(f.ctx, TestContainerInfo, newReader("boop"), nil, cmds, true)
+	require.NoError(t, err)
+
+	// tar + 2 cmds
+	assert.Len(t, f.kCli.ExecCalls, 3)
+	assert.Equal(t, tarCmd().Argv, f.kCli.ExecCalls[0].Cmd)
+	assert.Contains(t, f.out.String(), "Sync agent unavailable")
+
+	// We don't keep trying to reach an agent that isn't there.
+	err = f.cu.ForPort(syncagent.DefaultPort).UpdateContainer(f.ctx, TestContainerInfo, newReader("boop"), nil, cmds, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.pf.count())
+}
+
+func TestAgentUpdaterRetriesWithBackoff(t *testing.T) {
+	f := newAgentFixture(t, false)
+	update := func() {
+		err := f.cu.ForPort(syncagent.DefaultPort).UpdateContainer(f.ctx, TestContainerInfo, newReader("boop"), nil, nil, true)
+		require.NoError(t, err)
+	}
+
+	update()
+	assert.Equal(t, 1, f.pf.count())
+
+	// The agent may still be starting, so we try again once the backoff expires.
+	f.now = f.now.Add(agentRetryInitialBackoff)
+	update()
+	assert.Equal(t, 2, f.pf.count())
+
+	// The backoff doubles.
+	f.now = f.now.Add(agentRetryInitialBackoff)
+	update()
+	assert.Equal(t, 2, f.pf.count())
+	f.now = f.now.Add(agentRetryInitialBackoff)
+	update()
+	assert.Equal(t, 3, f.pf.count())
+}
+
+func TestAgentUpdaterForgetsPod(t *testing.T) {
+	f := newAgentFixture(t, false)
+
+	err := f.cu.ForPort(syncagent.DefaultPort).UpdateContainer(f.ctx, TestContainerInfo, newReader("boop"), nil, nil, true)
+	require.NoError(t, err)
+	assert.Len(t, f.cu.unavailable, 1)
+
+	f.cu.Forget(TestContainerInfo.Namespace, TestContainerInfo.PodID)
+	assert.Empty(t, f.cu.unavailable)
+}
+
+type agentFixture struct {
+	ctx  context.Context
+	out  *bytes.Buffer
+	kCli *k8s.FakeK8sClient
+	pf   *fakeAgentPortForwardClient
+	cu   *AgentUpdater
+	src  *tempdir.TempDirFixture
+	dst  string
+	now  time.Time
+}
+
+func newAgentFixture(t *testing.T, withAgent bool) *agentFixture {
+	kCli := k8s.NewFakeK8sClient(t)
+	out := &bytes.Buffer{}
+	ctx, _, _ := testutils.ForkedCtxAndAnalyticsForTest(out)
+
+	dst := t.TempDir()
+	pf := &fakeAgentPortForwardClient{}
+	if withAgent {
+		server := httptest.NewServer(syncagent.NewServer(dst, syncagent.StaticToken("fake-token")).Handler())
+		t.Cleanup(server.Close)
+		_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+		require.NoError(t, err)
+		pf.localPort, err = strconv.Atoi(port)
+		require.NoError(t, err)
+	} else {
+		// Nothing listens on the port this forwards to.
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		pf.localPort = l.Addr().(*net.TCPAddr).Port
+		require.NoError(t, l.Close())
+	}
+
+	f := &agentFixture{
+		ctx:  ctx,
+		out:  out,
+		kCli: kCli,
+		pf:   pf,
+		src:  tempdir.NewTempDirFixture(t),
+		dst:  dst,
+		now:  time.Now(),
+	}
+	f.cu = NewAgentUpdater(kCli, NewExecUpdater(kCli), "fake-token")
+	f.cu.pfClient = pf
+	f.cu.now = func() time.Time { return f.now }
+	return f
+}
+
+func newTarReader(t *testing.T) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	require.NoError(t, tar.NewWriter(buf).Close())
+	return buf
+}
+
+// Pretends to port-forward to the pod, but actually points at a local test server.
+type fakeAgentPortForwardClient struct {
+	mu         sync.Mutex
+	localPort  int
+	remotePort int
+	calls      int
+}
+
+func (c *fakeAgentPortForwardClient) CreatePortForwarder(ctx context.Context, namespace k8s.Namespace, podID k8s.PodID, localPort int, remotePort int, host string) (k8s.PortForwarder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+	c.remotePort = remotePort
+	return k8s.NewFakePortForwarder(ctx, c.localPort, namespace), nil
+}
+
+func (c *fakeAgentPortForwardClient) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
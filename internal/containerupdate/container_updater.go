@@ -3,8 +3,13 @@ package containerupdate
 import (
 	"context"
 	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -12,3 +17,31 @@ type ContainerUpdater interface {
 	UpdateContainer(ctx context.Context, cInfo liveupdates.Container,
 		archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error
 }
+
+// ContainerDownloader copies files out of a container,
+// for syncing them back to the host.
+type ContainerDownloader interface {
+	// Writes a tar archive of the container path to w. Entries are named
+	// relative to the path's parent directory, like `tar -C dir -c base`.
+	DownloadArchive(ctx context.Context, cInfo liveupdates.Container, containerPath string, w io.Writer) error
+}
+
+// ContainerHasher can report the content of container files without copying
+// them, so that sync-back only downloads the files that changed.
+type ContainerHasher interface {
+	ContainerDownloader
+
+	// Returns the sha256 of every regular file under the container path,
+	// keyed by the entry names that DownloadArchive uses.
+	HashFiles(ctx context.Context, cInfo liveupdates.Container, containerPath string) (map[string]string, error)
+
+	// Like DownloadArchive, but only includes the named regular files.
+	DownloadFiles(ctx context.Context, cInfo liveupdates.Container, containerPath string, names []string, w io.Writer) error
+}
+
+// Starts a span for running one live update exec step in a container.
+func startExecSpan(ctx context.Context, cInfo liveupdates.Container, cmd model.Cmd) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "liveupdate.exec",
+		attribute.String("container", cInfo.ContainerName.String()),
+		attribute.String("cmd", strings.Join(cmd.Argv, " ")))
+}
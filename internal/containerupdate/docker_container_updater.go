@@ -13,6 +13,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -22,6 +23,7 @@ type DockerUpdater struct {
 }
 
 var _ ContainerUpdater = &DockerUpdater{}
+var _ ContainerDownloader = &DockerUpdater{}
 
 func NewDockerUpdater(dCli docker.Client) *DockerUpdater {
 	return &DockerUpdater{dCli: dCli}
@@ -60,7 +62,9 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.
 		if !cmd.EchoOff {
 			l.Infof("[CMD %d/%d] %s", i+1, len(cmds), strings.Join(cmd.Argv, " "))
 		}
-		err = cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, cmd, nil, l.Writer(logger.InfoLvl))
+		execCtx, span := startExecSpan(ctx, cInfo, cmd)
+		err = cu.dCli.ExecInContainer(execCtx, cInfo.ContainerID, cmd, nil, l.Writer(logger.InfoLvl))
+		tracer.EndWithError(span, err)
 		if err != nil {
 			return fmt.Errorf(
 				"executing on container %s: %w",
@@ -84,6 +88,22 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.
 	return nil
 }
 
+// DownloadArchive uses the docker copy API, rather than `tar` over exec,
+// because exec output goes through a TTY that would mangle the archive.
+func (cu *DockerUpdater) DownloadArchive(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string, w io.Writer) error {
+	r, err := cu.dCli.ArchiveFromContainer(ctx, cInfo.ContainerID.String(), containerPath)
+	if err != nil {
+		return fmt.Errorf("copying %s from container: %w", containerPath, err)
+	}
+	defer func() { _ = r.Close() }()
+	_, err = io.Copy(w, r)
+	if err != nil {
+		return fmt.Errorf("copying %s from container: %w", containerPath, err)
+	}
+	return nil
+}
+
 func (cu *DockerUpdater) rmPathsFromContainer(ctx context.Context, cID container.ID, paths []string) error {
 	if len(paths) == 0 {
 		return nil
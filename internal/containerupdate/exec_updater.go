@@ -9,6 +9,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -18,6 +19,7 @@ type ExecUpdater struct {
 }
 
 var _ ContainerUpdater = &ExecUpdater{}
+var _ ContainerDownloader = &ExecUpdater{}
 
 func NewExecUpdater(kCli k8s.Client) *ExecUpdater {
 	return &ExecUpdater{kCli: kCli}
@@ -62,8 +64,10 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 		if !c.EchoOff {
 			l.Infof("[CMD %d/%d] %s", i+1, len(cmds), strings.Join(c.Argv, " "))
 		}
-		err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+		execCtx, span := startExecSpan(ctx, cInfo, c)
+		err := cu.kCli.Exec(execCtx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
 			c.Argv, nil, w, w)
+		tracer.EndWithError(span, err)
 		if err != nil {
 			return fmt.Errorf(
 				"executing on container %s: %w",
@@ -77,6 +81,18 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 	return nil
 }
 
+func (cu *ExecUpdater) DownloadArchive(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string, w io.Writer) error {
+	stderr := bytes.NewBuffer(nil)
+	cmd := tarCreateCmd(containerPath)
+	err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+		cmd.Argv, nil, w, stderr)
+	if err != nil {
+		return wrapK8sTarErr(stderr, err, cmd, fmt.Sprintf("copying %s from container", containerPath))
+	}
+	return nil
+}
+
 // wrapK8sTarErr provides user-friendly diagnostics for common failures when
 // running `tar` as part of a Live Update.
 func wrapK8sTarErr(out *bytes.Buffer, err error, cmd model.Cmd, action string) error {
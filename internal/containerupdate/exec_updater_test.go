@@ -149,6 +149,21 @@ func TestUpdateContainerPermissionDenied(t *testing.T) {
 	assert.Equal(t, 1, len(f.kCli.ExecCalls))
 }
 
+func TestDownloadArchive(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader("archive contents")}
+
+	out := &bytes.Buffer{}
+	err := f.ecu.DownloadArchive(f.ctx, TestContainerInfo, "/app/gen/", out)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "archive contents", out.String())
+	}
+	if assert.Equal(t, 1, len(f.kCli.ExecCalls)) {
+		assert.Equal(t, []string{"tar", "-C", "/app", "-c", "-f", "-", "gen"}, f.kCli.ExecCalls[0].Cmd)
+	}
+}
+
 type execUpdaterFixture struct {
 	t    testing.TB
 	ctx  context.Context
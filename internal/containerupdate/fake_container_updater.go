@@ -1,8 +1,11 @@
 package containerupdate
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 
@@ -10,10 +13,22 @@ import (
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+var _ ContainerHasher = &FakeContainerUpdater{}
+
 type FakeContainerUpdater struct {
 	UpdateErrs []error
 
 	Calls []UpdateContainerCall
+
+	// Tar archives returned by DownloadArchive, keyed by container path.
+	Archives      map[string][]byte
+	DownloadCalls []string
+
+	// If false, HashFiles fails, like an updater that can't reach a sync agent.
+	// FileDownloads records the files requested from DownloadFiles.
+	SupportsHashes bool
+	HashCalls      []string
+	FileDownloads  [][]string
 }
 
 type UpdateContainerCall struct {
@@ -24,6 +39,89 @@ type UpdateContainerCall struct {
 	HotReload     bool
 }
 
+func (cu *FakeContainerUpdater) DownloadArchive(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string, w io.Writer) error {
+	cu.DownloadCalls = append(cu.DownloadCalls, containerPath)
+	archive, ok := cu.Archives[containerPath]
+	if !ok {
+		return fmt.Errorf("no such path in container: %s", containerPath)
+	}
+	_, err := w.Write(archive)
+	return err
+}
+
+// HashFiles hashes the regular files in the archive for the container path.
+func (cu *FakeContainerUpdater) HashFiles(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string) (map[string]string, error) {
+	if !cu.SupportsHashes {
+		return nil, fmt.Errorf("hashes not supported")
+	}
+	cu.HashCalls = append(cu.HashCalls, containerPath)
+	archive, ok := cu.Archives[containerPath]
+	if !ok {
+		return nil, fmt.Errorf("no such path in container: %s", containerPath)
+	}
+
+	hashes := map[string]string{}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return hashes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, tr)
+		if err != nil {
+			return nil, err
+		}
+		hashes[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+}
+
+// DownloadFiles writes a copy of the archive for the container path
+// with only the named files.
+func (cu *FakeContainerUpdater) DownloadFiles(ctx context.Context, cInfo liveupdates.Container,
+	containerPath string, names []string, w io.Writer) error {
+	cu.FileDownloads = append(cu.FileDownloads, names)
+	archive, ok := cu.Archives[containerPath]
+	if !ok {
+		return fmt.Errorf("no such path in container: %s", containerPath)
+	}
+
+	only := make(map[string]bool, len(names))
+	for _, n := range names {
+		only[n] = true
+	}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	tw := tar.NewWriter(w)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return tw.Close()
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg || !only[hdr.Name] {
+			continue
+		}
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tw, tr)
+		if err != nil {
+			return err
+		}
+	}
+}
+
 func (cu *FakeContainerUpdater) SetUpdateErr(err error) {
 	cu.UpdateErrs = []error{err}
 }
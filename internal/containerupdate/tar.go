@@ -2,6 +2,7 @@ package containerupdate
 
 import (
 	"fmt"
+	"path"
 
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -16,6 +17,14 @@ func tarCmd() model.Cmd {
 	}
 }
 
+// Archives the container path to stdout.
+func tarCreateCmd(containerPath string) model.Cmd {
+	containerPath = path.Clean(containerPath)
+	return model.Cmd{
+		Argv: []string{"tar", "-C", path.Dir(containerPath), "-c", "-f", "-", path.Base(containerPath)},
+	}
+}
+
 func permissionDeniedErr(err error) error {
 	return fmt.Errorf("%v\n"+
 		"This usually means the container filesystem denied access. Please check:\n"+
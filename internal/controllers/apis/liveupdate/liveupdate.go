@@ -40,6 +40,21 @@ func SyncSteps(spec v1alpha1.LiveUpdateSpec) []model.Sync {
 	return syncs
 }
 
+// Evaluates live-update sync_backs relative to the base path,
+// and returns a sync with resolved paths.
+func SyncBackSteps(spec v1alpha1.LiveUpdateSpec) []model.Sync {
+	var syncs []model.Sync
+	for _, syncBack := range spec.SyncBacks {
+		localPath := syncBack.LocalPath
+		if !filepath.IsAbs(localPath) {
+			localPath = filepath.Join(spec.BasePath, localPath)
+		}
+
+		syncs = append(syncs, model.Sync{LocalPath: localPath, ContainerPath: syncBack.ContainerPath})
+	}
+	return syncs
+}
+
 // Evaluates live-update exec relative to the base path,
 // and returns a run with resolved paths.
 func RunSteps(spec v1alpha1.LiveUpdateSpec) []model.Run {
@@ -55,6 +55,14 @@ type connection struct {
 	serverVersion string
 	registry      *v1alpha1.RegistryHosting
 	connStatus    *v1alpha1.ClusterConnectionStatus
+
+	// Whether we've created the namespace requested by the spec.
+	namespaceCreated bool
+
+	// namespaceError is populated when we have a client but couldn't create
+	// the namespace requested by the spec. Unlike initError, we keep the
+	// client and retry the namespace on the next reconcile.
+	namespaceError string
 }
 
 func (k *ConnectionManager) GetK8sClient(clusterKey types.NamespacedName) (k8s.Client, metav1.MicroTime, error) {
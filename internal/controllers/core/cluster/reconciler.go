@@ -39,6 +39,7 @@ const ArchUnknown string = "unknown"
 const (
 	clientInitBackoff        = 30 * time.Second
 	clientHealthPollInterval = 15 * time.Second
+	createNamespaceTimeout   = 30 * time.Second
 	// Like Kubernetes probes, require successive health check failures before turning
 	// a point-in-time control-plane error into a terminal CI result.
 	clientHealthFailureThreshold = 4
@@ -183,6 +184,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 	}
 
 	r.populateClusterMetadata(ctx, nn, &conn)
+	if conn.namespaceError != "" {
+		// requeue the cluster Obj so that we can attempt to create the namespace again
+		requeueAfter = clientInitBackoff
+	}
 
 	r.connManager.store(nn, conn)
 
@@ -329,6 +334,19 @@ func (r *Reconciler) populateClusterMetadata(ctx context.Context, clusterNN type
 }
 
 func (r *Reconciler) populateK8sMetadata(ctx context.Context, clusterNN types.NamespacedName, conn *connection) {
+	k8sConn := conn.spec.Connection.Kubernetes
+	if k8sConn.CreateNamespace && k8sConn.Namespace != "" && !conn.namespaceCreated {
+		ns := k8s.NewNamespaceEntity(k8sConn.Namespace)
+		_, err := conn.k8sClient.Upsert(ctx, []k8s.K8sEntity{ns}, createNamespaceTimeout, k8s.SSAOptions{})
+		if err != nil {
+			conn.namespaceError = fmt.Sprintf("creating namespace %q: %v", k8sConn.Namespace, err)
+			return
+		}
+		logger.Get(ctx).Infof("Using namespace %q", k8sConn.Namespace)
+		conn.namespaceCreated = true
+		conn.namespaceError = ""
+	}
+
 	if conn.arch == "" {
 		conn.arch = r.readKubernetesArch(ctx, conn.k8sClient)
 	}
@@ -459,12 +477,15 @@ func (r *Reconciler) cleanup(clusterNN types.NamespacedName) {
 
 func (c *connection) toStatus(statusErr string) v1alpha1.ClusterStatus {
 	var connectedAt *metav1.MicroTime
-	if c.initError == "" && !c.createdAt.IsZero() {
+	if c.initError == "" && c.namespaceError == "" && !c.createdAt.IsZero() {
 		t := apis.NewMicroTime(c.createdAt)
 		connectedAt = &t
 	}
 
 	clusterError := c.initError
+	if clusterError == "" {
+		clusterError = c.namespaceError
+	}
 	if clusterError == "" {
 		clusterError = statusErr
 	}
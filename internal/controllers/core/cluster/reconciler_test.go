@@ -87,6 +87,63 @@ func TestKubernetesError(t *testing.T) {
 	}
 }
 
+func TestKubernetesCreateNamespace(t *testing.T) {
+	f := newFixture(t)
+
+	cluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{
+					Namespace:       "tilt-alice",
+					CreateNamespace: true,
+				},
+			},
+		},
+	}
+	nn := apis.Key(cluster)
+
+	f.Create(cluster)
+	f.MustGet(nn, cluster)
+	assert.Equal(t, "", cluster.Status.Error)
+	assert.Contains(t, f.k8sClient.Yaml, "kind: Namespace")
+	assert.Contains(t, f.k8sClient.Yaml, "name: tilt-alice")
+}
+
+func TestKubernetesCreateNamespaceError(t *testing.T) {
+	f := newFixture(t)
+	f.k8sClient.UpsertError = errors.New("namespaces is forbidden")
+
+	cluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{
+					Namespace:       "tilt-alice",
+					CreateNamespace: true,
+				},
+			},
+		},
+	}
+	nn := apis.Key(cluster)
+
+	result := f.Create(cluster)
+	assert.Equal(t, clientInitBackoff, result.RequeueAfter)
+	f.MustGet(nn, cluster)
+	assert.Equal(t, `creating namespace "tilt-alice": namespaces is forbidden`, cluster.Status.Error)
+	assert.Nil(t, cluster.Status.ConnectedAt, "ConnectedAt should be empty on error")
+
+	// Once the namespace can be created, the next reconcile recovers
+	// without needing a new client.
+	f.k8sClient.UpsertError = nil
+	result = f.MustReconcile(nn)
+	assert.Zero(t, result.RequeueAfter)
+	f.MustGet(nn, cluster)
+	assert.Equal(t, "", cluster.Status.Error)
+	assert.NotNil(t, cluster.Status.ConnectedAt)
+	assert.Contains(t, f.k8sClient.Yaml, "name: tilt-alice")
+}
+
 func TestDockerDesktopContainerdWithoutSnapshotter(t *testing.T) {
 	f := newFixture(t)
 
@@ -43,6 +43,9 @@ func UpdateImageMap(
 	}
 
 	result.ImageMapStatus.BuildStartTime = startTime
+	if iTarget.ImageMapSpec.SyncAgent != nil {
+		result.ImageMapStatus.Entrypoint, result.ImageMapStatus.Cmd = imageCommand(ctx, docker, taggedRefs.LocalRef)
+	}
 	nn := types.NamespacedName{Name: iTarget.ImageMapName()}
 	im, ok := imageMaps[nn]
 	if !ok {
@@ -52,6 +55,22 @@ func UpdateImageMap(
 	return result, nil
 }
 
+// imageCommand reads the image's ENTRYPOINT and CMD, so that the sync agent
+// can wrap them when the container doesn't set a command.
+//
+// Images that were never loaded into the local Docker (e.g., custom builds
+// that skip local docker) have no known command.
+func imageCommand(ctx context.Context, dCli docker.Client, ref reference.NamedTagged) ([]string, []string) {
+	if ref == nil {
+		return nil, nil
+	}
+	inspect, err := dCli.ImageInspect(ctx, ref.String())
+	if err != nil || inspect.Config == nil {
+		return nil, nil
+	}
+	return inspect.Config.Entrypoint, inspect.Config.Cmd
+}
+
 // tagWithExpected tags the given ref as whatever Docker Compose expects, i.e. as
 // the `image` value given in docker-compose.yaml. (If DC yaml specifies an image
 // with a tag, use that name + tag; otherwise, tag as latest.)
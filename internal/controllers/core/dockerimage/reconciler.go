@@ -2,6 +2,7 @@ package dockerimage
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -130,6 +131,44 @@ func (r *Reconciler) ForceApply(
 	return buildResult, nil
 }
 
+// Reuse an image that was built by a previous run of Tilt
+// as the result of this image target, without building it.
+func (r *Reconciler) ForceReuse(
+	iTarget model.ImageTarget,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	buildResult store.ImageBuildResult) error {
+	imNN := types.NamespacedName{Name: iTarget.ImageMapName()}
+	im, ok := imageMaps[imNN]
+	if !ok {
+		return fmt.Errorf("apiserver missing ImageMap: %s", iTarget.ID().Name)
+	}
+	im.Status = buildResult.ImageMapStatus
+
+	now := apis.NowMicro()
+	nn := types.NamespacedName{Name: iTarget.DockerImageName}
+	r.setImageStatus(nn, v1alpha1.DockerImageStatus{
+		Ref: buildResult.ImageMapStatus.ImageFromLocal,
+		Completed: &v1alpha1.DockerImageStateCompleted{
+			StartedAt:  now,
+			FinishedAt: now,
+		},
+	})
+	r.setImageMapStatus(nn, iTarget, buildResult.ImageMapStatus)
+	r.requeuer.Add(nn)
+	return nil
+}
+
+// Record the hash of the inputs that the last image was built from
+// on the ImageMap, so that a later run of Tilt can reuse the image.
+func (r *Reconciler) SetBuildInputHash(iTarget model.ImageTarget, hash string) {
+	nn := types.NamespacedName{Name: iTarget.DockerImageName}
+	r.mu.Lock()
+	result := r.ensureResult(nn)
+	result.imageMap.BuildInputHash = hash
+	r.mu.Unlock()
+	r.requeuer.Add(nn)
+}
+
 func (r *Reconciler) ensureResult(nn types.NamespacedName) *result {
 	res, ok := r.results[nn]
 	if !ok {
@@ -57,7 +57,9 @@ func newFixture(t testing.TB) *fixture {
 	ib := build.NewImageBuilder(
 		build.NewDockerBuilder(dockerCli, nil),
 		build.NewCustomBuilder(dockerCli, clock, cmds),
-		build.NewKINDLoader())
+		build.NewImageLoader(),
+		build.NewImageSigner(),
+		build.NewFakeImageRegistry())
 
 	r := NewReconciler(cfb.Client, cfb.Store, cfb.Scheme(), dockerCli, ib)
 	return &fixture{
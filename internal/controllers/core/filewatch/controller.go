@@ -50,6 +50,7 @@ type Controller struct {
 
 	targetWatches  map[types.NamespacedName]*watcher
 	fsWatcherMaker fsevent.WatcherMaker
+	pollMaker      fsevent.PollWatcherMaker
 	timerMaker     fsevent.TimerMaker
 	mu             sync.Mutex
 	clock          clockwork.Clock
@@ -57,12 +58,13 @@ type Controller struct {
 	requeuer       *indexer.Requeuer
 }
 
-func NewController(client ctrlclient.Client, store store.RStore, fsWatcherMaker fsevent.WatcherMaker, timerMaker fsevent.TimerMaker, scheme *runtime.Scheme, clock clockwork.Clock) *Controller {
+func NewController(client ctrlclient.Client, store store.RStore, fsWatcherMaker fsevent.WatcherMaker, pollMaker fsevent.PollWatcherMaker, timerMaker fsevent.TimerMaker, scheme *runtime.Scheme, clock clockwork.Clock) *Controller {
 	return &Controller{
 		Client:         client,
 		Store:          store,
 		targetWatches:  make(map[types.NamespacedName]*watcher),
 		fsWatcherMaker: fsWatcherMaker,
+		pollMaker:      pollMaker,
 		timerMaker:     timerMaker,
 		indexer:        indexer.NewIndexer(scheme, indexFw),
 		requeuer:       indexer.NewRequeuer(),
@@ -199,7 +201,11 @@ func (c *Controller) addOrReplace(ctx context.Context, name types.NamespacedName
 
 	ignoreMatcher := ignore.CreateFileChangeFilter(fw.Spec.Ignores)
 	startFileChangeLoop := false
-	notify, err := c.fsWatcherMaker(
+	watcherMaker := c.fsWatcherMaker
+	if fw.Spec.Poll != nil {
+		watcherMaker = c.pollMaker(fw.Spec.Poll.Interval.Duration)
+	}
+	notify, err := watcherMaker(
 		append([]string{}, fw.Spec.WatchedPaths...),
 		ignoreMatcher,
 		logger.Get(ctx))
@@ -226,6 +232,7 @@ func (c *Controller) addOrReplace(ctx context.Context, name types.NamespacedName
 	if startFileChangeLoop {
 		w.notify = notify
 		status.MonitorStartTime = apis.NowMicro()
+		recordWatchStats(status, notify)
 		go c.dispatchFileChangesLoop(ctx, w)
 	}
 
@@ -72,7 +72,7 @@ func newFixture(t *testing.T) *fixture {
 	cfb := fake.NewControllerFixtureBuilder(t)
 	testingStore := NewTestingStore(cfb.OutWriter())
 	clock := clockwork.NewFakeClock()
-	controller := NewController(cfb.Client, testingStore, fakeMultiWatcher.NewSub, timerMaker.Maker(), filewatches.NewScheme(), clock)
+	controller := NewController(cfb.Client, testingStore, fakeMultiWatcher.NewSub, fakeMultiWatcher.NewPollSub, timerMaker.Maker(), filewatches.NewScheme(), clock)
 
 	return &fixture{
 		ControllerFixture: cfb.WithRequeuer(controller.requeuer).Build(controller),
@@ -268,6 +268,34 @@ func TestController_Reconcile_Create(t *testing.T) {
 	assert.NotZero(t, fw.Status.MonitorStartTime, "Filesystem monitor was not started")
 }
 
+func TestController_Reconcile_WatchStats(t *testing.T) {
+	f := newFixture(t)
+	f.fakeMultiWatcher.Stats = watch.WatchStats{Watches: 5, PolledDirs: 2}
+	key, fw := f.CreateSimpleFileWatch()
+
+	f.MustGet(key, fw)
+	assert.Equal(t, int32(5), fw.Status.WatchCount)
+	assert.Equal(t, int32(2), fw.Status.PolledDirCount)
+
+	f.ChangeAndWaitForSeenFile(key, "a", "1")
+	f.MustGet(key, fw)
+	assert.Equal(t, int32(5), fw.Status.WatchCount)
+}
+
+func TestController_Reconcile_Poll(t *testing.T) {
+	f := newFixture(t)
+
+	key, fw := f.CreateSimpleFileWatch()
+	assert.Empty(t, f.fakeMultiWatcher.PollIntervals())
+
+	f.MustGet(key, fw)
+	fw.Spec.Poll = &filewatches.FileWatchPoll{Interval: metav1.Duration{Duration: 5 * time.Second}}
+	f.Update(fw)
+	assert.Equal(t, []time.Duration{5 * time.Second}, f.fakeMultiWatcher.PollIntervals())
+
+	f.ChangeAndWaitForSeenFile(key, "a", "1")
+}
+
 // TestController_Reconcile_Delete peeks into internal/unexported portions of the controller to inspect the actual
 // filesystem monitor so it can ensure reconciler is not leaking resources; other tests should prefer observing
 // desired state!
@@ -9,12 +9,23 @@ import (
 
 type WatcherMaker func(paths []string, ignore watch.PathMatcher, l logger.Logger) (watch.Notify, error)
 
+// Creates a WatcherMaker that polls for changes every interval.
+type PollWatcherMaker func(interval time.Duration) WatcherMaker
+
 type TimerMaker func(d time.Duration) <-chan time.Time
 
 func ProvideWatcherMaker() WatcherMaker {
 	return watch.NewWatcher
 }
 
+func ProvidePollWatcherMaker() PollWatcherMaker {
+	return func(interval time.Duration) WatcherMaker {
+		return func(paths []string, ignore watch.PathMatcher, l logger.Logger) (watch.Notify, error) {
+			return watch.NewPollWatcher(paths, ignore, interval, l)
+		}
+	}
+}
+
 func ProvideTimerMaker() TimerMaker {
 	return time.After
 }
@@ -4,6 +4,7 @@ import (
 	"path/filepath"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/watch"
@@ -14,10 +15,14 @@ type FakeMultiWatcher struct {
 	Events chan watch.FileEvent
 	Errors chan error
 
-	mu         sync.Mutex
-	watchers   []*FakeWatcher
-	subs       []chan watch.FileEvent
-	subsErrors []chan error
+	// Stats are reported by every watcher created after they're set.
+	Stats watch.WatchStats
+
+	mu            sync.Mutex
+	watchers      []*FakeWatcher
+	subs          []chan watch.FileEvent
+	subsErrors    []chan error
+	pollIntervals []time.Duration
 }
 
 func NewFakeMultiWatcher() *FakeMultiWatcher {
@@ -36,12 +41,29 @@ func (w *FakeMultiWatcher) NewSub(paths []string, ignore watch.PathMatcher, _ lo
 	defer w.mu.Unlock()
 
 	watcher := NewFakeWatcher(subCh, errorCh, paths, ignore)
+	watcher.FakeStats = w.Stats
 	w.watchers = append(w.watchers, watcher)
 	w.subs = append(w.subs, subCh)
 	w.subsErrors = append(w.subsErrors, errorCh)
 	return watcher, nil
 }
 
+// NewPollSub is a PollWatcherMaker. The polling watchers it makes
+// get the same fake events as every other watcher.
+func (w *FakeMultiWatcher) NewPollSub(interval time.Duration) WatcherMaker {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pollIntervals = append(w.pollIntervals, interval)
+	return w.NewSub
+}
+
+// PollIntervals returns the interval of each polling watcher maker created, in order.
+func (w *FakeMultiWatcher) PollIntervals() []time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]time.Duration{}, w.pollIntervals...)
+}
+
 func (w *FakeMultiWatcher) getSubs() []chan watch.FileEvent {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -102,6 +124,8 @@ type FakeWatcher struct {
 
 	Running  bool
 	StartErr error
+
+	FakeStats watch.WatchStats
 }
 
 func NewFakeWatcher(inboundCh chan watch.FileEvent, errorCh chan error, paths []string, ignore watch.PathMatcher) *FakeWatcher {
@@ -156,6 +180,10 @@ func (w *FakeWatcher) Events() chan watch.FileEvent {
 	return w.outboundCh
 }
 
+func (w *FakeWatcher) Stats() watch.WatchStats {
+	return w.FakeStats
+}
+
 func (w *FakeWatcher) TotalEventCount() uint64 {
 	return atomic.LoadUint64(&w.eventCount)
 }
@@ -191,4 +219,4 @@ func (w *FakeWatcher) loop() {
 	}
 }
 
-var _ watch.Notify = &FakeWatcher{}
+var _ watch.StatsNotify = &FakeWatcher{}
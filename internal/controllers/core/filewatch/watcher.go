@@ -10,6 +10,7 @@ import (
 
 	"github.com/jonboulle/clockwork"
 
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/watch"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -103,7 +104,9 @@ func (w *watcher) recordEvent(fsEvents []watch.FileEvent) {
 	for _, fsEvent := range fsEvents {
 		event.SeenFiles = append(event.SeenFiles, fsEvent.Path())
 	}
+	recordWatchStats(w.status, w.notify)
 	if len(event.SeenFiles) != 0 {
+		metrics.FileWatchEventsTotal.WithLabelValues(w.name.Name).Add(float64(len(event.SeenFiles)))
 		w.status.LastEventTime = *now.DeepCopy()
 		w.status.FileEvents = append(w.status.FileEvents, event)
 		if len(w.status.FileEvents) > MaxFileEventHistory {
@@ -112,3 +115,15 @@ func (w *watcher) recordEvent(fsEvents []watch.FileEvent) {
 		w.status.Error = ""
 	}
 }
+
+// recordWatchStats copies the OS resources used by the notifier
+// (if it reports them) into the status.
+func recordWatchStats(status *v1alpha1.FileWatchStatus, notify watch.Notify) {
+	statsNotify, ok := notify.(watch.StatsNotify)
+	if !ok {
+		return
+	}
+	stats := statsNotify.Stats()
+	status.WatchCount = int32(stats.Watches)
+	status.PolledDirCount = int32(stats.PolledDirs)
+}
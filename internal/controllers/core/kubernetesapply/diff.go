@@ -0,0 +1,211 @@
+package kubernetesapply
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Fields that the apiserver manages on every write. They change on every apply,
+// so including them would make every object look modified.
+var serverManagedMetadataFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"generation",
+	"uid",
+	"creationTimestamp",
+	"selfLink",
+}
+
+// Runs a server-side dry-run of the entities, with the same apply options
+// as the real apply, and compares the result against the live objects.
+//
+// Never returns an error. Any dry-run failure is reported on the diff itself,
+// so that it doesn't block the real apply.
+func (r *Reconciler) dryRunDiff(ctx context.Context, entities []k8s.K8sEntity, timeout time.Duration, ssa k8s.SSAOptions) *v1alpha1.KubernetesApplyDiff {
+	diff := &v1alpha1.KubernetesApplyDiff{
+		DryRunTime: apis.NowMicro(),
+	}
+
+	results, err := r.k8sClient.DryRunApply(ctx, entities, timeout, ssa)
+	if err != nil {
+		diff.Error = err.Error()
+		logger.Get(ctx).Infof("Dry-run failed: %v", err)
+		return diff
+	}
+
+	counts := map[v1alpha1.KubernetesApplyDiffOperation]int{}
+	for _, result := range results {
+		objDiff, err := diffDryRunResult(result)
+		if err != nil {
+			diff.Error = err.Error()
+			logger.Get(ctx).Infof("Dry-run failed: %v", err)
+			return diff
+		}
+		counts[objDiff.Operation]++
+		diff.Objects = append(diff.Objects, objDiff)
+	}
+
+	logger.Get(ctx).Infof("Dry-run: %d to create, %d to update, %d unchanged",
+		counts[v1alpha1.KubernetesApplyDiffOperationCreate],
+		counts[v1alpha1.KubernetesApplyDiffOperationUpdate],
+		counts[v1alpha1.KubernetesApplyDiffOperationUnchanged])
+	return diff
+}
+
+func diffDryRunResult(result k8s.DryRunResult) (v1alpha1.KubernetesApplyObjectDiff, error) {
+	apiVersion, kind := result.Entity.GVK().ToAPIVersionAndKind()
+	objDiff := v1alpha1.KubernetesApplyObjectDiff{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  result.DryRun.GetNamespace(),
+		Name:       result.DryRun.GetName(),
+	}
+
+	after := normalizeForDiff(result.DryRun)
+	var before map[string]interface{}
+	if result.Live != nil {
+		before = normalizeForDiff(result.Live)
+	}
+
+	beforeYAML, err := diffYAML(before)
+	if err != nil {
+		return objDiff, err
+	}
+	afterYAML, err := diffYAML(after)
+	if err != nil {
+		return objDiff, err
+	}
+
+	text, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(beforeYAML),
+		B:        difflib.SplitLines(afterYAML),
+		FromFile: "live",
+		ToFile:   "dry-run",
+		Context:  3,
+	})
+	if err != nil {
+		return objDiff, err
+	}
+	objDiff.Diff = text
+
+	switch {
+	case before == nil:
+		objDiff.Operation = v1alpha1.KubernetesApplyDiffOperationCreate
+	case text == "":
+		objDiff.Operation = v1alpha1.KubernetesApplyDiffOperationUnchanged
+	default:
+		objDiff.Operation = v1alpha1.KubernetesApplyDiffOperationUpdate
+		objDiff.ChangedFields = changedFields("", before, after)
+	}
+	return objDiff, nil
+}
+
+// Strips status and server-managed metadata from a copy of the object,
+// and redacts Secret values.
+func normalizeForDiff(obj *unstructured.Unstructured) map[string]interface{} {
+	content := obj.DeepCopy().Object
+	delete(content, "status")
+
+	if obj.GetAPIVersion() == "v1" && obj.GetKind() == "Secret" {
+		redactSecretValues(content, "data")
+		redactSecretValues(content, "stringData")
+	}
+
+	metadata, ok := content["metadata"].(map[string]interface{})
+	if ok {
+		for _, f := range serverManagedMetadataFields {
+			delete(metadata, f)
+		}
+
+		annotations, ok := metadata["annotations"].(map[string]interface{})
+		if ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return content
+}
+
+// Diffs are stored on the API object and shown in the UI, so replace each
+// Secret value with a hash. The diff still shows which keys changed.
+func redactSecretValues(content map[string]interface{}, field string) {
+	values, ok := content[field].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for k, v := range values {
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%v", v)))
+		values[k] = fmt.Sprintf("<redacted sha256:%s>", hex.EncodeToString(hash[:])[:12])
+	}
+}
+
+func diffYAML(content map[string]interface{}) (string, error) {
+	if content == nil {
+		return "", nil
+	}
+	b, err := yaml.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Returns the sorted paths of all the leaf fields that differ between a and b.
+//
+// Lists that change length are reported as a single changed field,
+// because index-by-index comparison of a shifted list is mostly noise.
+func changedFields(path string, a, b interface{}) []string {
+	aMap, aIsMap := a.(map[string]interface{})
+	bMap, bIsMap := b.(map[string]interface{})
+	if aIsMap && bIsMap {
+		keys := make(map[string]bool, len(aMap)+len(bMap))
+		for k := range aMap {
+			keys[k] = true
+		}
+		for k := range bMap {
+			keys[k] = true
+		}
+
+		var result []string
+		for k := range keys {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			result = append(result, changedFields(childPath, aMap[k], bMap[k])...)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	aList, aIsList := a.([]interface{})
+	bList, bIsList := b.([]interface{})
+	if aIsList && bIsList && len(aList) == len(bList) {
+		var result []string
+		for i := range aList {
+			result = append(result, changedFields(fmt.Sprintf("%s[%d]", path, i), aList[i], bList[i])...)
+		}
+		return result
+	}
+
+	if reflect.DeepEqual(a, b) {
+		return nil
+	}
+	return []string{path}
+}
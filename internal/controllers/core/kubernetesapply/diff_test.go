@@ -0,0 +1,84 @@
+package kubernetesapply
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestDiffIgnoresServerManagedFields(t *testing.T) {
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+
+	live := sanchoUnstructured(t, entities[0])
+	live.SetResourceVersion("1")
+	live.SetGeneration(1)
+	live.SetUID("uid-1")
+	live.SetAnnotations(map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"})
+	require.NoError(t, unstructured.SetNestedField(live.Object, int64(1), "status", "readyReplicas"))
+
+	dryRun := sanchoUnstructured(t, entities[0])
+	dryRun.SetResourceVersion("2")
+	dryRun.SetGeneration(2)
+	dryRun.SetUID("uid-1")
+
+	objDiff, err := diffDryRunResult(k8s.DryRunResult{Entity: entities[0], Live: live, DryRun: dryRun})
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.KubernetesApplyDiffOperationUnchanged, objDiff.Operation)
+	assert.Empty(t, objDiff.Diff)
+	assert.Empty(t, objDiff.ChangedFields)
+}
+
+func TestDiffRedactsSecrets(t *testing.T) {
+	secret := k8s.NewSyncAgentSecret("tilt-sync-agent-deployment-sancho", "default", "old-token")
+	live := sanchoUnstructured(t, secret)
+	dryRun := sanchoUnstructured(t, k8s.NewSyncAgentSecret("tilt-sync-agent-deployment-sancho", "default", "new-token"))
+
+	objDiff, err := diffDryRunResult(k8s.DryRunResult{Entity: secret, Live: live, DryRun: dryRun})
+	require.NoError(t, err)
+	assert.Equal(t, v1alpha1.KubernetesApplyDiffOperationUpdate, objDiff.Operation)
+	assert.Equal(t, []string{"data.token"}, objDiff.ChangedFields)
+	assert.Contains(t, objDiff.Diff, "<redacted sha256:")
+	assert.NotContains(t, objDiff.Diff, "b2xkLXRva2Vu")
+	assert.NotContains(t, objDiff.Diff, "bmV3LXRva2Vu")
+}
+
+func TestChangedFields(t *testing.T) {
+	a := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"ports":    []interface{}{int64(80)},
+			"args":     []interface{}{"a", "b"},
+		},
+	}
+	b := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"ports":    []interface{}{int64(80), int64(443)},
+			"args":     []interface{}{"a", "c"},
+			"paused":   true,
+		},
+	}
+	assert.Equal(t, []string{
+		"spec.args[1]",
+		"spec.paused",
+		"spec.ports",
+		"spec.replicas",
+	}, changedFields("", a, b))
+}
+
+func sanchoUnstructured(t *testing.T, e k8s.K8sEntity) *unstructured.Unstructured {
+	t.Helper()
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(e.DeepCopy().Obj)
+	require.NoError(t, err)
+	obj := &unstructured.Unstructured{Object: content}
+	obj.SetGroupVersionKind(e.GVK())
+	return obj
+}
@@ -105,8 +105,10 @@ func (r *Reconciler) toDesiredKubernetesDiscovery(ka *v1alpha1.KubernetesApply)
 
 	kapp := ka.Spec
 	var extraSelectors []metav1.LabelSelector
+	var readinessChecks []v1alpha1.KubernetesReadinessCheck
 	if kapp.KubernetesDiscoveryTemplateSpec != nil {
 		extraSelectors = kapp.KubernetesDiscoveryTemplateSpec.ExtraSelectors
+		readinessChecks = kapp.KubernetesDiscoveryTemplateSpec.ReadinessChecks
 	}
 
 	kd := &v1alpha1.KubernetesDiscovery{
@@ -121,6 +123,7 @@ func (r *Reconciler) toDesiredKubernetesDiscovery(ka *v1alpha1.KubernetesApply)
 			Cluster:                  ka.Spec.Cluster,
 			Watches:                  watchRefs,
 			ExtraSelectors:           extraSelectors,
+			ReadinessChecks:          readinessChecks,
 			PodLogStreamTemplateSpec: kapp.PodLogStreamTemplateSpec.DeepCopy(),
 			PortForwardTemplateSpec:  kapp.PortForwardTemplateSpec.DeepCopy(),
 		},
@@ -157,9 +160,11 @@ func (r *Reconciler) toWatchRefs(ka *v1alpha1.KubernetesApply) ([]v1alpha1.Kuber
 			}
 			seenNamespaces[ns] = true
 			result = append(result, v1alpha1.KubernetesWatchRef{
-				UID:       string(ref.UID),
-				Namespace: ns.String(),
-				Name:      ref.Name,
+				UID:        string(ref.UID),
+				Namespace:  ns.String(),
+				Name:       ref.Name,
+				APIVersion: ref.APIVersion,
+				Kind:       ref.Kind,
 			})
 		}
 	}
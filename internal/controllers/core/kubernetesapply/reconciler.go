@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -31,7 +32,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/kubernetesapplys"
+	"github.com/tilt-dev/tilt/internal/syncagent"
 	"github.com/tilt-dev/tilt/internal/timecmp"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -52,6 +55,9 @@ type Reconciler struct {
 	execer     localexec.Execer
 	requeuer   *indexer.Requeuer
 
+	// Injected into containers whose live updates go through a sync agent.
+	agentToken syncagent.Token
+
 	mu sync.Mutex
 
 	// Protected by the mutex.
@@ -77,12 +83,13 @@ func (r *Reconciler) CreateBuilder(mgr ctrl.Manager) (*builder.Builder, error) {
 	return b, nil
 }
 
-func NewReconciler(ctrlClient ctrlclient.Client, k8sClient k8s.Client, scheme *runtime.Scheme, st store.RStore, execer localexec.Execer) *Reconciler {
+func NewReconciler(ctrlClient ctrlclient.Client, k8sClient k8s.Client, scheme *runtime.Scheme, st store.RStore, execer localexec.Execer, agentToken syncagent.Token) *Reconciler {
 	return &Reconciler{
 		ctrlClient: ctrlClient,
 		k8sClient:  k8sClient,
 		indexer:    indexer.NewIndexer(scheme, indexKubernetesApply),
 		execer:     execer,
+		agentToken: agentToken,
 		st:         st,
 		results:    make(map[types.NamespacedName]*Result),
 		requeuer:   indexer.NewRequeuer(),
@@ -264,7 +271,14 @@ func (r *Reconciler) ForceApply(
 	spec v1alpha1.KubernetesApplySpec,
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) v1alpha1.KubernetesApplyStatus {
+	ctx, span := tracer.Start(ctx, "kubernetes.apply", attribute.String("kubernetesapply", nn.Name))
 	status := r.forceApplyHelper(ctx, nn, spec, cluster, imageMaps)
+	var err error
+	if status.Error != "" {
+		err = errors.New(status.Error)
+	}
+	tracer.EndWithError(span, err)
+
 	r.requeuer.Add(nn)
 	return status
 }
@@ -298,7 +312,7 @@ func (r *Reconciler) forceApplyHelper(
 	var deployed []k8s.K8sEntity
 	deployCtx := r.indentLogger(ctx)
 	if spec.YAML != "" {
-		deployed, err = r.runYAMLDeploy(deployCtx, spec, imageMaps)
+		deployed, status.Diff, err = r.runYAMLDeploy(deployCtx, spec, cluster, imageMaps)
 		if err != nil {
 			return recordErrorStatus(err)
 		}
@@ -337,32 +351,58 @@ func (r *Reconciler) printAppliedReport(ctx context.Context, msg string, deploye
 	}
 }
 
-func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec, imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) ([]k8s.K8sEntity, error) {
+func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec, cluster *v1alpha1.Cluster, imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) ([]k8s.K8sEntity, *v1alpha1.KubernetesApplyDiff, error) {
 	// Create API objects.
 	newK8sEntities, err := r.createEntitiesToDeploy(ctx, imageMaps, spec)
 	if err != nil {
-		return newK8sEntities, err
+		return newK8sEntities, nil, err
 	}
 
-	logger.Get(ctx).Infof("Applying YAML to cluster")
+	// Deploy unnamespaced objects to the namespace the cluster is connected to
+	// (e.g., the dev namespace from k8s_dev_namespace()).
+	if ns := clusterNamespace(cluster); ns != "" {
+		newK8sEntities, err = k8s.InjectNamespace(ctx, r.k8sClient, newK8sEntities, ns)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "injecting namespace")
+		}
+	}
 
 	timeout := spec.Timeout.Duration
 	if timeout == 0 {
 		timeout = v1alpha1.KubernetesApplyTimeoutDefault
 	}
 
-	deployed, err := r.k8sClient.Upsert(ctx, newK8sEntities, timeout, k8s.SSAOptions{
+	ssa := k8s.SSAOptions{
 		Enabled:      spec.ServerSideApply,
 		Force:        spec.ServerSideApply,
-		FieldManager: "tilt",
-	})
+		FieldManager: k8s.FieldManager,
+	}
+
+	var diff *v1alpha1.KubernetesApplyDiff
+	if spec.DryRunDiff {
+		logger.Get(ctx).Infof("Running server-side dry-run")
+		diff = r.dryRunDiff(ctx, newK8sEntities, timeout, ssa)
+	}
+
+	logger.Get(ctx).Infof("Applying YAML to cluster")
+
+	deployed, err := r.k8sClient.Upsert(ctx, newK8sEntities, timeout, ssa)
 	if err != nil {
 		r.printAppliedReport(ctx, "Tried to apply objects to cluster:", newK8sEntities)
-		return nil, err
+		return nil, diff, err
 	}
 	r.printAppliedReport(ctx, "Objects applied to cluster:", deployed)
 
-	return deployed, nil
+	return deployed, diff, nil
+}
+
+func clusterNamespace(cluster *v1alpha1.Cluster) k8s.Namespace {
+	if cluster == nil ||
+		cluster.Spec.Connection == nil ||
+		cluster.Spec.Connection.Kubernetes == nil {
+		return ""
+	}
+	return k8s.Namespace(cluster.Spec.Connection.Kubernetes.Namespace)
 }
 
 func (r *Reconciler) maybeInjectKubeconfig(cmd *model.Cmd, cluster *v1alpha1.Cluster) error {
@@ -474,6 +514,7 @@ func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
 	var injectResults []injectResult
 	imageMapNames := spec.ImageMaps
 	injectedImageMaps := map[string]bool{}
+	agentSecrets := map[string]bool{}
 	for _, e := range entities {
 		e, err = k8s.InjectLabels(e, []model.LabelPair{
 			k8s.TiltManagedByLabel(),
@@ -541,6 +582,22 @@ func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
 						return nil, err
 					}
 				}
+
+				if imageMapSpec.SyncAgent != nil {
+					var injectedAgent bool
+					secretName := k8s.SyncAgentSecretName(e)
+					e, injectedAgent, err = k8s.InjectSyncAgent(e, ref, *imageMapSpec.SyncAgent, imageMap.Status, secretName)
+					if err != nil {
+						return nil, err
+					}
+					if injectedAgent && !agentSecrets[secretName] {
+						// The token is mounted from a Secret rather than set in the pod spec,
+						// so that a new session's token doesn't roll out new pods.
+						agentSecrets[secretName] = true
+						newK8sEntities = append(newK8sEntities,
+							k8s.NewSyncAgentSecret(secretName, e.Namespace(), r.agentToken))
+					}
+				}
 			}
 		}
 
@@ -592,6 +649,7 @@ type applyResult struct {
 	LastApplyStartTime metav1.MicroTime
 	AppliedInputHash   string
 	Objects            []k8s.K8sEntity
+	Diff               *v1alpha1.KubernetesApplyDiff
 }
 
 // conditionsFromApply extracts any conditions based on the result.
@@ -658,6 +716,7 @@ func (r *Reconciler) recordApplyResult(
 	updatedStatus.LastApplyTime = applyResult.LastApplyTime
 	updatedStatus.AppliedInputHash = applyResult.AppliedInputHash
 	updatedStatus.Conditions = conditionsFromApply(applyResult)
+	updatedStatus.Diff = applyResult.Diff
 
 	result.Cluster = cluster
 	result.Spec = spec
@@ -972,6 +1031,7 @@ func (r *Result) clearApplyStatus() {
 	update.LastApplyStartTime = metav1.MicroTime{}
 	update.Error = ""
 	update.ResultYAML = ""
+	update.Diff = nil
 	r.Status = *update
 }
 
@@ -2,6 +2,7 @@ package kubernetesapply
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -23,6 +25,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/syncagent"
 	"github.com/tilt-dev/tilt/internal/testutils/configmap"
 	"github.com/tilt-dev/tilt/internal/timecmp"
 	"github.com/tilt-dev/tilt/pkg/apis"
@@ -112,6 +115,46 @@ func TestBasicApplyYAML(t *testing.T) {
 	assert.Equal(f.T(), f.kClient.Yaml, "")
 }
 
+func TestApplyYAMLInjectsSyncAgent(t *testing.T) {
+	f := newFixture(t)
+
+	f.Create(&v1alpha1.ImageMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "sancho",
+		},
+		Spec: v1alpha1.ImageMapSpec{
+			Selector:  "gcr.io/some-project-162817/sancho",
+			SyncAgent: &v1alpha1.LiveUpdateSyncAgent{Port: 8473, Image: "tiltdev/tilt-sync-agent:dev"},
+		},
+		Status: v1alpha1.ImageMapStatus{
+			Image:            "gcr.io/some-project-162817/sancho:my-tag",
+			ImageFromCluster: "gcr.io/some-project-162817/sancho:my-tag",
+			Entrypoint:       []string{"/app/server"},
+		},
+	})
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:      testyaml.SanchoYAML,
+			ImageMaps: []string{"sancho"},
+		},
+	}
+	f.Create(&ka)
+
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+	assert.Contains(f.T(), f.kClient.Yaml, "image: tiltdev/tilt-sync-agent:dev")
+	assert.Contains(f.T(), f.kClient.Yaml, "secretName: tilt-sync-agent-deployment-sancho")
+	assert.Contains(f.T(), f.kClient.Yaml, "- /.tilt-sync-agent/tilt-sync-agent")
+
+	// The token is in a Secret, not the pod spec.
+	assert.Contains(f.T(), f.kClient.Yaml, "kind: Secret")
+	assert.NotContains(f.T(), f.kClient.Yaml, "TILT_SYNC_AGENT_TOKEN")
+	assert.Contains(f.T(), f.kClient.Yaml, "token: "+base64.StdEncoding.EncodeToString([]byte("fake-token")))
+}
+
 func TestBasicApplyCmd(t *testing.T) {
 	f := newFixture(t)
 
@@ -184,6 +227,58 @@ func TestApplyCmdWithImages(t *testing.T) {
 	}
 }
 
+func TestApplyYAMLInjectsClusterNamespace(t *testing.T) {
+	f := newFixture(t)
+
+	f.Create(&v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default-cluster",
+		},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{
+					Namespace:       "alice-dev",
+					CreateNamespace: true,
+				},
+			},
+		},
+		Status: v1alpha1.ClusterStatus{
+			Connection: &v1alpha1.ClusterConnectionStatus{
+				Kubernetes: &v1alpha1.KubernetesClusterConnectionStatus{},
+			},
+		},
+	})
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			Cluster: "default-cluster",
+			YAML: testyaml.SanchoYAML + "\n---\n" + `
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: letsencrypt
+`,
+		},
+	}
+	f.kClient.ClusterScopedKinds[schema.GroupKind{Group: "cert-manager.io", Kind: "ClusterIssuer"}] = true
+	f.Create(&ka)
+
+	entities, err := k8s.ParseYAMLFromString(f.kClient.Yaml)
+	require.NoError(t, err)
+	require.Len(t, entities, 2)
+	for _, e := range entities {
+		switch e.GVK().Kind {
+		case "Deployment":
+			assert.Equal(t, "alice-dev", e.Meta().GetNamespace())
+		case "ClusterIssuer":
+			assert.Equal(t, "", e.Meta().GetNamespace())
+		}
+	}
+}
+
 func TestApplyCmdWithKubeconfig(t *testing.T) {
 	f := newFixture(t)
 
@@ -348,6 +443,64 @@ func TestBasicApplyYAML_JobComplete(t *testing.T) {
 		"KubernetesApply status should reflect Job completion")
 }
 
+func TestDryRunDiff(t *testing.T) {
+	f := newFixture(t)
+	nn := types.NamespacedName{Name: "a"}
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:       testyaml.SanchoYAML,
+			DryRunDiff: true,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(nn, &ka)
+	require.NotNil(t, ka.Status.Diff)
+	assert.Empty(t, ka.Status.Diff.Error)
+	require.Len(t, ka.Status.Diff.Objects, 1)
+	assert.Equal(t, v1alpha1.KubernetesApplyDiffOperationCreate, ka.Status.Diff.Objects[0].Operation)
+	assert.Equal(t, "Deployment", ka.Status.Diff.Objects[0].Kind)
+	assert.Contains(t, ka.Status.Diff.Objects[0].Diff, "+  name: sancho")
+	assert.Contains(t, f.Stdout(), "Dry-run: 1 to create, 0 to update, 0 unchanged")
+
+	// Pretend the apply made it to the cluster, then change the image.
+	f.kClient.Inject(f.kClient.LastUpsertResult...)
+	ka.Spec.YAML = strings.Replace(testyaml.SanchoYAML, testyaml.SanchoImage, testyaml.SanchoImage+":v2", 1)
+	f.Update(&ka)
+
+	f.MustGet(nn, &ka)
+	require.NotNil(t, ka.Status.Diff)
+	require.Len(t, ka.Status.Diff.Objects, 1)
+	objDiff := ka.Status.Diff.Objects[0]
+	assert.Equal(t, v1alpha1.KubernetesApplyDiffOperationUpdate, objDiff.Operation)
+	assert.Contains(t, objDiff.ChangedFields, "spec.template.spec.containers[0].image")
+	assert.Contains(t, objDiff.Diff, "+        image: gcr.io/some-project-162817/sancho:v2")
+}
+
+func TestDryRunDiffErrorDoesNotBlockApply(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.DryRunError = errors.New("no matches for kind \"Sancho\"")
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "a",
+		},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:       testyaml.SanchoYAML,
+			DryRunDiff: true,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Empty(t, ka.Status.Error)
+	assert.Contains(t, ka.Status.ResultYAML, "name: sancho")
+	require.NotNil(t, ka.Status.Diff)
+	assert.Equal(t, "no matches for kind \"Sancho\"", ka.Status.Diff.Error)
+}
+
 func TestGarbageCollectAllOnDelete_YAML(t *testing.T) {
 	f := newFixture(t)
 	ka := v1alpha1.KubernetesApply{
@@ -786,7 +939,7 @@ func newFixture(t *testing.T) *fixture {
 
 	execer := localexec.NewFakeExecer(t)
 
-	r := NewReconciler(cfb.Client, kClient, v1alpha1.NewScheme(), cfb.Store, execer)
+	r := NewReconciler(cfb.Client, kClient, v1alpha1.NewScheme(), cfb.Store, execer, syncagent.Token("fake-token"))
 
 	f := &fixture{
 		ControllerFixture: cfb.Build(r),
@@ -0,0 +1,246 @@
+package kubernetesdiscovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// objKey is a tuple of Cluster metadata, the kind of object, and the K8s
+// namespace being watched for readiness checks.
+type objKey struct {
+	cluster   clusterKey
+	gvk       schema.GroupVersionKind
+	namespace string
+}
+
+// objWatch tracks the watchers for the given kind+namespace and allows the
+// watch to be canceled.
+type objWatch struct {
+	watchers map[watcherID]bool
+	cancel   context.CancelFunc
+}
+
+// readinessRefs returns the watch refs of deployed objects that
+// at least one readiness check applies to.
+func readinessRefs(spec v1alpha1.KubernetesDiscoverySpec) []v1alpha1.KubernetesWatchRef {
+	if len(spec.ReadinessChecks) == 0 {
+		return nil
+	}
+
+	var result []v1alpha1.KubernetesWatchRef
+	for _, ref := range spec.Watches {
+		if ref.UID == "" || ref.Kind == "" {
+			continue
+		}
+		for _, check := range spec.ReadinessChecks {
+			if readinessCheckMatches(check, ref) {
+				result = append(result, ref)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func readinessCheckMatches(check v1alpha1.KubernetesReadinessCheck, ref v1alpha1.KubernetesWatchRef) bool {
+	if !strings.EqualFold(check.Kind, ref.Kind) {
+		return false
+	}
+	return check.Name == "" || check.Name == ref.Name
+}
+
+func readinessObjectRef(ref v1alpha1.KubernetesWatchRef) v1.ObjectReference {
+	return v1.ObjectReference{
+		APIVersion: ref.APIVersion,
+		Kind:       ref.Kind,
+		Namespace:  ref.Namespace,
+		Name:       ref.Name,
+		UID:        types.UID(ref.UID),
+	}
+}
+
+// setupReadinessWatches fetches every object that a readiness check applies to,
+// and watches their kinds for changes so that they can be re-fetched.
+//
+// mu must be held by caller.
+//
+// Like setupNamespaceWatch, only one watch is created for a given kind+namespace.
+func (w *Reconciler) setupReadinessWatches(ctx context.Context, cluster clusterKey, watcherKey watcherID, kCli k8s.Client, refs []v1alpha1.KubernetesWatchRef) {
+	for _, ref := range refs {
+		objRef := readinessObjectRef(ref)
+		key := objKey{cluster: cluster, gvk: k8s.ReferenceGVK(objRef), namespace: ref.Namespace}
+		if watch, ok := w.watchedObjects[key]; ok {
+			watch.watchers[watcherKey] = true
+		} else {
+			watchCtx, cancel := context.WithCancel(ctx)
+			ch, err := kCli.WatchMeta(watchCtx, key.gvk, k8s.Namespace(key.namespace))
+			if err != nil {
+				// The object will still be fetched once below, so the check can
+				// pass, it just won't notice later changes.
+				logger.Get(ctx).Debugf("Error watching %s for readiness: %v", key.gvk.Kind, err)
+				cancel()
+			} else {
+				w.watchedObjects[key] = objWatch{
+					watchers: map[watcherID]bool{watcherKey: true},
+					cancel:   cancel,
+				}
+				go w.dispatchObjectChangesLoop(watchCtx, key, kCli, ch)
+			}
+		}
+
+		go w.fetchObject(ctx, cluster, kCli, objRef)
+	}
+}
+
+// cleanupAbandonedObjectWatches removes the watch on any kinds that no longer
+// have any active watchers, and forgets objects that nothing is watching.
+//
+// mu must be held by caller.
+func (w *Reconciler) cleanupAbandonedObjectWatches() {
+	for key, watch := range w.watchedObjects {
+		if len(watch.watchers) == 0 {
+			watch.cancel()
+			delete(w.watchedObjects, key)
+		}
+	}
+
+	for key := range w.knownObjects {
+		if len(w.uidWatchers[key]) == 0 {
+			delete(w.knownObjects, key)
+		}
+	}
+}
+
+func (w *Reconciler) dispatchObjectChangesLoop(ctx context.Context, key objKey, kCli k8s.Client, ch <-chan metav1.Object) {
+	for {
+		select {
+		case meta, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			objUIDKey := uidKey{cluster: key.cluster, uid: meta.GetUID()}
+			w.mu.Lock()
+			_, isKnown := w.knownObjects[objUIDKey]
+			isWatched := len(w.uidWatchers[objUIDKey]) > 0
+			w.mu.Unlock()
+			if !isKnown && !isWatched {
+				continue
+			}
+
+			ref := v1.ObjectReference{
+				APIVersion: key.gvk.GroupVersion().String(),
+				Kind:       key.gvk.Kind,
+				Namespace:  meta.GetNamespace(),
+				Name:       meta.GetName(),
+				UID:        meta.GetUID(),
+			}
+			go w.fetchObject(ctx, key.cluster, kCli, ref)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// fetchObject gets the latest version of an object and requeues any watchers
+// that have a readiness check for it.
+func (w *Reconciler) fetchObject(ctx context.Context, cluster clusterKey, kCli k8s.Client, ref v1.ObjectReference) {
+	obj, err := kCli.GetByReference(ctx, ref)
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Get(ctx).Debugf("Error fetching %s %s for readiness: %v", ref.Kind, ref.Name, err)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := uidKey{cluster: cluster, uid: ref.UID}
+	if obj == nil {
+		delete(w.knownObjects, key)
+	} else {
+		w.knownObjects[key] = obj
+	}
+
+	for watcherID := range w.uidWatchers[key] {
+		w.requeuer.Add(types.NamespacedName(watcherID))
+	}
+}
+
+// buildReadinessCheckResults evaluates each readiness check against each
+// deployed object it applies to.
+//
+// A check that applies to none of the deployed objects can never pass,
+// so it gets a single result with an error.
+//
+// mu must be held by caller.
+func (w *Reconciler) buildReadinessCheckResults(watcher watcher) []v1alpha1.KubernetesReadinessCheckResult {
+	// Until the apply finishes, we don't know what objects were deployed.
+	if !hasDeployedObjects(watcher.spec) {
+		return nil
+	}
+
+	var results []v1alpha1.KubernetesReadinessCheckResult
+	for _, check := range watcher.spec.ReadinessChecks {
+		cond, condErr := k8s.ParseReadinessCondition(check.Condition)
+		matched := false
+		for _, ref := range watcher.spec.Watches {
+			if ref.UID == "" || !readinessCheckMatches(check, ref) {
+				continue
+			}
+			matched = true
+
+			result := v1alpha1.KubernetesReadinessCheckResult{
+				APIVersion: ref.APIVersion,
+				Kind:       ref.Kind,
+				Namespace:  ref.Namespace,
+				Name:       ref.Name,
+			}
+
+			obj, ok := w.knownObjects[uidKey{cluster: watcher.cluster, uid: types.UID(ref.UID)}]
+			if condErr != nil {
+				result.Error = condErr.Error()
+			} else if !ok {
+				result.Message = fmt.Sprintf("waiting for %s %s", ref.Kind, ref.Name)
+			} else {
+				result.Ready, result.Message = cond.Evaluate(obj.Object)
+			}
+			results = append(results, result)
+		}
+
+		if !matched {
+			results = append(results, v1alpha1.KubernetesReadinessCheckResult{
+				Kind:  check.Kind,
+				Name:  check.Name,
+				Error: unmatchedReadinessCheckError(check),
+			})
+		}
+	}
+	return results
+}
+
+func hasDeployedObjects(spec v1alpha1.KubernetesDiscoverySpec) bool {
+	for _, ref := range spec.Watches {
+		if ref.UID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func unmatchedReadinessCheckError(check v1alpha1.KubernetesReadinessCheck) string {
+	if check.Name != "" {
+		return fmt.Sprintf("readiness check for %s %q matched no deployed objects", check.Kind, check.Name)
+	}
+	return fmt.Sprintf("readiness check for kind %s matched no deployed objects", check.Kind)
+}
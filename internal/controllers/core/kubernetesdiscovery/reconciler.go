@@ -10,6 +10,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -73,6 +74,12 @@ type Reconciler struct {
 	// the watch.
 	watchedNamespaces map[nsKey]nsWatch
 
+	// watchedObjects tracks the kinds+namespaces that are being observed for changes
+	// to objects with readiness checks.
+	//
+	// Like watchedNamespaces, a single watch is shared by all watchers.
+	watchedObjects map[objKey]objWatch
+
 	// watchers reflects the current state of the Reconciler namespace + UID watches.
 	//
 	// On reconcile, if the latest spec differs from what's tracked here, it will be acted upon.
@@ -92,6 +99,9 @@ type Reconciler struct {
 	knownPods             map[uidKey]*v1.Pod
 	knownPodOwnerCreation map[uidKey]metav1.Time
 
+	// knownObjects is an index of the full contents of objects with readiness checks, by UID.
+	knownObjects map[uidKey]*unstructured.Unstructured
+
 	// deletedPods is an index of pods that have been deleted from the cluster,
 	// but are preserved for their termination status.
 	//
@@ -124,11 +134,13 @@ func NewReconciler(ctrlClient ctrlclient.Client, scheme *runtime.Scheme, clients
 		st:                     st,
 		indexer:                indexer.NewIndexer(scheme, indexKubernetesDiscovery),
 		watchedNamespaces:      make(map[nsKey]nsWatch),
+		watchedObjects:         make(map[objKey]objWatch),
 		uidWatchers:            make(map[uidKey]watcherSet),
 		watchers:               make(map[watcherID]watcher),
 		knownDescendentPodUIDs: make(map[uidKey]k8s.UIDSet),
 		knownPods:              make(map[uidKey]*v1.Pod),
 		knownPodOwnerCreation:  make(map[uidKey]metav1.Time),
+		knownObjects:           make(map[uidKey]*unstructured.Unstructured),
 		deletedPods:            make(map[uidKey]bool),
 	}
 }
@@ -288,6 +300,7 @@ func (w *Reconciler) addOrReplace(ctx context.Context, watcherKey watcherID, kd
 			for watchUID := range currentUIDs {
 				w.setupUIDWatch(ctx, newUIDKey(cluster, watchUID), watcherKey)
 			}
+			w.setupReadinessWatches(ctx, newWatcher.cluster, watcherKey, kCli, readinessRefs(kd.Spec))
 
 			newWatcher.startTime = time.Now()
 		}
@@ -317,6 +330,10 @@ func (w *Reconciler) teardown(watcherKey watcherID) {
 		}
 	}
 
+	for _, objWatch := range w.watchedObjects {
+		delete(objWatch.watchers, watcherKey)
+	}
+
 	delete(w.watchers, watcherKey)
 }
 
@@ -332,6 +349,7 @@ func (w *Reconciler) cleanupAbandonedNamespaces() {
 			delete(w.watchedNamespaces, nsKey)
 		}
 	}
+	w.cleanupAbandonedObjectWatches()
 }
 
 // setupNamespaceWatch creates a namespace watch if necessary and adds a key to the list of watchers for it.
@@ -495,6 +513,7 @@ func (w *Reconciler) buildStatus(ctx context.Context, watcher watcher) v1alpha1.
 		Running: &v1alpha1.KubernetesDiscoveryStateRunning{
 			StartTime: startTime,
 		},
+		ReadinessCheckResults: w.buildReadinessCheckResults(watcher),
 	}
 }
 
@@ -14,6 +14,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -716,6 +717,126 @@ type fixture struct {
 	clients *cluster.FakeClientProvider
 }
 
+func TestReadinessCheck(t *testing.T) {
+	f := newFixture(t)
+
+	ns := k8s.Namespace("ns")
+	db := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "db.example.com/v1",
+		"kind":       "Database",
+		"metadata": map[string]interface{}{
+			"name":      "db",
+			"namespace": ns.String(),
+			"uid":       "db-uid",
+		},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+			},
+		},
+	}}
+
+	key := types.NamespacedName{Namespace: "some-ns", Name: "kd"}
+	kd := &v1alpha1.KubernetesDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: v1alpha1.KubernetesDiscoverySpec{
+			Watches: []v1alpha1.KubernetesWatchRef{
+				{
+					UID:        "db-uid",
+					Namespace:  ns.String(),
+					Name:       "db",
+					APIVersion: "db.example.com/v1",
+					Kind:       "Database",
+				},
+			},
+			ReadinessChecks: []v1alpha1.KubernetesReadinessCheck{
+				{Kind: "Database", Condition: "status.conditions[type=Ready].status == True"},
+			},
+		},
+	}
+
+	f.injectK8sObjects(*kd, db)
+	f.Create(kd)
+
+	expected := v1alpha1.KubernetesReadinessCheckResult{
+		APIVersion: "db.example.com/v1",
+		Kind:       "Database",
+		Namespace:  ns.String(),
+		Name:       "db",
+		Message:    `{.status.conditions[?(@.type=="Ready")].status}: got "False", want "True"`,
+	}
+	f.requireReadinessCheckResults(key, []v1alpha1.KubernetesReadinessCheckResult{expected})
+
+	db = db.DeepCopy()
+	require.NoError(t, unstructured.SetNestedSlice(db.Object, []interface{}{
+		map[string]interface{}{"type": "Ready", "status": "True"},
+	}, "status", "conditions"))
+	f.injectK8sObjects(*kd, db)
+
+	expected.Ready = true
+	expected.Message = ""
+	f.requireReadinessCheckResults(key, []v1alpha1.KubernetesReadinessCheckResult{expected})
+}
+
+func TestReadinessCheckInvalidCondition(t *testing.T) {
+	f := newFixture(t)
+
+	key := types.NamespacedName{Namespace: "some-ns", Name: "kd"}
+	kd := &v1alpha1.KubernetesDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: v1alpha1.KubernetesDiscoverySpec{
+			Watches: []v1alpha1.KubernetesWatchRef{
+				{UID: "db-uid", Namespace: "ns", Name: "db", APIVersion: "db.example.com/v1", Kind: "Database"},
+			},
+			ReadinessChecks: []v1alpha1.KubernetesReadinessCheck{
+				{Kind: "Database", Condition: "== True"},
+			},
+		},
+	}
+
+	f.Create(kd)
+	f.requireReadinessCheckResults(key, []v1alpha1.KubernetesReadinessCheckResult{
+		{
+			APIVersion: "db.example.com/v1",
+			Kind:       "Database",
+			Namespace:  "ns",
+			Name:       "db",
+			Error:      `invalid readiness condition "== True": missing JSONPath`,
+		},
+	})
+}
+
+func TestReadinessCheckMatchesNoObjects(t *testing.T) {
+	f := newFixture(t)
+
+	key := types.NamespacedName{Namespace: "some-ns", Name: "kd"}
+	kd := &v1alpha1.KubernetesDiscovery{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: v1alpha1.KubernetesDiscoverySpec{
+			Watches: []v1alpha1.KubernetesWatchRef{
+				{UID: "db-uid", Namespace: "ns", Name: "db", APIVersion: "db.example.com/v1", Kind: "Database"},
+			},
+			ReadinessChecks: []v1alpha1.KubernetesReadinessCheck{
+				{Kind: "Databse", Condition: "status.phase == Ready"},
+				{Kind: "Database", Name: "cache", Condition: "status.phase == Ready"},
+			},
+		},
+	}
+
+	f.Create(kd)
+	f.requireReadinessCheckResults(key, []v1alpha1.KubernetesReadinessCheckResult{
+		{
+			Kind:  "Databse",
+			Error: "readiness check for kind Databse matched no deployed objects",
+		},
+		{
+			Kind:  "Database",
+			Name:  "cache",
+			Error: `readiness check for Database "cache" matched no deployed objects`,
+		},
+	})
+}
+
 func newFixture(t *testing.T) *fixture {
 	rd := NewContainerRestartDetector()
 	cfb := fake.NewControllerFixtureBuilder(t)
@@ -803,6 +924,24 @@ func (f *fixture) requireState(key types.NamespacedName, cond func(kd *v1alpha1.
 	}, stdTimeout, 20*time.Millisecond, msg, args...)
 }
 
+func (f *fixture) requireReadinessCheckResults(key types.NamespacedName, expected []v1alpha1.KubernetesReadinessCheckResult) {
+	f.t.Helper()
+	var desc strings.Builder
+	f.requireState(key, func(kd *v1alpha1.KubernetesDiscovery) bool {
+		desc.Reset()
+		if kd == nil {
+			desc.WriteString("object does not exist in apiserver")
+			return false
+		}
+		if diff := cmp.Diff(expected, kd.Status.ReadinessCheckResults); diff != "" {
+			desc.WriteString("\n")
+			desc.WriteString(diff)
+			return false
+		}
+		return true
+	}, "Expected readiness check results were not observed for key[%s]: %s", key, &desc)
+}
+
 // buildK8sDeployment creates fake Deployment + associated ReplicaSet objects.
 func (f *fixture) buildK8sDeployment(namespace k8s.Namespace, name string) (*appsv1.Deployment, *appsv1.ReplicaSet) {
 	d := &appsv1.Deployment{
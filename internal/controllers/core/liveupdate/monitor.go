@@ -1,6 +1,8 @@
 package liveupdate
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -30,6 +32,14 @@ type monitor struct {
 	// History of container updates.
 	hasChangesToSync bool
 	containers       map[monitorContainerKey]monitorContainerStatus
+
+	// Hashes of files copied back from the container by sync_back,
+	// keyed by absolute local path.
+	syncBackHashes map[string]string
+
+	// When we last copied files back from the container. Zero until the
+	// first successful update with sync_back.
+	lastSyncBack time.Time
 }
 
 type monitorSource struct {
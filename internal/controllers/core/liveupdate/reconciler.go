@@ -10,6 +10,9 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -32,12 +35,14 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
 	"github.com/tilt-dev/tilt/internal/ignore"
 	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -55,6 +60,9 @@ var imageMapGVK = v1alpha1.SchemeGroupVersion.WithKind("ImageMap")
 
 var reasonObjectNotFound = "ObjectNotFound"
 
+// How often we check the container for sync_back changes between updates.
+const syncBackPollInterval = 3 * time.Second
+
 // Manages the LiveUpdate API object.
 type Reconciler struct {
 	client  ctrlclient.Client
@@ -63,10 +71,15 @@ type Reconciler struct {
 
 	ExecUpdater   containerupdate.ContainerUpdater
 	DockerUpdater containerupdate.ContainerUpdater
+	AgentUpdater  *containerupdate.AgentUpdater
 	updateMode    liveupdates.UpdateMode
 	kubeContext   k8s.KubeContext
 	startedTime   metav1.MicroTime
 
+	tracerProvider trace.TracerProvider
+
+	syncBackPollInterval time.Duration
+
 	monitors map[string]*monitor
 
 	// We need to be able to map trigger events to known resources while
@@ -81,20 +94,26 @@ func NewReconciler(
 	st store.RStore,
 	dcu *containerupdate.DockerUpdater,
 	ecu *containerupdate.ExecUpdater,
+	acu *containerupdate.AgentUpdater,
 	updateMode liveupdates.UpdateMode,
 	kubeContext k8s.KubeContext,
 	client ctrlclient.Client,
-	scheme *runtime.Scheme) *Reconciler {
+	scheme *runtime.Scheme,
+	tp trace.TracerProvider) *Reconciler {
 	return &Reconciler{
-		DockerUpdater: dcu,
-		ExecUpdater:   ecu,
-		updateMode:    updateMode,
-		kubeContext:   kubeContext,
-		client:        client,
-		indexer:       indexer.NewIndexer(scheme, indexLiveUpdate),
-		store:         st,
-		startedTime:   apis.NowMicro(),
-		monitors:      make(map[string]*monitor),
+		DockerUpdater:  dcu,
+		ExecUpdater:    ecu,
+		AgentUpdater:   acu,
+		updateMode:     updateMode,
+		kubeContext:    kubeContext,
+		client:         client,
+		indexer:        indexer.NewIndexer(scheme, indexLiveUpdate),
+		store:          st,
+		startedTime:    apis.NowMicro(),
+		monitors:       make(map[string]*monitor),
+		tracerProvider: tp,
+
+		syncBackPollInterval: syncBackPollInterval,
 	}
 }
 
@@ -105,15 +124,18 @@ func NewFakeReconciler(
 	client ctrlclient.Client) *Reconciler {
 	scheme := v1alpha1.NewScheme()
 	return &Reconciler{
-		DockerUpdater: cu,
-		ExecUpdater:   cu,
-		updateMode:    liveupdates.UpdateModeAuto,
-		kubeContext:   k8s.KubeContext("fake-context"),
-		client:        client,
-		indexer:       indexer.NewIndexer(scheme, indexLiveUpdate),
-		store:         st,
-		startedTime:   apis.NowMicro(),
-		monitors:      make(map[string]*monitor),
+		DockerUpdater:  cu,
+		ExecUpdater:    cu,
+		updateMode:     liveupdates.UpdateModeAuto,
+		kubeContext:    k8s.KubeContext("fake-context"),
+		client:         client,
+		indexer:        indexer.NewIndexer(scheme, indexLiveUpdate),
+		store:          st,
+		startedTime:    apis.NowMicro(),
+		monitors:       make(map[string]*monitor),
+		tracerProvider: noop.NewTracerProvider(),
+
+		syncBackPollInterval: syncBackPollInterval,
 	}
 }
 
@@ -218,7 +240,64 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	monitor.hasChangesToSync = false
 
-	return ctrl.Result{}, nil
+	return r.maybePollSyncBack(ctx, lu, monitor), nil
+}
+
+// Copies sync_back paths from the container between updates, so that files
+// the container changes on its own (e.g., a code generator in watch mode)
+// don't wait for the next live update.
+//
+// Polling starts after the first sync-back, once we've recorded
+// the hashes of the files on both sides.
+func (r *Reconciler) maybePollSyncBack(ctx context.Context, lu *v1alpha1.LiveUpdate, monitor *monitor) ctrl.Result {
+	if len(lu.Spec.SyncBacks) == 0 || monitor.lastSyncBack.IsZero() {
+		return ctrl.Result{}
+	}
+
+	wait := r.syncBackPollInterval - time.Since(monitor.lastSyncBack)
+	if wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}
+	}
+
+	c, ok := r.syncBackPollTarget(lu, monitor)
+	if ok {
+		input := Input{IsDC: lu.Spec.Selector.DockerCompose != nil}
+		r.syncBack(ctx, lu.Spec, monitor, r.containerUpdater(lu.Spec, input), c, time.Time{})
+	}
+	monitor.lastSyncBack = time.Now()
+	return ctrl.Result{RequeueAfter: r.syncBackPollInterval}
+}
+
+// Picks a running container to poll for sync_back. All the containers
+// should end up with the same files, so any healthy one will do.
+func (r *Reconciler) syncBackPollTarget(lu *v1alpha1.LiveUpdate, monitor *monitor) (liveupdates.Container, bool) {
+	resource, err := r.resource(lu, monitor)
+	if err != nil {
+		return liveupdates.Container{}, false
+	}
+
+	var result liveupdates.Container
+	resource.visitSelectedContainers(func(pod v1alpha1.Pod, cInfo v1alpha1.Container) bool {
+		if cInfo.ID == "" || cInfo.State.Running == nil {
+			return false
+		}
+		cKey := monitorContainerKey{
+			containerID: cInfo.ID,
+			podName:     pod.Name,
+			namespace:   pod.Namespace,
+		}
+		if monitor.containers[cKey].failedReason != "" {
+			return false
+		}
+		result = liveupdates.Container{
+			ContainerID:   container.ID(cInfo.ID),
+			ContainerName: container.Name(cInfo.Name),
+			PodID:         k8s.PodID(pod.Name),
+			Namespace:     k8s.Namespace(pod.Namespace),
+		}
+		return true
+	})
+	return result, !result.Empty()
 }
 
 func (r *Reconciler) shouldLogFailureReason(obj *v1alpha1.LiveUpdateStateFailed) bool {
@@ -277,6 +356,8 @@ func (r *Reconciler) ensureMonitorExists(name string, obj *v1alpha1.LiveUpdate)
 		spec:         spec,
 		sources:      make(map[string]*monitorSource),
 		containers:   make(map[monitorContainerKey]monitorContainerStatus),
+
+		syncBackHashes: make(map[string]string),
 	}
 	r.monitors[name] = m
 	return m
@@ -548,16 +629,24 @@ func (r *Reconciler) garbageCollectFileChanges(res luResource, monitor *monitor)
 func (r *Reconciler) garbageCollectMonitorContainers(res luResource, monitor *monitor) {
 	// All containers are guaranteed to have container IDs if they're still active.
 	containerIDs := map[string]bool{}
+	pods := map[types.NamespacedName]bool{}
 	res.visitSelectedContainers(func(pod v1alpha1.Pod, c v1alpha1.Container) bool {
 		if c.ID != "" {
 			containerIDs[c.ID] = true
 		}
+		pods[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}] = true
 		return false
 	})
 
 	for key := range monitor.containers {
 		if !containerIDs[key.containerID] {
 			delete(monitor.containers, key)
+
+			// Close our connections to any sync agent in a pod that's gone.
+			pod := types.NamespacedName{Namespace: key.namespace, Name: key.podName}
+			if r.AgentUpdater != nil && !pods[pod] {
+				r.AgentUpdater.Forget(k8s.Namespace(key.namespace), k8s.PodID(key.podName))
+			}
 		}
 	}
 }
@@ -673,6 +762,7 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 	}
 
 	updateEventDispatched := false
+	syncedBack := false
 
 	// Visit all containers, apply changes, and return their statuses.
 	terminatedContainerPodName := ""
@@ -740,6 +830,7 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 		}
 
 		// Sort the files so that they're deterministic.
+		filesChanged = filterSyncedBackFiles(filesChanged, monitor.syncBackHashes)
 		filesChanged = sliceutils.DedupedAndSorted(filesChanged)
 		if len(filesChanged) > 0 {
 			hasAnyFilesToSync = true
@@ -815,14 +906,35 @@ func (r *Reconciler) maybeSync(ctx context.Context, lu *v1alpha1.LiveUpdate, mon
 			}
 
 			// Apply the change to the container.
-			oneUpdateStatus = r.applyInternal(ctx, lu.Spec, Input{
+			syncStart := time.Now()
+			syncCtx, span := tracer.StartWithProvider(ctx, r.tracerProvider, "liveupdate.sync",
+				attribute.String("liveupdate", lu.Name),
+				attribute.Int("files", len(plan.SyncPaths)))
+			input := Input{
 				IsDC:               lu.Spec.Selector.DockerCompose != nil,
 				ChangedFiles:       plan.SyncPaths,
 				Containers:         []liveupdates.Container{c},
 				LastFileTimeSynced: newHighWaterMark,
 				InitialSync:        isInitialSync,
 				InitialSyncFilter:  initialSyncFilter,
-			})
+			}
+			oneUpdateStatus = r.applyInternal(syncCtx, lu.Spec, input)
+
+			// All the containers should end up with the same files,
+			// so we only need to sync back from one of them.
+			if len(lu.Spec.SyncBacks) > 0 && !syncedBack && oneUpdateStatus.Failed == nil &&
+				len(oneUpdateStatus.Containers) > 0 && oneUpdateStatus.Containers[0].LastExecError == "" {
+				syncedBack = true
+				r.syncBack(syncCtx, lu.Spec, monitor, r.containerUpdater(lu.Spec, input), c, syncStart)
+				monitor.lastSyncBack = time.Now()
+			}
+			metrics.LiveUpdateSyncDuration.WithLabelValues(lu.Annotations[v1alpha1.AnnotationManifest]).
+				Observe(time.Since(syncStart).Seconds())
+			var syncErr error
+			if oneUpdateStatus.Failed != nil {
+				syncErr = errors.New(oneUpdateStatus.Failed.Message)
+			}
+			tracer.EndWithError(span, syncErr)
 			filesApplied = true
 		}
 
@@ -929,7 +1041,7 @@ func (r *Reconciler) applyInternal(
 	input Input) v1alpha1.LiveUpdateStatus {
 
 	var result v1alpha1.LiveUpdateStatus
-	cu := r.containerUpdater(input)
+	cu := r.containerUpdater(spec, input)
 	l := logger.Get(ctx)
 	containers := input.Containers
 	names := liveupdates.ContainerDisplayNames(containers)
@@ -1057,7 +1169,7 @@ func (r *Reconciler) applyInternal(
 	return result
 }
 
-func (r *Reconciler) containerUpdater(input Input) containerupdate.ContainerUpdater {
+func (r *Reconciler) containerUpdater(spec v1alpha1.LiveUpdateSpec, input Input) containerupdate.ContainerUpdater {
 	isDC := input.IsDC
 	if isDC || r.updateMode == liveupdates.UpdateModeContainer {
 		return r.DockerUpdater
@@ -1067,6 +1179,10 @@ func (r *Reconciler) containerUpdater(input Input) containerupdate.ContainerUpda
 		return r.ExecUpdater
 	}
 
+	if spec.SyncAgent != nil && r.AgentUpdater != nil {
+		return r.AgentUpdater.ForPort(int(spec.SyncAgent.Port))
+	}
+
 	return r.ExecUpdater
 }
 
@@ -21,9 +21,13 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apis/configmap"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/syncagent"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -930,6 +934,27 @@ func (s *TestingStore) Dispatch(action store.Action) {
 	}
 }
 
+func TestContainerUpdaterSyncAgent(t *testing.T) {
+	kCli := k8s.NewFakeK8sClient(t)
+	ecu := containerupdate.NewExecUpdater(kCli)
+	dcu := containerupdate.NewDockerUpdater(docker.NewFakeClient())
+	r := &Reconciler{
+		ExecUpdater:   ecu,
+		DockerUpdater: dcu,
+		AgentUpdater:  containerupdate.NewAgentUpdater(kCli, ecu, syncagent.Token("fake-token")),
+		updateMode:    liveupdates.UpdateModeAuto,
+	}
+
+	agentSpec := v1alpha1.LiveUpdateSpec{SyncAgent: &v1alpha1.LiveUpdateSyncAgent{Port: 8473}}
+	assert.Equal(t, ecu, r.containerUpdater(v1alpha1.LiveUpdateSpec{}, Input{}))
+	assert.Equal(t, dcu, r.containerUpdater(agentSpec, Input{IsDC: true}))
+	assert.Equal(t, r.AgentUpdater.ForPort(8473), r.containerUpdater(agentSpec, Input{}))
+
+	// Explicitly asking for kubectl exec wins over the agent.
+	r.updateMode = liveupdates.UpdateModeKubectlExec
+	assert.Equal(t, ecu, r.containerUpdater(agentSpec, Input{}))
+}
+
 type fixture struct {
 	*fake.ControllerFixture
 	r  *Reconciler
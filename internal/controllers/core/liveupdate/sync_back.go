@@ -0,0 +1,373 @@
+package liveupdate
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type syncBackDecision int
+
+const (
+	// Copy the container's version over the local file.
+	syncBackWrite syncBackDecision = iota
+
+	// Leave the local file alone.
+	syncBackSkip
+
+	// Both sides changed. Leave the local file alone and warn.
+	syncBackConflict
+)
+
+// The state of one file at sync-back time. Hashes are empty
+// if the file doesn't exist (or has never been synced back).
+type syncBackFile struct {
+	localHash     string
+	localModTime  time.Time
+	containerHash string
+	recordedHash  string
+}
+
+// Decides what to do with one file in a sync_back path.
+//
+// The recorded hash is the content we last saw on both sides. A local file
+// that differs from the record has been edited by the user, and a container
+// file that differs from the record has been regenerated. If both happened,
+// that's a conflict, and we keep the local edit.
+//
+// After an update, we also treat local files touched after the update started
+// as edited, even if we've never synced them back. When polling, updateStart
+// is zero, because there's no update that could have raced with the edit.
+func decideSyncBack(f syncBackFile, updateStart time.Time) syncBackDecision {
+	if f.localHash == f.containerHash {
+		return syncBackSkip
+	}
+
+	if f.localHash == "" {
+		if f.recordedHash != "" && f.containerHash == f.recordedHash {
+			// The user deleted a file we synced back, and the
+			// container hasn't regenerated it.
+			return syncBackSkip
+		}
+		return syncBackWrite
+	}
+
+	if !updateStart.IsZero() && f.localModTime.After(updateStart) {
+		return syncBackConflict
+	}
+
+	if f.recordedHash == "" || f.localHash == f.recordedHash {
+		return syncBackWrite
+	}
+
+	if f.containerHash == f.recordedHash {
+		// Only the local file changed. The normal sync will copy it over.
+		return syncBackSkip
+	}
+	return syncBackConflict
+}
+
+// Copies the sync_back paths from the container to the host.
+//
+// Called after each successful live update, and then periodically
+// (with a zero updateStart) to pick up files that the container changes
+// on its own.
+//
+// Errors are logged rather than returned. A failed sync-back
+// shouldn't fail an update that already succeeded. Errors while polling
+// are only logged at debug level, because the container may be going away.
+func (r *Reconciler) syncBack(ctx context.Context, spec v1alpha1.LiveUpdateSpec, monitor *monitor,
+	cu containerupdate.ContainerUpdater, c liveupdates.Container, updateStart time.Time) {
+	l := logger.Get(ctx)
+	downloader, ok := cu.(containerupdate.ContainerDownloader)
+	if !ok {
+		l.Warnf("sync_back is not supported for container %s", c.DisplayName())
+		return
+	}
+
+	for _, step := range liveupdate.SyncBackSteps(spec) {
+		written, conflicts, err := syncBackStep(ctx, downloader, c, step, monitor.syncBackHashes, updateStart)
+		if err != nil {
+			if updateStart.IsZero() {
+				l.Debugf("sync_back %s: %v", step.ContainerPath, err)
+			} else {
+				l.Warnf("sync_back %s: %v", step.ContainerPath, err)
+			}
+		}
+		if len(written) > 0 {
+			l.Infof("Synced back %d file(s) from container %s:", len(written), c.DisplayName())
+			for _, p := range written {
+				l.Infof("- %s", p)
+			}
+		}
+		for _, p := range conflicts {
+			l.Warnf("sync_back: %s changed both locally and in the container. Keeping the local copy.", p)
+		}
+	}
+}
+
+// Syncs back one path.
+//
+// If the container can report file hashes, we compare them against the
+// local files and the recorded hashes first, and only download the files
+// that we're going to write. Otherwise, we download the whole path.
+func syncBackStep(ctx context.Context, downloader containerupdate.ContainerDownloader, c liveupdates.Container,
+	step model.Sync, hashes map[string]string, updateStart time.Time) (written []string, conflicts []string, err error) {
+	hasher, ok := downloader.(containerupdate.ContainerHasher)
+	if ok {
+		containerHashes, err := hasher.HashFiles(ctx, c, step.ContainerPath)
+		if err == nil {
+			var toDownload []string
+			toDownload, conflicts, err = planSyncBack(step, containerHashes, hashes, updateStart)
+			if err != nil || len(toDownload) == 0 {
+				return nil, conflicts, err
+			}
+			written, _, err = downloadSyncBack(ctx, step, hashes, updateStart,
+				func(w io.Writer) error {
+					return hasher.DownloadFiles(ctx, c, step.ContainerPath, toDownload, w)
+				})
+			return written, conflicts, err
+		}
+	}
+
+	return downloadSyncBack(ctx, step, hashes, updateStart,
+		func(w io.Writer) error {
+			return downloader.DownloadArchive(ctx, c, step.ContainerPath, w)
+		})
+}
+
+// Decides what to do with each file from its container hash, without
+// downloading it. Returns the archive entries to download, and the files
+// in conflict.
+func planSyncBack(step model.Sync, containerHashes map[string]string, hashes map[string]string,
+	updateStart time.Time) (toDownload []string, conflicts []string, err error) {
+	names := make([]string, 0, len(containerHashes))
+	for name := range containerHashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		dst, ok := syncBackDest(step.LocalPath, name)
+		if !ok {
+			continue
+		}
+		f, err := localSyncBackFile(dst, containerHashes[name], hashes[dst])
+		if err != nil {
+			return toDownload, conflicts, err
+		}
+
+		switch decideSyncBack(f, updateStart) {
+		case syncBackSkip:
+			if f.localHash == f.containerHash {
+				hashes[dst] = f.containerHash
+			}
+		case syncBackConflict:
+			conflicts = append(conflicts, dst)
+		case syncBackWrite:
+			toDownload = append(toDownload, name)
+		}
+	}
+	return toDownload, conflicts, nil
+}
+
+// Streams an archive from the container into extractSyncBack, so that
+// syncing back a big directory doesn't hold it all in memory.
+func downloadSyncBack(ctx context.Context, step model.Sync, hashes map[string]string, updateStart time.Time,
+	download func(w io.Writer) error) (written []string, conflicts []string, err error) {
+	pr, pw := io.Pipe()
+	downloadDone := make(chan struct{})
+	go func() {
+		defer close(downloadDone)
+		_ = pw.CloseWithError(download(pw))
+	}()
+
+	written, conflicts, err = extractSyncBack(pr, step, hashes, updateStart)
+	_ = pr.CloseWithError(err)
+	<-downloadDone
+	return written, conflicts, err
+}
+
+// Reads the local side of a file for decideSyncBack.
+func localSyncBackFile(dst string, containerHash string, recordedHash string) (syncBackFile, error) {
+	f := syncBackFile{
+		containerHash: containerHash,
+		recordedHash:  recordedHash,
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		return f, nil
+	}
+	f.localModTime = info.ModTime()
+	f.localHash, err = hashFile(dst)
+	return f, err
+}
+
+// Extracts the regular files from a sync-back archive onto the local filesystem.
+//
+// Archive entries are named relative to the container path's parent directory,
+// so we swap the first path element for the local path. Returns the files
+// written and the files in conflict.
+//
+// Each entry is spooled to a temp file while we hash it, because we can't
+// decide whether to write it until we know its hash.
+func extractSyncBack(r io.Reader, step model.Sync, hashes map[string]string, updateStart time.Time) (written []string, conflicts []string, err error) {
+	tmpDir, err := os.MkdirTemp("", "tilt-sync-back-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+	tmpPath := filepath.Join(tmpDir, "entry")
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return written, conflicts, nil
+		}
+		if err != nil {
+			return written, conflicts, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			// We don't sync back directories, symlinks or deletes.
+			continue
+		}
+
+		dst, ok := syncBackDest(step.LocalPath, hdr.Name)
+		if !ok {
+			continue
+		}
+
+		containerHash, err := spoolEntry(tr, tmpPath)
+		if err != nil {
+			return written, conflicts, err
+		}
+
+		f, err := localSyncBackFile(dst, containerHash, hashes[dst])
+		if err != nil {
+			return written, conflicts, err
+		}
+
+		switch decideSyncBack(f, updateStart) {
+		case syncBackSkip:
+			if f.localHash == f.containerHash {
+				hashes[dst] = f.containerHash
+			}
+		case syncBackConflict:
+			conflicts = append(conflicts, dst)
+		case syncBackWrite:
+			err = os.MkdirAll(filepath.Dir(dst), 0o755)
+			if err != nil {
+				return written, conflicts, err
+			}
+			err = copyFile(tmpPath, dst, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return written, conflicts, err
+			}
+			hashes[dst] = f.containerHash
+			written = append(written, dst)
+		}
+	}
+}
+
+// Copies the current archive entry to path, and returns its hash.
+func spoolEntry(r io.Reader, path string) (string, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), f.Close()
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Maps an archive entry onto the local path, dropping entries
+// that would land outside of it.
+func syncBackDest(localPath string, name string) (string, bool) {
+	name = path.Clean(strings.TrimPrefix(name, "/"))
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", false
+	}
+	_, rel, found := strings.Cut(name, "/")
+	if !found {
+		// The container path is a single file.
+		return localPath, true
+	}
+	return filepath.Join(localPath, filepath.FromSlash(rel)), true
+}
+
+// Drops files that still have the content we synced back,
+// so that sync_back doesn't trigger another update.
+func filterSyncedBackFiles(files []string, hashes map[string]string) []string {
+	if len(hashes) == 0 {
+		return files
+	}
+
+	result := make([]string, 0, len(files))
+	for _, f := range files {
+		recorded, ok := hashes[f]
+		if ok {
+			h, err := hashFile(f)
+			if err == nil && h == recorded {
+				continue
+			}
+		}
+		result = append(result, f)
+	}
+	return result
+}
+
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", fmt.Errorf("hashing %s: %v", p, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,276 @@
+package liveupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestDecideSyncBack(t *testing.T) {
+	start := time.Now()
+	before := start.Add(-time.Minute)
+	after := start.Add(time.Second)
+
+	for _, tc := range []struct {
+		name     string
+		file     syncBackFile
+		expected syncBackDecision
+	}{
+		{"same content", syncBackFile{localHash: "a", containerHash: "a", localModTime: after}, syncBackSkip},
+		{"new file", syncBackFile{containerHash: "a"}, syncBackWrite},
+		{"deleted locally, unchanged in container", syncBackFile{containerHash: "a", recordedHash: "a"}, syncBackSkip},
+		{"deleted locally, changed in container", syncBackFile{containerHash: "b", recordedHash: "a"}, syncBackWrite},
+		{"never synced back", syncBackFile{localHash: "a", containerHash: "b", localModTime: before}, syncBackWrite},
+		{"changed in container", syncBackFile{localHash: "a", containerHash: "b", recordedHash: "a", localModTime: before}, syncBackWrite},
+		{"changed locally", syncBackFile{localHash: "b", containerHash: "a", recordedHash: "a", localModTime: before}, syncBackSkip},
+		{"changed on both sides", syncBackFile{localHash: "b", containerHash: "c", recordedHash: "a", localModTime: before}, syncBackConflict},
+		{"edited during update", syncBackFile{localHash: "a", containerHash: "b", localModTime: after}, syncBackConflict},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, decideSyncBack(tc.file, start))
+		})
+	}
+}
+
+func TestDecideSyncBackWhilePolling(t *testing.T) {
+	recent := time.Now()
+
+	// With no update in flight, a recent local edit is only a conflict
+	// if the container changed the file too.
+	assert.Equal(t, syncBackSkip, decideSyncBack(
+		syncBackFile{localHash: "b", containerHash: "a", recordedHash: "a", localModTime: recent}, time.Time{}))
+	assert.Equal(t, syncBackConflict, decideSyncBack(
+		syncBackFile{localHash: "b", containerHash: "c", recordedHash: "a", localModTime: recent}, time.Time{}))
+}
+
+func TestSyncBackDest(t *testing.T) {
+	local := filepath.Join("src", "gen")
+
+	dst, ok := syncBackDest(local, "gen/api/types.pb.go")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(local, "api", "types.pb.go"), dst)
+
+	dst, ok = syncBackDest(local, "package-lock.json")
+	assert.True(t, ok)
+	assert.Equal(t, local, dst)
+
+	_, ok = syncBackDest(local, "gen/../../etc/passwd")
+	assert.False(t, ok)
+}
+
+func TestExtractSyncBack(t *testing.T) {
+	dir := t.TempDir()
+	step := model.Sync{LocalPath: filepath.Join(dir, "gen"), ContainerPath: "/app/gen"}
+	start := time.Now()
+	hashes := make(map[string]string)
+
+	written, conflicts, err := extractSyncBack(syncBackTar(t, map[string]string{
+		"gen/a.pb.go":     "package a",
+		"gen/sub/b.pb.go": "package b",
+	}), step, hashes, start)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(dir, "gen", "a.pb.go"),
+		filepath.Join(dir, "gen", "sub", "b.pb.go"),
+	}, written)
+	assertFileContents(t, filepath.Join(dir, "gen", "a.pb.go"), "package a")
+	assertFileContents(t, filepath.Join(dir, "gen", "sub", "b.pb.go"), "package b")
+
+	// Edit a.pb.go locally, then start an update that regenerates both in the container.
+	aPath := filepath.Join(dir, "gen", "a.pb.go")
+	require.NoError(t, os.WriteFile(aPath, []byte("package a // edited"), 0o644))
+	start = time.Now().Add(time.Second)
+
+	written, conflicts, err = extractSyncBack(syncBackTar(t, map[string]string{
+		"gen/a.pb.go":     "package a // regenerated",
+		"gen/sub/b.pb.go": "package b // regenerated",
+	}), step, hashes, start)
+	require.NoError(t, err)
+	assert.Equal(t, []string{aPath}, conflicts)
+	assert.Equal(t, []string{filepath.Join(dir, "gen", "sub", "b.pb.go")}, written)
+	assertFileContents(t, aPath, "package a // edited")
+	assertFileContents(t, filepath.Join(dir, "gen", "sub", "b.pb.go"), "package b // regenerated")
+}
+
+func TestExtractSyncBackDownloadError(t *testing.T) {
+	dir := t.TempDir()
+	step := model.Sync{LocalPath: filepath.Join(dir, "gen"), ContainerPath: "/app/gen"}
+
+	// Cut the download off partway through the file contents.
+	archive := syncBackTar(t, map[string]string{"gen/a.pb.go": "package a"}).Bytes()
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = pw.Write(archive[:512+4])
+		_ = pw.CloseWithError(errors.New("connection reset"))
+	}()
+
+	written, _, err := extractSyncBack(pr, step, make(map[string]string), time.Now())
+	assert.EqualError(t, err, "connection reset")
+	assert.Empty(t, written)
+	assert.NoFileExists(t, filepath.Join(dir, "gen", "a.pb.go"))
+}
+
+func TestFilterSyncedBackFiles(t *testing.T) {
+	dir := t.TempDir()
+	synced := filepath.Join(dir, "synced.txt")
+	edited := filepath.Join(dir, "edited.txt")
+	other := filepath.Join(dir, "other.txt")
+	require.NoError(t, os.WriteFile(synced, []byte("synced"), 0o644))
+	require.NoError(t, os.WriteFile(edited, []byte("edited"), 0o644))
+
+	hashes := map[string]string{
+		synced: hashBytes([]byte("synced")),
+		edited: hashBytes([]byte("original")),
+	}
+	assert.Equal(t, []string{edited, other},
+		filterSyncedBackFiles([]string{synced, edited, other}, hashes))
+}
+
+func TestReconcileSyncBack(t *testing.T) {
+	f := newFixture(t)
+	f.setupFrontend()
+
+	dir := t.TempDir()
+	genPath := filepath.Join(dir, "gen")
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	lu.Spec.SyncBacks = []v1alpha1.LiveUpdateSyncBack{{ContainerPath: "/app/gen", LocalPath: genPath}}
+	f.Update(&lu)
+
+	f.cu.Archives = map[string][]byte{
+		"/app/gen": syncBackTar(t, map[string]string{"gen/types.go": "package gen"}).Bytes(),
+	}
+
+	p, _ := os.Getwd()
+	f.addFileEvent("frontend-fw", filepath.Join(p, "a.txt"), metav1.MicroTime{Time: apis.NowMicro().Add(time.Second)})
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	assert.Equal(t, 1, len(f.cu.Calls))
+	assert.Equal(t, []string{"/app/gen"}, f.cu.DownloadCalls)
+	typesPath := filepath.Join(genPath, "types.go")
+	assertFileContents(t, typesPath, "package gen")
+
+	// The file watcher sees the file we just wrote. That shouldn't trigger another update.
+	f.addFileEvent("frontend-fw", typesPath, metav1.MicroTime{Time: apis.NowMicro().Add(2 * time.Second)})
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+	assert.Equal(t, 1, len(f.cu.Calls))
+}
+
+func TestReconcileSyncBackPolls(t *testing.T) {
+	f := newFixture(t)
+	genPath := f.setupSyncBack(map[string]string{"gen/types.go": "package gen"})
+	typesPath := filepath.Join(genPath, "types.go")
+	assertFileContents(t, typesPath, "package gen")
+
+	// The container regenerates the file on its own. We pick it up
+	// on the next poll, without another live update.
+	f.cu.Archives["/app/gen"] = syncBackTar(t, map[string]string{"gen/types.go": "package gen // v2"}).Bytes()
+	f.r.syncBackPollInterval = 0
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	assert.Equal(t, 1, len(f.cu.Calls))
+	assert.Equal(t, []string{"/app/gen", "/app/gen"}, f.cu.DownloadCalls)
+	assertFileContents(t, typesPath, "package gen // v2")
+}
+
+func TestReconcileSyncBackOnlyDownloadsChangedFiles(t *testing.T) {
+	f := newFixture(t)
+	f.cu.SupportsHashes = true
+	genPath := f.setupSyncBack(map[string]string{
+		"gen/a.go": "package gen // a",
+		"gen/b.go": "package gen // b",
+	})
+	assertFileContents(t, filepath.Join(genPath, "a.go"), "package gen // a")
+	assert.Empty(t, f.cu.DownloadCalls)
+	assert.Equal(t, [][]string{{"gen/a.go", "gen/b.go"}}, f.cu.FileDownloads)
+
+	// Nothing changed, so polling doesn't download anything.
+	f.r.syncBackPollInterval = 0
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+	assert.Equal(t, 2, len(f.cu.HashCalls))
+	assert.Equal(t, 1, len(f.cu.FileDownloads))
+
+	// Only the changed file is downloaded.
+	f.cu.Archives["/app/gen"] = syncBackTar(t, map[string]string{
+		"gen/a.go": "package gen // a",
+		"gen/b.go": "package gen // b2",
+	}).Bytes()
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+	assert.Equal(t, []string{"gen/b.go"}, f.cu.FileDownloads[1])
+	assertFileContents(t, filepath.Join(genPath, "b.go"), "package gen // b2")
+}
+
+// Sets up a frontend LiveUpdate that syncs back /app/gen, and runs one update.
+// Returns the local path.
+func (f *fixture) setupSyncBack(files map[string]string) string {
+	f.setupFrontend()
+
+	genPath := filepath.Join(f.T().TempDir(), "gen")
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	lu.Spec.SyncBacks = []v1alpha1.LiveUpdateSyncBack{{ContainerPath: "/app/gen", LocalPath: genPath}}
+	f.Update(&lu)
+
+	f.cu.Archives = map[string][]byte{"/app/gen": syncBackTar(f.T(), files).Bytes()}
+
+	p, _ := os.Getwd()
+	f.addFileEvent("frontend-fw", filepath.Join(p, "a.txt"), metav1.MicroTime{Time: apis.NowMicro().Add(time.Second)})
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+	require.Equal(f.T(), 1, len(f.cu.Calls))
+	return genPath
+}
+
+func syncBackTar(t testing.TB, files map[string]string) *bytes.Buffer {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for _, name := range names {
+		contents := files[name]
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(contents)),
+		}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf
+}
+
+func hashBytes(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func assertFileContents(t *testing.T, p string, expected string) {
+	t.Helper()
+	contents, err := os.ReadFile(p)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, string(contents))
+	}
+}
@@ -34,7 +34,8 @@ func (r *Reconciler) targetsForResource(mt *store.ManifestTarget, holds buildcon
 
 func (r *Reconciler) k8sRuntimeTarget(mt *store.ManifestTarget, ci *v1alpha1.SessionCISpec, result *ctrl.Result) *session.Target {
 	krs := mt.State.K8sRuntimeState()
-	if mt.Manifest.PodReadinessMode() == model.PodReadinessIgnore && krs.HasEverDeployedSuccessfully && krs.PodLen() == 0 {
+	if mt.Manifest.PodReadinessMode() == model.PodReadinessIgnore && krs.HasEverDeployedSuccessfully && krs.PodLen() == 0 &&
+		!krs.HasReadinessChecks {
 		// HACK: engine assumes anything with an image will create a pod; PodReadinessIgnore is used in these
 		// 	instances to avoid getting stuck in pending forever; in reality, there's no "runtime" target being
 		// 	monitored by Tilt, so instead of faking it, just omit it (note: only applies AFTER first deploy so
@@ -151,7 +152,9 @@ func (r *Reconciler) k8sRuntimeTarget(mt *store.ManifestTarget, ci *v1alpha1.Ses
 
 		waitReason := pod.Status
 		if waitReason == "" {
-			if pod.Name == "" {
+			if pod.Name == "" && krs.HasReadinessChecks {
+				waitReason = "waiting-for-readiness-checks"
+			} else if pod.Name == "" {
 				waitReason = "waiting-for-pod"
 			} else {
 				waitReason = "unknown"
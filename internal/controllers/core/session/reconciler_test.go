@@ -400,6 +400,48 @@ func TestExitControlCI_PodReadinessMode_Ignore_NoPods(t *testing.T) {
 	f.requireDoneWithNoError()
 }
 
+// TestExitControlCI_ReadinessChecks covers resources with no pods that are
+// only ready once a check on a deployed object passes (e.g., a CRD status).
+func TestExitControlCI_ReadinessChecks(t *testing.T) {
+	f := newFixture(t, store.EngineModeCI)
+
+	m := manifestbuilder.New(f, "fe").
+		WithK8sYAML(testyaml.SecretYaml).
+		WithK8sPodReadiness(model.PodReadinessIgnore).
+		Build()
+	f.upsertManifest(m)
+	f.Store.WithState(func(state *store.EngineState) {
+		mt := state.ManifestTargets["fe"]
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  f.clock.Now(),
+			FinishTime: f.clock.Now(),
+		})
+
+		krs := store.NewK8sRuntimeState(mt.Manifest)
+		krs.HasEverDeployedSuccessfully = true
+		krs.HasReadinessChecks = true
+		krs.ReadinessCheckResults = []v1alpha1.KubernetesReadinessCheckResult{
+			{Kind: "Database", Name: "db", Message: "waiting for Database db"},
+		}
+		mt.State.RuntimeState = krs
+	})
+
+	f.MustReconcile(sessionKey)
+	f.requireNotDone()
+
+	f.Store.WithState(func(state *store.EngineState) {
+		mt := state.ManifestTargets["fe"]
+		krs := mt.State.K8sRuntimeState()
+		krs.ReadinessCheckResults = []v1alpha1.KubernetesReadinessCheckResult{
+			{Kind: "Database", Name: "db", Ready: true},
+		}
+		mt.State.RuntimeState = krs
+	})
+
+	f.MustReconcile(sessionKey)
+	f.requireDoneWithNoError()
+}
+
 func TestExitControlCI_JobSuccess(t *testing.T) {
 	f := newFixture(t, store.EngineModeCI)
 
@@ -27,18 +27,20 @@ type ConfigsReloadedAction struct {
 	Tiltignore  model.Dockerignore
 	ConfigFiles []string
 
-	FinishTime           time.Time
-	Err                  error
-	Warnings             []string
-	Features             map[string]bool
-	TeamID               string
-	TelemetrySettings    model.TelemetrySettings
-	Secrets              model.SecretSet
-	DockerPruneSettings  model.DockerPruneSettings
-	AnalyticsTiltfileOpt analytics.Opt
-	VersionSettings      model.VersionSettings
-	UpdateSettings       model.UpdateSettings
-	WatchSettings        model.WatchSettings
+	FinishTime            time.Time
+	Err                   error
+	Warnings              []string
+	Features              map[string]bool
+	TeamID                string
+	TelemetrySettings     model.TelemetrySettings
+	NotifySettings        model.NotifySettings
+	Secrets               model.SecretSet
+	DockerPruneSettings   model.DockerPruneSettings
+	RegistryPruneSettings model.RegistryPruneSettings
+	AnalyticsTiltfileOpt  analytics.Opt
+	VersionSettings       model.VersionSettings
+	UpdateSettings        model.UpdateSettings
+	WatchSettings         model.WatchSettings
 
 	// A checkpoint into the logstore when Tiltfile execution started.
 	// Useful for knowing how far back in time we have to scrub secrets.
@@ -616,6 +616,12 @@ func toClusterObjects(nn types.NamespacedName, tlr *tiltfile.TiltfileLoadResult,
 
 	if tlr.HasOrchestrator(model.OrchestratorK8s) {
 		name := v1alpha1.ClusterNameDefault
+		k8sConnection := defaultK8sConnection.DeepCopy()
+		if tlr.DevNamespace.Name != "" {
+			k8sConnection.Namespace = tlr.DevNamespace.Name.String()
+			k8sConnection.CreateNamespace = true
+		}
+
 		result[name] = &v1alpha1.Cluster{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:        name,
@@ -623,7 +629,7 @@ func toClusterObjects(nn types.NamespacedName, tlr *tiltfile.TiltfileLoadResult,
 			},
 			Spec: v1alpha1.ClusterSpec{
 				Connection: &v1alpha1.ClusterConnection{
-					Kubernetes: defaultK8sConnection.DeepCopy(),
+					Kubernetes: k8sConnection,
 				},
 				DefaultRegistry: tlr.DefaultRegistry,
 			},
@@ -20,6 +20,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils/manifestbuilder"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/internal/tiltfile"
+	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -279,6 +280,31 @@ func TestCreateClusterDefaultRegistry(t *testing.T) {
 	require.Equal(t, "fake-repo", cluster.Spec.DefaultRegistry.SingleName, "Default registry single name")
 }
 
+func TestCreateClusterDevNamespace(t *testing.T) {
+	f := newAPIFixture(t)
+	fe := manifestbuilder.New(f, "fe").
+		WithImageTarget(NewSanchoDockerBuildImageTarget(f)).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build()
+	tf := &v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{Name: model.MainTiltfileManifestName.String()},
+	}
+	nn := apis.Key(tf)
+	tlr := &tiltfile.TiltfileLoadResult{
+		Manifests:    []model.Manifest{fe},
+		DevNamespace: k8scontext.DevNamespace{Name: "tilt-alice"},
+	}
+	err := f.updateOwnedObjects(nn, tf, tlr)
+	assert.NoError(t, err)
+
+	var cluster v1alpha1.Cluster
+	require.NoError(t, f.Get(types.NamespacedName{Name: "default"}, &cluster))
+	require.Equal(t, &v1alpha1.KubernetesClusterConnection{
+		Namespace:       "tilt-alice",
+		CreateNamespace: true,
+	}, cluster.Spec.Connection.Kubernetes)
+}
+
 // Ensure that we emit disable-related objects/field appropriately
 func TestDisableObjects(t *testing.T) {
 	f := newAPIFixture(t)
@@ -97,6 +97,7 @@ func ToFileWatchObjects(watchInputs WatchInputs, disableSources map[model.Manife
 					Spec: *spec.DeepCopy(),
 				}
 				fw.Spec.DisableSource = disableSources[m.Name]
+				fw.Spec.Poll = watchInputs.WatchSettings.Poll.DeepCopy()
 				result[fw.Name] = fw
 			}
 		}
@@ -123,6 +124,7 @@ func ToFileWatchObjects(watchInputs WatchInputs, disableSources map[model.Manife
 			},
 			Spec: v1alpha1.FileWatchSpec{
 				WatchedPaths: paths,
+				Poll:         watchInputs.WatchSettings.Poll.DeepCopy(),
 			},
 		}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
@@ -132,6 +134,31 @@ func TestFileWatch_IgnoreWatchSettings(t *testing.T) {
 	})
 }
 
+func TestFileWatch_PollWatchSettings(t *testing.T) {
+	f := newFWFixture(t)
+
+	target := model.LocalTarget{
+		Name: "foo",
+		Deps: []string{"."},
+	}
+	f.SetManifestLocalTarget(target)
+	f.inputs.ConfigFiles = append(f.inputs.ConfigFiles, "Tiltfile")
+
+	poll := &v1alpha1.FileWatchPoll{Interval: metav1.Duration{Duration: time.Second}}
+	f.inputs.WatchSettings.Poll = poll
+
+	f.RequireFileWatchSpecEqual(target.ID(), v1alpha1.FileWatchSpec{
+		WatchedPaths: []string{"."},
+		Poll:         poll,
+	})
+
+	id := model.TargetID{Type: model.TargetTypeConfigs, Name: model.TargetName(model.MainTiltfileManifestName)}
+	f.RequireFileWatchSpecEqual(id, v1alpha1.FileWatchSpec{
+		WatchedPaths: []string{"Tiltfile"},
+		Poll:         poll,
+	})
+}
+
 func TestFileWatch_PickUpTiltIgnoreChanges(t *testing.T) {
 	f := newFWFixture(t)
 
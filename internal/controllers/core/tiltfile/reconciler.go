@@ -394,9 +394,11 @@ func (r *Reconciler) handleLoaded(
 		Features:              tlr.FeatureFlags,
 		TeamID:                tlr.TeamID,
 		TelemetrySettings:     tlr.TelemetrySettings,
+		NotifySettings:        tlr.NotifySettings,
 		Secrets:               tlr.Secrets,
 		AnalyticsTiltfileOpt:  tlr.AnalyticsOpt,
 		DockerPruneSettings:   tlr.DockerPruneSettings,
+		RegistryPruneSettings: tlr.RegistryPruneSettings,
 		CheckpointAtExecStart: entry.CheckpointAtExecStart,
 		VersionSettings:       tlr.VersionSettings,
 		UpdateSettings:        tlr.UpdateSettings,
@@ -573,9 +575,10 @@ func (rs *runStatus) TiltfileStatus() v1alpha1.TiltfileStatus {
 		}
 		return v1alpha1.TiltfileStatus{
 			Terminated: &v1alpha1.TiltfileStateTerminated{
-				StartedAt:  apis.NewMicroTime(rs.startTime),
-				FinishedAt: apis.NewMicroTime(rs.finishTime),
-				Error:      error,
+				StartedAt:     apis.NewMicroTime(rs.startTime),
+				FinishedAt:    apis.NewMicroTime(rs.finishTime),
+				Error:         error,
+				OverridesPath: rs.tlr.OverridesPath,
 			},
 		}
 	}
@@ -225,6 +225,30 @@ func TestCluster(t *testing.T) {
 	}
 }
 
+func TestOverridesPathInStatus(t *testing.T) {
+	f := newFixture(t)
+	p := f.tempdir.JoinPath("Tiltfile")
+
+	m := manifestbuilder.New(f.tempdir, "foo").WithLocalServeCmd(".").Build()
+	f.tfl.Result = tiltfile.TiltfileLoadResult{
+		Manifests:     []model.Manifest{m},
+		OverridesPath: f.tempdir.JoinPath("Tiltfile.local"),
+	}
+
+	tf := v1alpha1.Tiltfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: model.MainTiltfileManifestName.String(),
+		},
+		Spec: v1alpha1.TiltfileSpec{
+			Path: p,
+		},
+	}
+	f.createAndWaitForLoaded(&tf)
+
+	assert.Equal(t, "", tf.Status.Terminated.Error)
+	assert.Equal(t, f.tempdir.JoinPath("Tiltfile.local"), tf.Status.Terminated.OverridesPath)
+}
+
 func TestLocalServe(t *testing.T) {
 	f := newFixture(t)
 	p := f.tempdir.JoinPath("Tiltfile")
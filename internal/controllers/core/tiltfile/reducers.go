@@ -183,9 +183,11 @@ func HandleConfigsReloaded(
 	if isMainTiltfile {
 		state.Features = event.Features
 		state.TelemetrySettings = event.TelemetrySettings
+		state.NotifySettings = event.NotifySettings
 		state.VersionSettings = event.VersionSettings
 		state.AnalyticsTiltfileOpt = event.AnalyticsTiltfileOpt
 		state.UpdateSettings = event.UpdateSettings
 		state.DockerPruneSettings = event.DockerPruneSettings
+		state.RegistryPruneSettings = event.RegistryPruneSettings
 	}
 }
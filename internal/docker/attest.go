@@ -0,0 +1,237 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/session"
+	"github.com/moby/moby/api/types/jsonstream"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+)
+
+// The exporter that writes images to the Docker daemon's image store.
+const mobyExporter = "moby"
+
+// The driver type that the daemon reports when it stores images in containerd.
+const containerdSnapshotterDriverType = "io.containerd.snapshotter.v1"
+
+// The Engine API's /build endpoint doesn't accept attestation options,
+// so builds with attestations go through the daemon's BuildKit API directly.
+//
+// To reuse Tilt's handling of build output, we re-encode the BuildKit
+// status stream in the same JSON format that /build sends.
+//
+// The moby exporter only keeps attestations in the containerd image store;
+// the classic image store silently drops them. So we refuse to build with
+// attestations on the classic store, rather than push an image without them.
+func (c *Cli) solveWithAttestations(ctx context.Context, s *session.Session, options BuildOptions) (client.ImageBuildResult, error) {
+	if options.DirSource == nil {
+		return client.ImageBuildResult{}, fmt.Errorf("Docker build attestations require a BuildKit file sync session")
+	}
+
+	info, err := c.DaemonInfo(ctx)
+	if err != nil {
+		return client.ImageBuildResult{}, errors.Wrap(err, "checking the Docker image store")
+	}
+	if !usesContainerdImageStore(info) {
+		return client.ImageBuildResult{}, fmt.Errorf("Docker build attestations require the containerd image store, " +
+			"but this Docker daemon uses the classic image store, which drops attestations. " +
+			"See https://docs.docker.com/engine/storage/containerd/")
+	}
+
+	attrs, err := solveFrontendAttrs(options)
+	if err != nil {
+		return client.ImageBuildResult{}, err
+	}
+
+	bkClient, err := bkclient.New(ctx, "",
+		bkclient.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return c.Client.DialHijack(ctx, "/grpc", "h2c", nil)
+		}),
+		bkclient.WithSessionDialer(func(ctx context.Context, proto string, meta map[string][]string) (net.Conn, error) {
+			return c.Client.DialHijack(ctx, "/session", proto, meta)
+		}))
+	if err != nil {
+		return client.ImageBuildResult{}, errors.Wrap(err, "connecting to BuildKit")
+	}
+
+	export := bkclient.ExportEntry{Type: mobyExporter, Attrs: map[string]string{}}
+	if len(options.ExtraTags) > 0 {
+		export.Attrs["name"] = strings.Join(options.ExtraTags, ",")
+	}
+
+	var cacheImports []bkclient.CacheOptionsEntry
+	for _, ref := range options.CacheFrom {
+		cacheImports = append(cacheImports, bkclient.CacheOptionsEntry{
+			Type:  "registry",
+			Attrs: map[string]string{"ref": ref},
+		})
+	}
+
+	solveOpt := bkclient.SolveOpt{
+		Frontend:              "dockerfile.v0",
+		FrontendAttrs:         attrs,
+		Exports:               []bkclient.ExportEntry{export},
+		CacheImports:          cacheImports,
+		SharedSession:         s,
+		SessionPreInitialized: true,
+	}
+
+	body := streamSolve(func(statusCh chan *bkclient.SolveStatus) (*bkclient.SolveResponse, error) {
+		defer func() {
+			_ = bkClient.Close()
+		}()
+		return bkClient.Solve(ctx, nil, solveOpt, statusCh)
+	})
+	return client.ImageBuildResult{Body: body}, nil
+}
+
+// Whether the daemon stores images in containerd, which can hold attestations.
+func usesContainerdImageStore(info system.Info) bool {
+	for _, kv := range info.DriverStatus {
+		if kv[0] == "driver-type" && kv[1] == containerdSnapshotterDriverType {
+			return true
+		}
+	}
+	return false
+}
+
+// Runs a BuildKit solve in the background, and returns its status updates
+// and result as the JSON messages that /build sends.
+//
+// The solve must close the status channel when it's done, like BuildKit's client does.
+func streamSolve(solve func(statusCh chan *bkclient.SolveStatus) (*bkclient.SolveResponse, error)) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		enc := json.NewEncoder(pw)
+		statusCh := make(chan *bkclient.SolveStatus)
+		statusDone := make(chan struct{})
+		go func() {
+			defer close(statusDone)
+			for ss := range statusCh {
+				for _, resp := range ss.Marshal() {
+					dt, err := resp.MarshalVT()
+					if err != nil {
+						continue
+					}
+					_ = writeAux(enc, "moby.buildkit.trace", dt)
+				}
+			}
+		}()
+
+		resp, err := solve(statusCh)
+		<-statusDone
+
+		if err != nil {
+			_ = enc.Encode(jsonstream.Message{
+				Error: &jsonstream.Error{Message: fmt.Sprintf("building with attestations: %v", err)},
+			})
+			_ = pw.Close()
+			return
+		}
+
+		id := resp.ExporterResponse[exptypes.ExporterImageDigestKey]
+		if id == "" {
+			id = resp.ExporterResponse[exptypes.ExporterImageConfigDigestKey]
+		}
+		if id != "" {
+			_ = writeAux(enc, "moby.image.id", map[string]string{"ID": id})
+		}
+		_ = pw.Close()
+	}()
+	return pr
+}
+
+func writeAux(enc *json.Encoder, id string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	aux := json.RawMessage(b)
+	return enc.Encode(jsonstream.Message{ID: id, Aux: &aux})
+}
+
+// Converts build options to the Dockerfile frontend's attributes.
+func solveFrontendAttrs(options BuildOptions) (map[string]string, error) {
+	attrs := map[string]string{
+		"filename": options.Dockerfile,
+	}
+	if options.Target != "" {
+		attrs["target"] = options.Target
+	}
+	if options.Platform != "" {
+		attrs["platform"] = options.Platform
+	}
+	if options.Network != "" && options.Network != "default" {
+		attrs["force-network-mode"] = options.Network
+	}
+	if options.PullParent {
+		attrs["image-resolve-mode"] = "pull"
+	}
+	if len(options.ExtraHosts) > 0 {
+		attrs["add-hosts"] = strings.Join(options.ExtraHosts, ",")
+	}
+
+	for k, v := range options.BuildArgs {
+		// Like the Docker CLI, an arg without a value reads from the environment.
+		if v == nil {
+			env, ok := os.LookupEnv(k)
+			if !ok {
+				continue
+			}
+			v = &env
+		}
+		attrs["build-arg:"+k] = *v
+	}
+
+	for k, v := range BuiltLabelSet {
+		attrs["label:"+k] = v
+	}
+
+	attests, err := ParseAttestations(options.Attestations)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range attests {
+		attrs[k] = v
+	}
+	return attrs, nil
+}
+
+// ParseAttestations converts attestations in the format of `docker buildx build --attest`
+// (e.g., "type=provenance,mode=max") to frontend attributes.
+func ParseAttestations(attests []string) (map[string]string, error) {
+	result := make(map[string]string, len(attests))
+	for _, attest := range attests {
+		var attestType string
+		var rest []string
+		for _, field := range strings.Split(attest, ",") {
+			field = strings.TrimSpace(field)
+			key, value, _ := strings.Cut(field, "=")
+			if strings.ToLower(key) == "type" {
+				attestType = value
+				continue
+			}
+			rest = append(rest, field)
+		}
+		if attestType == "" {
+			return nil, fmt.Errorf("attestation %q must have a type (e.g., type=sbom)", attest)
+		}
+
+		key := "attest:" + attestType
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("duplicate attestation type %q", attestType)
+		}
+		result[key] = strings.Join(rest, ",")
+	}
+	return result, nil
+}
@@ -0,0 +1,178 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	controlapi "github.com/moby/buildkit/api/services/control"
+	bkclient "github.com/moby/buildkit/client"
+	"github.com/moby/buildkit/exporter/containerimage/exptypes"
+	"github.com/moby/buildkit/session/filesync"
+	"github.com/moby/moby/api/types/jsonstream"
+	"github.com/moby/moby/api/types/system"
+	"github.com/moby/moby/client"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAttestations(t *testing.T) {
+	attrs, err := ParseAttestations([]string{
+		"type=sbom",
+		"type=provenance, mode=max",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"attest:sbom":       "",
+		"attest:provenance": "mode=max",
+	}, attrs)
+}
+
+func TestParseAttestationsErrors(t *testing.T) {
+	_, err := ParseAttestations([]string{"mode=max"})
+	assert.EqualError(t, err, `attestation "mode=max" must have a type (e.g., type=sbom)`)
+
+	_, err = ParseAttestations([]string{"type=sbom", "type=sbom,generator=foo"})
+	assert.EqualError(t, err, `duplicate attestation type "sbom"`)
+}
+
+func TestSolveFrontendAttrs(t *testing.T) {
+	t.Setenv("FROM_ENV", "env-value")
+	value := "value"
+	attrs, err := solveFrontendAttrs(BuildOptions{
+		Dockerfile:   "Dockerfile",
+		Target:       "dev",
+		Platform:     "linux/amd64",
+		Network:      "host",
+		PullParent:   true,
+		ExtraHosts:   []string{"a:10.0.0.1", "b:10.0.0.2"},
+		BuildArgs:    map[string]*string{"ARG": &value, "FROM_ENV": nil, "MISSING": nil},
+		Attestations: []string{"type=sbom"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"filename":                "Dockerfile",
+		"target":                  "dev",
+		"platform":                "linux/amd64",
+		"force-network-mode":      "host",
+		"image-resolve-mode":      "pull",
+		"add-hosts":               "a:10.0.0.1,b:10.0.0.2",
+		"build-arg:ARG":           "value",
+		"build-arg:FROM_ENV":      "env-value",
+		"label:" + BuiltLabel:     "true",
+		"label:" + GCEnabledLabel: "true",
+		"attest:sbom":             "",
+	}, attrs)
+}
+
+func TestStreamSolve(t *testing.T) {
+	body := streamSolve(func(statusCh chan *bkclient.SolveStatus) (*bkclient.SolveResponse, error) {
+		statusCh <- &bkclient.SolveStatus{
+			Vertexes: []*bkclient.Vertex{{Digest: digest.FromString("from"), Name: "[1/2] FROM busybox"}},
+		}
+		statusCh <- &bkclient.SolveStatus{
+			Vertexes: []*bkclient.Vertex{{Digest: digest.FromString("run"), Name: "[2/2] RUN make"}},
+		}
+		close(statusCh)
+		return &bkclient.SolveResponse{ExporterResponse: map[string]string{
+			exptypes.ExporterImageDigestKey: "sha256:1234",
+		}}, nil
+	})
+
+	msgs := readSolveMessages(t, body)
+	require.Len(t, msgs, 3)
+
+	// The status updates are all sent before the image ID.
+	assert.Equal(t, []string{"[1/2] FROM busybox"}, traceVertexNames(t, msgs[0]))
+	assert.Equal(t, []string{"[2/2] RUN make"}, traceVertexNames(t, msgs[1]))
+	assert.Equal(t, "moby.image.id", msgs[2].ID)
+	assert.JSONEq(t, `{"ID": "sha256:1234"}`, string(*msgs[2].Aux))
+}
+
+func TestStreamSolveError(t *testing.T) {
+	body := streamSolve(func(statusCh chan *bkclient.SolveStatus) (*bkclient.SolveResponse, error) {
+		close(statusCh)
+		return nil, fmt.Errorf("failed to solve: exit code 1")
+	})
+
+	msgs := readSolveMessages(t, body)
+	require.Len(t, msgs, 1)
+	require.NotNil(t, msgs[0].Error)
+	assert.Equal(t, "building with attestations: failed to solve: exit code 1", msgs[0].Error.Message)
+}
+
+func TestSolveWithAttestationsRequiresContainerdStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/info") {
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(system.Info{
+			DriverStatus: [][2]string{{"Backing Filesystem", "extfs"}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.New(client.WithHost("tcp://"+server.Listener.Addr().String()), client.WithVersion("1.47"))
+	require.NoError(t, err)
+	cli := &Cli{Client: c}
+
+	_, err = cli.solveWithAttestations(context.Background(), nil, BuildOptions{
+		Dockerfile:   "Dockerfile",
+		DirSource:    filesync.StaticDirSource{},
+		Attestations: []string{"type=sbom"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "require the containerd image store")
+}
+
+func TestUsesContainerdImageStore(t *testing.T) {
+	assert.False(t, usesContainerdImageStore(system.Info{
+		Driver:       "overlay2",
+		DriverStatus: [][2]string{{"Backing Filesystem", "extfs"}},
+	}))
+	assert.True(t, usesContainerdImageStore(system.Info{
+		Driver:       "overlayfs",
+		DriverStatus: [][2]string{{"driver-type", "io.containerd.snapshotter.v1"}},
+	}))
+}
+
+func readSolveMessages(t *testing.T, body io.ReadCloser) []jsonstream.Message {
+	t.Helper()
+	defer func() { _ = body.Close() }()
+
+	var result []jsonstream.Message
+	dec := json.NewDecoder(body)
+	for {
+		var msg jsonstream.Message
+		err := dec.Decode(&msg)
+		if err == io.EOF {
+			return result
+		}
+		require.NoError(t, err)
+		result = append(result, msg)
+	}
+}
+
+func traceVertexNames(t *testing.T, msg jsonstream.Message) []string {
+	t.Helper()
+	require.Equal(t, "moby.buildkit.trace", msg.ID)
+
+	var dt []byte
+	require.NoError(t, json.Unmarshal(*msg.Aux, &dt))
+	var resp controlapi.StatusResponse
+	require.NoError(t, resp.UnmarshalVT(dt))
+
+	var names []string
+	for _, v := range resp.Vertexes {
+		names = append(names, v.Name)
+	}
+	return names
+}
@@ -95,6 +95,10 @@ type Client interface {
 	ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error)
 	ContainerRestartNoWait(ctx context.Context, containerID string) error
 
+	// Returns a tar archive of the given path in the container.
+	// The caller must close the reader.
+	ArchiveFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, error)
+
 	Run(ctx context.Context, opts RunConfig) (RunResult, error)
 
 	// Execute a command in a container, streaming the command output to `out`.
@@ -213,6 +217,12 @@ func SupportsBuildkit(v client.ServerVersionResult, env Env) bool {
 		return false
 	}
 
+	if IsPodman(v) {
+		// Podman builds with Buildah, and its Docker-compatible API
+		// doesn't speak the Buildkit session protocol.
+		return false
+	}
+
 	version, err := semver.ParseTolerant(v.APIVersion)
 	if err != nil {
 		// If the server version doesn't parse, disable buildkit
@@ -519,11 +529,24 @@ func (c *Cli) ImageBuild(ctx context.Context, g *errgroup.Group, buildContext io
 			return client.ImageBuildResult{}, errors.Wrapf(err, "ImageBuild")
 		}
 		sessionID = oneTimeSession.ID()
+	} else if len(options.Attestations) > 0 {
+		return client.ImageBuildResult{},
+			fmt.Errorf("Docker build attestations only work on Buildkit, but Buildkit has been disabled")
 	} else if mustUseBuildkit {
 		return client.ImageBuildResult{},
 			fmt.Errorf("Docker SSH secrets only work on Buildkit, but Buildkit has been disabled")
 	}
 
+	if len(options.Attestations) > 0 {
+		response, err := c.solveWithAttestations(ctx, oneTimeSession, options)
+		if err != nil {
+			_ = oneTimeSession.Close()
+			return response, err
+		}
+		response.Body = WrapReadCloserWithTearDown(response.Body, oneTimeSession.Close)
+		return response, nil
+	}
+
 	opts := client.ImageBuildOptions{}
 	opts.Version = builderVersion
 
@@ -572,6 +595,14 @@ func (c *Cli) ImageBuild(ctx context.Context, g *errgroup.Group, buildContext io
 	return response, err
 }
 
+func (c *Cli) ArchiveFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, error) {
+	result, err := c.Client.CopyFromContainer(ctx, containerID, client.CopyFromContainerOptions{SourcePath: srcPath})
+	if err != nil {
+		return nil, err
+	}
+	return result.Content, nil
+}
+
 func (c *Cli) ContainerRestartNoWait(ctx context.Context, containerID string) error {
 
 	// Don't wait on the container to fully start.
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	typesbuild "github.com/moby/moby/api/types/build"
+	"github.com/moby/moby/api/types/system"
 	mobyclient "github.com/moby/moby/client"
 	"github.com/stretchr/testify/assert"
 
@@ -29,6 +30,8 @@ func TestSupportsBuildkit(t *testing.T) {
 		{mobyclient.ServerVersionResult{APIVersion: "1.40"}, Env{}, true},
 		{mobyclient.ServerVersionResult{APIVersion: "garbage"}, Env{}, false},
 		{mobyclient.ServerVersionResult{APIVersion: "1.39"}, Env{IsOldMinikube: true}, false},
+		{mobyclient.ServerVersionResult{APIVersion: "1.41", Platform: mobyclient.PlatformInfo{Name: "Podman Engine"}}, Env{}, false},
+		{mobyclient.ServerVersionResult{APIVersion: "1.41", Components: []system.ComponentVersion{{Name: "Podman Engine"}}}, Env{}, false},
 	}
 
 	for i, c := range cases {
@@ -157,8 +157,9 @@ func ProvideLocalEnv(
 	clusterEnv ClusterEnv,
 ) LocalEnv {
 	result := Env{}
-	client, err := creator.FromCLI(ctx)
+	client, environ, err := clientFromCLI(ctx, creator)
 	result.Client = client
+	result.Environ = environ
 	if err != nil {
 		result.Error = err
 	}
@@ -247,8 +248,9 @@ func ProvideClusterEnv(
 	}
 
 	if env.Client == nil {
-		client, err := creator.FromCLI(ctx)
+		client, environ, err := clientFromCLI(ctx, creator)
 		env.Client = client
+		env.Environ = environ
 		if err != nil {
 			env.Error = err
 		}
@@ -268,6 +270,31 @@ func ProvideClusterEnv(
 	return ClusterEnv(env)
 }
 
+// Creates a client from the Docker CLI config.
+//
+// If the Docker CLI is using the default socket, and there's no Docker daemon
+// there, we fall back to Podman's Docker-compatible socket. In that case, we also
+// return the environment variables that point subshells (like the docker CLI
+// and docker compose) at Podman.
+func clientFromCLI(ctx context.Context, creator ClientCreator) (DaemonClient, []string, error) {
+	client, err := creator.FromCLI(ctx)
+	if err != nil || client == nil {
+		return client, nil, err
+	}
+
+	host := podmanHost(client.DaemonHost(), podmanHosts(), fileExists)
+	if host == "" {
+		return client, nil, nil
+	}
+
+	podmanClient, err := creator.FromEnvMap(map[string]string{"DOCKER_HOST": host})
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to podman: %v", err)
+	}
+	logger.Get(ctx).Debugf("No Docker daemon found. Using Podman at %s", host)
+	return podmanClient, []string{"DOCKER_HOST=" + host}, nil
+}
+
 func isOldMinikube(ctx context.Context, minikubeClient k8s.MinikubeClient) bool {
 	v, err := minikubeClient.Version(ctx)
 	if err != nil {
@@ -55,6 +55,9 @@ func (c explodingClient) ContainerList(ctx context.Context, options client.Conta
 func (c explodingClient) ContainerRestartNoWait(ctx context.Context, containerID string) error {
 	return c.err
 }
+func (c explodingClient) ArchiveFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, error) {
+	return nil, c.err
+}
 func (c explodingClient) Run(ctx context.Context, opts RunConfig) (RunResult, error) {
 	return RunResult{}, c.err
 }
@@ -122,6 +122,9 @@ type FakeClient struct {
 	CopyContainer string
 	CopyContent   io.Reader
 
+	// Tar archives returned by ArchiveFromContainer, keyed by container path.
+	ContainerArchives map[string][]byte
+
 	ExecCalls         []ExecCall
 	ExecErrorsToThrow []error // next call to exec will throw ExecError[0] (which we then pop)
 
@@ -287,6 +290,14 @@ func (c *FakeClient) ContainerRestartNoWait(ctx context.Context, containerID str
 	return nil
 }
 
+func (c *FakeClient) ArchiveFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, error) {
+	archive, ok := c.ContainerArchives[srcPath]
+	if !ok {
+		return nil, fmt.Errorf("no such path in container: %s", srcPath)
+	}
+	return io.NopCloser(bytes.NewReader(archive)), nil
+}
+
 func (c *FakeClient) Run(ctx context.Context, opts RunConfig) (RunResult, error) {
 	return RunResult{}, nil
 }
@@ -22,4 +22,5 @@ type BuildOptions struct {
 	ForceLegacyBuilder bool
 	DirSource          filesync.DirSource
 	ExtraHosts         []string
+	Attestations       []string
 }
@@ -0,0 +1,89 @@
+package docker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/moby/moby/client"
+)
+
+// Podman serves a Docker-compatible API, so we talk to it with the same
+// Docker client. This file holds the shims for the places where it differs.
+
+// IsPodman determines if the server is Podman rather than Docker Engine.
+func IsPodman(v client.ServerVersionResult) bool {
+	if strings.Contains(strings.ToLower(v.Platform.Name), "podman") {
+		return true
+	}
+	for _, c := range v.Components {
+		if strings.HasPrefix(strings.ToLower(c.Name), "podman") {
+			return true
+		}
+	}
+	return false
+}
+
+// EngineName returns a human-readable name for the server's container engine.
+func EngineName(v client.ServerVersionResult) string {
+	if IsPodman(v) {
+		return "Podman"
+	}
+	return "Docker Engine"
+}
+
+// The places where Podman serves its Docker-compatible API,
+// in order of preference.
+func podmanHosts() []string {
+	if runtime.GOOS == "windows" {
+		return []string{"npipe:////./pipe/podman-machine-default"}
+	}
+
+	var result []string
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		result = append(result, "unix://"+filepath.Join(dir, "podman", "podman.sock"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		// Podman Machine on macOS.
+		result = append(result,
+			"unix://"+filepath.Join(home, ".local", "share", "containers", "podman", "machine", "podman.sock"),
+			"unix://"+filepath.Join(home, ".local", "share", "containers", "podman", "machine", "qemu", "podman.sock"))
+	}
+	return append(result, "unix:///run/podman/podman.sock")
+}
+
+// podmanHost picks a DOCKER_HOST for Podman, or returns "" if
+// we should stick with the Docker CLI's host.
+//
+// We only fall back to Podman when the Docker CLI points at the default
+// Docker socket (i.e., the user hasn't configured a DOCKER_HOST or a Docker
+// context) and nothing is listening there.
+func podmanHost(cliHost string, hosts []string, exists func(path string) bool) string {
+	defaultHost := "unix:///var/run/docker.sock"
+	if runtime.GOOS == "windows" {
+		defaultHost = "npipe:////./pipe/docker_engine"
+	}
+	if cliHost != defaultHost || exists(hostPath(cliHost)) {
+		return ""
+	}
+
+	for _, host := range hosts {
+		if exists(hostPath(host)) {
+			return host
+		}
+	}
+	return ""
+}
+
+func hostPath(host string) string {
+	if strings.HasPrefix(host, "npipe://") {
+		return filepath.FromSlash(strings.TrimPrefix(host, "npipe://"))
+	}
+	return strings.TrimPrefix(host, "unix://")
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
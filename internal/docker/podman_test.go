@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPodmanHost(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix socket paths")
+	}
+
+	hosts := []string{"unix:///run/user/1000/podman/podman.sock", "unix:///run/podman/podman.sock"}
+	existing := func(paths ...string) func(string) bool {
+		return func(path string) bool {
+			for _, p := range paths {
+				if p == path {
+					return true
+				}
+			}
+			return false
+		}
+	}
+
+	// Docker is running, so use it.
+	assert.Equal(t, "", podmanHost("unix:///var/run/docker.sock", hosts,
+		existing("/var/run/docker.sock", "/run/podman/podman.sock")))
+
+	// The user configured a different Docker host, so respect it.
+	assert.Equal(t, "", podmanHost("unix:///home/me/.colima/docker.sock", hosts,
+		existing("/run/podman/podman.sock")))
+
+	// No Docker, so fall back to the first Podman socket we find.
+	assert.Equal(t, "unix:///run/podman/podman.sock", podmanHost("unix:///var/run/docker.sock", hosts,
+		existing("/run/podman/podman.sock")))
+	assert.Equal(t, "unix:///run/user/1000/podman/podman.sock", podmanHost("unix:///var/run/docker.sock", hosts,
+		existing("/run/user/1000/podman/podman.sock", "/run/podman/podman.sock")))
+
+	// Neither is running.
+	assert.Equal(t, "", podmanHost("unix:///var/run/docker.sock", hosts, existing()))
+}
@@ -87,6 +87,9 @@ func (c *switchCli) ContainerInspect(ctx context.Context, containerID string, op
 func (c *switchCli) ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
 	return c.client(ctx).ContainerList(ctx, options)
 }
+func (c *switchCli) ArchiveFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, error) {
+	return c.client(ctx).ArchiveFromContainer(ctx, containerID, srcPath)
+}
 func (c *switchCli) ContainerRestartNoWait(ctx context.Context, containerID string) error {
 	return c.client(ctx).ContainerRestartNoWait(ctx, containerID)
 }
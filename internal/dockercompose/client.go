@@ -26,9 +26,19 @@ import (
 	compose "github.com/compose-spec/compose-go/v2/cli"
 )
 
-// versionRegex handles both v1 and v2 version outputs, which have several variations.
+// versionRegex handles both v1 and v2 version outputs, which have several variations,
+// as well as the podman-compose and nerdctl compose outputs.
 // (See TestParseComposeVersionOutput for various cases.)
-var versionRegex = regexp.MustCompile(`(?mi)^docker[ -]compose(?: version)?:? v?([^\s,]+),?(?: build ([a-z0-9-]+))?`)
+var versionRegex = regexp.MustCompile(`(?mi)^(?:docker|podman|nerdctl)[ -]compose(?: version)?:? v?([^\s,]+),?(?: build ([a-z0-9-]+))?`)
+
+// composeCommands are the compose backends we try, in order of preference,
+// if TILT_DOCKER_COMPOSE_CMD isn't set.
+var composeCommands = [][]string{
+	{"docker", "compose"},
+	{"docker-compose"},
+	{"podman", "compose"},
+	{"nerdctl", "compose"},
+}
 
 // dcProjectOptions are used when loading Docker Compose projects via the Go library.
 //
@@ -48,6 +58,7 @@ type DockerComposeClient interface {
 	Project(ctx context.Context, spec v1alpha1.DockerComposeProject) (*types.Project, error)
 	ContainerID(ctx context.Context, spec v1alpha1.DockerComposeServiceSpec) (container.ID, error)
 	Version(ctx context.Context) (canonicalVersion string, build string, err error)
+	Command(ctx context.Context) string
 }
 
 type cmdDCClient struct {
@@ -379,14 +390,25 @@ func dcExecutableVersion(environ []string) ([]string, string, string, error) {
 		return cmd, ver, build, err
 	}
 
-	cmd = []string{"docker", "compose"}
-	ver, build, err := execVersion(cmd)
-	if err != nil {
-		cmd = []string{"docker-compose"}
-		ver, build, err = execVersion(cmd)
+	var firstErr error
+	for _, cmd := range composeCommands {
+		ver, build, err := execVersion(cmd)
+		if err == nil {
+			return cmd, ver, build, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
 	}
 
-	return cmd, ver, build, err
+	// Report the error from the most common backend.
+	return composeCommands[0], "", "", firstErr
+}
+
+// Command returns the compose backend command (e.g., `docker compose` or `podman compose`).
+func (c *cmdDCClient) Command(ctx context.Context) string {
+	c.initDcCommand()
+	return strings.Join(c.composeCmd, " ")
 }
 
 func (c *cmdDCClient) initDcCommand() {
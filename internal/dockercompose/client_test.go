@@ -102,6 +102,20 @@ OpenSSL version: OpenSSL 1.1.0l  10 Sep 2019
 			// NOTE: this format is valid semver but as of v2.0.0, has not been used by Compose but is supported
 			output: []byte("Docker Compose version v2.0.0-rc.3+bu1ld-info\n"),
 		},
+		{
+			version: "v1.0.6",
+			output: []byte(`podman-compose version: 1.0.6
+['podman', '--version', '']
+using podman version: 4.9.3
+podman-compose version 1.0.6
+podman --version
+podman version 4.9.3
+`),
+		},
+		{
+			version: "v2.0.2",
+			output:  []byte("nerdctl Compose version v2.0.2\n"),
+		},
 	}
 	for _, tc := range tcs {
 		name := tc.version
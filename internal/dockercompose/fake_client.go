@@ -180,6 +180,10 @@ func (c *FakeDCClient) Version(_ context.Context) (string, string, error) {
 	return "v1.29.2", "tilt-fake", nil
 }
 
+func (c *FakeDCClient) Command(_ context.Context) string {
+	return "docker compose"
+}
+
 func (c *FakeDCClient) UpCalls() []UpCall {
 	c.mu.Lock()
 	defer c.mu.Unlock()
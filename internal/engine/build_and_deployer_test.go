@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/liveupdate"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
@@ -80,6 +81,61 @@ func TestGKEDeploy(t *testing.T) {
 	}
 }
 
+func TestGKEDeployTagPolicy(t *testing.T) {
+	f := newBDFixture(t, clusterid.ProductGKE, container.RuntimeDocker)
+
+	manifest := NewSanchoLiveUpdateManifest(f)
+	iTarget := manifest.ImageTargets[0]
+	db := iTarget.DockerBuildInfo()
+	db.TagPolicy = &v1alpha1.ImageTagPolicy{
+		Template:       "dev-{digest8}",
+		AdditionalTags: []string{"dev-latest"},
+	}
+	manifest = manifest.WithImageTarget(iTarget.WithBuildDetails(db))
+
+	targets := buildcontrol.BuildTargets(manifest)
+	_, err := f.BuildAndDeploy(targets, store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, f.docker.PushCount)
+	assert.Equal(t, "gcr.io/some-project-162817/sancho:dev-latest", f.docker.PushImage)
+	assert.Contains(t, f.k8s.Yaml, "image: gcr.io/some-project-162817/sancho:dev-11cd0b38")
+}
+
+func TestGKEDeploySignsPushedImage(t *testing.T) {
+	f := newBDFixture(t, clusterid.ProductGKE, container.RuntimeDocker)
+
+	manifest := NewSanchoLiveUpdateManifest(f)
+	iTarget := manifest.ImageTargets[0]
+	db := iTarget.DockerBuildInfo()
+	db.SigningKey = "cosign.key"
+	manifest = manifest.WithImageTarget(iTarget.WithBuildDetails(db))
+
+	targets := buildcontrol.BuildTargets(manifest)
+	_, err := f.BuildAndDeploy(targets, store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"cosign.key gcr.io/some-project-162817/sancho@" + docker.ExamplePushSHA1}, f.signer.signed)
+	assert.Contains(t, f.k8s.Yaml, "image: gcr.io/some-project-162817/sancho:tilt-11cd0b38bc3ceb95")
+}
+
+func TestGKEDeploySignFailureBlocksDeploy(t *testing.T) {
+	f := newBDFixture(t, clusterid.ProductGKE, container.RuntimeDocker)
+	f.signer.err = fmt.Errorf("no such key")
+
+	manifest := NewSanchoLiveUpdateManifest(f)
+	iTarget := manifest.ImageTargets[0]
+	db := iTarget.DockerBuildInfo()
+	db.SigningKey = "cosign.key"
+	manifest = manifest.WithImageTarget(iTarget.WithBuildDetails(db))
+
+	targets := buildcontrol.BuildTargets(manifest)
+	_, err := f.BuildAndDeploy(targets, store.BuildStateSet{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Error signing image: no such key")
+	assert.Empty(t, f.k8s.Yaml)
+}
+
 func TestYamlManifestDeploy(t *testing.T) {
 	f := newBDFixture(t, clusterid.ProductGKE, container.RuntimeDocker)
 
@@ -313,6 +369,7 @@ type bdFixture struct {
 	dcCli      *dockercompose.FakeDCClient
 	logs       *bytes.Buffer
 	ctrlClient ctrlclient.Client
+	signer     *fakeImageSigner
 }
 
 func newBDFixture(t *testing.T, env clusterid.Product, runtime container.Runtime) *bdFixture {
@@ -337,12 +394,13 @@ func newBDFixtureWithUpdateMode(t *testing.T, env clusterid.Product, runtime con
 	k8s.Runtime = runtime
 	mode := liveupdates.UpdateModeFlag(um)
 	dcc := dockercompose.NewFakeDockerComposeClient(t, ctx)
-	kl := &fakeKINDLoader{}
+	kl := &fakeImageLoader{}
+	signer := &fakeImageSigner{}
 	ctrlClient := fake.NewFakeTiltClient()
 	st := NewTestingStore(logs)
 	execer := localexec.NewFakeExecer(t)
 	bd, err := provideFakeBuildAndDeployer(ctx, dockerClient, k8s, dir, env, mode, dcc,
-		fakeClock{now: time.Unix(1551202573, 0)}, kl, ta, ctrlClient, st, execer)
+		fakeClock{now: time.Unix(1551202573, 0)}, kl, signer, build.NewFakeImageRegistry(), ta, ctrlClient, st, execer)
 	require.NoError(t, err)
 
 	ret := &bdFixture{
@@ -356,6 +414,7 @@ func newBDFixtureWithUpdateMode(t *testing.T, env clusterid.Product, runtime con
 		dcCli:          dcc,
 		logs:           logs,
 		ctrlClient:     ctrlClient,
+		signer:         signer,
 	}
 
 	t.Cleanup(ret.TearDown)
@@ -505,11 +564,21 @@ type fakeClock struct {
 
 func (c fakeClock) Now() time.Time { return c.now }
 
-type fakeKINDLoader struct {
+type fakeImageLoader struct {
 	loadCount int
 }
 
-func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+func (kl *fakeImageLoader) LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
 	kl.loadCount++
 	return nil
 }
+
+type fakeImageSigner struct {
+	signed []string
+	err    error
+}
+
+func (s *fakeImageSigner) SignImage(ctx context.Context, key string, ref reference.Named, dig digest.Digest) error {
+	s.signed = append(s.signed, fmt.Sprintf("%s %s@%s", key, ref.Name(), dig))
+	return s.err
+}
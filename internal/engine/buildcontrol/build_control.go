@@ -1,6 +1,7 @@
 package buildcontrol
 
 import (
+	"sort"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -79,13 +80,15 @@ func NextTargetToBuild(state store.EngineState) (*store.ManifestTarget, HoldSet)
 	HoldTargetsWithBuildingComponents(state, targets, holds)
 	HoldTargetsWaitingOnDependencies(state, targets, holds)
 	HoldTargetsWaitingOnCluster(state, targets, holds)
+	HoldTargetsOverLabelLimit(state, targets, holds)
 
-	// If any of the manifest targets haven't been built yet, build them now.
+	// If any of the manifest targets haven't been built yet, build them now,
+	// starting with the ones that unblock the most work.
 	targets = holds.RemoveIneligibleTargets(targets)
 	unbuilt := FindTargetsNeedingInitialBuild(targets)
 
 	if len(unbuilt) > 0 {
-		return NextUnbuiltTargetToBuild(unbuilt), holds
+		return NextUnbuiltTargetToBuild(state, unbuilt), holds
 	}
 
 	// Check to see if any targets are currently being successfully reconciled,
@@ -293,17 +296,65 @@ func HoldTargetsWaitingOnDependencies(state store.EngineState, mts []*store.Mani
 	}
 }
 
+// Hold targets with a label that already has as many updates in progress
+// as update_settings(max_parallel_updates_by_label=...) allows.
+func HoldTargetsOverLabelLimit(state store.EngineState, mts []*store.ManifestTarget, holds HoldSet) {
+	limits := state.UpdateSettings.MaxParallelUpdatesByLabel()
+	if len(limits) == 0 {
+		return
+	}
+
+	building := make(map[string][]model.TargetID)
+	for _, mt := range state.Targets() {
+		if !mt.State.IsBuilding() {
+			continue
+		}
+		for label := range mt.Manifest.Labels {
+			if _, ok := limits[label]; ok {
+				building[label] = append(building[label], mt.Manifest.ID())
+			}
+		}
+	}
+
+	for _, mt := range mts {
+		labels := make([]string, 0, len(mt.Manifest.Labels))
+		for label := range mt.Manifest.Labels {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+
+		for _, label := range labels {
+			limit, ok := limits[label]
+			if ok && len(building[label]) >= limit {
+				holds.AddHold(mt, store.Hold{
+					Reason: store.HoldReasonLabelConcurrency,
+					HoldOn: building[label],
+				})
+				break
+			}
+		}
+	}
+}
+
 // Helper function for ordering targets that have never been built before.
-func NextUnbuiltTargetToBuild(unbuilt []*store.ManifestTarget) *store.ManifestTarget {
+//
+// The builds at the start of the longest chain of resource_deps go first
+// (see buildPriorities). Ties are broken by kind, then by how many other
+// manifests share the build's images, then by the order in the Tiltfile.
+func NextUnbuiltTargetToBuild(state store.EngineState, unbuilt []*store.ManifestTarget) *store.ManifestTarget {
+	top := HighestPriorityTargets(state, unbuilt)
+
 	// Local resources come before all cluster resources, because they
 	// can't be parallelized. (LR's may change things on disk that cluster
 	// resources then pull in).
-	localTargets := FindLocalTargets(unbuilt)
+	localTargets := FindLocalTargets(top)
 	if len(localTargets) > 0 {
 		return localTargets[0]
 	}
 
-	// unresourced YAML goes next
+	// unresourced YAML goes next, even if it's not on the critical path,
+	// because it might contain namespaces or volumes that every cluster
+	// resource needs.
 	unresourced := FindUnresourcedYAML(unbuilt)
 	if unresourced != nil {
 		return unresourced
@@ -312,12 +363,12 @@ func NextUnbuiltTargetToBuild(unbuilt []*store.ManifestTarget) *store.ManifestTa
 	// If this is Kubernetes, unbuilt resources go first.
 	// (If this is Docker Compose, we want to trust the ordering
 	// that docker-compose put things in.)
-	deployOnlyK8sTargets := FindDeployOnlyK8sManifestTargets(unbuilt)
+	deployOnlyK8sTargets := FindDeployOnlyK8sManifestTargets(top)
 	if len(deployOnlyK8sTargets) > 0 {
 		return deployOnlyK8sTargets[0]
 	}
 
-	return unbuilt[0]
+	return top[0]
 }
 
 func FindUnresourcedYAML(targets []*store.ManifestTarget) *store.ManifestTarget {
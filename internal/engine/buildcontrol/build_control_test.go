@@ -278,6 +278,100 @@ func TestTwoK8sTargetsWithBaseImage(t *testing.T) {
 	f.assertNextTargetToBuild("sancho-two")
 }
 
+func TestCriticalPathBuildsFirst(t *testing.T) {
+	f := newTestFixture(t)
+
+	f.upsertK8sManifest("a")
+	b := f.upsertK8sManifest("b")
+	f.upsertK8sManifest("c", withResourceDeps("b"))
+	f.upsertK8sManifest("d", withResourceDeps("b"))
+
+	// "b" unblocks two other resources, so it goes before "a".
+	f.assertNextTargetToBuild("b")
+
+	b.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.assertNextTargetToBuild("a")
+	f.assertHold("c", store.HoldReasonWaitingForDep, b.Manifest.ID())
+}
+
+func TestCriticalPathBeforeLocalResource(t *testing.T) {
+	f := newTestFixture(t)
+
+	f.upsertK8sManifest("a")
+	lint := f.upsertLocalManifest("lint")
+	b := f.upsertK8sManifest("b")
+	f.upsertK8sManifest("c", withResourceDeps("b"))
+
+	// "lint" isn't on the critical path, so it waits for "b",
+	// even though local resources usually go first.
+	f.assertNextTargetToBuild("b")
+
+	// Among resources that unblock the same amount of work,
+	// local resources still go first.
+	b.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+	})
+	f.assertNextTargetToBuild("lint")
+
+	lint.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+	})
+	f.assertNextTargetToBuild("a")
+}
+
+func TestUnresourcedYAMLBeforeCriticalPath(t *testing.T) {
+	f := newTestFixture(t)
+
+	f.upsertK8sManifest("b")
+	f.upsertK8sManifest("c", withResourceDeps("b"))
+	f.upsertK8sManifest(model.UnresourcedYAMLManifestName)
+
+	f.assertNextTargetToBuild(model.UnresourcedYAMLManifestName)
+}
+
+func TestCriticalPathPrefersSharedImages(t *testing.T) {
+	f := newTestFixture(t)
+
+	fooImage := newDockerImageTarget("foo")
+	barImage := newDockerImageTarget("bar")
+
+	f.upsertManifest(manifestbuilder.New(f, "foo").
+		WithImageTargets(fooImage).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build())
+	f.upsertManifest(manifestbuilder.New(f, "bar-one").
+		WithImageTargets(barImage).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build())
+	f.upsertManifest(manifestbuilder.New(f, "bar-two").
+		WithImageTargets(barImage).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build())
+
+	// Building "bar-one" builds an image that "bar-two" also needs.
+	f.assertNextTargetToBuild("bar-one")
+}
+
+func TestMaxParallelUpdatesByLabel(t *testing.T) {
+	f := newTestFixture(t)
+	f.st.UpdateSettings = f.st.UpdateSettings.WithMaxParallelUpdatesByLabel(map[string]int{"db": 1})
+
+	db1 := f.upsertK8sManifest("db1")
+	db1.Manifest = db1.Manifest.WithLabels(map[string]string{"db": "db"})
+	db2 := f.upsertK8sManifest("db2")
+	db2.Manifest = db2.Manifest.WithLabels(map[string]string{"db": "db"})
+	frontend := f.upsertK8sManifest("frontend")
+	frontend.Manifest = frontend.Manifest.WithLabels(map[string]string{"web": "web"})
+
+	f.assertNextTargetToBuild("db1")
+
+	db1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.assertNextTargetToBuild("frontend")
+	f.assertHold("db2", store.HoldReasonLabelConcurrency, db1.Manifest.ID())
+}
+
 func TestLiveUpdateMainImageHold(t *testing.T) {
 	f := newTestFixture(t)
 
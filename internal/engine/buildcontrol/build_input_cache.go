@@ -0,0 +1,496 @@
+package buildcontrol
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/ignore"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The file in the Tilt dev dir where we save the build input cache.
+const buildInputCacheFile = "build_inputs.json"
+
+// When the cache gets bigger than this, we drop the least recently used entries.
+const maxBuildInputCacheEntries = 200
+
+// When we remember the digests of more files than this, we drop the files
+// that we haven't seen since Tilt started.
+const maxBuildInputFileDigests = 100000
+
+// BuildInputCache remembers which image we built from each set of build
+// inputs, across restarts of Tilt.
+//
+// On startup, every image target needs an initial build. If the target's inputs
+// (the build context, the Dockerfile, the build args, and so on) hash to an image
+// we've built before, and that image still exists, we can skip the build.
+//
+// Only docker_build() images are cached. A custom_build() can depend on
+// anything, so we can't tell when its inputs have changed.
+//
+// The cache is opt-in with update_settings(cache_build_inputs=True).
+//
+// To avoid re-reading the whole build context on every build, we remember
+// the digest of each file, and only read files whose size, mode, or
+// modification time changed since we last saw them.
+type BuildInputCache struct {
+	dir *dirs.TiltDevDir
+
+	mu      sync.Mutex
+	loaded  bool
+	entries map[string]buildInputCacheEntry
+	files   map[string]fileDigest
+
+	// Files whose digests we've used since Tilt started.
+	seenFiles map[string]bool
+}
+
+// The format of the cache file.
+type buildInputCacheContents struct {
+	Images map[string]buildInputCacheEntry `json:"images"`
+	Files  map[string]fileDigest           `json:"files"`
+}
+
+type fileDigest struct {
+	Size    int64       `json:"size"`
+	Mode    fs.FileMode `json:"mode"`
+	ModTime time.Time   `json:"modTime"`
+	Digest  string      `json:"digest"`
+}
+
+type buildInputCacheEntry struct {
+	TargetID         string    `json:"targetID"`
+	Image            string    `json:"image"`
+	ImageFromLocal   string    `json:"imageFromLocal"`
+	ImageFromCluster string    `json:"imageFromCluster"`
+	LastUsed         time.Time `json:"lastUsed"`
+}
+
+func NewBuildInputCache(dir *dirs.TiltDevDir) *BuildInputCache {
+	return &BuildInputCache{dir: dir}
+}
+
+// Checks whether an image built by an earlier run of Tilt
+// is still everywhere the cluster needs it.
+type ReuseBuiltImageChecker func(ctx context.Context, iTarget model.ImageTarget, cluster *v1alpha1.Cluster, ref reference.NamedTagged) (bool, error)
+
+// Whether the API server saves ImageMaps across restarts of Tilt
+// (with `tilt up --persist-state`).
+//
+// If so, we record the hash of each image's build inputs on its ImageMap,
+// so that the next run of Tilt can skip the build if the inputs haven't changed.
+type ImageMapsPersisted bool
+
+// ReuseInitialBuilds looks up image targets that have never been built in this
+// session, and checks whether we've already built an image from the same inputs.
+//
+// First checks the image recorded on the target's ImageMap, which is only there
+// if the ImageMap survived a restart. If useCache is true, also checks the cache
+// of every image we've built recently.
+//
+// Returns a copy of the state set, where the targets with a cached image have that
+// image as their last result (so that the TargetQueue won't rebuild them),
+// and the set of results that came from the cache.
+func (c *BuildInputCache) ReuseInitialBuilds(
+	ctx context.Context,
+	iTargets []model.ImageTarget,
+	stateSet store.BuildStateSet,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	useCache bool,
+	canReuse ReuseBuiltImageChecker,
+) (store.BuildStateSet, store.ImageBuildResultSet) {
+	cached := store.ImageBuildResultSet{}
+	if stateSet.FullBuildTriggered() {
+		return stateSet, cached
+	}
+
+	targets := make([]model.TargetSpec, 0, len(iTargets))
+	for _, iTarget := range iTargets {
+		targets = append(targets, iTarget)
+	}
+	sortedTargets, err := model.TopologicalSort(targets)
+	if err != nil {
+		return stateSet, cached
+	}
+
+	newStateSet := make(store.BuildStateSet, len(stateSet))
+	for id, state := range stateSet {
+		newStateSet[id] = state
+	}
+
+	for _, target := range sortedTargets {
+		iTarget := target.(model.ImageTarget)
+		state := newStateSet[iTarget.ID()]
+		if state.HasLastResult() || iTarget.IsLiveUpdateOnly {
+			continue
+		}
+
+		depResults, ok := lastImageResults(newStateSet, iTarget.DependencyIDs())
+		if !ok {
+			continue
+		}
+
+		hash, err := c.InputHash(ctx, iTarget, state.ClusterOrEmpty(), depResults)
+		if err != nil {
+			logger.Get(ctx).Debugf("Error hashing build inputs for %s: %v", iTarget.ID(), err)
+			continue
+		}
+
+		result, ok := lookupImageMap(iTarget, imageMaps, hash)
+		fromCache := false
+		if !ok && useCache {
+			result, ok = c.lookup(ctx, iTarget.ID(), hash)
+			fromCache = ok
+		}
+		if !ok {
+			continue
+		}
+
+		ref, err := container.ParseNamedTagged(result.ImageMapStatus.ImageFromLocal)
+		if err != nil {
+			continue
+		}
+		exists, err := canReuse(ctx, iTarget, state.ClusterOrEmpty(), ref)
+		if err != nil || !exists {
+			continue
+		}
+
+		if fromCache {
+			c.markUsed(ctx, hash)
+		}
+
+		state.LastResult = result
+		newStateSet[iTarget.ID()] = state
+		cached[iTarget.ID()] = result
+	}
+	return newStateSet, cached
+}
+
+// Returns the image recorded on the target's ImageMap,
+// if it was built from inputs with the given hash.
+func lookupImageMap(iTarget model.ImageTarget, imageMaps map[types.NamespacedName]*v1alpha1.ImageMap, hash string) (store.ImageBuildResult, bool) {
+	im, ok := imageMaps[types.NamespacedName{Name: iTarget.ImageMapName()}]
+	if hash == "" || !ok || im.Status.BuildInputHash != hash || im.Status.ImageFromLocal == "" {
+		return store.ImageBuildResult{}, false
+	}
+
+	status := *im.Status.DeepCopy()
+	now := apis.NowMicro()
+	status.BuildStartTime = &now
+	return store.NewImageBuildResultFromStatus(iTarget.ID(), status), true
+}
+
+// Collect the last image result of each dependency, or return false if
+// any of them don't have one.
+func lastImageResults(stateSet store.BuildStateSet, ids []model.TargetID) ([]store.ImageBuildResult, bool) {
+	results := make([]store.ImageBuildResult, 0, len(ids))
+	for _, id := range ids {
+		result, ok := stateSet[id].LastResult.(store.ImageBuildResult)
+		if !ok {
+			return nil, false
+		}
+		results = append(results, result)
+	}
+	return results, true
+}
+
+// InputHash hashes everything that goes into building an image.
+//
+// Returns an empty string if the image can't be cached.
+func (c *BuildInputCache) InputHash(ctx context.Context, iTarget model.ImageTarget, cluster *v1alpha1.Cluster, depResults []store.ImageBuildResult) (string, error) {
+	db, ok := iTarget.BuildDetails.(model.DockerBuild)
+	if !ok {
+		return "", nil
+	}
+
+	refs, err := iTarget.Refs(cluster)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "target %s\n", iTarget.ID())
+	fmt.Fprintf(h, "local %s\n", refs.LocalRef())
+	fmt.Fprintf(h, "cluster %s\n", refs.ClusterRef())
+
+	// The cluster determines where the image ends up (e.g., loaded into KIND
+	// vs pushed to a registry), so an image built for one cluster
+	// can't be reused on another.
+	var clusterSpec v1alpha1.ClusterSpec
+	if cluster != nil {
+		clusterSpec = cluster.Spec
+	}
+	for _, v := range []interface{}{db.DockerImageSpec, clusterSpec} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		_, _ = h.Write(b)
+		_, _ = h.Write([]byte("\n"))
+	}
+
+	for _, dep := range depResults {
+		fmt.Fprintf(h, "dep %s %s\n", dep.TargetID(), dep.ImageMapStatus.Image)
+	}
+
+	if db.Context != "" {
+		err = c.hashBuildContext(ctx, h, db.Context, ignore.CreateBuildContextFilter(db.ContextIgnores))
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Hash the path, mode, and contents of every file in the build context
+// that isn't ignored.
+func (c *BuildInputCache) hashBuildContext(ctx context.Context, w io.Writer, contextDir string, filter model.PathMatcher) error {
+	return filepath.WalkDir(contextDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path == contextDir {
+				return nil
+			}
+			skip, err := filter.MatchesEntireDir(path)
+			if err != nil {
+				return err
+			}
+			if skip {
+				return filepath.SkipDir
+			}
+		}
+
+		matches, err := filter.Matches(path)
+		if err != nil {
+			return err
+		}
+		if matches {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(contextDir, path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "file %s %s %d\n", filepath.ToSlash(rel), info.Mode(), info.Size())
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\n", target)
+		case info.Mode().IsRegular():
+			digest, err := c.fileDigest(ctx, path, info)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s\n", digest)
+		}
+		return nil
+	})
+}
+
+// Returns the digest of a file's contents, reading the file only if it
+// changed since we last computed its digest.
+func (c *BuildInputCache) fileDigest(ctx context.Context, path string, info fs.FileInfo) (string, error) {
+	c.mu.Lock()
+	c.loadLocked(ctx)
+	existing, ok := c.files[path]
+	c.mu.Unlock()
+
+	if ok && existing.Size == info.Size() && existing.Mode == info.Mode() && existing.ModTime.Equal(info.ModTime()) {
+		c.mu.Lock()
+		c.seenFiles[path] = true
+		c.mu.Unlock()
+		return existing.Digest, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+
+	digest := hex.EncodeToString(h.Sum(nil))
+	c.mu.Lock()
+	c.files[path] = fileDigest{
+		Size:    info.Size(),
+		Mode:    info.Mode(),
+		ModTime: info.ModTime(),
+		Digest:  digest,
+	}
+	c.seenFiles[path] = true
+	c.mu.Unlock()
+	return digest, nil
+}
+
+func (c *BuildInputCache) lookup(ctx context.Context, id model.TargetID, hash string) (store.ImageBuildResult, bool) {
+	if hash == "" {
+		return store.ImageBuildResult{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadLocked(ctx)
+
+	entry, ok := c.entries[hash]
+	if !ok || entry.TargetID != id.String() {
+		return store.ImageBuildResult{}, false
+	}
+
+	now := apis.NowMicro()
+	return store.NewImageBuildResultFromStatus(id, v1alpha1.ImageMapStatus{
+		Image:            entry.Image,
+		ImageFromLocal:   entry.ImageFromLocal,
+		ImageFromCluster: entry.ImageFromCluster,
+		BuildStartTime:   &now,
+		BuildInputHash:   hash,
+	}), true
+}
+
+// Store records the image built from the inputs with the given hash.
+func (c *BuildInputCache) Store(ctx context.Context, hash string, result store.ImageBuildResult) {
+	if hash == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadLocked(ctx)
+
+	c.entries[hash] = buildInputCacheEntry{
+		TargetID:         result.TargetID().String(),
+		Image:            result.ImageMapStatus.Image,
+		ImageFromLocal:   result.ImageMapStatus.ImageFromLocal,
+		ImageFromCluster: result.ImageMapStatus.ImageFromCluster,
+		LastUsed:         time.Now(),
+	}
+	c.pruneLocked()
+	c.pruneFilesLocked()
+	c.saveLocked(ctx)
+}
+
+// Bump the last use of the entry for the given hash, so that
+// pruning drops the entries we haven't reused in the longest time.
+func (c *BuildInputCache) markUsed(ctx context.Context, hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadLocked(ctx)
+
+	entry, ok := c.entries[hash]
+	if !ok {
+		return
+	}
+	entry.LastUsed = time.Now()
+	c.entries[hash] = entry
+	c.saveLocked(ctx)
+}
+
+// mu must be held by the caller.
+func (c *BuildInputCache) saveLocked(ctx context.Context) {
+	contents, err := json.Marshal(buildInputCacheContents{Images: c.entries, Files: c.files})
+	if err == nil {
+		err = c.dir.WriteFile(buildInputCacheFile, string(contents))
+	}
+	if err != nil {
+		logger.Get(ctx).Debugf("Error saving build input cache: %v", err)
+	}
+}
+
+// Read the cache from disk the first time we need it.
+//
+// mu must be held by the caller.
+func (c *BuildInputCache) loadLocked(ctx context.Context) {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	c.entries = make(map[string]buildInputCacheEntry)
+	c.files = make(map[string]fileDigest)
+	c.seenFiles = make(map[string]bool)
+
+	contents, err := c.dir.ReadFile(buildInputCacheFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Get(ctx).Debugf("Error reading build input cache: %v", err)
+		}
+		return
+	}
+
+	var decoded buildInputCacheContents
+	err = json.Unmarshal([]byte(contents), &decoded)
+	if err != nil {
+		logger.Get(ctx).Debugf("Error reading build input cache: %v", err)
+		return
+	}
+	if decoded.Images != nil {
+		c.entries = decoded.Images
+	}
+	if decoded.Files != nil {
+		c.files = decoded.Files
+	}
+}
+
+// mu must be held by the caller.
+func (c *BuildInputCache) pruneLocked() {
+	if len(c.entries) <= maxBuildInputCacheEntries {
+		return
+	}
+
+	hashes := make([]string, 0, len(c.entries))
+	for hash := range c.entries {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return c.entries[hashes[i]].LastUsed.Before(c.entries[hashes[j]].LastUsed)
+	})
+	for _, hash := range hashes[:len(hashes)-maxBuildInputCacheEntries] {
+		delete(c.entries, hash)
+	}
+}
+
+// mu must be held by the caller.
+func (c *BuildInputCache) pruneFilesLocked() {
+	if len(c.files) <= maxBuildInputFileDigests {
+		return
+	}
+	for path := range c.files {
+		if !c.seenFiles[path] {
+			delete(c.files, path)
+		}
+	}
+}
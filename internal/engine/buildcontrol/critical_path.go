@@ -0,0 +1,145 @@
+package buildcontrol
+
+import (
+	"sort"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How much downstream work is blocked on a manifest's first build.
+type buildPriority struct {
+	// The estimated cost of the longest chain of unbuilt manifests
+	// that follows resource_deps downstream from this manifest,
+	// not counting the manifest itself.
+	//
+	// We leave out the manifest's own cost so that resources that don't
+	// block anything tie, however expensive they are to build.
+	criticalPath int
+
+	// The number of unbuilt manifests that this build unblocks, either
+	// because they depend on it, or because they share one of its images.
+	unblocks int
+}
+
+// Computes the priority of every manifest's first build.
+//
+// The cost of a manifest is one unit for the deploy, plus one for each image
+// it builds. Manifests that have already started their first build cost
+// nothing, because resource_deps only block the first build.
+//
+// An unbuilt manifest always has a longer critical path than the manifests
+// that depend on it, so we never prefer a manifest over its unbuilt deps.
+func buildPriorities(state store.EngineState) map[model.ManifestName]buildPriority {
+	mts := state.Targets()
+	pending := make(map[model.ManifestName]bool, len(mts))
+	dependents := make(map[model.ManifestName][]model.ManifestName, len(mts))
+	imageUsers := make(map[model.TargetID][]model.ManifestName)
+	for _, mt := range mts {
+		mn := mt.Manifest.Name
+		pending[mn] = !mt.State.StartedFirstBuild() && mt.State.DisableState != v1alpha1.DisableStateDisabled
+		for _, dep := range mt.Manifest.ResourceDependencies {
+			dependents[dep] = append(dependents[dep], mn)
+		}
+		for _, iTarget := range mt.Manifest.ImageTargets {
+			imageUsers[iTarget.ID()] = append(imageUsers[iTarget.ID()], mn)
+		}
+	}
+
+	cost := func(mt *store.ManifestTarget) int {
+		if !pending[mt.Manifest.Name] {
+			return 0
+		}
+		return 1 + len(mt.Manifest.ImageTargets)
+	}
+
+	// Longest path to a sink, memoized. resource_deps cycles are rejected
+	// when the Tiltfile loads, but we guard against them anyway.
+	paths := make(map[model.ManifestName]int, len(mts))
+	visiting := make(map[model.ManifestName]bool)
+	var criticalPath func(mn model.ManifestName) int
+	criticalPath = func(mn model.ManifestName) int {
+		if p, ok := paths[mn]; ok {
+			return p
+		}
+		mt, ok := state.ManifestTargets[mn]
+		if !ok || visiting[mn] {
+			return 0
+		}
+
+		visiting[mn] = true
+		longest := 0
+		for _, d := range dependents[mn] {
+			if p := criticalPath(d); p > longest {
+				longest = p
+			}
+		}
+		visiting[mn] = false
+
+		paths[mn] = cost(mt) + longest
+		return paths[mn]
+	}
+
+	result := make(map[model.ManifestName]buildPriority, len(mts))
+	for _, mt := range mts {
+		mn := mt.Manifest.Name
+		unblocked := make(map[model.ManifestName]bool)
+		downstream := make(map[model.ManifestName]bool)
+		addDownstream(mn, dependents, downstream)
+		for d := range downstream {
+			if d != mn && pending[d] {
+				unblocked[d] = true
+			}
+		}
+		for _, iTarget := range mt.Manifest.ImageTargets {
+			for _, user := range imageUsers[iTarget.ID()] {
+				if user != mn && pending[user] {
+					unblocked[user] = true
+				}
+			}
+		}
+
+		result[mn] = buildPriority{
+			criticalPath: criticalPath(mn) - cost(mt),
+			unblocks:     len(unblocked),
+		}
+	}
+	return result
+}
+
+// Adds all the manifests downstream of mn to the set.
+func addDownstream(mn model.ManifestName, dependents map[model.ManifestName][]model.ManifestName, set map[model.ManifestName]bool) {
+	for _, d := range dependents[mn] {
+		if set[d] {
+			continue
+		}
+		set[d] = true
+		addDownstream(d, dependents, set)
+	}
+}
+
+// Returns the targets with the longest critical path, sorted so that the
+// ones that unblock the most other manifests come first.
+//
+// Ties keep their existing order, which is the order in the Tiltfile.
+func HighestPriorityTargets(state store.EngineState, targets []*store.ManifestTarget) []*store.ManifestTarget {
+	priorities := buildPriorities(state)
+	longest := 0
+	for _, mt := range targets {
+		if p := priorities[mt.Manifest.Name].criticalPath; p > longest {
+			longest = p
+		}
+	}
+
+	var result []*store.ManifestTarget
+	for _, mt := range targets {
+		if priorities[mt.Manifest.Name].criticalPath == longest {
+			result = append(result, mt)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return priorities[result[i].Manifest.Name].unblocks > priorities[result[j].Manifest.Name].unblocks
+	})
+	return result
+}
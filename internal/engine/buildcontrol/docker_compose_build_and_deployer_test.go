@@ -15,6 +15,7 @@ import (
 
 	"github.com/tilt-dev/wmclient/pkg/dirs"
 
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/docker"
@@ -215,7 +216,7 @@ func newDCBDFixture(t *testing.T) *dcbdFixture {
 	dCli.ImageAlwaysExists = true
 
 	clock := clockwork.NewFakeClock()
-	dcbad, err := ProvideDockerComposeBuildAndDeployer(ctx, dcCli, dCli, cdc, st, clock, dir)
+	dcbad, err := ProvideDockerComposeBuildAndDeployer(ctx, dcCli, dCli, cdc, st, clock, dir, build.NewFakeImageRegistry())
 	if err != nil {
 		t.Fatal(err)
 	}
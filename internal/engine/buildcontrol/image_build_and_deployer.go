@@ -16,6 +16,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -29,6 +30,8 @@ type ImageBuildAndDeployer struct {
 	clock      build.Clock
 	ctrlClient ctrlclient.Client
 	r          *kubernetesapply.Reconciler
+	inputCache *BuildInputCache
+	persisted  ImageMapsPersisted
 }
 
 func NewImageBuildAndDeployer(
@@ -39,6 +42,8 @@ func NewImageBuildAndDeployer(
 	c build.Clock,
 	ctrlClient ctrlclient.Client,
 	r *kubernetesapply.Reconciler,
+	inputCache *BuildInputCache,
+	persisted ImageMapsPersisted,
 ) *ImageBuildAndDeployer {
 	return &ImageBuildAndDeployer{
 		dr:         dr,
@@ -48,6 +53,8 @@ func NewImageBuildAndDeployer(
 		clock:      c,
 		ctrlClient: ctrlClient,
 		r:          r,
+		inputCache: inputCache,
+		persisted:  persisted,
 	}
 }
 
@@ -67,13 +74,40 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		})
 	}()
 
+	state := st.RLockState()
+	cacheBuildInputs := state.UpdateSettings.CacheBuildInputs()
+	st.RUnlockState()
+
+	hashBuildInputs := cacheBuildInputs || bool(ibd.persisted)
+
+	imageMapSet := make(map[types.NamespacedName]*v1alpha1.ImageMap, len(kTarget.ImageMaps))
+	for _, iTarget := range iTargets {
+		if iTarget.IsLiveUpdateOnly {
+			continue
+		}
+
+		var im v1alpha1.ImageMap
+		nn := types.NamespacedName{Name: iTarget.ImageMapName()}
+		err := ibd.ctrlClient.Get(ctx, nn, &im)
+		if err != nil {
+			return nil, err
+		}
+		imageMapSet[nn] = im.DeepCopy()
+	}
+
+	cached := store.ImageBuildResultSet{}
+	if hashBuildInputs {
+		stateSet, cached = ibd.inputCache.ReuseInitialBuilds(ctx, iTargets, stateSet, imageMapSet, cacheBuildInputs, ibd.ib.CanReuseBuiltImage)
+	}
+
 	q, err := NewImageTargetQueue(ctx, iTargets, stateSet, ibd.ib.CanReuseRef)
 	if err != nil {
 		return store.BuildResultSet{}, err
 	}
 
-	// each image target has two stages: one for build, and one for push
-	numStages := q.CountBuilds()*2 + 1
+	// each image target has two stages: one for build, and one for push,
+	// plus one more if it signs the image
+	numStages := q.CountBuilds()*2 + q.CountSignedBuilds() + 1
 
 	reused := q.ReusedResults()
 	hasReusedStep := len(reused) > 0
@@ -106,19 +140,15 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		ps.EndPipelineStep(ctx)
 	}
 
-	imageMapSet := make(map[types.NamespacedName]*v1alpha1.ImageMap, len(kTarget.ImageMaps))
 	for _, iTarget := range iTargets {
-		if iTarget.IsLiveUpdateOnly {
+		result, ok := cached[iTarget.ID()]
+		if !ok {
 			continue
 		}
-
-		var im v1alpha1.ImageMap
-		nn := types.NamespacedName{Name: iTarget.ImageMapName()}
-		err := ibd.ctrlClient.Get(ctx, nn, &im)
+		err := ibd.dr.ForceReuse(iTarget, imageMapSet, result)
 		if err != nil {
 			return nil, err
 		}
-		imageMapSet[nn] = im.DeepCopy()
 	}
 
 	err = q.RunBuilds(func(target model.TargetSpec, depResults []store.ImageBuildResult) (store.ImageBuildResult, error) {
@@ -138,10 +168,37 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		}
 
 		cluster := stateSet[target.ID()].ClusterOrEmpty()
-		return ibd.build(ctx, iTarget, cmd, cluster, imageMapSet, ps)
+		hash := ""
+		if hashBuildInputs {
+			var err error
+			hash, err = ibd.inputCache.InputHash(ctx, iTarget, cluster, depResults)
+			if err != nil {
+				logger.Get(ctx).Debugf("Error hashing build inputs for %s: %v", iTarget.ID(), err)
+			}
+		}
+
+		result, err := ibd.build(ctx, iTarget, cmd, cluster, imageMapSet, ps)
+		if err != nil || hash == "" {
+			return result, err
+		}
+
+		result.ImageMapStatus.BuildInputHash = hash
+		if im, ok := imageMapSet[types.NamespacedName{Name: iTarget.ImageMapName()}]; ok {
+			im.Status.BuildInputHash = hash
+		}
+		ibd.dr.SetBuildInputHash(iTarget, hash)
+		if cacheBuildInputs {
+			ibd.inputCache.Store(ctx, hash, result)
+		}
+		return result, nil
 	})
 
 	newResults := q.NewResults().ToBuildResultSet()
+	for id, result := range cached {
+		// Images from the cache are new to the engine, even though
+		// we didn't build them in this session.
+		newResults[id] = result
+	}
 	if err != nil {
 		return newResults, WrapDontFallBackError(err)
 	}
@@ -186,6 +243,13 @@ func (ibd *ImageBuildAndDeployer) deploy(
 
 	kTargetNN := types.NamespacedName{Name: kTargetID.Name.String()}
 	status := ibd.r.ForceApply(ctx, kTargetNN, spec, cluster, imageMaps)
+	build.TimelineFrom(ctx).Add(model.BuildStep{
+		Name:       fmt.Sprintf("Applying %s", kTargetID.Name),
+		Type:       model.BuildStepTypeApply,
+		StartTime:  status.LastApplyStartTime.Time,
+		FinishTime: status.LastApplyTime.Time,
+		Error:      status.Error,
+	})
 	if status.Error != "" {
 		return store.K8sBuildResult{}, fmt.Errorf("%s", status.Error)
 	}
@@ -3,8 +3,10 @@ package buildcontrol
 import (
 	"archive/tar"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"testing"
@@ -24,6 +26,7 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/docker"
@@ -62,6 +65,39 @@ func TestDeployTwinImages(t *testing.T) {
 		"Expected image to update twice in YAML: %s", f.k8s.Yaml)
 }
 
+func TestBuildTimeline(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	timeline := build.NewTimeline()
+	f.ctx = build.WithTimeline(f.ctx, timeline)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	var types []model.BuildStepType
+	var names []string
+	for _, step := range timeline.Steps() {
+		types = append(types, step.Type)
+		names = append(names, step.Name)
+		assert.Empty(t, step.Error)
+		assert.False(t, step.FinishTime.IsZero(), "step %q has no finish time", step.Name)
+	}
+	assert.Equal(t, []string{
+		"Building Dockerfile: [gcr.io/some-project-162817/sancho]",
+		"Pushing gcr.io/some-project-162817/sancho:tilt-11cd0b38bc3ceb95",
+		"docker push",
+		"Deploying",
+		"Applying sancho",
+	}, names)
+	assert.Equal(t, []model.BuildStepType{
+		model.BuildStepTypePipeline,
+		model.BuildStepTypePipeline,
+		model.BuildStepTypeImageStage,
+		model.BuildStepTypePipeline,
+		model.BuildStepTypeApply,
+	}, types)
+}
+
 func TestForceUpdateK8s(t *testing.T) {
 	f := newIBDFixture(t, clusterid.ProductGKE)
 
@@ -434,6 +470,20 @@ func TestKINDLoad(t *testing.T) {
 	assert.Equal(t, 0, f.docker.PushCount)
 }
 
+func TestK3DLoad(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductK3D)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, f.docker.BuildCount)
+	assert.Equal(t, 1, f.kl.loadCount)
+	assert.Equal(t, 0, f.docker.PushCount)
+}
+
 func TestDockerPushIfKINDAndClusterRef(t *testing.T) {
 	f := newIBDFixture(t, clusterid.ProductKIND)
 	f.cluster.Spec.DefaultRegistry = &v1alpha1.RegistryHosting{
@@ -1067,6 +1117,213 @@ func TestDockerForMacDeploy(t *testing.T) {
 	}
 }
 
+func TestDockerForMacSkipsSigning(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductDockerDesktop)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarget := manifest.ImageTargetAt(0)
+	db := iTarget.DockerBuildInfo()
+	db.SigningKey = "cosign.key"
+	manifest = manifest.WithImageTarget(iTarget.WithBuildDetails(db))
+
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, f.docker.PushCount)
+	assert.Contains(t, f.out.String(), "Skipping signing: image wasn't pushed to a registry")
+	assert.Contains(t, f.out.String(), "STEP 3/4")
+}
+
+func TestBuildInputCacheReusesImageOnRestart(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.useInputCacheDir(t.TempDir())
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTargetID := manifest.ImageTargetAt(0).ID()
+	result, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	require.Equal(t, 1, f.docker.BuildCount)
+
+	f.restartInputCache()
+	result2, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.docker.BuildCount, "expected image from the cache to be reused")
+	assert.Equal(t,
+		store.ClusterImageRefFromBuildResult(result[iTargetID]),
+		store.ClusterImageRefFromBuildResult(result2[iTargetID]))
+	assert.Contains(t, f.k8s.Yaml, store.ClusterImageRefFromBuildResult(result[iTargetID]))
+}
+
+func TestBuildInputCacheMissOnContextChange(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.useInputCacheDir(t.TempDir())
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	f.WriteFile("main.go", "package main")
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+}
+
+func TestBuildInputCacheMissWhenImageDeleted(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.useInputCacheDir(t.TempDir())
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	f.docker.ImageAlwaysExists = false
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+}
+
+func TestBuildInputCacheMissWhenImageNotInRegistry(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.useInputCacheDir(t.TempDir())
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTargetID := manifest.ImageTargetAt(0).ID()
+	result, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	// The image is still in the local image store, but it's been
+	// garbage-collected from the registry the cluster pulls from.
+	ref, err := container.ParseNamedTagged(result[iTargetID].(store.ImageBuildResult).ImageMapStatus.ImageFromLocal)
+	require.NoError(t, err)
+	f.registry.SetMissing(ref)
+
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+}
+
+func TestBuildInputCacheMissWhenImageLoadedIntoCluster(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductKIND)
+	f.useInputCacheDir(t.TempDir())
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	// We can't tell whether KIND still has the image, so we rebuild it.
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+	assert.Equal(t, 2, f.kl.loadCount)
+}
+
+func TestBuildInputCacheBumpsLastUsedOnReuse(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.useInputCacheDir(t.TempDir())
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	built := f.readInputCache()
+	require.Len(t, built.Images, 1)
+
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	require.Equal(t, 1, f.docker.BuildCount)
+
+	reused := f.readInputCache()
+	for hash, entry := range built.Images {
+		assert.True(t, reused.Images[hash].LastUsed.After(entry.LastUsed),
+			"expected LastUsed to move forward from %s, got %s", entry.LastUsed, reused.Images[hash].LastUsed)
+	}
+}
+
+func TestBuildInputCacheOffByDefault(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.inputCacheDir = t.TempDir()
+	f.restartInputCache()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+	assert.NoFileExists(t, filepath.Join(f.inputCacheDir, buildInputCacheFile))
+}
+
+func TestPersistedImageMapReusesImageOnRestart(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.persistImageMaps()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTargetID := manifest.ImageTargetAt(0).ID()
+	result, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	require.Equal(t, 1, f.docker.BuildCount)
+
+	var im v1alpha1.ImageMap
+	require.NoError(t, f.ctrlClient.Get(f.ctx, ktypes.NamespacedName{Name: manifest.ImageTargetAt(0).ImageMapName()}, &im))
+	assert.NotEmpty(t, im.Status.BuildInputHash)
+
+	f.restartInputCache()
+	result2, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.docker.BuildCount, "expected image from the ImageMap to be reused")
+	assert.Equal(t,
+		store.ClusterImageRefFromBuildResult(result[iTargetID]),
+		store.ClusterImageRefFromBuildResult(result2[iTargetID]))
+	assert.Contains(t, f.k8s.Yaml, store.ClusterImageRefFromBuildResult(result[iTargetID]))
+
+	// The ImageMap is enough on its own. We don't write the build input cache.
+	assert.NoFileExists(t, filepath.Join(f.inputCacheDir, buildInputCacheFile))
+}
+
+func TestPersistedImageMapMissOnContextChange(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.persistImageMaps()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	f.WriteFile("main.go", "package main")
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+}
+
+func TestBuildInputCacheOnlyReadsChangedFiles(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.useInputCacheDir(t.TempDir())
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	f.WriteFile("main.go", "package a")
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	// Change the contents, but keep the size and modification time. The file
+	// looks unchanged, so the cache uses its digest from the last build.
+	path := f.JoinPath("main.go")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	f.WriteFile("main.go", "package b")
+	require.NoError(t, os.Chtimes(path, info.ModTime(), info.ModTime()))
+
+	f.restartInputCache()
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.docker.BuildCount)
+}
+
 func resultKeys(result store.BuildResultSet) []string {
 	keys := []string{}
 	for id := range result {
@@ -1084,9 +1341,12 @@ type ibdFixture struct {
 	k8s        *k8s.FakeK8sClient
 	ibd        *ImageBuildAndDeployer
 	st         *store.TestingStore
-	kl         *fakeKINDLoader
+	kl         *fakeImageLoader
+	registry   *build.FakeImageRegistry
 	ctrlClient ctrlclient.Client
 	cluster    *v1alpha1.Cluster
+
+	inputCacheDir string
 }
 
 func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
@@ -1108,7 +1368,7 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 	ctx, _, ta := testutils.CtxAndAnalyticsForTest()
 	ctx = logger.WithLogger(ctx, logger.NewTestLogger(out))
 	kClient := k8s.NewFakeK8sClient(t)
-	kl := &fakeKINDLoader{}
+	kl := &fakeImageLoader{}
 	clock := fakeClock{time.Date(2019, 1, 1, 1, 1, 1, 1, time.UTC)}
 	kubeContext := k8s.KubeContext(fmt.Sprintf("%s-me", env))
 	clusterEnv := docker.ClusterEnv(docker.Env{})
@@ -1120,8 +1380,9 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 	ctrlClient := fake.NewFakeTiltClient()
 	st := store.NewTestingStore()
 	cclock := clockwork.NewFakeClock()
+	reg := build.NewFakeImageRegistry()
 	ibd, err := ProvideImageBuildAndDeployer(ctx, dockerClient, kClient, env, kubeContext,
-		clusterEnv, dir, clock, cclock, kl, ta, ctrlClient, st)
+		clusterEnv, dir, clock, cclock, kl, ta, ctrlClient, st, reg)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1145,6 +1406,7 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 		ibd:            ibd,
 		st:             st,
 		kl:             kl,
+		registry:       reg,
 		ctrlClient:     ctrlClient,
 		cluster:        cluster,
 	}
@@ -1152,6 +1414,37 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 	return ret
 }
 
+// Keep the build input cache outside the build context,
+// so that writing the cache doesn't change the build inputs.
+func (f *ibdFixture) useInputCacheDir(path string) {
+	f.inputCacheDir = path
+	f.restartInputCache()
+	f.st.WithState(func(state *store.EngineState) {
+		state.UpdateSettings = state.UpdateSettings.WithCacheBuildInputs(true)
+	})
+}
+
+func (f *ibdFixture) readInputCache() buildInputCacheContents {
+	f.T().Helper()
+	b, err := os.ReadFile(filepath.Join(f.inputCacheDir, buildInputCacheFile))
+	require.NoError(f.T(), err)
+	var contents buildInputCacheContents
+	require.NoError(f.T(), json.Unmarshal(b, &contents))
+	return contents
+}
+
+// Simulate `tilt up --persist-state`, where ImageMaps survive a restart.
+func (f *ibdFixture) persistImageMaps() {
+	f.inputCacheDir = f.T().TempDir()
+	f.restartInputCache()
+	f.ibd.persisted = true
+}
+
+// Simulate a restart of Tilt, by dropping the in-memory build input cache.
+func (f *ibdFixture) restartInputCache() {
+	f.ibd.inputCache = NewBuildInputCache(dirs.NewTiltDevDirAt(f.inputCacheDir))
+}
+
 func (f *ibdFixture) upsertSpec(obj ctrlclient.Object) {
 	fake.UpsertSpec(f.ctx, f.T(), f.ctrlClient, obj)
 }
@@ -1181,7 +1474,10 @@ func (f *ibdFixture) BuildAndDeploy(specs []model.TargetSpec, stateSet store.Bui
 		if ok {
 			im.Status = imageBuildResult.ImageMapStatus
 		}
-		f.updateStatus(&im)
+		if ok || !bool(f.ibd.persisted) {
+			// Persisted ImageMaps keep their status from the last run of Tilt.
+			f.updateStatus(&im)
+		}
 
 		s := stateSet[iTarget.ID()]
 		s.Cluster = f.cluster
@@ -1203,6 +1499,10 @@ func (f *ibdFixture) BuildAndDeploy(specs []model.TargetSpec, stateSet store.Bui
 			defer f.ibd.cr.Reconcile(context.Background(), ctrl.Request{NamespacedName: ktypes.NamespacedName{Name: iTarget.CmdImageName}})
 		}
 		if iTarget.DockerImageName != "" {
+			f.upsertSpec(&v1alpha1.DockerImage{
+				ObjectMeta: metav1.ObjectMeta{Name: iTarget.DockerImageName},
+				Spec:       iTarget.DockerBuildInfo().DockerImageSpec,
+			})
 			defer f.ibd.dr.Reconcile(context.Background(), ctrl.Request{NamespacedName: ktypes.NamespacedName{Name: iTarget.DockerImageName}})
 		}
 	}
@@ -1251,11 +1551,11 @@ status: {}`, name, name)
 	return model.Manifest{Name: model.ManifestName(name)}.WithDeployTarget(model.NewK8sTargetForTesting(yaml))
 }
 
-type fakeKINDLoader struct {
+type fakeImageLoader struct {
 	loadCount int
 }
 
-func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+func (kl *fakeImageLoader) LoadImage(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
 	kl.loadCount++
 	return nil
 }
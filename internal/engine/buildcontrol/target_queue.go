@@ -155,6 +155,21 @@ func (q *TargetQueue) CountBuilds() int {
 	return result
 }
 
+// Count the builds that sign their image, which adds a stage after the push.
+func (q *TargetQueue) CountSignedBuilds() int {
+	result := 0
+	for _, target := range q.sortedTargets {
+		iTarget, ok := target.(model.ImageTarget)
+		if !ok || !q.isBuilding(target.ID()) {
+			continue
+		}
+		if db, ok := iTarget.BuildDetails.(model.DockerBuild); ok && db.SigningKey != "" {
+			result++
+		}
+	}
+	return result
+}
+
 func (q *TargetQueue) backfillExistingResults() error {
 	for _, target := range q.sortedTargets {
 		id := target.ID()
@@ -30,6 +30,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/syncagent"
 	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -48,12 +49,17 @@ var BaseWireSet = wire.NewSet(
 	// BuildOrder
 	NewDockerComposeBuildAndDeployer,
 	NewImageBuildAndDeployer,
+	NewBuildInputCache,
 	NewLocalTargetBuildAndDeployer,
 	containerupdate.NewDockerUpdater,
 	containerupdate.NewExecUpdater,
+	containerupdate.NewAgentUpdater,
+	syncagent.ProvideToken,
 	build.NewImageBuilder,
 
-	tracer.InitOpenTelemetry,
+	tracer.ProvideTracerProvider,
+	tracer.ProvideTracer,
+	tracer.NewOTLPExporter,
 
 	liveupdates.ProvideUpdateMode,
 )
@@ -68,22 +74,25 @@ func ProvideImageBuildAndDeployer(
 	dir *dirs.TiltDevDir,
 	clock build.Clock,
 	clock2 clockwork.Clock,
-	kp build.KINDLoader,
+	kp build.ImageLoader,
 	analytics *analytics.TiltAnalytics,
 	ctrlclient ctrlclient.Client,
-	st store.RStore) (*ImageBuildAndDeployer, error) {
+	st store.RStore,
+	reg build.ImageRegistry) (*ImageBuildAndDeployer, error) {
 	wire.Build(
 		BaseWireSet,
 		kubernetesapply.NewReconciler,
 		dockerimage.NewReconciler,
 		cmdimage.NewReconciler,
 		cmd.NewController,
+		build.NewImageSigner,
 		localexec.EmptyEnv,
 		localexec.NewProcessExecer,
 		cmd.ProvideExecer,
 		wire.Bind(new(localexec.Execer), new(*localexec.ProcessExecer)),
 		cmd.NewFakeProberManager,
 		wire.Bind(new(cmd.ProberManager), new(*cmd.FakeProberManager)),
+		wire.Value(ImageMapsPersisted(false)),
 	)
 
 	return nil, nil
@@ -96,13 +105,15 @@ func ProvideDockerComposeBuildAndDeployer(
 	ctrlclient ctrlclient.Client,
 	st store.RStore,
 	clock clockwork.Clock,
-	dir *dirs.TiltDevDir) (*DockerComposeBuildAndDeployer, error) {
+	dir *dirs.TiltDevDir,
+	reg build.ImageRegistry) (*DockerComposeBuildAndDeployer, error) {
 	wire.Build(
 		BaseWireSet,
 		dockercomposeservice.WireSet,
 		model.ProvideStartTime,
 		build.ProvideClock,
-		build.NewKINDLoader,
+		build.NewImageLoader,
+		build.NewImageSigner,
 		dockerimage.NewReconciler,
 		cmdimage.NewReconciler,
 		cmd.NewController,
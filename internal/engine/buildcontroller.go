@@ -10,6 +10,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/uibutton"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -39,6 +40,9 @@ type buildEntry struct {
 	filesChanged  []string
 	buildReason   model.BuildReason
 	spanID        logstore.SpanID
+
+	// The time of the earliest change that this build picks up, if any.
+	queuedSince time.Time
 }
 
 func (e buildEntry) Name() model.ManifestName       { return e.name }
@@ -77,6 +81,7 @@ func (c *BuildController) needsBuild(ctx context.Context, st store.RStore) (buil
 	manifest := mt.Manifest
 
 	buildReason := mt.NextBuildReason()
+	_, queuedSince := ms.HasPendingChanges()
 	targets := buildcontrol.BuildTargets(manifest)
 	buildStateSet := buildStateSet(ctx,
 		manifest,
@@ -94,6 +99,7 @@ func (c *BuildController) needsBuild(ctx context.Context, st store.RStore) (buil
 		buildStateSet: buildStateSet,
 		filesChanged:  append(ms.ConfigFilesThatCausedChange, buildStateSet.FilesChanged()...),
 		spanID:        SpanIDForBuildLog(c.buildsStartedCount),
+		queuedSince:   queuedSince,
 	}, true
 }
 
@@ -116,9 +122,20 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 		return nil
 	}
 
+	startTime := time.Now()
+	timeline := build.NewTimeline()
+	if !entry.queuedSince.IsZero() && entry.queuedSince.Before(startTime) {
+		timeline.Add(model.BuildStep{
+			Name:       "Queued",
+			Type:       model.BuildStepTypeQueue,
+			StartTime:  entry.queuedSince,
+			FinishTime: startTime,
+		})
+	}
+
 	st.Dispatch(buildcontrols.BuildStartedAction{
 		ManifestName:       entry.name,
-		StartTime:          time.Now(),
+		StartTime:          startTime,
 		FilesChanged:       entry.filesChanged,
 		Reason:             entry.buildReason,
 		SpanID:             entry.spanID,
@@ -128,6 +145,7 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 
 	go func() {
 		ctx = c.buildContext(ctx, entry, st)
+		ctx = build.WithTimeline(ctx, timeline)
 		defer c.cleanupBuildContext(entry.name)
 
 		buildcontrols.LogBuildEntry(ctx, buildcontrols.BuildEntry{
@@ -140,7 +158,9 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 		if ctx.Err() == context.Canceled {
 			err = errors.New("build canceled")
 		}
-		st.Dispatch(buildcontrols.NewBuildCompleteAction(entry.name, BuildControlSource, entry.spanID, result, err))
+		action := buildcontrols.NewBuildCompleteAction(entry.name, BuildControlSource, entry.spanID, result, err)
+		action.Steps = timeline.Steps()
+		st.Dispatch(action)
 	}()
 
 	return nil
@@ -55,6 +55,39 @@ func TestBuildControllerLocalResource(t *testing.T) {
 	f.assertAllBuildsConsumed()
 }
 
+func TestBuildControllerRecordsQueueTime(t *testing.T) {
+	t.Parallel()
+	f := newTestFixture(t)
+
+	dep := f.JoinPath("stuff.json")
+	manifest := manifestbuilder.New(f, "local").
+		WithLocalResource("echo beep boop", []string{dep}).
+		Build()
+	f.Start([]model.Manifest{manifest})
+	f.nextCallComplete()
+
+	f.fsWatcher.Events <- watch.NewFileEvent(dep)
+	f.nextCallComplete()
+
+	f.WaitUntilManifestState("build with file change completed", "local", func(ms store.ManifestState) bool {
+		return len(ms.BuildHistory) == 2
+	})
+
+	state := f.store.RLockState()
+	lastBuild := state.ManifestTargets["local"].State.LastBuild()
+	f.store.RUnlockState()
+
+	require.NotEmpty(t, lastBuild.Steps)
+	queued := lastBuild.Steps[0]
+	assert.Equal(t, model.BuildStepTypeQueue, queued.Type)
+	assert.Equal(t, lastBuild.StartTime, queued.FinishTime)
+	assert.False(t, queued.StartTime.After(queued.FinishTime))
+
+	err := f.Stop()
+	assert.NoError(t, err)
+	f.assertAllBuildsConsumed()
+}
+
 func TestBuildControllerManualTriggerBuildReasonInit(t *testing.T) {
 	t.Parallel()
 	for _, tc := range []struct {
@@ -0,0 +1,349 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// How long we wait for a webhook or command before giving up.
+const notifyTimeout = 30 * time.Second
+
+type resourceState int
+
+const (
+	resourceStatePending resourceState = iota
+	resourceStateReady
+	resourceStateError
+)
+
+// Event is the payload of a notification.
+//
+// Webhooks receive it as JSON. The Text field duplicates the Message so that
+// Slack-compatible incoming webhooks render it without any extra config.
+type Event struct {
+	Event    model.NotifyEvent `json:"event"`
+	Resource string            `json:"resource,omitempty"`
+	Message  string            `json:"message"`
+	Text     string            `json:"text"`
+	Time     time.Time         `json:"time"`
+}
+
+// Controller watches UIResources and fires the notifiers from the Tiltfile's
+// notify() calls when a resource goes into an error state, recovers,
+// or when all resources become ready.
+type Controller struct {
+	client *http.Client
+	queue  sendQueue
+
+	settings  model.NotifySettings
+	resources resourceSnapshots
+
+	// Whether each notifier's resources were all ready at the last change,
+	// indexed by notifier.
+	allReady []bool
+}
+
+func NewController() *Controller {
+	return &Controller{
+		client: &http.Client{Timeout: notifyTimeout},
+	}
+}
+
+func (c *Controller) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	// The notifiers only change on a Tiltfile load, which is a legacy action.
+	if !summary.Legacy && summary.UIResources.Empty() {
+		return nil
+	}
+
+	now := time.Now()
+	state := st.RLockState()
+	settings := state.NotifySettings
+
+	// When the notifiers change, use the current state as the baseline,
+	// so that a Tiltfile reload doesn't re-send old news.
+	notifiersChanged := !reflect.DeepEqual(settings.Notifiers, c.settings.Notifiers)
+	var events []Event
+	if notifiersChanged || c.resources == nil {
+		c.resources = currentResources(state)
+	} else {
+		events = c.resources.update(state, summary, now)
+	}
+	st.RUnlockState()
+
+	if notifiersChanged {
+		c.settings = settings
+		c.allReady = make([]bool, len(settings.Notifiers))
+		for i, n := range settings.Notifiers {
+			c.allReady[i] = allReady(n, c.resources)
+		}
+		return nil
+	}
+
+	for i, n := range settings.Notifiers {
+		var toSend []Event
+		for _, e := range events {
+			if n.HasEvent(e.Event) && n.MatchesResource(model.ManifestName(e.Resource)) {
+				toSend = append(toSend, e)
+			}
+		}
+
+		ready := allReady(n, c.resources)
+		if ready && !c.allReady[i] && n.HasEvent(model.NotifyEventAllReady) {
+			toSend = append(toSend, newEvent(model.NotifyEventAllReady, "", "All resources are ready", now))
+		}
+		c.allReady[i] = ready
+
+		if len(toSend) > 0 {
+			c.send(ctx, st, n, toSend)
+		}
+	}
+
+	return nil
+}
+
+// The status of a resource, as far as notifications are concerned.
+type resourceSnapshot struct {
+	state resourceState
+	err   string
+}
+
+// The status of each enabled resource, indexed by name.
+type resourceSnapshots map[string]resourceSnapshot
+
+// Reads the status of all enabled resources.
+func currentResources(state store.EngineState) resourceSnapshots {
+	result := make(resourceSnapshots, len(state.UIResources))
+	for name, r := range state.UIResources {
+		if r.Status.DisableStatus.State == v1alpha1.DisableStateDisabled {
+			continue
+		}
+		result[name] = snapshot(r)
+	}
+	return result
+}
+
+func snapshot(r *v1alpha1.UIResource) resourceSnapshot {
+	result := resourceSnapshot{state: classify(r)}
+	if result.state == resourceStateError {
+		result.err = errorMessage(r)
+	}
+	return result
+}
+
+// Re-reads the status of the resources named in the change summary,
+// and returns the error and recovered events, sorted by resource name.
+//
+// A legacy change doesn't say which resources changed, so we re-read them all.
+func (s resourceSnapshots) update(state store.EngineState, summary store.ChangeSummary, now time.Time) []Event {
+	var names []string
+	if summary.Legacy {
+		for name := range state.UIResources {
+			names = append(names, name)
+		}
+		for name := range s {
+			if _, ok := state.UIResources[name]; !ok {
+				names = append(names, name)
+			}
+		}
+	} else {
+		for nn := range summary.UIResources.Changes {
+			names = append(names, nn.Name)
+		}
+	}
+	sort.Strings(names)
+
+	var events []Event
+	for _, name := range names {
+		before := s[name].state
+		r, ok := state.UIResources[name]
+		if !ok || r.Status.DisableStatus.State == v1alpha1.DisableStateDisabled {
+			delete(s, name)
+			continue
+		}
+
+		after := snapshot(r)
+		s[name] = after
+		if before != resourceStateError && after.state == resourceStateError {
+			msg := fmt.Sprintf("Resource %s failed: %s", name, after.err)
+			events = append(events, newEvent(model.NotifyEventError, name, msg, now))
+		} else if before == resourceStateError && after.state == resourceStateReady {
+			msg := fmt.Sprintf("Resource %s recovered", name)
+			events = append(events, newEvent(model.NotifyEventRecovered, name, msg, now))
+		}
+	}
+	return events
+}
+
+func newEvent(event model.NotifyEvent, resource string, msg string, t time.Time) Event {
+	return Event{
+		Event:    event,
+		Resource: resource,
+		Message:  msg,
+		Text:     msg,
+		Time:     t,
+	}
+}
+
+func classify(r *v1alpha1.UIResource) resourceState {
+	runtime := r.Status.RuntimeStatus
+	update := r.Status.UpdateStatus
+	if runtime == v1alpha1.RuntimeStatusError || update == v1alpha1.UpdateStatusError {
+		return resourceStateError
+	}
+	if runtime == v1alpha1.RuntimeStatusOK ||
+		(runtime == v1alpha1.RuntimeStatusNotApplicable && update == v1alpha1.UpdateStatusOK) {
+		return resourceStateReady
+	}
+	return resourceStatePending
+}
+
+func errorMessage(r *v1alpha1.UIResource) string {
+	if r.Status.UpdateStatus == v1alpha1.UpdateStatusError &&
+		len(r.Status.BuildHistory) > 0 && r.Status.BuildHistory[0].Error != "" {
+		return r.Status.BuildHistory[0].Error
+	}
+	if r.Status.UpdateStatus == v1alpha1.UpdateStatusError {
+		return "update failed"
+	}
+	return "runtime error"
+}
+
+// Whether all the resources that this notifier watches are ready.
+// A notifier that doesn't watch any resources is never ready.
+func allReady(n model.Notifier, resources resourceSnapshots) bool {
+	count := 0
+	for name, r := range resources {
+		if !n.MatchesResource(model.ManifestName(name)) {
+			continue
+		}
+		if r.state != resourceStateReady {
+			return false
+		}
+		count++
+	}
+	return count > 0
+}
+
+// Queues the notifications to send in the background, so that a slow webhook
+// doesn't block the store.
+func (c *Controller) send(ctx context.Context, st store.RStore, n model.Notifier, events []Event) {
+	c.queue.add(func() {
+		for _, e := range events {
+			err := c.sendOne(ctx, n, e)
+			if err != nil && ctx.Err() == nil {
+				logError(st, fmt.Errorf("Error sending %s notification: %v", e.Event, err))
+			}
+		}
+	})
+}
+
+func (c *Controller) sendOne(ctx context.Context, n model.Notifier, e Event) error {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	if n.URL != "" {
+		return c.postWebhook(ctx, n, e)
+	}
+	return runCmd(ctx, n.Cmd, e)
+}
+
+func (c *Controller) postWebhook(ctx context.Context, n model.Notifier, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range n.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("POST %s: %s", n.URL, resp.Status)
+	}
+	return nil
+}
+
+func runCmd(ctx context.Context, cmd model.Cmd, e Event) error {
+	c := exec.CommandContext(ctx, cmd.Argv[0], cmd.Argv[1:]...)
+	c.Dir = cmd.Dir
+	c.Env = append(os.Environ(), cmd.Env...)
+	c.Env = append(c.Env,
+		fmt.Sprintf("TILT_NOTIFY_EVENT=%s", e.Event),
+		fmt.Sprintf("TILT_NOTIFY_RESOURCE=%s", e.Resource),
+		fmt.Sprintf("TILT_NOTIFY_MESSAGE=%s", e.Message))
+
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v\noutput: %s", err, out)
+	}
+	return nil
+}
+
+func logError(st store.RStore, err error) {
+	st.Dispatch(store.NewLogAction(model.MainTiltfileManifestName, "notify", logger.InfoLvl, nil, []byte(err.Error())))
+}
+
+// Runs the queued sends one at a time, in the order they were queued,
+// so that notifications arrive in the order that the changes happened.
+//
+// The worker exits when the queue is empty, and starts again on the next add.
+type sendQueue struct {
+	mu      sync.Mutex
+	pending []func()
+	running bool
+}
+
+func (q *sendQueue) add(send func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, send)
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+}
+
+func (q *sendQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		send := q.pending[0]
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		send()
+	}
+}
+
+var _ store.Subscriber = &Controller{}
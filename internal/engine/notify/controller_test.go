@@ -0,0 +1,292 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestErrorAndRecovered(t *testing.T) {
+	f := newFixture(t)
+	f.setNotifiers(model.Notifier{Events: model.AllNotifyEvents, URL: f.server.URL})
+	f.setResource("fe", v1alpha1.RuntimeStatusPending, v1alpha1.UpdateStatusInProgress)
+
+	f.setResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusError)
+	f.assertEvents(
+		Event{Event: model.NotifyEventError, Resource: "fe", Message: "Resource fe failed: oh noes"},
+	)
+
+	f.setResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK)
+	f.assertEvents(
+		Event{Event: model.NotifyEventError, Resource: "fe", Message: "Resource fe failed: oh noes"},
+		Event{Event: model.NotifyEventRecovered, Resource: "fe", Message: "Resource fe recovered"},
+		Event{Event: model.NotifyEventAllReady, Message: "All resources are ready"},
+	)
+}
+
+func TestAllReady(t *testing.T) {
+	f := newFixture(t)
+	f.setNotifiers(model.Notifier{Events: []model.NotifyEvent{model.NotifyEventAllReady}, URL: f.server.URL})
+	f.setResource("fe", v1alpha1.RuntimeStatusPending, v1alpha1.UpdateStatusInProgress)
+	f.setResource("be", v1alpha1.RuntimeStatusPending, v1alpha1.UpdateStatusInProgress)
+
+	f.setResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK)
+	f.assertEvents()
+
+	f.setResource("be", v1alpha1.RuntimeStatusNotApplicable, v1alpha1.UpdateStatusOK)
+	f.assertEvents(Event{Event: model.NotifyEventAllReady, Message: "All resources are ready"})
+
+	// Doesn't fire again until something goes un-ready.
+	f.setResource("be", v1alpha1.RuntimeStatusNotApplicable, v1alpha1.UpdateStatusOK)
+	f.assertEvents(Event{Event: model.NotifyEventAllReady, Message: "All resources are ready"})
+}
+
+func TestResourceFilter(t *testing.T) {
+	f := newFixture(t)
+	f.setNotifiers(model.Notifier{
+		Events:    []model.NotifyEvent{model.NotifyEventError},
+		Resources: []model.ManifestName{"fe"},
+		URL:       f.server.URL,
+	})
+	f.setResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK)
+	f.setResource("be", v1alpha1.RuntimeStatusPending, v1alpha1.UpdateStatusInProgress)
+
+	f.setResource("be", v1alpha1.RuntimeStatusError, v1alpha1.UpdateStatusOK)
+	f.assertEvents()
+
+	f.setResource("fe", v1alpha1.RuntimeStatusError, v1alpha1.UpdateStatusOK)
+	f.assertEvents(Event{Event: model.NotifyEventError, Resource: "fe", Message: "Resource fe failed: runtime error"})
+}
+
+func TestDisabledResourceIgnored(t *testing.T) {
+	f := newFixture(t)
+	f.setNotifiers(model.Notifier{Events: model.AllNotifyEvents, URL: f.server.URL})
+	f.setResource("be", v1alpha1.RuntimeStatusPending, v1alpha1.UpdateStatusNone)
+	f.setResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK)
+	f.assertEvents()
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.UIResources["be"].Status.DisableStatus.State = v1alpha1.DisableStateDisabled
+	})
+	f.onChange()
+	f.assertEvents(Event{Event: model.NotifyEventAllReady, Message: "All resources are ready"})
+}
+
+func TestNoReplayOnSettingsChange(t *testing.T) {
+	f := newFixture(t)
+	f.setResource("fe", v1alpha1.RuntimeStatusError, v1alpha1.UpdateStatusOK)
+	f.setNotifiers(model.Notifier{Events: model.AllNotifyEvents, URL: f.server.URL})
+	f.onChange()
+	f.assertEvents()
+}
+
+func TestWebhookHeaders(t *testing.T) {
+	f := newFixture(t)
+	f.setNotifiers(model.Notifier{
+		Events:  model.AllNotifyEvents,
+		URL:     f.server.URL,
+		Headers: map[string]string{"Authorization": "Bearer abc"},
+	})
+	f.setResource("fe", v1alpha1.RuntimeStatusError, v1alpha1.UpdateStatusOK)
+
+	f.assertEvents(Event{Event: model.NotifyEventError, Resource: "fe", Message: "Resource fe failed: runtime error"})
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.Equal(t, "Bearer abc", f.headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", f.headers[0].Get("Content-Type"))
+}
+
+func TestWebhookFailureLogged(t *testing.T) {
+	f := newFixture(t)
+	f.status = http.StatusInternalServerError
+	f.setNotifiers(model.Notifier{Events: model.AllNotifyEvents, URL: f.server.URL})
+	f.setResource("fe", v1alpha1.RuntimeStatusError, v1alpha1.UpdateStatusOK)
+
+	require.Eventually(t, func() bool {
+		for _, a := range f.st.Actions() {
+			la, ok := a.(store.LogAction)
+			if ok && string(la.Message()) == "Error sending error notification: POST "+f.server.URL+": 500 Internal Server Error" {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a unix shell")
+	}
+	f := newFixture(t)
+	out := filepath.Join(t.TempDir(), "out.txt")
+	f.setNotifiers(model.Notifier{
+		Events: model.AllNotifyEvents,
+		Cmd:    model.ToUnixCmd(`echo "$TILT_NOTIFY_EVENT $TILT_NOTIFY_RESOURCE $TILT_NOTIFY_MESSAGE" > ` + out),
+	})
+	f.setResource("fe", v1alpha1.RuntimeStatusError, v1alpha1.UpdateStatusOK)
+
+	require.Eventually(t, func() bool {
+		contents, _ := os.ReadFile(out)
+		return string(contents) == "error fe Resource fe failed: runtime error\n"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestOnlyChangedResourcesRead(t *testing.T) {
+	f := newFixture(t)
+	f.setNotifiers(model.Notifier{Events: []model.NotifyEvent{model.NotifyEventError}, URL: f.server.URL})
+	f.setResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK)
+	f.setResource("be", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK)
+
+	// A change summary that doesn't name "be" doesn't look at it.
+	f.st.WithState(func(state *store.EngineState) {
+		state.UIResources["be"].Status.RuntimeStatus = v1alpha1.RuntimeStatusError
+	})
+	f.onResourcesChange("fe")
+	f.assertEvents()
+
+	f.onResourcesChange("be")
+	f.assertEvents(Event{Event: model.NotifyEventError, Resource: "be", Message: "Resource be failed: runtime error"})
+}
+
+func TestEventsSentInOrder(t *testing.T) {
+	f := newFixture(t)
+	f.delay = map[model.NotifyEvent]time.Duration{model.NotifyEventError: 100 * time.Millisecond}
+	f.setNotifiers(model.Notifier{Events: model.AllNotifyEvents, URL: f.server.URL})
+	f.setResource("fe", v1alpha1.RuntimeStatusPending, v1alpha1.UpdateStatusInProgress)
+
+	// The recovery is queued behind the slow error webhook.
+	f.setResource("fe", v1alpha1.RuntimeStatusError, v1alpha1.UpdateStatusOK)
+	f.setResource("fe", v1alpha1.RuntimeStatusOK, v1alpha1.UpdateStatusOK)
+	f.assertEvents(
+		Event{Event: model.NotifyEventError, Resource: "fe", Message: "Resource fe failed: runtime error"},
+		Event{Event: model.NotifyEventRecovered, Resource: "fe", Message: "Resource fe recovered"},
+		Event{Event: model.NotifyEventAllReady, Message: "All resources are ready"},
+	)
+}
+
+type fixture struct {
+	t      *testing.T
+	ctx    context.Context
+	st     *store.TestingStore
+	c      *Controller
+	server *httptest.Server
+
+	mu      sync.Mutex
+	status  int
+	delay   map[model.NotifyEvent]time.Duration
+	events  []Event
+	headers []http.Header
+}
+
+func newFixture(t *testing.T) *fixture {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	f := &fixture{
+		t:      t,
+		ctx:    ctx,
+		st:     store.NewTestingStore(),
+		c:      NewController(),
+		status: http.StatusOK,
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		err := json.NewDecoder(r.Body).Decode(&e)
+		assert.NoError(t, err)
+
+		f.mu.Lock()
+		delay := f.delay[e.Event]
+		f.mu.Unlock()
+		time.Sleep(delay)
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.events = append(f.events, e)
+		f.headers = append(f.headers, r.Header)
+		w.WriteHeader(f.status)
+	}))
+	t.Cleanup(f.server.Close)
+
+	f.st.WithState(func(state *store.EngineState) {
+		state.UIResources = make(map[string]*v1alpha1.UIResource)
+	})
+	return f
+}
+
+func (f *fixture) onChange() {
+	err := f.c.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+	require.NoError(f.t, err)
+}
+
+// Notifies the controller that only the named resources changed.
+func (f *fixture) onResourcesChange(names ...string) {
+	summary := store.ChangeSummary{}
+	for _, name := range names {
+		summary.UIResources.Add(types.NamespacedName{Name: name})
+	}
+	err := f.c.OnChange(f.ctx, f.st, summary)
+	require.NoError(f.t, err)
+}
+
+func (f *fixture) setNotifiers(notifiers ...model.Notifier) {
+	f.st.WithState(func(state *store.EngineState) {
+		state.NotifySettings = model.NotifySettings{Notifiers: notifiers}
+	})
+	f.onChange()
+}
+
+func (f *fixture) setResource(name string, runtime v1alpha1.RuntimeStatus, update v1alpha1.UpdateStatus) {
+	f.st.WithState(func(state *store.EngineState) {
+		r := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		r.Status.RuntimeStatus = runtime
+		r.Status.UpdateStatus = update
+		if update == v1alpha1.UpdateStatusError {
+			r.Status.BuildHistory = []v1alpha1.UIBuildTerminated{{Error: "oh noes"}}
+		}
+		state.UIResources[name] = r
+	})
+	f.onResourcesChange(name)
+}
+
+// Waits for the webhook to receive exactly the given events, in order.
+func (f *fixture) assertEvents(expected ...Event) {
+	f.t.Helper()
+
+	var actual []Event
+	assert.Eventually(f.t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		actual = nil
+		for _, e := range f.events {
+			actual = append(actual, Event{Event: e.Event, Resource: e.Resource, Message: e.Message})
+		}
+		return len(actual) >= len(expected)
+	}, time.Second, 10*time.Millisecond)
+
+	// Give any unexpected events a chance to arrive.
+	time.Sleep(20 * time.Millisecond)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	actual = nil
+	for _, e := range f.events {
+		assert.Equal(f.t, e.Message, e.Text)
+		actual = append(actual, Event{Event: e.Event, Resource: e.Resource, Message: e.Message})
+	}
+	assert.Equal(f.t, expected, actual)
+}
@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// DesktopFlag is true if the user passed --notify to tilt up.
+type DesktopFlag bool
+
+// DesktopNotifier shows a notification on the user's desktop.
+type DesktopNotifier interface {
+	Notify(ctx context.Context, summary, body string) error
+}
+
+// DesktopController shows a desktop notification when a resource fails or
+// recovers, so that you notice even when Tilt is running in a background
+// terminal.
+//
+// Enabled with tilt up --notify, or desktop_notify() in the Tiltfile.
+type DesktopController struct {
+	flag     DesktopFlag
+	notifier DesktopNotifier
+	queue    sendQueue
+
+	started   bool
+	resources resourceSnapshots
+
+	// We only warn once if we can't show notifications, rather than on every failure.
+	warnOnce sync.Once
+}
+
+func NewDesktopController(flag DesktopFlag) *DesktopController {
+	return &DesktopController{
+		flag:     flag,
+		notifier: NewDBusNotifier(),
+	}
+}
+
+func (c *DesktopController) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	// The Tiltfile settings only change on a Tiltfile load, which is a legacy action.
+	if !summary.Legacy && summary.UIResources.Empty() {
+		return nil
+	}
+
+	now := time.Now()
+	state := st.RLockState()
+	settings := state.NotifySettings.Desktop
+	enabled := bool(c.flag) || settings.Enabled
+	var events []Event
+	if !enabled {
+		c.started = false
+		c.resources = nil
+	} else if !c.started {
+		// When notifications are first enabled, use the current state as the baseline.
+		c.started = true
+		c.resources = currentResources(state)
+	} else {
+		events = c.resources.update(state, summary, now)
+	}
+	st.RUnlockState()
+
+	if len(events) > 0 {
+		c.send(ctx, st, settings.FallbackCmd, events)
+	}
+	return nil
+}
+
+// Queues the notifications to show in the background, in order.
+func (c *DesktopController) send(ctx context.Context, st store.RStore, fallback model.Cmd, events []Event) {
+	c.queue.add(func() {
+		for _, e := range events {
+			err := c.sendOne(ctx, fallback, e)
+			if err != nil && ctx.Err() == nil {
+				c.warnOnce.Do(func() {
+					logError(st, fmt.Errorf("Error showing desktop notification: %v\n"+
+						"To use a different notifier, see desktop_notify(fallback_cmd=...) in the Tiltfile API", err))
+				})
+			}
+		}
+	})
+}
+
+func (c *DesktopController) sendOne(ctx context.Context, fallback model.Cmd, e Event) error {
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+
+	err := c.notifier.Notify(ctx, fmt.Sprintf("Tilt: %s", e.Resource), e.Message)
+	if err != nil && !fallback.Empty() {
+		return runCmd(ctx, fallback, e)
+	}
+	return err
+}
+
+// DBusNotifier sends notifications to the freedesktop.org notification
+// service over the D-Bus session bus.
+//
+// https://specifications.freedesktop.org/notification-spec/latest/protocol.html
+type DBusNotifier struct {
+	// Connects to the session bus. Replaced in tests with a private bus.
+	connect func(ctx context.Context) (*dbus.Conn, error)
+}
+
+func NewDBusNotifier() DBusNotifier {
+	return DBusNotifier{
+		connect: func(ctx context.Context) (*dbus.Conn, error) {
+			return dbus.ConnectSessionBus(dbus.WithContext(ctx))
+		},
+	}
+}
+
+const (
+	dbusNotificationsName   = "org.freedesktop.Notifications"
+	dbusNotificationsPath   = dbus.ObjectPath("/org/freedesktop/Notifications")
+	dbusNotificationsMethod = "org.freedesktop.Notifications.Notify"
+)
+
+func (n DBusNotifier) Notify(ctx context.Context, summary, body string) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("D-Bus notifications are only supported on Linux")
+	}
+
+	conn, err := n.connect(ctx)
+	if err != nil {
+		return fmt.Errorf("connecting to the D-Bus session bus: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	call := conn.Object(dbusNotificationsName, dbusNotificationsPath).CallWithContext(ctx,
+		dbusNotificationsMethod, 0,
+		"Tilt",    // app_name
+		uint32(0), // replaces_id
+		"",        // app_icon
+		summary,
+		body,
+		[]string{},                // actions
+		map[string]dbus.Variant{}, // hints
+		int32(-1),                 // expire_timeout: use the server default
+	)
+	if call.Err != nil {
+		return fmt.Errorf("calling %s: %v", dbusNotificationsName, call.Err)
+	}
+	return nil
+}
+
+var _ store.Subscriber = &DesktopController{}
+var _ DesktopNotifier = DBusNotifier{}
@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBusNotifier(t *testing.T) {
+	addr := startPrivateBus(t)
+
+	server := &fakeNotificationServer{}
+	conn, err := dbus.Connect(addr)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	require.NoError(t, conn.Export(server, dbusNotificationsPath, dbusNotificationsName))
+	reply, err := conn.RequestName(dbusNotificationsName, dbus.NameFlagDoNotQueue)
+	require.NoError(t, err)
+	require.Equal(t, dbus.RequestNameReplyPrimaryOwner, reply)
+
+	n := privateBusNotifier(addr)
+	err = n.Notify(context.Background(), "Tilt: fe", "Resource fe failed: can't find 'main'\nexit 1")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"Tilt | Tilt: fe | Resource fe failed: can't find 'main'\nexit 1 | -1",
+	}, server.calls())
+}
+
+func TestDBusNotifierNoServer(t *testing.T) {
+	addr := startPrivateBus(t)
+
+	n := privateBusNotifier(addr)
+	err := n.Notify(context.Background(), "Tilt: fe", "Resource fe failed")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "calling org.freedesktop.Notifications")
+}
+
+func privateBusNotifier(addr string) DBusNotifier {
+	return DBusNotifier{
+		connect: func(ctx context.Context) (*dbus.Conn, error) {
+			return dbus.Connect(addr, dbus.WithContext(ctx))
+		},
+	}
+}
+
+type fakeNotificationServer struct {
+	mu       sync.Mutex
+	received []string
+}
+
+func (s *fakeNotificationServer) Notify(appName string, replacesID uint32, appIcon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = append(s.received, fmt.Sprintf("%s | %s | %s | %d", appName, summary, body, timeout))
+	return uint32(len(s.received)), nil
+}
+
+func (s *fakeNotificationServer) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.received...)
+}
+
+const privateBusConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:path=%s</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// Starts a D-Bus daemon that only this test talks to, and returns its address.
+func startPrivateBus(t *testing.T) string {
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "bus.conf")
+	require.NoError(t, os.WriteFile(config,
+		[]byte(fmt.Sprintf(privateBusConfig, filepath.Join(dir, "bus"))), 0600))
+
+	cmd := exec.Command(daemon, "--config-file", config, "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	return strings.TrimSpace(addr)
+}
@@ -0,0 +1,174 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestDesktopDisabledByDefault(t *testing.T) {
+	f := newDesktopFixture(t, false)
+	f.setResource("fe", v1alpha1.RuntimeStatusOK)
+	f.setResource("fe", v1alpha1.RuntimeStatusError)
+	f.assertNotifications()
+}
+
+func TestDesktopFlag(t *testing.T) {
+	f := newDesktopFixture(t, true)
+	f.setResource("fe", v1alpha1.RuntimeStatusOK)
+	f.setResource("fe", v1alpha1.RuntimeStatusError)
+	f.assertNotifications("Tilt: fe | Resource fe failed: runtime error")
+
+	f.setResource("fe", v1alpha1.RuntimeStatusOK)
+	f.assertNotifications(
+		"Tilt: fe | Resource fe failed: runtime error",
+		"Tilt: fe | Resource fe recovered")
+}
+
+func TestDesktopTiltfileSetting(t *testing.T) {
+	f := newDesktopFixture(t, false)
+	f.setResource("fe", v1alpha1.RuntimeStatusError)
+	f.setDesktopSettings(model.DesktopNotifySettings{Enabled: true})
+
+	// The error happened before notifications were enabled.
+	f.assertNotifications()
+
+	f.setResource("fe", v1alpha1.RuntimeStatusOK)
+	f.assertNotifications("Tilt: fe | Resource fe recovered")
+}
+
+func TestDesktopFallbackCmd(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a unix shell")
+	}
+	f := newDesktopFixture(t, true)
+	f.notifier.err = fmt.Errorf("no session bus")
+	out := filepath.Join(t.TempDir(), "out.txt")
+	f.setDesktopSettings(model.DesktopNotifySettings{
+		FallbackCmd: model.ToUnixCmd(`echo "$TILT_NOTIFY_EVENT $TILT_NOTIFY_MESSAGE" > ` + out),
+	})
+	f.setResource("fe", v1alpha1.RuntimeStatusError)
+
+	require.Eventually(t, func() bool {
+		contents, _ := os.ReadFile(out)
+		return string(contents) == "error Resource fe failed: runtime error\n"
+	}, time.Second, 10*time.Millisecond)
+	f.st.AssertNoErrorActions(t)
+}
+
+func TestDesktopErrorLoggedOnce(t *testing.T) {
+	f := newDesktopFixture(t, true)
+	f.notifier.err = fmt.Errorf("no session bus")
+	f.setResource("fe", v1alpha1.RuntimeStatusError)
+	f.assertNotifications("Tilt: fe | Resource fe failed: runtime error")
+	f.setResource("be", v1alpha1.RuntimeStatusError)
+	f.assertNotifications(
+		"Tilt: fe | Resource fe failed: runtime error",
+		"Tilt: be | Resource be failed: runtime error")
+
+	count := 0
+	for _, a := range f.st.Actions() {
+		if _, ok := a.(store.LogAction); ok {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+}
+
+type fakeDesktopNotifier struct {
+	mu            sync.Mutex
+	err           error
+	notifications []string
+}
+
+func (n *fakeDesktopNotifier) Notify(ctx context.Context, summary, body string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, summary+" | "+body)
+	return n.err
+}
+
+type desktopFixture struct {
+	t        *testing.T
+	ctx      context.Context
+	st       *store.TestingStore
+	c        *DesktopController
+	notifier *fakeDesktopNotifier
+}
+
+func newDesktopFixture(t *testing.T, flag bool) *desktopFixture {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	notifier := &fakeDesktopNotifier{}
+	c := NewDesktopController(DesktopFlag(flag))
+	c.notifier = notifier
+
+	f := &desktopFixture{
+		t:        t,
+		ctx:      ctx,
+		st:       store.NewTestingStore(),
+		c:        c,
+		notifier: notifier,
+	}
+	f.st.WithState(func(state *store.EngineState) {
+		state.UIResources = make(map[string]*v1alpha1.UIResource)
+	})
+	f.onChange()
+	return f
+}
+
+func (f *desktopFixture) onChange() {
+	err := f.c.OnChange(f.ctx, f.st, store.LegacyChangeSummary())
+	require.NoError(f.t, err)
+}
+
+func (f *desktopFixture) setDesktopSettings(settings model.DesktopNotifySettings) {
+	f.st.WithState(func(state *store.EngineState) {
+		state.NotifySettings.Desktop = settings
+	})
+	f.onChange()
+}
+
+func (f *desktopFixture) setResource(name string, status v1alpha1.RuntimeStatus) {
+	f.st.WithState(func(state *store.EngineState) {
+		r := &v1alpha1.UIResource{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		r.Status.RuntimeStatus = status
+		r.Status.UpdateStatus = v1alpha1.UpdateStatusOK
+		state.UIResources[name] = r
+	})
+	err := f.c.OnChange(f.ctx, f.st, store.ChangeSummary{
+		UIResources: store.NewChangeSet(types.NamespacedName{Name: name}),
+	})
+	require.NoError(f.t, err)
+}
+
+func (f *desktopFixture) assertNotifications(expected ...string) {
+	f.t.Helper()
+
+	assert.Eventually(f.t, func() bool {
+		f.notifier.mu.Lock()
+		defer f.notifier.mu.Unlock()
+		return len(f.notifier.notifications) >= len(expected)
+	}, time.Second, 10*time.Millisecond)
+
+	// Give any unexpected notifications a chance to arrive.
+	time.Sleep(20 * time.Millisecond)
+	f.notifier.mu.Lock()
+	defer f.notifier.mu.Unlock()
+	assert.Equal(f.t, expected, f.notifier.notifications)
+}
@@ -0,0 +1,345 @@
+package registryprune
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/distribution/reference"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/registry"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// RegistryPruner deletes images that Tilt pushed to remote registries.
+//
+// An image is eligible if its tag is one that Tilt generates for each build
+// (with Tilt's tag prefix, or from an image_tag_policy() template with a digest),
+// and its config has the GC label that Tilt adds to every image it builds.
+// We never delete a manifest that's also referenced by a tag that isn't eligible.
+//
+// Templates without a digest (e.g., additional_tags=['{git_branch}-latest'])
+// render the same tag for many builds, so they could match tags that Tilt
+// didn't push. A tag like that is only deleted along with an eligible
+// build that it points to.
+type RegistryPruner struct {
+	client *registry.Client
+
+	disabledForTesting bool
+
+	lastPruneTime time.Time
+}
+
+var _ store.Subscriber = &RegistryPruner{}
+
+func NewRegistryPruner(client *registry.Client) *RegistryPruner {
+	return &RegistryPruner{client: client}
+}
+
+func (rp *RegistryPruner) DisabledForTesting(disabled bool) {
+	rp.disabledForTesting = disabled
+}
+
+// OnChange prunes the registries of the images in the Tiltfile,
+// if registry_prune_settings() enabled it and it's been long enough.
+func (rp *RegistryPruner) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if rp.disabledForTesting || summary.IsLogOnly() {
+		return nil
+	}
+
+	state := st.RLockState()
+	settings := state.RegistryPruneSettings
+	if !settings.Enabled || len(state.CurrentBuildSet) > 0 || !state.HasBuild() || buildcontrol.NextManifestNameToBuild(state) != "" {
+		st.RUnlockState()
+		return nil
+	}
+
+	// Wait until we've pushed SOMETHING, then prune on the interval.
+	interval := settings.Interval
+	if interval == 0 {
+		interval = model.RegistryPruneDefaultInterval
+	}
+	shouldPrune := state.CompletedBuildCount > 0 && time.Since(rp.lastPruneTime) >= interval
+	if !shouldPrune {
+		st.RUnlockState()
+		return nil
+	}
+
+	imgSelectors := model.LocalRefSelectorsForManifests(state.Manifests(), state.Clusters)
+	tagPolicy := TagPolicyForManifests(state.Manifests())
+	st.RUnlockState()
+
+	rp.Prune(ctx, settings.MaxAge, settings.KeepRecent, ReposForSelectors(imgSelectors), tagPolicy, false)
+	rp.lastPruneTime = time.Now()
+	return nil
+}
+
+// ReposForSelectors returns the registry repositories for a set of images.
+//
+// Skips images on Docker Hub, which doesn't support deletes through the registry API.
+func ReposForSelectors(selectors []container.RefSelector) []reference.Named {
+	seen := make(map[string]bool)
+	var result []reference.Named
+	for _, sel := range selectors {
+		repo := sel.AsNamedOnly()
+		if reference.Domain(repo) == "docker.io" || seen[repo.Name()] {
+			continue
+		}
+		seen[repo.Name()] = true
+		result = append(result, repo)
+	}
+	return result
+}
+
+// TagPolicyForManifests returns the image_tag_policy() for a set of manifests,
+// or nil if there isn't one.
+//
+// The Tiltfile only allows one policy, so every image built with a policy has the same one.
+func TagPolicyForManifests(manifests []model.Manifest) *v1alpha1.ImageTagPolicy {
+	for _, m := range manifests {
+		for _, iTarg := range m.ImageTargets {
+			if iTarg.IsDockerBuild() && iTarg.DockerBuildInfo().TagPolicy != nil {
+				return iTarg.DockerBuildInfo().TagPolicy
+			}
+			if iTarg.IsCustomBuild() && iTarg.CustomBuildInfo().TagPolicy != nil {
+				return iTarg.CustomBuildInfo().TagPolicy
+			}
+		}
+	}
+	return nil
+}
+
+// Prune deletes eligible images older than maxAge, except for the keepRecent
+// most recent builds of each image. In a dry run, it only logs what it would delete.
+//
+// Returns the refs deleted (or that would be deleted).
+func (rp *RegistryPruner) Prune(ctx context.Context, maxAge time.Duration, keepRecent int, repos []reference.Named,
+	tagPolicy *v1alpha1.ImageTagPolicy, dryRun bool) []string {
+	l := logger.Get(ctx)
+	policyTags, err := newPolicyTagMatcher(tagPolicy)
+	if err != nil {
+		l.Infof("[Registry Prune] error reading image_tag_policy: %v", err)
+		return nil
+	}
+
+	var result []string
+	for _, repo := range repos {
+		deleted, err := rp.pruneRepo(ctx, repo, maxAge, keepRecent, policyTags, dryRun)
+		result = append(result, deleted...)
+		if err != nil {
+			l.Infof("[Registry Prune] error pruning %s: %v", reference.FamiliarName(repo), err)
+		}
+	}
+
+	if len(result) == 0 {
+		l.Debugf("[Registry Prune] no images to prune")
+	}
+	return result
+}
+
+// A tag in a registry repo.
+type remoteTag struct {
+	tag     string
+	digest  string
+	created time.Time
+}
+
+func (rp *RegistryPruner) pruneRepo(ctx context.Context, repo reference.Named, maxAge time.Duration, keepRecent int,
+	policyTags policyTagMatcher, dryRun bool) ([]string, error) {
+	l := logger.Get(ctx)
+	tags, err := rp.client.Tags(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Digests that we must not delete, because deleting a manifest
+	// deletes every tag that points to it.
+	protected := make(map[string]bool)
+
+	// Eligible tags, grouped by the image name embedded in the tag (if any),
+	// for when images share a single repo with default_registry(single_name=...).
+	candidates := make(map[string][]remoteTag)
+
+	// Tags from templates without a digest, indexed by the digest they point to.
+	moving := make(map[string][]string)
+	for _, tag := range tags {
+		group, ok := tiltTagGroup(tag, policyTags.build)
+		if !ok {
+			dig, err := rp.client.ManifestDigest(ctx, repo, tag)
+			if err != nil {
+				return nil, err
+			}
+			if matchesTagTemplate(tag, policyTags.moving) {
+				moving[dig] = append(moving[dig], tag)
+			} else {
+				protected[dig] = true
+			}
+			continue
+		}
+
+		m, err := rp.client.Manifest(ctx, repo, tag)
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := rp.client.ImageConfig(ctx, repo, m.ConfigDigest)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Labels[docker.GCEnabledLabel] != "true" {
+			protected[m.Digest] = true
+			continue
+		}
+		candidates[group] = append(candidates[group], remoteTag{tag: tag, digest: m.Digest, created: cfg.Created})
+	}
+
+	// One build can have several tags (e.g., additional_tags in the tag policy),
+	// so keepRecent counts digests rather than tags.
+	var toDelete []remoteTag
+	candidateDigests := make(map[string]bool)
+	for _, list := range candidates {
+		sort.SliceStable(list, func(i, j int) bool {
+			return list[i].created.After(list[j].created)
+		})
+		kept := make(map[string]bool)
+		for _, t := range list {
+			candidateDigests[t.digest] = true
+			if kept[t.digest] {
+				continue
+			}
+			if len(kept) < keepRecent || time.Since(t.created) < maxAge {
+				kept[t.digest] = true
+				protected[t.digest] = true
+				continue
+			}
+			toDelete = append(toDelete, t)
+		}
+	}
+	sort.SliceStable(toDelete, func(i, j int) bool {
+		return toDelete[i].created.Before(toDelete[j].created)
+	})
+
+	// A moving tag that doesn't point to one of Tilt's builds might not be Tilt's.
+	for dig := range moving {
+		if !candidateDigests[dig] {
+			protected[dig] = true
+		}
+	}
+
+	var deleted []string
+	deletedDigests := make(map[string]bool)
+	for _, t := range toDelete {
+		ref := reference.FamiliarName(repo) + ":" + t.tag
+		if protected[t.digest] {
+			l.Debugf("[Registry Prune] skipping %s: its digest is shared with a tag we're keeping", ref)
+			continue
+		}
+
+		// Deleting the manifest also deletes any moving tags that point to it.
+		refs := []string{ref}
+		if !deletedDigests[t.digest] {
+			for _, tag := range moving[t.digest] {
+				refs = append(refs, reference.FamiliarName(repo)+":"+tag)
+			}
+		}
+
+		age := time.Since(t.created).Round(time.Minute)
+		if dryRun {
+			for _, ref := range refs {
+				l.Infof("[Registry Prune] would delete %s (%s old)", ref, age)
+			}
+			deleted = append(deleted, refs...)
+			deletedDigests[t.digest] = true
+			continue
+		}
+
+		if !deletedDigests[t.digest] {
+			err := rp.client.DeleteManifest(ctx, repo, t.digest)
+			if err != nil {
+				return deleted, err
+			}
+			deletedDigests[t.digest] = true
+		}
+		for _, ref := range refs {
+			l.Infof("[Registry Prune] deleted %s (%s old)", ref, age)
+		}
+		deleted = append(deleted, refs...)
+	}
+	return deleted, nil
+}
+
+// Returns the group key of a tag that Tilt generated, and false
+// if Tilt didn't generate it.
+//
+// Tilt tags are "tilt-<digest>", or "<image name>-tilt-<digest>"
+// when images share a single repo. With an image_tag_policy(), they're
+// rendered from the policy's templates with a digest (also with the image name in front
+// when images share a single repo). We can't reliably split the image name
+// off a templated tag, so all templated tags in a repo share a group.
+func tiltTagGroup(tag string, policyTags []*regexp.Regexp) (string, bool) {
+	if strings.HasPrefix(tag, build.ImageTagPrefix) {
+		return "", true
+	}
+	i := strings.LastIndex(tag, "-"+build.ImageTagPrefix)
+	if i > 0 {
+		return tag[:i], true
+	}
+
+	if matchesTagTemplate(tag, policyTags) {
+		return "", true
+	}
+	return "", false
+}
+
+// Whether the tag was rendered from one of the templates,
+// with or without an image name in front.
+func matchesTagTemplate(tag string, templates []*regexp.Regexp) bool {
+	for _, re := range templates {
+		if re.MatchString(tag) {
+			return true
+		}
+		for j, c := range tag {
+			if c == '-' && re.MatchString(tag[j+1:]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Matches the tags rendered from an image_tag_policy().
+type policyTagMatcher struct {
+	// Templates with a digest, which render a new tag for each build.
+	build []*regexp.Regexp
+
+	// Templates without a digest, which render the same tag for many builds.
+	moving []*regexp.Regexp
+}
+
+func newPolicyTagMatcher(policy *v1alpha1.ImageTagPolicy) (policyTagMatcher, error) {
+	if policy == nil || policy.Template == "" {
+		return policyTagMatcher{}, nil
+	}
+
+	var result policyTagMatcher
+	for _, tmpl := range append([]string{policy.Template}, policy.AdditionalTags...) {
+		re, err := container.TagTemplateRegexp(tmpl)
+		if err != nil {
+			return policyTagMatcher{}, err
+		}
+		if container.ValidateTagTemplate(tmpl, true) == nil {
+			result.build = append(result.build, re)
+		} else {
+			result.moving = append(result.moving, re)
+		}
+	}
+	return result, nil
+}
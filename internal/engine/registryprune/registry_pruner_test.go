@@ -0,0 +1,226 @@
+package registryprune
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/registry"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+var gcLabels = map[string]string{docker.GCEnabledLabel: "true"}
+
+func TestPruneOldTiltImages(t *testing.T) {
+	f := newFixture(t)
+	old := f.reg.PushImage("app", "tilt-0001", daysAgo(10), gcLabels)
+	f.reg.PushImage("app", "tilt-0002", daysAgo(9), gcLabels)
+	f.reg.PushImage("app", "tilt-0003", daysAgo(1), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 1, false)
+	assert.Equal(t, []string{f.ref("app:tilt-0001"), f.ref("app:tilt-0002")}, deleted)
+	assert.Equal(t, []string{"tilt-0003"}, f.reg.Tags("app"))
+	assert.Contains(t, f.reg.Deleted(), old)
+}
+
+func TestPruneKeepRecent(t *testing.T) {
+	f := newFixture(t)
+	f.reg.PushImage("app", "tilt-0001", daysAgo(10), gcLabels)
+	f.reg.PushImage("app", "tilt-0002", daysAgo(9), gcLabels)
+	f.reg.PushImage("app", "tilt-0003", daysAgo(8), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 2, false)
+	assert.Equal(t, []string{f.ref("app:tilt-0001")}, deleted)
+	assert.Equal(t, []string{"tilt-0002", "tilt-0003"}, f.reg.Tags("app"))
+}
+
+func TestPruneDryRun(t *testing.T) {
+	f := newFixture(t)
+	f.reg.PushImage("app", "tilt-0001", daysAgo(10), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 0, true)
+	assert.Equal(t, []string{f.ref("app:tilt-0001")}, deleted)
+	assert.Equal(t, []string{"tilt-0001"}, f.reg.Tags("app"))
+	assert.Empty(t, f.reg.Deleted())
+	assert.Contains(t, f.out.String(), "would delete")
+}
+
+func TestPruneSkipsImagesTiltDidNotBuild(t *testing.T) {
+	f := newFixture(t)
+	f.reg.PushImage("app", "tilt-0001", daysAgo(10), nil)
+	f.reg.PushImage("app", "v1.2.3", daysAgo(10), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 0, false)
+	assert.Empty(t, deleted)
+	assert.Equal(t, []string{"tilt-0001", "v1.2.3"}, f.reg.Tags("app"))
+}
+
+func TestPruneSkipsDigestSharedWithOtherTag(t *testing.T) {
+	f := newFixture(t)
+	dig := f.reg.PushImage("app", "tilt-0001", daysAgo(10), gcLabels)
+	f.reg.Tag("app", "main-latest", dig)
+
+	deleted := f.prune(7*24*time.Hour, 0, false)
+	assert.Empty(t, deleted)
+	assert.Equal(t, []string{"main-latest", "tilt-0001"}, f.reg.Tags("app"))
+}
+
+func TestPruneSingleNameKeepsRecentPerImage(t *testing.T) {
+	f := newFixture(t)
+	f.reg.PushImage("dev", "frontend-tilt-0001", daysAgo(10), gcLabels)
+	f.reg.PushImage("dev", "frontend-tilt-0002", daysAgo(9), gcLabels)
+	f.reg.PushImage("dev", "backend-tilt-0001", daysAgo(10), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 1, false)
+	assert.Equal(t, []string{f.ref("dev:frontend-tilt-0001")}, deleted)
+	assert.Equal(t, []string{"backend-tilt-0001", "frontend-tilt-0002"}, f.reg.Tags("dev"))
+}
+
+var branchTagPolicy = &v1alpha1.ImageTagPolicy{
+	Template:       "{git_branch}-{digest8}",
+	AdditionalTags: []string{"{git_branch}-latest"},
+}
+
+func TestPruneTemplatedTags(t *testing.T) {
+	f := newFixture(t)
+	f.tagPolicy = branchTagPolicy
+	f.reg.PushImage("app", "main-0001aaaa", daysAgo(10), gcLabels)
+	dig := f.reg.PushImage("app", "main-0002bbbb", daysAgo(9), gcLabels)
+	f.reg.Tag("app", "main-latest", dig)
+	f.reg.PushImage("app", "main-0003cccc", daysAgo(1), gcLabels)
+	f.reg.PushImage("app", "v1.2.3", daysAgo(10), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 0, false)
+	assert.ElementsMatch(t, []string{
+		f.ref("app:main-0001aaaa"),
+		f.ref("app:main-0002bbbb"),
+		f.ref("app:main-latest"),
+	}, deleted)
+	assert.Equal(t, []string{"main-0003cccc", "v1.2.3"}, f.reg.Tags("app"))
+}
+
+func TestPruneTemplatedTagsWithoutPolicy(t *testing.T) {
+	f := newFixture(t)
+	f.reg.PushImage("app", "main-0001aaaa", daysAgo(10), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 0, false)
+	assert.Empty(t, deleted)
+}
+
+func TestPruneKeepRecentCountsBuildsNotTags(t *testing.T) {
+	f := newFixture(t)
+	f.tagPolicy = branchTagPolicy
+	f.reg.PushImage("app", "main-0001aaaa", daysAgo(10), gcLabels)
+	f.reg.PushImage("app", "main-0002bbbb", daysAgo(9), gcLabels)
+	dig := f.reg.PushImage("app", "main-0003cccc", daysAgo(8), gcLabels)
+	f.reg.Tag("app", "main-latest", dig)
+
+	deleted := f.prune(7*24*time.Hour, 2, false)
+	assert.Equal(t, []string{f.ref("app:main-0001aaaa")}, deleted)
+	assert.Equal(t, []string{"main-0002bbbb", "main-0003cccc", "main-latest"}, f.reg.Tags("app"))
+}
+
+func TestPruneMovingTagOnlyWithItsBuild(t *testing.T) {
+	f := newFixture(t)
+	f.tagPolicy = &v1alpha1.ImageTagPolicy{
+		Template:       "{git_branch}-{digest8}",
+		AdditionalTags: []string{"{git_branch}"},
+	}
+	dig := f.reg.PushImage("app", "main-0001aaaa", daysAgo(10), gcLabels)
+	f.reg.Tag("app", "main", dig)
+	f.reg.PushImage("app", "main-0002bbbb", daysAgo(1), gcLabels)
+
+	// Matches the {git_branch} template, but Tilt didn't push it.
+	f.reg.PushImage("app", "v1.2.3", daysAgo(10), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 0, false)
+	assert.Equal(t, []string{f.ref("app:main-0001aaaa"), f.ref("app:main")}, deleted)
+	assert.Equal(t, []string{"main-0002bbbb", "v1.2.3"}, f.reg.Tags("app"))
+}
+
+func TestPruneMovingTagDryRun(t *testing.T) {
+	f := newFixture(t)
+	f.tagPolicy = branchTagPolicy
+	dig := f.reg.PushImage("app", "main-0001aaaa", daysAgo(10), gcLabels)
+	f.reg.Tag("app", "main-latest", dig)
+
+	deleted := f.prune(7*24*time.Hour, 0, true)
+	assert.Equal(t, []string{f.ref("app:main-0001aaaa"), f.ref("app:main-latest")}, deleted)
+	assert.Equal(t, []string{"main-0001aaaa", "main-latest"}, f.reg.Tags("app"))
+}
+
+func TestPruneSingleNameTemplatedTags(t *testing.T) {
+	f := newFixture(t)
+	f.tagPolicy = &v1alpha1.ImageTagPolicy{Template: "v-{digest8}"}
+	f.reg.PushImage("dev", "frontend-v-0001aaaa", daysAgo(10), gcLabels)
+	f.reg.PushImage("dev", "frontend-v-0002bbbb", daysAgo(1), gcLabels)
+
+	deleted := f.prune(7*24*time.Hour, 0, false)
+	assert.Equal(t, []string{f.ref("dev:frontend-v-0001aaaa")}, deleted)
+}
+
+func TestReposForSelectors(t *testing.T) {
+	repos := ReposForSelectors([]container.RefSelector{
+		container.MustParseSelector("localhost:5000/app"),
+		container.MustParseSelector("localhost:5000/app"),
+		container.MustParseSelector("gcr.io/project/api"),
+		container.MustParseSelector("frontend"),
+	})
+
+	var names []string
+	for _, r := range repos {
+		names = append(names, r.String())
+	}
+	assert.Equal(t, []string{"localhost:5000/app", "gcr.io/project/api"}, names)
+}
+
+type fixture struct {
+	t   *testing.T
+	ctx context.Context
+	out *bytes.Buffer
+	reg *registry.FakeRegistry
+	rp  *RegistryPruner
+
+	tagPolicy *v1alpha1.ImageTagPolicy
+}
+
+func newFixture(t *testing.T) *fixture {
+	reg := registry.NewFakeRegistry()
+	t.Cleanup(reg.Close)
+
+	out := new(bytes.Buffer)
+	ctx, _, _ := testutils.ForkedCtxAndAnalyticsForTest(out)
+	client := registry.NewClient(func(host string) registry.Credentials { return registry.Credentials{} })
+	return &fixture{
+		t:   t,
+		ctx: ctx,
+		out: out,
+		reg: reg,
+		rp:  NewRegistryPruner(client),
+	}
+}
+
+func (f *fixture) prune(maxAge time.Duration, keepRecent int, dryRun bool) []string {
+	var repos []reference.Named
+	for _, name := range []string{"app", "dev"} {
+		if len(f.reg.Tags(name)) > 0 {
+			repos = append(repos, container.MustParseNamed(f.reg.Host()+"/"+name))
+		}
+	}
+	return f.rp.Prune(f.ctx, maxAge, keepRecent, repos, f.tagPolicy, dryRun)
+}
+
+func (f *fixture) ref(s string) string {
+	return f.reg.Host() + "/" + s
+}
+
+func daysAgo(n int) time.Time {
+	return time.Now().Add(-time.Duration(n) * 24 * time.Hour)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/notify"
+	"github.com/tilt-dev/tilt/internal/engine/registryprune"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -17,6 +19,7 @@ import (
 	hudclient "github.com/tilt-dev/tilt/internal/hud/client"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
 	"github.com/tilt-dev/tilt/internal/hud/server"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/store"
 )
 
@@ -57,12 +60,16 @@ func ProvideSubscribers(
 	ewm *k8swatch.EventWatchManager,
 	tcum *cloud.CloudStatusManager,
 	dp *dockerprune.DockerPruner,
+	rp *registryprune.RegistryPruner,
 	tc *telemetry.Controller,
 	lsc *local.ServerController,
 	podm *k8srollout.PodMonitor,
 	sc *session.Controller,
 	uss *uisession.Subscriber,
 	urs *uiresource.Subscriber,
+	ms *metrics.Subscriber,
+	nc *notify.Controller,
+	dnc *notify.DesktopController,
 ) []store.Subscriber {
 	apiSubscribers := ProvideSubscribersAPIOnly(hudsc, tscm, cb, ts)
 
@@ -78,12 +85,16 @@ func ProvideSubscribers(
 		ewm,
 		tcum,
 		dp,
+		rp,
 		tc,
 		lsc,
 		podm,
 		sc,
 		uss,
 		urs,
+		ms,
+		nc,
+		dnc,
 	}
 	return append(apiSubscribers, legacySubscribers...)
 }
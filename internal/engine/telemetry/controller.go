@@ -17,15 +17,17 @@ import (
 
 type Controller struct {
 	spans      tracer.SpanSource
+	otlp       *tracer.OTLPExporter
 	clock      build.Clock
 	runCounter int
 	lastRunAt  time.Time
 }
 
-func NewController(clock build.Clock, spans tracer.SpanSource) *Controller {
+func NewController(clock build.Clock, spans tracer.SpanSource, otlp *tracer.OTLPExporter) *Controller {
 	return &Controller{
 		clock:      clock,
 		spans:      spans,
+		otlp:       otlp,
 		runCounter: 0,
 	}
 }
@@ -36,6 +38,11 @@ func (t *Controller) OnChange(ctx context.Context, st store.RStore, _ store.Chan
 	tc := ts.Cmd
 	st.RUnlockState()
 
+	err := t.otlp.Configure(ctx, ts.OTLP)
+	if err != nil {
+		t.logError(st, fmt.Errorf("Error configuring trace_export: %v", err))
+	}
+
 	period := ts.Period
 	if period == 0 {
 		period = model.DefaultTelemetryPeriod
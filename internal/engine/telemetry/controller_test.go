@@ -187,7 +187,7 @@ func (tcf *tcFixture) run() {
 		TelemetrySettings: ts,
 	})
 
-	tc := NewController(tcf.clock, tcf.sc)
+	tc := NewController(tcf.clock, tcf.sc, tracer.NewOTLPExporter())
 	tc.lastRunAt = tcf.lastRun
 	tcf.controller = tc
 	_ = tc.OnChange(tcf.ctx, tcf.st, store.LegacyChangeSummary())
@@ -69,6 +69,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/notify"
+	"github.com/tilt-dev/tilt/internal/engine/registryprune"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -83,11 +85,14 @@ import (
 	"github.com/tilt-dev/tilt/internal/k8s/kubeconfig"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/openurl"
+	"github.com/tilt-dev/tilt/internal/registry"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/internal/store/tiltfiles"
+	"github.com/tilt-dev/tilt/internal/syncagent"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
 	tiltconfigmap "github.com/tilt-dev/tilt/internal/testutils/configmap"
@@ -3293,7 +3298,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	tcum := cloud.NewStatusManager(httptest.NewFakeClientEmptyJSON(), clock)
 	fe := cmd.NewFakeExecer()
 	fpm := cmd.NewFakeProberManager()
-	fwc := filewatch.NewController(cdc, st, watcher.NewSub, timerMaker.Maker(), v1alpha1.NewScheme(), clock)
+	fwc := filewatch.NewController(cdc, st, watcher.NewSub, watcher.NewPollSub, timerMaker.Maker(), v1alpha1.NewScheme(), clock)
 	cmds := cmd.NewController(ctx, fe, fpm, cdc, st, clock, v1alpha1.NewScheme())
 	lsc := local.NewServerController(cdc)
 	sr := ctrlsession.NewReconciler(cdc, st, clock)
@@ -3319,7 +3324,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 
 	wsl := server.NewWebsocketList()
 
-	kar := kubernetesapply.NewReconciler(cdc, kClient, sch, st, execer)
+	kar := kubernetesapply.NewReconciler(cdc, kClient, sch, st, execer, syncagent.Token("fake-token"))
 	dcds := dockercomposeservice.NewDisableSubscriber(ctx, fakeDcc, clock)
 	dcr := dockercomposeservice.NewReconciler(cdc, fakeDcc, dockerClient, st, sch, dcds, model.ProvideStartTime())
 
@@ -3334,8 +3339,8 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	lur := liveupdate.NewFakeReconciler(st, cu, cdc)
 	dockerBuilder := build.NewDockerBuilder(dockerClient, nil)
 	customBuilder := build.NewCustomBuilder(dockerClient, clock, cmds)
-	kp := build.NewKINDLoader()
-	ib := build.NewImageBuilder(dockerBuilder, customBuilder, kp)
+	kp := build.NewImageLoader()
+	ib := build.NewImageBuilder(dockerBuilder, customBuilder, kp, build.NewImageSigner(), build.NewFakeImageRegistry())
 	dir := dockerimage.NewReconciler(cdc, st, sch, dockerClient, ib)
 	cir := cmdimage.NewReconciler(cdc, st, sch, dockerClient, ib)
 	kubeconfigWriter := kubeconfig.NewWriter(base, fs, "tilt-default")
@@ -3377,6 +3382,8 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 
 	dp := dockerprune.NewDockerPruner(dockerClient)
 	dp.DisabledForTesting(true)
+	rp := registryprune.NewRegistryPruner(registry.ProvideClient())
+	rp.DisabledForTesting(true)
 
 	b := newFakeBuildAndDeployer(t, kClient, fakeDcc, cdc, kar, dcr)
 	bc := NewBuildController(b)
@@ -3414,13 +3421,13 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 
 	ret.disableEnvAnalyticsOpt()
 
-	tc := telemetry.NewController(clock, tracer.NewSpanCollector(ctx))
+	tc := telemetry.NewController(clock, tracer.NewSpanCollector(ctx), tracer.NewOTLPExporter())
 	podm := k8srollout.NewPodMonitor(clock)
 
 	uss := uisession.NewSubscriber(cdc)
 	urs := uiresource.NewSubscriber(cdc)
 
-	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs)
+	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, ar, au, ewm, tcum, dp, rp, tc, lsc, podm, sessionController, uss, urs, metrics.NewSubscriber(), notify.NewController(), notify.NewDesktopController(false))
 	ret.upper, err = NewUpper(ctx, st, subs)
 	require.NoError(t, err)
 
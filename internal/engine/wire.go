@@ -63,7 +63,9 @@ func provideFakeBuildAndDeployer(
 	updateMode liveupdates.UpdateModeFlag,
 	dcc dockercompose.DockerComposeClient,
 	clock build.Clock,
-	kp build.KINDLoader,
+	kp build.ImageLoader,
+	signer build.ImageSigner,
+	reg build.ImageRegistry,
 	analytics *analytics.TiltAnalytics,
 	ctrlClient ctrlclient.Client,
 	st store.RStore,
@@ -80,6 +82,7 @@ func provideFakeBuildAndDeployer(
 		cmd.WireSet,
 		clockwork.NewRealClock,
 		provideFakeEnv,
+		wire.Value(buildcontrol.ImageMapsPersisted(false)),
 	)
 
 	return nil, nil
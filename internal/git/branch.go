@@ -0,0 +1,20 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Returns the name of the branch checked out in the repo that contains fromDir.
+//
+// Returns the empty string if the directory isn't in a git repo,
+// or if HEAD is detached.
+func CurrentBranch(fromDir string) string {
+	cmd := exec.Command("git", "-C", fromDir, "symbolic-ref", "--short", "HEAD")
+	b, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimRight(string(b), "\n")
+}
@@ -0,0 +1,30 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestCurrentBranch(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+
+	err := exec.Command("git", "init", tf.Path()).Run()
+	if err != nil {
+		t.Fatalf("failed to init git repo: %+v", err)
+	}
+	err = exec.Command("git", "-C", tf.Path(), "checkout", "-b", "feature/login").Run()
+	if err != nil {
+		t.Fatalf("failed to create branch: %+v", err)
+	}
+
+	assert.Equal(t, "feature/login", CurrentBranch(tf.Path()))
+}
+
+func TestCurrentBranchNotARepo(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+	assert.Equal(t, "", CurrentBranch(tf.Path()))
+}
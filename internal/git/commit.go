@@ -0,0 +1,20 @@
+package git
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Returns the SHA of the commit checked out in the repo that contains fromDir.
+//
+// Returns the empty string if the directory isn't in a git repo,
+// or if the repo has no commits.
+func HeadCommit(fromDir string) string {
+	cmd := exec.Command("git", "-C", fromDir, "rev-parse", "--verify", "-q", "HEAD")
+	b, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimRight(string(b), "\n")
+}
@@ -0,0 +1,35 @@
+package git
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+func TestHeadCommit(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+
+	err := exec.Command("git", "init", tf.Path()).Run()
+	if err != nil {
+		t.Fatalf("failed to init git repo: %+v", err)
+	}
+	assert.Equal(t, "", HeadCommit(tf.Path()))
+
+	err = exec.Command("git", "-C", tf.Path(),
+		"-c", "user.name=tilt", "-c", "user.email=tilt@example.com",
+		"commit", "--allow-empty", "-m", "initial").Run()
+	if err != nil {
+		t.Fatalf("failed to commit: %+v", err)
+	}
+
+	sha := HeadCommit(tf.Path())
+	assert.Len(t, sha, 40)
+}
+
+func TestHeadCommitNotARepo(t *testing.T) {
+	tf := tempdir.NewTempDirFixture(t)
+	assert.Equal(t, "", HeadCommit(tf.Path()))
+}
@@ -49,7 +49,9 @@ func (ls *LogStreamer) Stream(ctx context.Context) error {
 	wsURL.RawQuery = url.Values{"csrf": []string{csrfToken}}.Encode()
 	logger.Get(ctx).Debugf("connecting to %s", wsURL.String())
 
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
+	header := http.Header{}
+	header.Set(server.TiltTokenHeaderName, token.Load())
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), header)
 	if err != nil {
 		return errors.Wrapf(err, "dialing websocket %s", wsURL.String())
 	}
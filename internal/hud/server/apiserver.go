@@ -15,6 +15,7 @@ import (
 
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/apiserver"
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder"
+	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/options"
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/testdata"
 	"github.com/tilt-dev/tilt/internal/xdg"
@@ -55,6 +56,27 @@ type APIServerConfig = apiserver.Config
 
 type DynamicInterface = dynamic.Interface
 
+// A directory where the API server saves objects that should survive
+// a restart of Tilt.
+//
+// If empty, all objects are stored in memory.
+type PersistentStateDir string
+
+// Whether objects of this type are saved to the PersistentStateDir.
+//
+// We only persist types that hold the user's choices (like which resources
+// are disabled, or what they typed into a button's inputs), and ImageMaps,
+// whose status records the last image built for each target (so that we
+// can skip building it again on startup). Most types describe the running
+// system, and are re-created from the Tiltfile on startup.
+func isPersistentResource(obj resource.Object) bool {
+	switch obj.(type) {
+	case *v1alpha1.ConfigMap, *v1alpha1.UIButton, *v1alpha1.ImageMap:
+		return true
+	}
+	return false
+}
+
 func ProvideDefaultConnProvider() apiserver.ConnProvider {
 	return nil
 }
@@ -133,7 +155,8 @@ func ProvideTiltServerOptions(
 	connProvider apiserver.ConnProvider,
 	token BearerToken,
 	certKey options.GeneratableKeyCert,
-	apiPort APIServerPort) (*APIServerConfig, error) {
+	apiPort APIServerPort,
+	stateDir PersistentStateDir) (*APIServerConfig, error) {
 	w := logger.Get(ctx).Writer(logger.DebugLvl)
 	builder := builder.NewServerBuilder().
 		WithOutputWriter(w).
@@ -141,7 +164,11 @@ func ProvideTiltServerOptions(
 		WithCertKey(certKey)
 
 	for _, obj := range v1alpha1.AllResourceObjects() {
-		builder = builder.WithResourceMemoryStorage(obj, "data")
+		if stateDir != "" && isPersistentResource(obj) {
+			builder = builder.WithResourceFileStorage(obj, string(stateDir))
+		} else {
+			builder = builder.WithResourceMemoryStorage(obj, "data")
+		}
 	}
 	builder = builder.WithOpenAPIDefinitions("tilt", tiltBuild.Version, openapi.GetOpenAPIDefinitions)
 
@@ -192,7 +219,7 @@ func ProvideTiltServerOptions(
 // 2) Skips OpenAPI installation
 func ProvideTiltServerOptionsForTesting(ctx context.Context) (*APIServerConfig, error) {
 	config, err := ProvideTiltServerOptions(ctx,
-		model.TiltBuild{}, ProvideMemConn(), "corgi-charge", testdata.CertKey(), 0)
+		model.TiltBuild{}, ProvideMemConn(), "corgi-charge", testdata.CertKey(), 0, "")
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +237,7 @@ func ProvideTiltServerOptionsForTesting(ctx context.Context) (*APIServerConfig,
 // (where we don't open up any webserver or apiserver).
 func ProvideTiltServerOptionsForHeadless(ctx context.Context, keyCert options.GeneratableKeyCert, memconn apiserver.ConnProvider, version model.TiltBuild) (*APIServerConfig, error) {
 	config, err := ProvideTiltServerOptions(ctx,
-		version, memconn, "corgi-charge", keyCert, 0)
+		version, memconn, "corgi-charge", keyCert, 0, "")
 	if err != nil {
 		return nil, err
 	}
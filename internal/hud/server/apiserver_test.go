@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -135,6 +136,74 @@ func TestAPIServerDynamicClient(t *testing.T) {
 	}
 }
 
+func TestAPIServerPersistentState(t *testing.T) {
+	stateDir := PersistentStateDir(t.TempDir())
+
+	f := newAPIServerFixtureWithStateDir(t, stateDir)
+	hudsc := f.start()
+
+	cmClient := f.dynamic.Resource((&v1alpha1.ConfigMap{}).GetGroupVersionResource())
+	_, err := cmClient.Create(f.ctx, &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "ConfigMap",
+			"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+			"metadata":   map[string]interface{}{"name": "my-config"},
+			"data":       map[string]interface{}{"isDisabled": "true"},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	cmdClient := f.dynamic.Resource((&v1alpha1.Cmd{}).GetGroupVersionResource())
+	_, err = cmdClient.Create(f.ctx, &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "Cmd",
+			"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+			"metadata":   map[string]interface{}{"name": "my-cmd"},
+			"spec":       map[string]interface{}{"args": []interface{}{"echo", "hi"}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	imClient := f.dynamic.Resource((&v1alpha1.ImageMap{}).GetGroupVersionResource())
+	im, err := imClient.Create(f.ctx, &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "ImageMap",
+			"apiVersion": v1alpha1.SchemeGroupVersion.String(),
+			"metadata":   map[string]interface{}{"name": "my-image"},
+			"spec":       map[string]interface{}{"selector": "my-image"},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	im.Object["status"] = map[string]interface{}{
+		"image":          "my-image:tilt-123",
+		"buildInputHash": "abc",
+	}
+	_, err = imClient.UpdateStatus(f.ctx, im, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	hudsc.TearDown(f.ctx)
+
+	// Restart the server on the same state dir.
+	f = newAPIServerFixtureWithStateDir(t, stateDir)
+	f.start()
+
+	cm, err := f.dynamic.Resource((&v1alpha1.ConfigMap{}).GetGroupVersionResource()).
+		Get(f.ctx, "my-config", metav1.GetOptions{})
+	require.NoError(t, err)
+	data, _, _ := unstructured.NestedStringMap(cm.Object, "data")
+	assert.Equal(t, map[string]string{"isDisabled": "true"}, data)
+
+	im, err = f.dynamic.Resource((&v1alpha1.ImageMap{}).GetGroupVersionResource()).
+		Get(f.ctx, "my-image", metav1.GetOptions{})
+	require.NoError(t, err)
+	hash, _, _ := unstructured.NestedString(im.Object, "status", "buildInputHash")
+	assert.Equal(t, "abc", hash)
+
+	_, err = f.dynamic.Resource((&v1alpha1.Cmd{}).GetGroupVersionResource()).
+		Get(f.ctx, "my-cmd", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err), "Cmds should not be persisted, got: %v", err)
+}
+
 func TestAPIServerProxy(t *testing.T) {
 	// This fixture constructs an empty HeadsUpServer without a backing store,
 	// so a real Tilt-Token validation cannot run. The fixture exists to test
@@ -184,6 +253,11 @@ type apiserverFixture struct {
 
 func newAPIServerFixture(t testing.TB) *apiserverFixture {
 	t.Helper()
+	return newAPIServerFixtureWithStateDir(t, "")
+}
+
+func newAPIServerFixtureWithStateDir(t testing.TB, stateDir PersistentStateDir) *apiserverFixture {
+	t.Helper()
 
 	tmpdir := tempdir.NewTempDirFixture(t)
 
@@ -195,7 +269,7 @@ func newAPIServerFixture(t testing.TB) *apiserverFixture {
 
 	memconn := ProvideMemConn()
 
-	cfg, err := ProvideTiltServerOptions(ctx, model.TiltBuild{}, memconn, "corgi-charge", testdata.CertKey(), 0)
+	cfg, err := ProvideTiltServerOptions(ctx, model.TiltBuild{}, memconn, "corgi-charge", testdata.CertKey(), 0, stateDir)
 	require.NoError(t, err)
 
 	const host = "localhost"
@@ -9,6 +9,7 @@ import (
 	_ "net/http/pprof"
 	"net/url"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -22,8 +23,10 @@ import (
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/hud/webview"
+	"github.com/tilt-dev/tilt/internal/metrics"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/tiltfiles"
+	"github.com/tilt-dev/tilt/internal/token"
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/model"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
@@ -65,6 +68,9 @@ type HeadsUpServer struct {
 	a          *tiltanalytics.TiltAnalytics
 	wsList     *WebsocketList
 	ctrlClient ctrlclient.Client
+
+	// Scoped tokens minted by `tilt share`. May be nil.
+	shareTokens *token.ShareTokens
 }
 
 func ProvideHeadsUpServer(
@@ -73,29 +79,36 @@ func ProvideHeadsUpServer(
 	assetServer assets.Server,
 	analytics *tiltanalytics.TiltAnalytics,
 	wsList *WebsocketList,
-	ctrlClient ctrlclient.Client) (*HeadsUpServer, error) {
+	ctrlClient ctrlclient.Client,
+	shareTokens *token.ShareTokens) (*HeadsUpServer, error) {
 	r := mux.NewRouter().UseEncodedPath()
 	r.Use(originCheckMiddleware)
 	s := &HeadsUpServer{
-		ctx:        ctx,
-		store:      store,
-		router:     r,
-		a:          analytics,
-		wsList:     wsList,
-		ctrlClient: ctrlClient,
+		ctx:         ctx,
+		store:       store,
+		router:      r,
+		a:           analytics,
+		wsList:      wsList,
+		ctrlClient:  ctrlClient,
+		shareTokens: shareTokens,
 	}
 
-	r.Handle("/api/view", s.requireToken(http.HandlerFunc(s.ViewJSON)))
+	r.Handle("/api/view", s.requireScope(token.ScopeReadOnly, http.HandlerFunc(s.ViewJSON)))
 	r.Handle("/api/dump/engine", s.requireToken(http.HandlerFunc(s.DumpEngineJSON)))
-	r.Handle("/api/analytics", s.requireToken(http.HandlerFunc(s.HandleAnalytics)))
+	r.Handle("/api/analytics", s.requireScope(token.ScopeReadOnly, http.HandlerFunc(s.HandleAnalytics)))
 	r.Handle("/api/analytics_opt", s.requireToken(http.HandlerFunc(s.HandleAnalyticsOpt)))
 	r.Handle("/api/trigger", s.requireToken(http.HandlerFunc(s.HandleTrigger)))
 	r.Handle("/api/override/trigger_mode", s.requireToken(http.HandlerFunc(s.HandleOverrideTriggerMode)))
-	r.Handle("/api/snapshot/{snapshot_id}", s.requireToken(http.HandlerFunc(s.SnapshotJSON)))
-	r.Handle("/api/websocket_token", s.requireToken(http.HandlerFunc(s.WebsocketToken)))
-	r.HandleFunc("/ws/view", s.ViewWebsocket)
+	r.Handle("/api/snapshot/{snapshot_id}", s.requireScope(token.ScopeReadOnly, http.HandlerFunc(s.SnapshotJSON)))
+	r.Handle("/api/websocket_token", s.requireScope(token.ScopeReadOnly, http.HandlerFunc(s.WebsocketToken)))
+	r.Handle("/ws/view", s.requireScope(token.ScopeReadOnly, http.HandlerFunc(s.ViewWebsocket)))
 	r.Handle("/api/set_tiltfile_args", s.requireToken(http.HandlerFunc(s.HandleSetTiltfileArgs))).Methods("POST")
 
+	// Metrics include resource names and build timings, so they need at
+	// least a read-only token. Prometheus can send one with its
+	// `authorization` config (see `tilt share --print-token`).
+	r.Handle("/metrics", s.requireScope(token.ScopeReadOnly, metrics.Handler()))
+
 	r.PathPrefix("/").Handler(s.cookieWrapper(assetServer))
 
 	return s, nil
@@ -137,32 +150,72 @@ func originCheckMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// cookieWrapper gives the browser a token cookie.
+//
+// Usually this is the session token. If the request came with a token
+// in the header (e.g., from a teammate connected through `tilt share`), the
+// cookie gets that token instead, so that we never hand out the session
+// token to someone who only has limited access.
 func (s *HeadsUpServer) cookieWrapper(handler http.Handler) http.Handler {
 	return funcHandler{f: func(w http.ResponseWriter, r *http.Request) {
 		state := s.store.RLockState()
-		http.SetCookie(w, &http.Cookie{
-			Name:     TiltTokenCookieName,
-			Value:    string(state.Token),
-			Path:     "/",
-			SameSite: http.SameSiteStrictMode,
-		})
+		value := string(state.Token)
 		s.store.RUnlockState()
+
+		candidate := r.Header.Get(TiltTokenHeaderName)
+		if candidate != "" && candidate != value {
+			value = ""
+			scoped, ok := s.shareTokens.Lookup(token.Token(candidate))
+			if ok {
+				value = string(scoped.Token)
+			}
+		}
+
+		if value != "" {
+			http.SetCookie(w, &http.Cookie{
+				Name:     TiltTokenCookieName,
+				Value:    value,
+				Path:     "/",
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
 		handler.ServeHTTP(w, r)
 	}}
 }
 
-// requireToken validates the Tilt-Token header or cookie against the current
-// session token. The middleware is bypassed when TILT_DISABLE_HUD_AUTH=1 is
+type scopedTokenKey struct{}
+
+// Returns the token that authorized the request, if any.
+func scopedTokenFromContext(ctx context.Context) (token.ScopedToken, bool) {
+	t, ok := ctx.Value(scopedTokenKey{}).(token.ScopedToken)
+	return t, ok
+}
+
+// requireToken only allows requests with full access.
+func (s *HeadsUpServer) requireToken(next http.Handler) http.Handler {
+	return s.requireScope(token.ScopeFull, next)
+}
+
+// requireScope validates the Tilt-Token header, an Authorization bearer token,
+// or the Tilt-Token cookie against the current session token, or against the
+// scoped tokens minted by `tilt share`.
+// The session token has full access; scoped tokens only pass if their scope
+// allows the required scope.
+//
+// The middleware is bypassed when TILT_DISABLE_HUD_AUTH=1 is
 // set — intended for deployments that authenticate at a reverse-proxy or
 // load-balancer layer, NOT for general use. The env var is read per request
 // so it can be flipped at runtime (and so tests can use t.Setenv).
-func (s *HeadsUpServer) requireToken(next http.Handler) http.Handler {
+func (s *HeadsUpServer) requireScope(scope token.Scope, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if os.Getenv("TILT_DISABLE_HUD_AUTH") == "1" {
 			next.ServeHTTP(w, r)
 			return
 		}
 		candidate := r.Header.Get(TiltTokenHeaderName)
+		if candidate == "" {
+			candidate = BearerTokenFromRequest(r)
+		}
 		if candidate == "" {
 			cookie, err := r.Cookie(TiltTokenCookieName)
 			if err != nil {
@@ -171,17 +224,42 @@ func (s *HeadsUpServer) requireToken(next http.Handler) http.Handler {
 			}
 			candidate = cookie.Value
 		}
+
 		state := s.store.RLockState()
-		valid := candidate == string(state.Token)
+		sessionToken := state.Token
 		s.store.RUnlockState()
-		if !valid {
-			http.Error(w, "invalid session token", http.StatusForbidden)
+
+		var scoped token.ScopedToken
+		if candidate == string(sessionToken) {
+			scoped = token.ScopedToken{Token: sessionToken, Scope: token.ScopeFull}
+		} else {
+			var ok bool
+			scoped, ok = s.shareTokens.Lookup(token.Token(candidate))
+			if !ok {
+				http.Error(w, "invalid session token", http.StatusForbidden)
+				return
+			}
+		}
+
+		if !scoped.Scope.Allows(scope) {
+			http.Error(w, fmt.Sprintf("session token has %s access", scoped.Scope), http.StatusForbidden)
 			return
 		}
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scopedTokenKey{}, scoped)))
 	})
 }
 
+// Returns the token from an `Authorization: Bearer` header, for clients
+// like Prometheus that can't set arbitrary headers.
+func BearerTokenFromRequest(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	prefix := "Bearer "
+	if len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+		return strings.TrimSpace(auth[len(prefix):])
+	}
+	return ""
+}
+
 func (s *HeadsUpServer) Router() http.Handler {
 	return s.router
 }
@@ -30,6 +30,7 @@ import (
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
+	"github.com/tilt-dev/wmclient/pkg/dirs"
 )
 
 func TestHandleAnalyticsEmptyRequest(t *testing.T) {
@@ -323,6 +324,7 @@ type serverFixture struct {
 	ctrlClient   ctrlclient.Client
 	getActions   func() []store.Action
 	snapshotHTTP *fakeHTTPClient
+	shareTokens  *token.ShareTokens
 }
 
 func newTestFixture(t *testing.T) *serverFixture {
@@ -342,7 +344,8 @@ func newTestFixture(t *testing.T) *serverFixture {
 
 	ctx := context.Background()
 
-	serv, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta, wsl, ctrlClient)
+	shareTokens := token.NewShareTokens(dirs.NewTiltDevDirAt(t.TempDir()), "tilt-default")
+	serv, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta, wsl, ctrlClient, shareTokens)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -357,6 +360,7 @@ func newTestFixture(t *testing.T) *serverFixture {
 		ctrlClient:   ctrlClient,
 		getActions:   getActions,
 		snapshotHTTP: snapshotHTTP,
+		shareTokens:  shareTokens,
 	}
 }
 
@@ -538,3 +542,120 @@ func TestRequireTokenBypassedWhenDisabled(t *testing.T) {
 	status, _ := f.routerReq(http.MethodGet, "/api/view", nil)
 	require.Equal(t, http.StatusOK, status)
 }
+
+func TestReadOnlyTokenCanView(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+	readOnly := f.mintShareToken(time.Hour)
+
+	for _, path := range []string{"/api/view", "/api/snapshot/test-id", "/api/websocket_token"} {
+		status, _ := f.routerReq(http.MethodGet, path, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: server.TiltTokenCookieName, Value: readOnly})
+		})
+		assert.Equal(t, http.StatusOK, status, path)
+	}
+}
+
+func TestReadOnlyTokenCannotModify(t *testing.T) {
+	f := newTestFixture(t).withDummyManifests("foo")
+	f.setToken(testToken)
+	readOnly := f.mintShareToken(time.Hour)
+
+	for _, path := range []string{"/api/trigger", "/api/override/trigger_mode", "/api/set_tiltfile_args", "/api/dump/engine"} {
+		status, body := f.routerReq(http.MethodPost, path, func(r *http.Request) {
+			r.Header.Set(server.TiltTokenHeaderName, readOnly)
+		})
+		assert.Equal(t, http.StatusForbidden, status, path)
+		assert.Contains(t, body, "session token has read-only access", path)
+	}
+	assert.Empty(t, f.getActions())
+}
+
+func TestReadOnlyTokenCannotOpenWebsocketAfterExpiry(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+	readOnly := f.mintShareToken(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	status, body := f.routerReq(http.MethodGet, "/ws/view", func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: server.TiltTokenCookieName, Value: readOnly})
+	})
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, "invalid session token")
+}
+
+func TestWebsocketRequiresToken(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+
+	status, body := f.routerReq(http.MethodGet, "/ws/view", nil)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, "missing session token")
+}
+
+func TestCookieGetsReadOnlyTokenFromHeader(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+	readOnly := f.mintShareToken(time.Hour)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(server.TiltTokenHeaderName, readOnly)
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+
+	cookies := rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, readOnly, cookies[0].Value)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	rr = httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+
+	cookies = rr.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, testToken, cookies[0].Value)
+}
+
+func TestCookieNotSetForInvalidHeaderToken(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(server.TiltTokenHeaderName, "wrong-token")
+	rr := httptest.NewRecorder()
+	f.serv.Router().ServeHTTP(rr, req)
+
+	assert.Empty(t, rr.Result().Cookies())
+}
+
+func (f *serverFixture) mintShareToken(ttl time.Duration) string {
+	t, err := f.shareTokens.Mint(token.ScopeReadOnly, ttl)
+	require.NoError(f.t, err)
+	return string(t.Token)
+}
+
+func TestMetricsRequiresToken(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+
+	status, body := f.routerReq(http.MethodGet, "/metrics", nil)
+	require.Equal(t, http.StatusForbidden, status)
+	assert.Contains(t, body, "missing session token")
+
+	status, _ = f.routerReq(http.MethodGet, "/metrics", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer not-a-token")
+	})
+	require.Equal(t, http.StatusForbidden, status)
+}
+
+func TestMetricsWithReadOnlyBearerToken(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+	readOnly := f.mintShareToken(time.Hour)
+
+	status, body := f.routerReq(http.MethodGet, "/metrics", func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+readOnly)
+	})
+	require.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, "tilt_log_store_bytes")
+}
@@ -40,8 +40,9 @@ var upgrader = websocket.Upgrader{
 	// Only allow the upgrade when the client presents the CSRF token.
 	//
 	// The token is served exclusively by /api/websocket_token, which is gated
-	// behind requireToken, so only a caller already holding the session token
-	// can obtain it. We deliberately do NOT fall back to an origin check: the
+	// behind requireScope, so only a caller already holding a session token
+	// (or a read-only share token) can obtain it. The socket itself is gated
+	// the same way. We deliberately do NOT fall back to an origin check: the
 	// previous fallback accepted any request whose Origin header was absent
 	// (originCheck returns true on missing Origin), which let a non-browser
 	// client open this socket and read the full HUD stream with no token.
@@ -344,6 +345,15 @@ func (s *HeadsUpServer) ViewWebsocket(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	// Scoped tokens expire, so disconnect the viewer when their token does.
+	scoped, ok := scopedTokenFromContext(req.Context())
+	if ok && !scoped.Expires.IsZero() {
+		timer := time.AfterFunc(time.Until(scoped.Expires), func() {
+			_ = conn.Close()
+		})
+		defer timer.Stop()
+	}
+
 	ws := NewWebsocketSubscriber(s.ctx, s.ctrlClient, s.store, conn)
 	s.wsList.Add(ws)
 	_ = s.store.AddSubscriber(s.ctx, ws)
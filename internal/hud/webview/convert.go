@@ -247,6 +247,9 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 	endpoints := store.ManifestTargetEndpoints(mt)
 
 	bh := ToBuildsTerminated(ms.BuildHistory, s.LogStore)
+	if mt.Manifest.IsK8s() {
+		addRolloutStep(bh, ms.BuildHistory, ms.K8sRuntimeState().Rollout)
+	}
 	lastDeploy := metav1.NewMicroTime(ms.LastSuccessfulDeployTime)
 	currentBuild := ms.EarliestCurrentBuild()
 	cb := ToBuildRunning(currentBuild)
@@ -296,6 +299,19 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 	return r, nil
 }
 
+// Appends the rollout step to the timeline of the build that rolled it out.
+func addRolloutStep(bh []v1alpha1.UIBuildTerminated, brs []model.BuildRecord, rollout store.K8sRollout) {
+	if rollout.Empty() {
+		return
+	}
+	for i, br := range brs {
+		if br.StartTime.Equal(rollout.BuildStartTime) {
+			bh[i].Steps = append(bh[i].Steps, ToBuildSteps([]model.BuildStep{rollout.Step})...)
+			return
+		}
+	}
+}
+
 // The "Ready" condition is a cross-resource status report that's synthesized
 // from the more type-specific fields of UIResource.
 func UIResourceReadyCondition(r v1alpha1.UIResourceStatus) v1alpha1.UIResourceCondition {
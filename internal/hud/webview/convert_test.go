@@ -368,6 +368,92 @@ func TestBuildHistory(t *testing.T) {
 	}
 }
 
+func TestBuildHistorySteps(t *testing.T) {
+	start := time.Now().Add(-1 * time.Hour)
+	br := model.BuildRecord{
+		StartTime:  start,
+		FinishTime: start.Add(time.Minute),
+		BuildTypes: []model.BuildType{model.BuildTypeImage, model.BuildTypeK8s},
+		Steps: []model.BuildStep{
+			{
+				Name:       "[1/2] FROM alpine",
+				Type:       model.BuildStepTypeImageStage,
+				StartTime:  start,
+				FinishTime: start.Add(time.Second),
+				Cached:     true,
+			},
+			{
+				Name:       "Applying foo",
+				Type:       model.BuildStepTypeApply,
+				StartTime:  start.Add(time.Second),
+				FinishTime: start.Add(time.Minute),
+				Error:      "forbidden",
+			},
+		},
+	}
+
+	m := model.Manifest{Name: "foo"}.WithDeployTarget(model.K8sTarget{})
+	state := newState([]model.Manifest{m})
+	state.ManifestTargets[m.Name].State.BuildHistory = []model.BuildRecord{br}
+
+	v := completeProtoView(t, *state)
+	r := v.UiResources[1]
+	require.Equal(t, "foo", r.Name)
+	require.Len(t, r.Status.BuildHistory, 1)
+
+	steps := r.Status.BuildHistory[0].Steps
+	require.Len(t, steps, 2)
+	assert.Equal(t, "[1/2] FROM alpine", steps[0].Name)
+	assert.Equal(t, "image-stage", steps[0].Type)
+	assert.True(t, steps[0].Cached)
+	timecmp.AssertTimeEqual(t, start, steps[0].StartTime)
+	assert.Equal(t, "apply", steps[1].Type)
+	assert.Equal(t, "forbidden", steps[1].Error)
+	timecmp.AssertTimeEqual(t, start.Add(time.Minute), steps[1].FinishTime)
+}
+
+func TestBuildHistoryRolloutStep(t *testing.T) {
+	start := time.Now().Add(-1 * time.Hour)
+	oldBuild := model.BuildRecord{
+		StartTime:  start,
+		FinishTime: start.Add(time.Minute),
+		BuildTypes: []model.BuildType{model.BuildTypeK8s},
+	}
+	newBuild := model.BuildRecord{
+		StartTime:  start.Add(10 * time.Minute),
+		FinishTime: start.Add(11 * time.Minute),
+		BuildTypes: []model.BuildType{model.BuildTypeK8s},
+	}
+
+	m := model.Manifest{Name: "foo"}.WithDeployTarget(model.K8sTarget{})
+	state := newState([]model.Manifest{m})
+	ms := state.ManifestTargets[m.Name].State
+	ms.BuildHistory = []model.BuildRecord{newBuild, oldBuild}
+	krs := ms.K8sRuntimeState()
+	krs.Rollout = store.K8sRollout{
+		BuildStartTime: oldBuild.StartTime,
+		Step: model.BuildStep{
+			Name:       "Waiting for pod pod-a",
+			Type:       model.BuildStepTypeRollout,
+			StartTime:  oldBuild.FinishTime,
+			FinishTime: oldBuild.FinishTime.Add(time.Minute),
+		},
+	}
+	ms.RuntimeState = krs
+
+	v := completeProtoView(t, *state)
+	r := v.UiResources[1]
+	require.Equal(t, "foo", r.Name)
+	require.Len(t, r.Status.BuildHistory, 2)
+	assert.Empty(t, r.Status.BuildHistory[0].Steps)
+
+	steps := r.Status.BuildHistory[1].Steps
+	require.Len(t, steps, 1)
+	assert.Equal(t, "rollout", steps[0].Type)
+	assert.Equal(t, "Waiting for pod pod-a", steps[0].Name)
+	assert.Empty(t, ms.BuildHistory[1].Steps)
+}
+
 func TestSpecs(t *testing.T) {
 	luSpec := v1alpha1.LiveUpdateSpec{
 		BasePath: ".",
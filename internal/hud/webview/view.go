@@ -82,9 +82,28 @@ func ToBuildTerminated(br model.BuildRecord, logStore *logstore.LogStore) v1alph
 		FinishTime:     metav1.NewMicroTime(br.FinishTime),
 		IsCrashRebuild: false,
 		SpanID:         string(br.SpanID),
+		Steps:          ToBuildSteps(br.Steps),
 	}
 }
 
+func ToBuildSteps(steps []model.BuildStep) []v1alpha1.UIBuildStep {
+	if len(steps) == 0 {
+		return nil
+	}
+	ret := make([]v1alpha1.UIBuildStep, len(steps))
+	for i, step := range steps {
+		ret[i] = v1alpha1.UIBuildStep{
+			Name:       step.Name,
+			Type:       string(step.Type),
+			StartTime:  metav1.NewMicroTime(step.StartTime),
+			FinishTime: metav1.NewMicroTime(step.FinishTime),
+			Cached:     step.Cached,
+			Error:      step.Error,
+		}
+	}
+	return ret
+}
+
 func ToBuildsTerminated(brs []model.BuildRecord, logStore *logstore.LogStore) []v1alpha1.UIBuildTerminated {
 	ret := make([]v1alpha1.UIBuildTerminated, len(brs))
 	for i, br := range brs {
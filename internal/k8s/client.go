@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/duration"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -107,6 +108,11 @@ type Client interface {
 	// than they were passed in) and with UUIDs from the Kube API
 	Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration, ssa SSAOptions) ([]K8sEntity, error)
 
+	// Asks the apiserver what the entities would look like after an apply
+	// with the given options, without persisting anything, and fetches their
+	// current live state.
+	DryRunApply(ctx context.Context, entities []K8sEntity, timeout time.Duration, ssa SSAOptions) ([]DryRunResult, error)
+
 	// Delete all given entities, optionally waiting for them to be fully deleted.
 	//
 	// Currently ignores any "not found" errors, because that seems like the correct
@@ -114,8 +120,18 @@ type Client interface {
 	Delete(ctx context.Context, entities []K8sEntity, wait time.Duration) error
 
 	GetMetaByReference(ctx context.Context, ref v1.ObjectReference) (metav1.Object, error)
+
+	// Fetches the whole object, for when we need to inspect fields
+	// outside the metadata (like the status of a custom resource).
+	GetByReference(ctx context.Context, ref v1.ObjectReference) (*unstructured.Unstructured, error)
+
 	ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error)
 
+	// Asks API discovery whether the kind lives outside any namespace.
+	//
+	// Returns a NoKindMatchError if the cluster doesn't know the kind.
+	IsClusterScoped(ctx context.Context, gvk schema.GroupVersionKind) (bool, error)
+
 	// Streams the container logs
 	ContainerLogs(ctx context.Context, podID PodID, cName container.Name, n Namespace, startTime time.Time) (io.ReadCloser, error)
 
@@ -765,6 +781,14 @@ func (k *K8sClient) forceDiscovery(ctx context.Context, gvk schema.GroupVersionK
 	return rm, nil
 }
 
+func (k *K8sClient) IsClusterScoped(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	mapping, err := k.forceDiscovery(ctx, gvk)
+	if err != nil {
+		return false, err
+	}
+	return mapping.Scope != nil && mapping.Scope.Name() == meta.RESTScopeNameRoot, nil
+}
+
 // Returns true if the list successfully deleted. False if we timed out.
 func (k *K8sClient) waitForDelete(ctx context.Context, list kube.ResourceList, duration time.Duration) error {
 	results := make([]bool, len(list))
@@ -848,6 +872,31 @@ func (k *K8sClient) GetMetaByReference(ctx context.Context, ref v1.ObjectReferen
 	return &meta, nil
 }
 
+func (k *K8sClient) GetByReference(ctx context.Context, ref v1.ObjectReference) (*unstructured.Unstructured, error) {
+	gvk := ReferenceGVK(ref)
+	mapping, err := k.forceDiscovery(ctx, gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	gvr := mapping.Resource
+	var ri dynamic.ResourceInterface = k.dynamic.Resource(gvr)
+	if mapping.Scope == nil || mapping.Scope.Name() != meta.RESTScopeNameRoot {
+		ri = k.dynamic.Resource(gvr).Namespace(ref.Namespace)
+	}
+
+	obj, err := ri.Get(ctx, ref.Name, metav1.GetOptions{
+		ResourceVersion: ref.ResourceVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if ref.UID != "" && obj.GetUID() != ref.UID {
+		return nil, apierrors.NewNotFound(v1.Resource(gvr.Resource), ref.Name)
+	}
+	return obj, nil
+}
+
 func (k *K8sClient) ClusterHealth(ctx context.Context, verbose bool) (ClusterHealth, error) {
 	isLive, livezResp, err := k.apiServerHealthCheck(ctx, "/livez", verbose)
 	if err != nil {
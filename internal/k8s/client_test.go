@@ -192,6 +192,24 @@ roleRef:
 	assert.Equal(t, "app-worker-discovery", f.resourceClient.updates[0].Name)
 }
 
+func TestDryRunApplyUsesApplyMode(t *testing.T) {
+	f := newClientTestFixture(t)
+	pod := mustParseYAML(t, testyaml.SanchoYAML)
+
+	csa := SSAOptions{}
+	ssa := SSAOptions{Enabled: true, Force: true, FieldManager: FieldManager}
+	for _, opts := range []SSAOptions{csa, ssa} {
+		results, err := f.client.DryRunApply(f.ctx, pod, time.Minute, opts)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Nil(t, results[0].Live)
+		assert.Equal(t, "sancho", results[0].DryRun.GetName())
+	}
+
+	assert.Equal(t, []SSAOptions{csa, ssa}, f.resourceClient.dryRuns)
+	assert.Empty(t, f.resourceClient.updates, "dry-run should not apply")
+}
+
 func TestUpsertToTerminatingNamespaceForbidden(t *testing.T) {
 	f := newClientTestFixture(t)
 	postgres, err := ParseYAMLFromString(testyaml.SanchoYAML)
@@ -451,6 +469,7 @@ type fakeResourceClient struct {
 	creates          kube.ResourceList
 	deletes          kube.ResourceList
 	createOrReplaces kube.ResourceList
+	dryRuns          []SSAOptions
 	updateErr        error
 	buildErrFn       func(e K8sEntity) error
 	applyFn          *func(target kube.ResourceList, ssa SSAOptions) (*kube.Result, error)
@@ -471,6 +490,10 @@ func (c *fakeResourceClient) Apply(target kube.ResourceList, ssa SSAOptions) (*k
 	c.updates = append(c.updates, target...)
 	return &kube.Result{Updated: target}, nil
 }
+func (c *fakeResourceClient) DryRunApply(target kube.ResourceList, ssa SSAOptions) (*kube.Result, error) {
+	c.dryRuns = append(c.dryRuns, ssa)
+	return &kube.Result{Updated: target}, nil
+}
 func (c *fakeResourceClient) Delete(l kube.ResourceList) (*kube.Result, []error) {
 	c.deletes = append(c.deletes, l...)
 	return &kube.Result{Deleted: l}, nil
@@ -0,0 +1,113 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+)
+
+// DryRunResult pairs the live state of an object with the state
+// the apiserver predicts it will have after an apply.
+type DryRunResult struct {
+	// The object that we asked the server to apply.
+	Entity K8sEntity
+
+	// The object as it currently exists in the cluster,
+	// or nil if it doesn't exist yet.
+	Live *unstructured.Unstructured
+
+	// The object as it would exist after the apply.
+	DryRun *unstructured.Unstructured
+}
+
+// Applies each entity with dryRun=All, so that admission webhooks
+// and defaulting run but nothing is persisted.
+//
+// Uses the same apply mode as Upsert with the same SSAOptions, so that the
+// dry-run predicts what the real apply will do (e.g., a client-side apply
+// won't take ownership of fields that another manager owns).
+//
+// Returns the results in the order that the entities were passed in.
+func (k *K8sClient) DryRunApply(ctx context.Context, entities []K8sEntity, timeout time.Duration, ssa SSAOptions) ([]DryRunResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]DryRunResult, 0, len(entities))
+	for _, e := range entities {
+		result, err := k.dryRunApplyEntity(ctx, e, ssa)
+		if err != nil {
+			return nil, errors.Wrapf(err, "dry-run %s %s", e.GVK().Kind, e.Name())
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (k *K8sClient) dryRunApplyEntity(ctx context.Context, e K8sEntity, ssa SSAOptions) (DryRunResult, error) {
+	mapping, err := k.forceDiscovery(ctx, e.GVK())
+	if err != nil {
+		return DryRunResult{}, err
+	}
+
+	var ri dynamic.ResourceInterface = k.dynamic.Resource(mapping.Resource)
+	if mapping.Scope == nil || mapping.Scope.Name() != meta.RESTScopeNameRoot {
+		ns := e.NamespaceOrDefault(k.configNamespace.String())
+		ri = k.dynamic.Resource(mapping.Resource).Namespace(ns)
+	}
+
+	live, err := ri.Get(ctx, e.Name(), metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return DryRunResult{}, err
+		}
+		live = nil
+	}
+
+	resources, err := k.buildResourceList(ctx, e)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+
+	_, err = k.resourceClient.DryRunApply(resources, ssa)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+	if len(resources) != 1 {
+		return DryRunResult{}, fmt.Errorf("expected 1 object from dry-run, got %d", len(resources))
+	}
+
+	dryRun, err := objectToUnstructured(resources[0].Object)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+
+	return DryRunResult{Entity: e, Live: live, DryRun: dryRun}, nil
+}
+
+func entityToUnstructured(e K8sEntity) (*unstructured.Unstructured, error) {
+	obj, err := objectToUnstructured(e.DeepCopy().Obj)
+	if err != nil {
+		return nil, err
+	}
+	obj.SetGroupVersionKind(e.GVK())
+	return obj, nil
+}
+
+func objectToUnstructured(obj runtime.Object) (*unstructured.Unstructured, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u, nil
+	}
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
+	}
+	return &unstructured.Unstructured{Object: content}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
@@ -35,6 +36,14 @@ func (ec *explodingClient) Upsert(ctx context.Context, entities []K8sEntity, tim
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
 
+func (ec *explodingClient) DryRunApply(ctx context.Context, entities []K8sEntity, timeout time.Duration, ssa SSAOptions) ([]DryRunResult, error) {
+	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
+}
+
+func (ec *explodingClient) IsClusterScoped(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	return false, errors.Wrap(ec.err, "could not set up kubernetes client")
+}
+
 func (ec *explodingClient) Delete(ctx context.Context, entities []K8sEntity, wait time.Duration) error {
 	return errors.Wrap(ec.err, "could not set up kubernetes client")
 }
@@ -43,6 +52,10 @@ func (ec *explodingClient) GetMetaByReference(ctx context.Context, ref v1.Object
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
 
+func (ec *explodingClient) GetByReference(ctx context.Context, ref v1.ObjectReference) (*unstructured.Unstructured, error) {
+	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
+}
+
 func (ec *explodingClient) ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error) {
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
@@ -16,6 +16,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
@@ -67,6 +68,7 @@ type FakeK8sClient struct {
 	podWatches     []fakePodWatch
 	serviceWatches []fakeServiceWatch
 	eventWatches   []fakeEventWatch
+	metaWatches    []*fakeMetaWatch
 	events         map[types.NamespacedName]*v1.Event
 	services       map[types.NamespacedName]*v1.Service
 	pods           map[types.NamespacedName]*v1.Pod
@@ -78,6 +80,12 @@ type FakeK8sClient struct {
 	LastUpsertResult []K8sEntity
 	UpsertTimeout    time.Duration
 
+	DryRunError error
+
+	// Kinds that IsClusterScoped reports as cluster-scoped. Any other
+	// kind is namespaced.
+	ClusterScopedKinds map[schema.GroupKind]bool
+
 	Runtime    container.Runtime
 	Registry   *v1alpha1.RegistryHosting
 	FakeNodeIP NodeIP
@@ -114,6 +122,12 @@ type ExecCall struct {
 	Stdin []byte
 }
 
+type fakeMetaWatch struct {
+	gvk schema.GroupVersionKind
+	ns  Namespace
+	ch  chan metav1.Object
+}
+
 type fakeServiceWatch struct {
 	cancel func()
 	ns     Namespace
@@ -285,7 +299,27 @@ func (c *FakeK8sClient) WatchMeta(ctx context.Context, gvk schema.GroupVersionKi
 		return nil, fmt.Errorf("missing namespace from watch request")
 	}
 
-	return make(chan metav1.Object), nil
+	c.mu.Lock()
+	w := &fakeMetaWatch{gvk: gvk, ns: ns, ch: make(chan metav1.Object, 20)}
+	c.metaWatches = append(c.metaWatches, w)
+	c.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		c.mu.Lock()
+		var newWatches []*fakeMetaWatch
+		for _, e := range c.metaWatches {
+			if e != w {
+				newWatches = append(newWatches, e)
+			}
+		}
+		c.metaWatches = newWatches
+		c.mu.Unlock()
+
+		close(w.ch)
+	}()
+	return w.ch, nil
 }
 
 func (c *FakeK8sClient) EmitPodDelete(p *v1.Pod) {
@@ -353,6 +387,13 @@ func NewFakeK8sClient(t testing.TB) *FakeK8sClient {
 		events:                   make(map[types.NamespacedName]*v1.Event),
 		entities:                 make(map[types.UID]K8sEntity),
 		currentVersions:          make(map[string]types.UID),
+		ClusterScopedKinds: map[schema.GroupKind]bool{
+			{Kind: "Namespace"}:        true,
+			{Kind: "PersistentVolume"}: true,
+			{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:        true,
+			{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}: true,
+			crdGroupKind: true,
+		},
 		FakeAPIConfig: &api.Config{
 			CurrentContext: "default",
 			Contexts: map[string]*api.Context{
@@ -428,6 +469,42 @@ func (c *FakeK8sClient) Upsert(_ context.Context, entities []K8sEntity, timeout
 	return result, nil
 }
 
+func (c *FakeK8sClient) IsClusterScoped(_ context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ClusterScopedKinds[gvk.GroupKind()], nil
+}
+
+// DryRunApply pairs each entity with the most recently injected entity
+// of the same name, kind, and namespace (if any).
+func (c *FakeK8sClient) DryRunApply(_ context.Context, entities []K8sEntity, timeout time.Duration, ssa SSAOptions) ([]DryRunResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.DryRunError != nil {
+		return nil, c.DryRunError
+	}
+
+	var result []DryRunResult
+	for _, e := range entities {
+		dryRun, err := entityToUnstructured(e)
+		if err != nil {
+			return nil, err
+		}
+
+		r := DryRunResult{Entity: e, DryRun: dryRun}
+		live, ok := c.entities[c.currentVersions[e.Name()]]
+		if ok && live.GVK() == e.GVK() && live.Namespace() == e.Namespace() {
+			r.Live, err = entityToUnstructured(live)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
 func (c *FakeK8sClient) Delete(_ context.Context, entities []K8sEntity, wait time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -460,6 +537,12 @@ func (c *FakeK8sClient) Inject(entities ...K8sEntity) {
 		}
 		c.entities[entity.UID()] = entity
 		c.currentVersions[entity.Name()] = entity.UID()
+
+		for _, w := range c.metaWatches {
+			if w.gvk == entity.GVK() && w.ns == entity.Namespace() {
+				w.ch <- entity.Meta()
+			}
+		}
 	}
 }
 
@@ -476,6 +559,19 @@ func (c *FakeK8sClient) GetMetaByReference(ctx context.Context, ref v1.ObjectRef
 	return resp.Meta(), nil
 }
 
+func (c *FakeK8sClient) GetByReference(ctx context.Context, ref v1.ObjectReference) (*unstructured.Unstructured, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.getByReferenceCallCount++
+	resp, ok := c.entities[ref.UID]
+	if !ok {
+		logger.Get(ctx).Infof("FakeK8sClient.GetByReference: resource not found: %s", ref.Name)
+		return nil, apierrors.NewNotFound(v1.Resource(ref.Kind), ref.Name)
+	}
+	return entityToUnstructured(resp)
+}
+
 func (c *FakeK8sClient) ListMeta(_ context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -0,0 +1,76 @@
+package k8s
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var crdGroupKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// Returns copies of the entities, with the namespace set on every namespaced
+// entity that doesn't already specify one.
+//
+// Asks the cluster whether each kind is namespaced, so that cluster-scoped
+// kinds (including cluster-scoped custom resources) are left alone. If the
+// cluster doesn't know a kind yet, we check for a CustomResourceDefinition
+// in the same list that defines it. Otherwise, we assume it's namespaced.
+func InjectNamespace(ctx context.Context, client Client, entities []K8sEntity, ns Namespace) ([]K8sEntity, error) {
+	var crdScopes map[schema.GroupKind]bool
+	clusterScoped := make(map[schema.GroupKind]bool)
+	result := make([]K8sEntity, 0, len(entities))
+	for _, e := range entities {
+		if e.Meta().GetNamespace() != "" {
+			result = append(result, e)
+			continue
+		}
+
+		gk := e.GVK().GroupKind()
+		isClusterScoped, ok := clusterScoped[gk]
+		if !ok {
+			var err error
+			isClusterScoped, err = client.IsClusterScoped(ctx, e.GVK())
+			if err != nil {
+				if !meta.IsNoMatchError(err) {
+					return nil, err
+				}
+				if crdScopes == nil {
+					crdScopes = customResourceScopes(entities)
+				}
+				isClusterScoped = crdScopes[gk]
+			}
+			clusterScoped[gk] = isClusterScoped
+		}
+
+		if !isClusterScoped {
+			e = e.WithNamespace(ns.String())
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// Indexes the CustomResourceDefinitions in the list by the kind they define,
+// and whether that kind is cluster-scoped.
+func customResourceScopes(entities []K8sEntity) map[schema.GroupKind]bool {
+	result := make(map[schema.GroupKind]bool)
+	for _, e := range entities {
+		if e.GVK().GroupKind() != crdGroupKind {
+			continue
+		}
+
+		obj, err := entityToUnstructured(e)
+		if err != nil {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		scope, _, _ := unstructured.NestedString(obj.Object, "spec", "scope")
+		if kind != "" {
+			result[schema.GroupKind{Group: group, Kind: kind}] = scope == "Cluster"
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+)
+
+func TestInjectNamespace(t *testing.T) {
+	entities, err := ParseYAMLFromString(testyaml.SanchoYAML + "\n---\n" +
+		testyaml.DoggosDeploymentYaml + "\n---\n" +
+		testyaml.MyNamespaceYAML)
+	require.NoError(t, err)
+	require.Len(t, entities, 3)
+
+	result, err := InjectNamespace(context.Background(), NewFakeK8sClient(t), entities, "alice-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "alice-dev", result[0].Meta().GetNamespace())
+	assert.Equal(t, "the-dog-zone", result[1].Meta().GetNamespace())
+	assert.Equal(t, "", result[2].Meta().GetNamespace())
+
+	// The originals are untouched.
+	assert.Equal(t, "", entities[0].Meta().GetNamespace())
+}
+
+func TestInjectNamespaceClusterScopedCustomResource(t *testing.T) {
+	entities, err := ParseYAMLFromString(`
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: letsencrypt
+`)
+	require.NoError(t, err)
+
+	client := NewFakeK8sClient(t)
+	client.ClusterScopedKinds[schema.GroupKind{Group: "cert-manager.io", Kind: "ClusterIssuer"}] = true
+
+	result, err := InjectNamespace(context.Background(), client, entities, "alice-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "", result[0].Meta().GetNamespace())
+}
+
+func TestInjectNamespaceUsesCRDScopeForUnknownKinds(t *testing.T) {
+	entities, err := ParseYAMLFromString(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tenants.example.dev
+spec:
+  group: example.dev
+  names:
+    kind: Tenant
+    plural: tenants
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true
+---
+apiVersion: example.dev/v1
+kind: Tenant
+metadata:
+  name: alice
+---
+apiVersion: example.dev/v1
+kind: Widget
+metadata:
+  name: gear
+`)
+	require.NoError(t, err)
+	require.Len(t, entities, 3)
+
+	client := &unknownKindsClient{
+		FakeK8sClient: NewFakeK8sClient(t),
+		unknown:       map[string]bool{"example.dev": true},
+	}
+	result, err := InjectNamespace(context.Background(), client, entities, "alice-dev")
+	require.NoError(t, err)
+	assert.Equal(t, "", result[0].Meta().GetNamespace())
+	assert.Equal(t, "", result[1].Meta().GetNamespace())
+	assert.Equal(t, "alice-dev", result[2].Meta().GetNamespace())
+}
+
+// A client that hasn't discovered the kinds in some API groups yet,
+// e.g., because their CRDs haven't been applied.
+type unknownKindsClient struct {
+	*FakeK8sClient
+	unknown map[string]bool
+}
+
+func (c *unknownKindsClient) IsClusterScoped(ctx context.Context, gvk schema.GroupVersionKind) (bool, error) {
+	if c.unknown[gvk.Group] {
+		return false, &meta.NoKindMatchError{GroupKind: gvk.GroupKind()}
+	}
+	return c.FakeK8sClient.IsClusterScoped(ctx, gvk)
+}
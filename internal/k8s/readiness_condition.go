@@ -0,0 +1,147 @@
+package k8s
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/tilt-dev/tilt/internal/k8s/jsonpath"
+)
+
+// A condition on an object's fields that determines whether it's ready.
+//
+// The condition is a JSONPath, optionally followed by == or != and a value.
+// See v1alpha1.KubernetesReadinessCheck for the syntax.
+type ReadinessCondition struct {
+	expr  string
+	path  string
+	op    string
+	value string
+}
+
+// Matches the [key=value] list filter shorthand.
+var readinessFilterShorthand = regexp.MustCompile(`\[([A-Za-z0-9_.-]+)=([^\]=]+)\]`)
+
+func ParseReadinessCondition(expr string) (ReadinessCondition, error) {
+	pathExpr := strings.TrimSpace(expr)
+	op := ""
+	value := ""
+	for _, candidate := range []string{"==", "!="} {
+		idx := strings.LastIndex(pathExpr, candidate)
+		if idx == -1 {
+			continue
+		}
+
+		// Don't split on comparisons inside a JSONPath filter.
+		if strings.Contains(pathExpr[idx:], "]") || strings.Contains(pathExpr[idx:], "}") {
+			continue
+		}
+
+		op = candidate
+		value = unquote(strings.TrimSpace(pathExpr[idx+len(candidate):]))
+		pathExpr = strings.TrimSpace(pathExpr[:idx])
+		break
+	}
+
+	if pathExpr == "" {
+		return ReadinessCondition{}, fmt.Errorf("invalid readiness condition %q: missing JSONPath", expr)
+	}
+
+	path := normalizeReadinessPath(pathExpr)
+	err := jsonpath.New("readiness").Parse(path)
+	if err != nil {
+		return ReadinessCondition{}, fmt.Errorf("invalid readiness condition %q: %v", expr, err)
+	}
+
+	return ReadinessCondition{
+		expr:  expr,
+		path:  path,
+		op:    op,
+		value: value,
+	}, nil
+}
+
+// Expands the shorthand syntax into a full JSONPath template.
+func normalizeReadinessPath(path string) string {
+	if strings.HasPrefix(path, "{") {
+		return path
+	}
+
+	path = readinessFilterShorthand.ReplaceAllString(path, `[?(@.$1=="$2")]`)
+	if !strings.HasPrefix(path, ".") {
+		path = "." + path
+	}
+	return "{" + path + "}"
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Evaluates the condition against an object decoded from JSON
+// (e.g., the Object field of an unstructured.Unstructured).
+//
+// Returns whether the condition holds and, if it doesn't,
+// a human-readable explanation.
+func (c ReadinessCondition) Evaluate(obj interface{}) (bool, string) {
+	// Objects often don't have a status until their controller has seen them,
+	// so a missing field means "not ready" rather than an error.
+	//
+	// JSONPath is stateful and not thread-safe, so we parse a new one each time.
+	matcher := jsonpath.New("readiness").AllowMissingKeys(true)
+	err := matcher.Parse(c.path)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	matches, err := matcher.FindResults(obj)
+	if err != nil {
+		return false, fmt.Sprintf("%s: %v", c.path, err)
+	}
+
+	var values []string
+	for _, matchSet := range matches {
+		for _, match := range matchSet {
+			values = append(values, fmt.Sprintf("%v", match.Interface()))
+		}
+	}
+
+	if len(values) == 0 {
+		return false, fmt.Sprintf("%s: no value found", c.path)
+	}
+
+	for _, v := range values {
+		ok := false
+		switch c.op {
+		case "==":
+			ok = strings.EqualFold(v, c.value)
+		case "!=":
+			ok = !strings.EqualFold(v, c.value)
+		default:
+			ok = v != "" && v != "0" && !strings.EqualFold(v, "false")
+		}
+
+		if !ok {
+			return false, fmt.Sprintf("%s: got %q, want %s", c.path, v, c.describeWant())
+		}
+	}
+	return true, ""
+}
+
+func (c ReadinessCondition) describeWant() string {
+	switch c.op {
+	case "==":
+		return fmt.Sprintf("%q", c.value)
+	case "!=":
+		return fmt.Sprintf("not %q", c.value)
+	default:
+		return "a non-empty value"
+	}
+}
+
+func (c ReadinessCondition) String() string {
+	return c.expr
+}
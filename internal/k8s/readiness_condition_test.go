@@ -0,0 +1,60 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readinessTestObject() map[string]interface{} {
+	return map[string]interface{}{
+		"status": map[string]interface{}{
+			"phase":         "Running",
+			"readyReplicas": int64(0),
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "Provisioning"},
+			},
+		},
+	}
+}
+
+func TestReadinessCondition(t *testing.T) {
+	for _, tc := range []struct {
+		expr  string
+		ready bool
+		msg   string
+	}{
+		{"status.phase == Running", true, ""},
+		{"status.phase == 'running'", true, ""},
+		{"{.status.phase} != Failed", true, ""},
+		{"status.phase == Pending", false, `{.status.phase}: got "Running", want "Pending"`},
+		{"status.conditions[type=Progressing].status == True", true, ""},
+		{"status.conditions[type=Ready].status == True", false,
+			`{.status.conditions[?(@.type=="Ready")].status}: got "False", want "True"`},
+		{`{.status.conditions[?(@.type=="Ready")].reason} == Provisioning`, true, ""},
+		{"status.phase", true, ""},
+		{"status.readyReplicas", false, `{.status.readyReplicas}: got "0", want a non-empty value`},
+		{"status.missing", false, "{.status.missing}: no value found"},
+	} {
+		t.Run(tc.expr, func(t *testing.T) {
+			c, err := ParseReadinessCondition(tc.expr)
+			require.NoError(t, err)
+
+			ready, msg := c.Evaluate(readinessTestObject())
+			assert.Equal(t, tc.ready, ready)
+			assert.Equal(t, tc.msg, msg)
+		})
+	}
+}
+
+func TestReadinessConditionInvalid(t *testing.T) {
+	_, err := ParseReadinessCondition("== True")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "missing JSONPath")
+	}
+
+	_, err = ParseReadinessCondition("status.conditions[")
+	assert.Error(t, err)
+}
@@ -15,6 +15,9 @@ import (
 	cmdutil "k8s.io/kubectl/pkg/cmd/util"
 )
 
+// The field manager that Tilt applies objects as.
+const FieldManager = "tilt"
+
 // SSAOptions configures server-side apply behavior.
 type SSAOptions struct {
 	Enabled      bool
@@ -25,6 +28,7 @@ type SSAOptions struct {
 // We've adapted Helm's kubernetes client for our needs
 type ResourceClient interface {
 	Apply(target kube.ResourceList, ssa SSAOptions) (*kube.Result, error)
+	DryRunApply(target kube.ResourceList, ssa SSAOptions) (*kube.Result, error)
 	CreateOrReplace(target kube.ResourceList) (*kube.Result, error)
 	Delete(existing kube.ResourceList) (*kube.Result, []error)
 	Create(l kube.ResourceList) (*kube.Result, error)
@@ -39,6 +43,18 @@ type resourceClient struct {
 // Helm's update function doesn't really work for us,
 // so we use the kubectl apply code directly.
 func (c *resourceClient) Apply(target kube.ResourceList, ssa SSAOptions) (*kube.Result, error) {
+	return c.apply(target, ssa, cmdutil.DryRunNone)
+}
+
+// Runs the same apply as Apply with dryRun=All, so that admission webhooks
+// and defaulting run but nothing is persisted.
+//
+// Each Info in the target is refreshed with the object the server returned.
+func (c *resourceClient) DryRunApply(target kube.ResourceList, ssa SSAOptions) (*kube.Result, error) {
+	return c.apply(target, ssa, cmdutil.DryRunServer)
+}
+
+func (c *resourceClient) apply(target kube.ResourceList, ssa SSAOptions, dryRun cmdutil.DryRunStrategy) (*kube.Result, error) {
 	f := c.factory
 	iostreams := genericclioptions.IOStreams{
 		In:     strings.NewReader(""),
@@ -95,6 +111,8 @@ func (c *resourceClient) Apply(target kube.ResourceList, ssa SSAOptions) (*kube.
 
 		IOStreams: flags.IOStreams,
 
+		DryRunStrategy: dryRun,
+
 		VisitedUids:       sets.New[types.UID](),
 		VisitedNamespaces: sets.New[string](),
 	}
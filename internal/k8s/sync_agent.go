@@ -0,0 +1,181 @@
+package k8s
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/distribution/reference"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/syncagent"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+const (
+	syncAgentContainerName   = "tilt-sync-agent"
+	syncAgentVolumeName      = "tilt-sync-agent"
+	syncAgentTokenVolumeName = "tilt-sync-agent-token"
+)
+
+// SyncAgentSecretName is the name of the Secret that holds the sync agent's
+// token for a workload.
+//
+// Each workload gets its own Secret, owned by the Tilt instance that deploys it.
+func SyncAgentSecretName(e K8sEntity) string {
+	name := fmt.Sprintf("tilt-sync-agent-%s-%s", strings.ToLower(e.GVK().Kind), e.Name())
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	hash := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(hash[:])[:10]
+	return name[:validation.DNS1123SubdomainMaxLength-len(suffix)] + suffix
+}
+
+// NewSyncAgentSecret creates the Secret that InjectSyncAgent mounts the token from.
+func NewSyncAgentSecret(name string, namespace Namespace, token syncagent.Token) K8sEntity {
+	return NewK8sEntity(&v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace.String(),
+			Labels:    NewTiltLabelMap(),
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: map[string][]byte{syncagent.SecretKeyToken: []byte(token.String())},
+	})
+}
+
+// InjectSyncAgent injects the live update sync agent into every container
+// that runs the given image.
+//
+// An init container copies the agent from the agent image into an emptyDir,
+// and the container's command is wrapped with the agent. If the container
+// doesn't set a command, we wrap the image's ENTRYPOINT and CMD from the
+// ImageMap status. The agent reads its token from the Secret named secretName.
+//
+// Returns false if no container runs the image.
+func InjectSyncAgent(entity K8sEntity, ref reference.Named, agent v1alpha1.LiveUpdateSyncAgent,
+	status v1alpha1.ImageMapStatus, secretName string) (K8sEntity, bool, error) {
+	entity = entity.DeepCopy()
+
+	pods, err := ExtractPods(&entity)
+	if err != nil {
+		return K8sEntity{}, false, err
+	}
+
+	image := agent.Image
+	if image == "" {
+		image = syncagent.DefaultImage
+	}
+
+	selector := container.NewRefSelector(ref)
+	injected := false
+	for _, pod := range pods {
+		podInjected := false
+		for i := range pod.Containers {
+			c := &pod.Containers[i]
+			existingRef, err := container.ParseNamed(c.Image)
+			if err != nil {
+				return K8sEntity{}, false, err
+			}
+			if !selector.Matches(existingRef) {
+				continue
+			}
+
+			err = wrapWithSyncAgent(c, agent.Port, status)
+			if err != nil {
+				return K8sEntity{}, false, fmt.Errorf("injecting sync agent into %s: %v", entity.Name(), err)
+			}
+			podInjected = true
+		}
+
+		if podInjected {
+			injectSyncAgentVolumes(pod, image, secretName)
+			injected = true
+		}
+	}
+	return entity, injected, nil
+}
+
+func wrapWithSyncAgent(c *v1.Container, port int32, status v1alpha1.ImageMapStatus) error {
+	if len(c.Command) > 0 && c.Command[0] == syncagent.InstallPath {
+		return nil
+	}
+
+	// Follow Kubernetes' rules for combining the container's command and args
+	// with the image's ENTRYPOINT and CMD.
+	command, args := c.Command, c.Args
+	if len(command) == 0 {
+		command = status.Entrypoint
+		if len(args) == 0 {
+			args = status.Cmd
+		}
+	}
+	if len(command) == 0 && len(args) == 0 {
+		return fmt.Errorf("container %q has no command, and Tilt couldn't read the image's entrypoint. "+
+			"Set a command in the container spec, or entrypoint in the image build", c.Name)
+	}
+
+	c.Command = append([]string{
+		syncagent.InstallPath,
+		"--port", strconv.Itoa(int(port)),
+		"--token-file", syncagent.TokenPath,
+		"--",
+	}, command...)
+	c.Args = args
+
+	addVolumeMount(c, v1.VolumeMount{Name: syncAgentVolumeName, MountPath: syncagent.InstallDir, ReadOnly: true})
+	addVolumeMount(c, v1.VolumeMount{Name: syncAgentTokenVolumeName, MountPath: syncagent.TokenDir, ReadOnly: true})
+	return nil
+}
+
+func injectSyncAgentVolumes(pod *v1.PodSpec, image, secretName string) {
+	addVolume(pod, v1.Volume{
+		Name:         syncAgentVolumeName,
+		VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+	})
+	addVolume(pod, v1.Volume{
+		Name:         syncAgentTokenVolumeName,
+		VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: secretName}},
+	})
+
+	for _, c := range pod.InitContainers {
+		if c.Name == syncAgentContainerName {
+			return
+		}
+	}
+	pod.InitContainers = append(pod.InitContainers, v1.Container{
+		Name:    syncAgentContainerName,
+		Image:   image,
+		Command: []string{syncagent.ImageBinaryPath, "install", syncagent.InstallPath},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: syncAgentVolumeName, MountPath: syncagent.InstallDir},
+		},
+	})
+}
+
+func addVolume(pod *v1.PodSpec, vol v1.Volume) {
+	for i := range pod.Volumes {
+		if pod.Volumes[i].Name == vol.Name {
+			pod.Volumes[i] = vol
+			return
+		}
+	}
+	pod.Volumes = append(pod.Volumes, vol)
+}
+
+func addVolumeMount(c *v1.Container, mount v1.VolumeMount) {
+	for i := range c.VolumeMounts {
+		if c.VolumeMounts[i].Name == mount.Name {
+			c.VolumeMounts[i] = mount
+			return
+		}
+	}
+	c.VolumeMounts = append(c.VolumeMounts, mount)
+}
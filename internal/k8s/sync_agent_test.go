@@ -0,0 +1,111 @@
+package k8s
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/syncagent"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestInjectSyncAgentWrapsCommand(t *testing.T) {
+	entity := parseOneEntity(t, testyaml.SanchoYAMLWithCommand)
+	ref, err := reference.ParseNamed("gcr.io/some-project-162817/sancho")
+	require.NoError(t, err)
+
+	newEntity, injected, err := InjectSyncAgent(entity, ref,
+		v1alpha1.LiveUpdateSyncAgent{Port: 9000}, v1alpha1.ImageMapStatus{}, "sancho-token")
+	require.NoError(t, err)
+	assert.True(t, injected)
+
+	pod := newEntity.Obj.(*appsv1.Deployment).Spec.Template.Spec
+	c := pod.Containers[0]
+	assert.Equal(t, []string{
+		syncagent.InstallPath, "--port", "9000", "--token-file", syncagent.TokenPath, "--", "foo.sh",
+	}, c.Command)
+	assert.Equal(t, []string{"something", "something_else"}, c.Args)
+	assert.ElementsMatch(t, []string{syncagent.InstallDir, syncagent.TokenDir},
+		[]string{c.VolumeMounts[0].MountPath, c.VolumeMounts[1].MountPath})
+
+	require.Len(t, pod.InitContainers, 1)
+	assert.Equal(t, syncagent.DefaultImage, pod.InitContainers[0].Image)
+	assert.Equal(t, []string{syncagent.ImageBinaryPath, "install", syncagent.InstallPath}, pod.InitContainers[0].Command)
+
+	require.Len(t, pod.Volumes, 2)
+	assert.NotNil(t, pod.Volumes[0].EmptyDir)
+	assert.Equal(t, "sancho-token", pod.Volumes[1].Secret.SecretName)
+
+	// The token isn't in the pod spec.
+	assert.Empty(t, c.Env)
+
+	// The original is unchanged.
+	c = entity.Obj.(*appsv1.Deployment).Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"foo.sh"}, c.Command)
+}
+
+func TestInjectSyncAgentUsesImageEntrypoint(t *testing.T) {
+	entity := parseOneEntity(t, testyaml.SanchoYAML)
+	ref, err := reference.ParseNamed("gcr.io/some-project-162817/sancho")
+	require.NoError(t, err)
+
+	status := v1alpha1.ImageMapStatus{Entrypoint: []string{"/app/server"}, Cmd: []string{"--verbose"}}
+	newEntity, injected, err := InjectSyncAgent(entity, ref,
+		v1alpha1.LiveUpdateSyncAgent{Port: 8473, Image: "my-agent:dev"}, status, "sancho-token")
+	require.NoError(t, err)
+	assert.True(t, injected)
+
+	pod := newEntity.Obj.(*appsv1.Deployment).Spec.Template.Spec
+	assert.Equal(t, "/app/server", pod.Containers[0].Command[len(pod.Containers[0].Command)-1])
+	assert.Equal(t, []string{"--verbose"}, pod.Containers[0].Args)
+	assert.Equal(t, "my-agent:dev", pod.InitContainers[0].Image)
+
+	// Injecting twice doesn't wrap the command twice.
+	newEntity, _, err = InjectSyncAgent(newEntity, ref,
+		v1alpha1.LiveUpdateSyncAgent{Port: 8473, Image: "my-agent:dev"}, status, "sancho-token")
+	require.NoError(t, err)
+	pod = newEntity.Obj.(*appsv1.Deployment).Spec.Template.Spec
+	assert.Equal(t, 1, strings.Count(strings.Join(pod.Containers[0].Command, " "), syncagent.InstallPath))
+	assert.Len(t, pod.InitContainers, 1)
+	assert.Len(t, pod.Volumes, 2)
+}
+
+func TestInjectSyncAgentUnknownEntrypoint(t *testing.T) {
+	entity := parseOneEntity(t, testyaml.SanchoYAML)
+	ref, err := reference.ParseNamed("gcr.io/some-project-162817/sancho")
+	require.NoError(t, err)
+
+	_, _, err = InjectSyncAgent(entity, ref,
+		v1alpha1.LiveUpdateSyncAgent{Port: 8473}, v1alpha1.ImageMapStatus{}, "sancho-token")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "couldn't read the image's entrypoint")
+}
+
+func TestInjectSyncAgentOtherImage(t *testing.T) {
+	entity := parseOneEntity(t, testyaml.SanchoYAMLWithCommand)
+	ref, err := reference.ParseNamed("gcr.io/some-project-162817/other")
+	require.NoError(t, err)
+
+	newEntity, injected, err := InjectSyncAgent(entity, ref,
+		v1alpha1.LiveUpdateSyncAgent{Port: 8473}, v1alpha1.ImageMapStatus{}, "sancho-token")
+	require.NoError(t, err)
+	assert.False(t, injected)
+	assert.Empty(t, newEntity.Obj.(*appsv1.Deployment).Spec.Template.Spec.InitContainers)
+}
+
+func TestSyncAgentSecret(t *testing.T) {
+	entity := parseOneEntity(t, testyaml.SanchoYAML)
+	name := SyncAgentSecretName(entity)
+	assert.Equal(t, "tilt-sync-agent-deployment-sancho", name)
+
+	secret := NewSyncAgentSecret(name, "sancho-ns", "fake-token").Obj.(*v1.Secret)
+	assert.Equal(t, "sancho-ns", secret.Namespace)
+	assert.Equal(t, "fake-token", string(secret.Data[syncagent.SecretKeyToken]))
+	assert.Equal(t, ManagedByValue, secret.Labels[ManagedByLabel])
+}
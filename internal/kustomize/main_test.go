@@ -0,0 +1,11 @@
+package kustomize
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	RunRendererIfRequested()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,299 @@
+package kustomize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+)
+
+// The helm binary used to inflate helmCharts, if none is specified.
+const DefaultHelmCommand = "helm"
+
+type RenderOptions struct {
+	// Extra components to apply on top of the kustomization.
+	// Absolute paths to directories.
+	Components []string
+
+	// Inflate helmCharts with the helm binary,
+	// like `kustomize build --enable-helm`.
+	EnableHelm  bool
+	HelmCommand string
+
+	// Allow plugins that aren't built in,
+	// like `kustomize build --enable-alpha-plugins`.
+	EnableAlphaPlugins bool
+
+	// Allow exec plugins, like `kustomize build --enable-exec`.
+	EnableExec bool
+
+	// Where to write kustomize's warnings (e.g., about deprecated fields).
+	// Discarded if nil.
+	Warnings io.Writer
+}
+
+// Set in the environment of a Tilt subprocess that should render
+// a kustomization and exit. See RunRendererIfRequested.
+const rendererEnv = "TILT_KUSTOMIZE_RENDERER"
+
+type renderRequest struct {
+	Dir                string
+	Components         []string
+	EnableHelm         bool
+	HelmCommand        string
+	EnableAlphaPlugins bool
+	EnableExec         bool
+}
+
+type renderResponse struct {
+	YAML  []byte
+	Deps  []string
+	Error string
+}
+
+// Render builds the kustomization in dir with the kustomize library,
+// the same way `kustomize build` does, without shelling out to a
+// kustomize binary.
+//
+// Kustomize prints its warnings straight to stderr and the standard logger.
+// So that they don't land on Tilt's own stderr, the library runs in a
+// subprocess of the current executable, and its stderr goes to opts.Warnings.
+//
+// Returns the rendered YAML, and every local file that kustomize read
+// while rendering it.
+func Render(ctx context.Context, dir string, opts RenderOptions) ([]byte, []string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := json.Marshal(renderRequest{
+		Dir:                dir,
+		Components:         opts.Components,
+		EnableHelm:         opts.EnableHelm,
+		HelmCommand:        opts.HelmCommand,
+		EnableAlphaPlugins: opts.EnableAlphaPlugins,
+		EnableExec:         opts.EnableExec,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, nil, fmt.Errorf("finding tilt executable: %v", err)
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, exe)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), rendererEnv+"=1")
+	cmd.Stdin = bytes.NewReader(req)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	runErr := cmd.Run()
+
+	var resp renderResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		if runErr == nil {
+			runErr = err
+		}
+		return nil, nil, fmt.Errorf("kustomize renderer failed: %v\n%s", runErr, stderr.String())
+	}
+
+	if opts.Warnings != nil {
+		_, _ = opts.Warnings.Write(stderr.Bytes())
+	}
+	if resp.Error != "" {
+		return nil, nil, errors.New(resp.Error)
+	}
+	return resp.YAML, resp.Deps, nil
+}
+
+// RunRendererIfRequested renders a kustomization and exits,
+// if the current process was started by Render.
+//
+// Must be called at the start of main(), and of TestMain() in any
+// package whose tests call Render.
+func RunRendererIfRequested() {
+	if os.Getenv(rendererEnv) == "" {
+		return
+	}
+	_ = os.Unsetenv(rendererEnv)
+
+	// Kustomize's log.Printf warnings are read by a human, so skip the timestamp.
+	log.SetFlags(0)
+
+	var resp renderResponse
+	var req renderRequest
+	err := json.NewDecoder(os.Stdin).Decode(&req)
+	if err == nil {
+		resp.YAML, resp.Deps, err = render(req)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	err = json.NewEncoder(os.Stdout).Encode(resp)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "encoding kustomize output: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func render(req renderRequest) ([]byte, []string, error) {
+	fSys := &recordingFS{FileSystem: filesys.MakeFsOnDisk(), reads: make(map[string]bool)}
+	root := req.Dir
+	if len(req.Components) > 0 {
+		var err error
+		root, err = writeComponentsKustomization(req.Dir, req.Components)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer func() { _ = os.RemoveAll(root) }()
+
+		// The generated kustomization isn't something to watch.
+		fSys.ignoreDir = root
+	}
+
+	k := krusty.MakeKustomizer(makeKrustyOptions(req))
+	m, err := k.Run(fSys, root)
+	if err != nil {
+		return nil, nil, err
+	}
+	yaml, err := m.AsYaml()
+	if err != nil {
+		return nil, nil, err
+	}
+	return yaml, fSys.deps(), nil
+}
+
+func makeKrustyOptions(opts renderRequest) *krusty.Options {
+	kOpts := krusty.MakeDefaultOptions()
+
+	// Match the default ordering of `kustomize build`.
+	kOpts.Reorder = krusty.ReorderOptionLegacy
+
+	if opts.EnableAlphaPlugins {
+		kOpts.PluginConfig = types.EnabledPluginConfig(types.BploUseStaticallyLinked)
+		kOpts.PluginConfig.FnpLoadingOptions.EnableExec = opts.EnableExec
+	}
+	kOpts.PluginConfig.HelmConfig.Enabled = opts.EnableHelm
+	kOpts.PluginConfig.HelmConfig.Command = opts.HelmCommand
+	if kOpts.PluginConfig.HelmConfig.Command == "" {
+		kOpts.PluginConfig.HelmConfig.Command = DefaultHelmCommand
+	}
+	return kOpts
+}
+
+// Writes a kustomization to a temp dir that applies the components on top of dir.
+//
+// Kustomize won't load a kustomization root from an absolute path,
+// so the entries are relative to the temp dir.
+func writeComponentsKustomization(dir string, components []string) (string, error) {
+	tmp, err := os.MkdirTemp("", "tilt-kustomize-")
+	if err != nil {
+		return "", err
+	}
+
+	rel := func(p string) (string, error) {
+		r, err := filepath.Rel(tmp, p)
+		if err != nil {
+			return "", fmt.Errorf("component %s: %v", p, err)
+		}
+		return filepath.ToSlash(r), nil
+	}
+
+	base, err := rel(dir)
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString("apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n")
+	fmt.Fprintf(&sb, "- %s\ncomponents:\n", base)
+	for _, c := range components {
+		r, err := rel(c)
+		if err != nil {
+			_ = os.RemoveAll(tmp)
+			return "", err
+		}
+		fmt.Fprintf(&sb, "- %s\n", r)
+	}
+
+	err = os.WriteFile(filepath.Join(tmp, "kustomization.yaml"), []byte(sb.String()), 0o644)
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", err
+	}
+	return tmp, nil
+}
+
+// Records every file that kustomize reads.
+type recordingFS struct {
+	filesys.FileSystem
+
+	// Reads under this dir aren't recorded.
+	ignoreDir string
+
+	mu    sync.Mutex
+	reads map[string]bool
+}
+
+func (fs *recordingFS) Open(path string) (filesys.File, error) {
+	fs.record(path)
+	return fs.FileSystem.Open(path)
+}
+
+func (fs *recordingFS) ReadFile(path string) ([]byte, error) {
+	fs.record(path)
+	return fs.FileSystem.ReadFile(path)
+}
+
+func (fs *recordingFS) record(path string) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return
+	}
+	if fs.ignoreDir != "" && strings.HasPrefix(abs, fs.ignoreDir+string(filepath.Separator)) {
+		return
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.reads[abs] = true
+}
+
+// Returns the files read, sorted.
+//
+// Skips files that no longer exist, like remote bases that
+// kustomize cloned into a temp dir and cleaned up.
+func (fs *recordingFS) deps() []string {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	var result []string
+	for p := range fs.reads {
+		info, err := os.Stat(p)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		result = append(result, p)
+	}
+	sort.Strings(result)
+	return result
+}
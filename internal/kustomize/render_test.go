@@ -0,0 +1,125 @@
+package kustomize
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+)
+
+const renderDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        image: web
+`
+
+func TestRender(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("base/kustomization.yaml", `
+resources:
+- deployment.yaml
+configMapGenerator:
+- name: web-config
+  files:
+  - config.properties
+`)
+	f.WriteFile("base/deployment.yaml", renderDeployment)
+	f.WriteFile("base/config.properties", "color=blue\n")
+	f.WriteFile("base/unused.yaml", "kind: Secret\n")
+	f.WriteFile("overlay/kustomization.yaml", `
+resources:
+- ../base
+namePrefix: dev-
+`)
+
+	yaml, deps, err := Render(context.Background(), f.JoinPath("overlay"), RenderOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, string(yaml), "name: dev-web\n")
+	assert.Contains(t, string(yaml), "color=blue")
+	assert.Equal(t, []string{
+		f.JoinPath("base", "config.properties"),
+		f.JoinPath("base", "deployment.yaml"),
+		f.JoinPath("base", "kustomization.yaml"),
+		f.JoinPath("overlay", "kustomization.yaml"),
+	}, deps)
+}
+
+func TestRenderWarnings(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("base/kustomization.yaml", "resources:\n- deployment.yaml\n")
+	f.WriteFile("base/deployment.yaml", renderDeployment)
+	f.WriteFile("overlay/kustomization.yaml", "bases:\n- ../base\n")
+
+	warnings := &bytes.Buffer{}
+	yaml, _, err := Render(context.Background(), f.JoinPath("overlay"), RenderOptions{Warnings: warnings})
+	require.NoError(t, err)
+	assert.Contains(t, string(yaml), "name: web\n")
+	assert.Contains(t, warnings.String(), "'bases' is deprecated")
+}
+
+func TestRenderLoggedWarnings(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("kustomization.yaml", `
+resources:
+- deployment.yaml
+vars:
+- name: WEB_NAME
+  objref:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+`)
+	f.WriteFile("deployment.yaml", renderDeployment)
+
+	warnings := &bytes.Buffer{}
+	_, _, err := Render(context.Background(), f.Path(), RenderOptions{Warnings: warnings})
+	require.NoError(t, err)
+	assert.Contains(t, warnings.String(), "well-defined vars that were never replaced: WEB_NAME\n")
+}
+
+func TestRenderComponents(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("base/kustomization.yaml", "resources:\n- deployment.yaml\n")
+	f.WriteFile("base/deployment.yaml", renderDeployment)
+	f.WriteFile("debug/kustomization.yaml", `
+apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+commonLabels:
+  debug: "true"
+`)
+
+	yaml, deps, err := Render(context.Background(), f.JoinPath("base"), RenderOptions{
+		Components: []string{f.JoinPath("debug")},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(yaml), `debug: "true"`)
+	assert.Equal(t, []string{
+		f.JoinPath("base", "deployment.yaml"),
+		f.JoinPath("base", "kustomization.yaml"),
+		f.JoinPath("debug", "kustomization.yaml"),
+	}, deps)
+}
+
+func TestRenderHelmDisabled(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("kustomization.yaml", `
+helmCharts:
+- name: redis
+  repo: https://charts.example.com
+`)
+
+	_, _, err := Render(context.Background(), f.Path(), RenderOptions{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "--enable-helm")
+	}
+}
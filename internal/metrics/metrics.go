@@ -0,0 +1,70 @@
+// Package metrics exposes Prometheus metrics about Tilt's own performance,
+// so that teams can graph their local dev loop.
+//
+// The metrics are served at /metrics on the Tilt web server.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "tilt"
+
+var (
+	BuildsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "builds_total",
+		Help:      "Number of completed builds, by manifest and result.",
+	}, []string{"manifest", "result"})
+
+	BuildDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "build_duration_seconds",
+		Help:      "How long builds took, by manifest.",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600},
+	}, []string{"manifest"})
+
+	LiveUpdateSyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "live_update_sync_duration_seconds",
+		Help:      "How long it took to sync files and run commands in a container, by manifest.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"manifest"})
+
+	FileWatchEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "file_watch_events_total",
+		Help:      "Number of file change events seen, by FileWatch.",
+	}, []string{"filewatch"})
+
+	LogStoreBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "log_store_bytes",
+		Help:      "Size of the logs that Tilt is holding in memory.",
+	})
+)
+
+// Registry holds the Tilt metrics.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		BuildsTotal,
+		BuildDuration,
+		LiveUpdateSyncDuration,
+		FileWatchEventsTotal,
+		LogStoreBytes,
+	)
+}
+
+// Handler serves the Tilt metrics, along with the controller-runtime metrics
+// (e.g., workqueue_depth for the reconcile queue of each controller).
+func Handler() http.Handler {
+	return promhttp.HandlerFor(
+		prometheus.Gatherers{Registry, ctrlmetrics.Registry},
+		promhttp.HandlerOpts{})
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Subscriber records metrics that we read off the engine state.
+type Subscriber struct {
+	// The finish time of the last build we recorded for each manifest,
+	// so that we record each build exactly once.
+	lastFinishTimes map[model.ManifestName]time.Time
+}
+
+var _ store.Subscriber = &Subscriber{}
+
+func NewSubscriber() *Subscriber {
+	return &Subscriber{
+		lastFinishTimes: make(map[model.ManifestName]time.Time),
+	}
+}
+
+func (s *Subscriber) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	LogStoreBytes.Set(float64(state.LogStore.Len()))
+
+	for _, ms := range state.GetTiltfileStates() {
+		s.recordBuilds(ms)
+	}
+	for _, mt := range state.Targets() {
+		s.recordBuilds(mt.State)
+	}
+	return nil
+}
+
+func (s *Subscriber) recordBuilds(ms *store.ManifestState) {
+	name := ms.Name
+	lastFinishTime := s.lastFinishTimes[name]
+
+	// BuildHistory is ordered from newest to oldest.
+	for i := len(ms.BuildHistory) - 1; i >= 0; i-- {
+		b := ms.BuildHistory[i]
+		if b.FinishTime.IsZero() || !b.FinishTime.After(lastFinishTime) {
+			continue
+		}
+
+		result := "success"
+		if b.Error != nil {
+			result = "error"
+		}
+		BuildsTotal.WithLabelValues(name.String(), result).Inc()
+		BuildDuration.WithLabelValues(name.String()).Observe(b.Duration().Seconds())
+		lastFinishTime = b.FinishTime
+	}
+	s.lastFinishTimes[name] = lastFinishTime
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestSubscriberRecordsEachBuildOnce(t *testing.T) {
+	st := store.NewTestingStore()
+	s := NewSubscriber()
+	start := time.Now()
+
+	state := st.LockMutableStateForTesting()
+	mt := store.NewManifestTarget(model.Manifest{Name: "subscriber-test"})
+	mt.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  start,
+		FinishTime: start.Add(2 * time.Second),
+	})
+	state.UpsertManifestTarget(mt)
+	st.UnlockMutableState()
+
+	require.NoError(t, s.OnChange(context.Background(), st, store.ChangeSummary{}))
+	require.NoError(t, s.OnChange(context.Background(), st, store.ChangeSummary{}))
+	assert.Equal(t, 1.0, testutil.ToFloat64(BuildsTotal.WithLabelValues("subscriber-test", "success")))
+
+	state = st.LockMutableStateForTesting()
+	mt.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  start.Add(time.Minute),
+		FinishTime: start.Add(2 * time.Minute),
+		Error:      fmt.Errorf("oh no"),
+	})
+	st.UnlockMutableState()
+
+	require.NoError(t, s.OnChange(context.Background(), st, store.ChangeSummary{}))
+	assert.Equal(t, 1.0, testutil.ToFloat64(BuildsTotal.WithLabelValues("subscriber-test", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(BuildsTotal.WithLabelValues("subscriber-test", "error")))
+}
+
+func TestSubscriberRecordsLogStoreSize(t *testing.T) {
+	st := store.NewTestingStore()
+	s := NewSubscriber()
+
+	state := st.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewLogAction("", "", logger.InfoLvl, nil, []byte("hello world\n")), nil)
+	st.UnlockMutableState()
+
+	require.NoError(t, s.OnChange(context.Background(), st, store.ChangeSummary{}))
+	assert.Equal(t, float64(len("hello world\n")), testutil.ToFloat64(LogStoreBytes))
+}
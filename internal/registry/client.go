@@ -0,0 +1,403 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/docker/cli/cli/config"
+)
+
+const (
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+)
+
+var manifestAccept = strings.Join([]string{
+	mediaTypeDockerManifest,
+	mediaTypeDockerManifestList,
+	mediaTypeOCIManifest,
+	mediaTypeOCIIndex,
+}, ", ")
+
+// Credentials for a registry host. Empty if the registry allows anonymous access.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+type CredentialsFunc func(host string) Credentials
+
+// DockerConfigCredentials reads credentials from the Docker CLI config
+// (~/.docker/config.json), including credential helpers.
+func DockerConfigCredentials(host string) Credentials {
+	cf := config.LoadDefaultConfigFile(io.Discard)
+	ac, err := cf.GetAuthConfig(host)
+	if err != nil {
+		return Credentials{}
+	}
+	return Credentials{Username: ac.Username, Password: ac.Password}
+}
+
+// An image manifest, with the fields we need for garbage collection.
+type Manifest struct {
+	// The content digest of the manifest, as reported by the registry.
+	// This is what a delete needs.
+	Digest string
+
+	// The digest of the image config blob. For a multi-platform index,
+	// the config of the first platform.
+	ConfigDigest string
+}
+
+// The fields of an image config blob we need for garbage collection.
+type ImageConfig struct {
+	Created time.Time
+	Labels  map[string]string
+}
+
+// Client talks to a Docker Registry HTTP API V2.
+//
+// https://distribution.github.io/distribution/spec/api/
+type Client struct {
+	http  *http.Client
+	creds CredentialsFunc
+
+	mu     sync.Mutex
+	tokens map[string]string
+}
+
+func NewClient(creds CredentialsFunc) *Client {
+	return &Client{
+		http:   &http.Client{Timeout: time.Minute},
+		creds:  creds,
+		tokens: make(map[string]string),
+	}
+}
+
+// ProvideClient returns a client that authenticates
+// with the user's Docker credentials.
+func ProvideClient() *Client {
+	return NewClient(DockerConfigCredentials)
+}
+
+// Tags lists all the tags in a repository.
+func (c *Client) Tags(ctx context.Context, repo reference.Named) ([]string, error) {
+	u := c.url(repo, "tags/list")
+	var result []string
+	for u != "" {
+		resp, err := c.do(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		err = decodeJSON(resp, &body)
+		if err != nil {
+			return nil, fmt.Errorf("listing tags for %s: %v", reference.FamiliarName(repo), err)
+		}
+		result = append(result, body.Tags...)
+
+		u, err = nextPage(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// ManifestDigest looks up the digest of a tag without downloading the manifest.
+func (c *Client) ManifestDigest(ctx context.Context, repo reference.Named, tag string) (string, error) {
+	resp, err := c.do(ctx, http.MethodHead, c.url(repo, "manifests/"+tag), http.Header{"Accept": {manifestAccept}})
+	if err != nil {
+		return "", err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("manifest %s:%s: %s", reference.FamiliarName(repo), tag, resp.Status)
+	}
+	dig := resp.Header.Get("Docker-Content-Digest")
+	if dig == "" {
+		// The registry didn't tell us, so we have to hash the manifest.
+		m, err := c.Manifest(ctx, repo, tag)
+		if err != nil {
+			return "", err
+		}
+		dig = m.Digest
+	}
+	return dig, nil
+}
+
+// Manifest downloads the manifest for a tag or digest.
+func (c *Client) Manifest(ctx context.Context, repo reference.Named, tagOrDigest string) (Manifest, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.url(repo, "manifests/"+tagOrDigest), http.Header{"Accept": {manifestAccept}})
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return Manifest{}, fmt.Errorf("manifest %s:%s: %s", reference.FamiliarName(repo), tagOrDigest, resp.Status)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	dig := resp.Header.Get("Docker-Content-Digest")
+	if dig == "" {
+		dig = fmt.Sprintf("sha256:%x", sha256.Sum256(b))
+	}
+
+	var body struct {
+		Config struct {
+			Digest string `json:"digest"`
+		} `json:"config"`
+		Manifests []struct {
+			Digest string `json:"digest"`
+		} `json:"manifests"`
+	}
+	err = json.Unmarshal(b, &body)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("manifest %s:%s: %v", reference.FamiliarName(repo), tagOrDigest, err)
+	}
+
+	if body.Config.Digest == "" && len(body.Manifests) > 0 {
+		platform, err := c.Manifest(ctx, repo, body.Manifests[0].Digest)
+		if err != nil {
+			return Manifest{}, err
+		}
+		return Manifest{Digest: dig, ConfigDigest: platform.ConfigDigest}, nil
+	}
+	return Manifest{Digest: dig, ConfigDigest: body.Config.Digest}, nil
+}
+
+// ImageConfig downloads an image config blob.
+func (c *Client) ImageConfig(ctx context.Context, repo reference.Named, configDigest string) (ImageConfig, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.url(repo, "blobs/"+configDigest), nil)
+	if err != nil {
+		return ImageConfig{}, err
+	}
+
+	var body struct {
+		Created time.Time `json:"created"`
+		Config  struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"config"`
+	}
+	err = decodeJSON(resp, &body)
+	if err != nil {
+		return ImageConfig{}, fmt.Errorf("image config %s@%s: %v", reference.FamiliarName(repo), configDigest, err)
+	}
+	return ImageConfig{Created: body.Created, Labels: body.Config.Labels}, nil
+}
+
+// DeleteManifest deletes a manifest, and every tag that points to it.
+func (c *Client) DeleteManifest(ctx context.Context, repo reference.Named, digest string) error {
+	resp, err := c.do(ctx, http.MethodDelete, c.url(repo, "manifests/"+digest), nil)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound:
+		return nil
+	case http.StatusMethodNotAllowed:
+		return fmt.Errorf("deleting %s@%s: registry %s doesn't allow deletes "+
+			"(for the reference registry, set REGISTRY_STORAGE_DELETE_ENABLED=true)",
+			reference.FamiliarName(repo), digest, reference.Domain(repo))
+	default:
+		return fmt.Errorf("deleting %s@%s: %s", reference.FamiliarName(repo), digest, resp.Status)
+	}
+}
+
+func (c *Client) url(repo reference.Named, suffix string) string {
+	host := apiHost(reference.Domain(repo))
+	return fmt.Sprintf("%s://%s/v2/%s/%s", scheme(host), host, reference.Path(repo), suffix)
+}
+
+// Sends a request, authenticating if the registry asks us to.
+func (c *Client) do(ctx context.Context, method string, u string, header http.Header) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+	host := req.URL.Host
+
+	c.mu.Lock()
+	token := c.tokens[host]
+	c.mu.Unlock()
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return resp, nil
+	}
+
+	challenge := resp.Header.Get("WWW-Authenticate")
+	_ = resp.Body.Close()
+	token, err = c.authorize(ctx, host, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.tokens[host] = token
+	c.mu.Unlock()
+
+	req, err = newRequest()
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", token)
+	return c.http.Do(req)
+}
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// Answers an auth challenge, and returns the value of the Authorization header.
+func (c *Client) authorize(ctx context.Context, host string, challenge string) (string, error) {
+	creds := c.creds(registryConfigKey(host))
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if creds.Username == "" {
+			return "", fmt.Errorf("registry %s requires credentials. Try `docker login %s`", host, host)
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(creds.Username, creds.Password)
+		return req.Header.Get("Authorization"), nil
+
+	case "bearer":
+		p := make(map[string]string)
+		for _, m := range challengeParamRegexp.FindAllStringSubmatch(params, -1) {
+			p[m[1]] = m[2]
+		}
+		realm, err := url.Parse(p["realm"])
+		if err != nil || p["realm"] == "" {
+			return "", fmt.Errorf("registry %s: malformed auth challenge: %s", host, challenge)
+		}
+		q := realm.Query()
+		if p["service"] != "" {
+			q.Set("service", p["service"])
+		}
+		if p["scope"] != "" {
+			q.Set("scope", p["scope"])
+		}
+		realm.RawQuery = q.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if creds.Username != "" {
+			req.SetBasicAuth(creds.Username, creds.Password)
+		}
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return "", err
+		}
+		var body struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		err = decodeJSON(resp, &body)
+		if err != nil {
+			return "", fmt.Errorf("registry %s: fetching token: %v", host, err)
+		}
+		if body.Token == "" {
+			body.Token = body.AccessToken
+		}
+		return "Bearer " + body.Token, nil
+	}
+	return "", fmt.Errorf("registry %s: unsupported auth challenge: %s", host, challenge)
+}
+
+// Decodes a JSON response body, and closes it.
+func decodeJSON(resp *http.Response, v interface{}) error {
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="?next"?`)
+
+// Returns the URL of the next page of a paginated response, or the empty string.
+func nextPage(resp *http.Response) (string, error) {
+	m := linkNextRegexp.FindStringSubmatch(resp.Header.Get("Link"))
+	if m == nil {
+		return "", nil
+	}
+	next, err := resp.Request.URL.Parse(m[1])
+	if err != nil {
+		return "", fmt.Errorf("malformed Link header: %v", err)
+	}
+	return next.String(), nil
+}
+
+// Docker Hub serves the registry API from a different host than its name.
+func apiHost(domain string) string {
+	if domain == "docker.io" {
+		return "registry-1.docker.io"
+	}
+	return domain
+}
+
+// The key for a host in the Docker CLI config.
+func registryConfigKey(host string) string {
+	if host == "registry-1.docker.io" {
+		return "https://index.docker.io/v1/"
+	}
+	return host
+}
+
+// Like Docker, we assume that registries on the loopback interface
+// (e.g., a local kind registry) don't serve TLS.
+func scheme(host string) string {
+	h, _, err := net.SplitHostPort(host)
+	if err != nil {
+		h = host
+	}
+	if h == "localhost" || strings.HasSuffix(h, ".localhost") {
+		return "http"
+	}
+	ip := net.ParseIP(h)
+	if ip != nil && ip.IsLoopback() {
+		return "http"
+	}
+	return "https"
+}
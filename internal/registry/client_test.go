@@ -0,0 +1,96 @@
+package registry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/container"
+)
+
+func TestTagsPaginated(t *testing.T) {
+	f := newFixture(t)
+	f.reg.PageSize = 2
+	for _, tag := range []string{"a", "b", "c", "d", "e"} {
+		f.reg.PushImage("app", tag, time.Now(), nil)
+	}
+
+	tags, err := f.client.Tags(f.ctx, f.repo("app"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, tags)
+}
+
+func TestManifestAndConfig(t *testing.T) {
+	f := newFixture(t)
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	dig := f.reg.PushImage("app", "tilt-abc", created, map[string]string{"dev.tilt.gc": "true"})
+
+	m, err := f.client.Manifest(f.ctx, f.repo("app"), "tilt-abc")
+	require.NoError(t, err)
+	assert.Equal(t, dig, m.Digest)
+
+	headDigest, err := f.client.ManifestDigest(f.ctx, f.repo("app"), "tilt-abc")
+	require.NoError(t, err)
+	assert.Equal(t, dig, headDigest)
+
+	cfg, err := f.client.ImageConfig(f.ctx, f.repo("app"), m.ConfigDigest)
+	require.NoError(t, err)
+	assert.True(t, created.Equal(cfg.Created))
+	assert.Equal(t, map[string]string{"dev.tilt.gc": "true"}, cfg.Labels)
+}
+
+func TestDeleteManifest(t *testing.T) {
+	f := newFixture(t)
+	dig := f.reg.PushImage("app", "tilt-abc", time.Now(), nil)
+	f.reg.PushImage("app", "main", time.Now(), map[string]string{"other": "image"})
+
+	require.NoError(t, f.client.DeleteManifest(f.ctx, f.repo("app"), dig))
+	assert.Equal(t, []string{"main"}, f.reg.Tags("app"))
+	assert.Equal(t, []string{dig}, f.reg.Deleted())
+}
+
+func TestBearerToken(t *testing.T) {
+	f := newFixture(t)
+	f.reg.RequireToken = true
+	f.reg.PushImage("app", "tilt-abc", time.Now(), nil)
+
+	tags, err := f.client.Tags(f.ctx, f.repo("app"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tilt-abc"}, tags)
+}
+
+func TestScheme(t *testing.T) {
+	assert.Equal(t, "http", scheme("localhost:5000"))
+	assert.Equal(t, "http", scheme("127.0.0.1:5000"))
+	assert.Equal(t, "http", scheme("registry.localhost"))
+	assert.Equal(t, "https", scheme("gcr.io"))
+	assert.Equal(t, "https", scheme("registry.example.com:5000"))
+}
+
+type fixture struct {
+	t      *testing.T
+	ctx    context.Context
+	reg    *FakeRegistry
+	client *Client
+}
+
+func newFixture(t *testing.T) *fixture {
+	reg := NewFakeRegistry()
+	t.Cleanup(reg.Close)
+	return &fixture{
+		t:   t,
+		ctx: context.Background(),
+		reg: reg,
+		client: NewClient(func(host string) Credentials {
+			return Credentials{}
+		}),
+	}
+}
+
+func (f *fixture) repo(name string) reference.Named {
+	return container.MustParseNamed(f.reg.Host() + "/" + name)
+}
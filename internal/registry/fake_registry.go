@@ -0,0 +1,205 @@
+package registry
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeRegistry is an in-memory registry that implements enough of the
+// V2 API to exercise the Client.
+type FakeRegistry struct {
+	*httptest.Server
+
+	// If set, list tags this many at a time.
+	PageSize int
+
+	// If set, require a bearer token from this fake's token endpoint.
+	RequireToken bool
+
+	mu        sync.Mutex
+	tags      map[string]map[string]string // repo -> tag -> digest
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	deleted   []string
+}
+
+func NewFakeRegistry() *FakeRegistry {
+	r := &FakeRegistry{
+		tags:      make(map[string]map[string]string),
+		manifests: make(map[string][]byte),
+		blobs:     make(map[string][]byte),
+	}
+	r.Server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+// Host returns the registry host, for image refs.
+func (r *FakeRegistry) Host() string {
+	u, _ := url.Parse(r.URL)
+	return u.Host
+}
+
+// PushImage adds an image with the given config to the registry, and returns its manifest digest.
+func (r *FakeRegistry) PushImage(repo, tag string, created time.Time, labels map[string]string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config, _ := json.Marshal(map[string]interface{}{
+		"created": created,
+		"config":  map[string]interface{}{"Labels": labels},
+	})
+	configDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(config))
+	r.blobs[configDigest] = config
+
+	manifest, _ := json.Marshal(map[string]interface{}{
+		"schemaVersion": 2,
+		"mediaType":     mediaTypeDockerManifest,
+		"config":        map[string]interface{}{"digest": configDigest},
+	})
+	dig := fmt.Sprintf("sha256:%x", sha256.Sum256(manifest))
+	r.manifests[dig] = manifest
+	r.tagLocked(repo, tag, dig)
+	return dig
+}
+
+// Tag points a tag at an existing manifest.
+func (r *FakeRegistry) Tag(repo, tag, digest string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tagLocked(repo, tag, digest)
+}
+
+func (r *FakeRegistry) tagLocked(repo, tag, digest string) {
+	if r.tags[repo] == nil {
+		r.tags[repo] = make(map[string]string)
+	}
+	r.tags[repo][tag] = digest
+}
+
+// Tags returns the sorted tags left in a repo.
+func (r *FakeRegistry) Tags(repo string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []string
+	for t := range r.tags[repo] {
+		result = append(result, t)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Deleted returns the manifest digests deleted, in order.
+func (r *FakeRegistry) Deleted() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string{}, r.deleted...)
+}
+
+const fakeToken = "fake-token"
+
+func (r *FakeRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/token" {
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": fakeToken})
+		return
+	}
+
+	if r.RequireToken && req.Header.Get("Authorization") != "Bearer "+fakeToken {
+		w.Header().Set("WWW-Authenticate",
+			fmt.Sprintf(`Bearer realm="%s/token",service="fake",scope="repository:app:pull"`, r.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(path, "/tags/list"):
+		r.serveTags(w, req, strings.TrimSuffix(path, "/tags/list"))
+
+	case strings.Contains(path, "/manifests/"):
+		repo, ref, _ := strings.Cut(path, "/manifests/")
+		dig := ref
+		if !strings.HasPrefix(ref, "sha256:") {
+			dig = r.tags[repo][ref]
+		}
+		manifest, ok := r.manifests[dig]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+
+		if req.Method == http.MethodDelete {
+			if dig != ref {
+				// The API only allows deletes by digest.
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for t, d := range r.tags[repo] {
+				if d == dig {
+					delete(r.tags[repo], t)
+				}
+			}
+			r.deleted = append(r.deleted, dig)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		w.Header().Set("Content-Type", mediaTypeDockerManifest)
+		w.Header().Set("Docker-Content-Digest", dig)
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(manifest)
+		}
+
+	case strings.Contains(path, "/blobs/"):
+		_, dig, _ := strings.Cut(path, "/blobs/")
+		blob, ok := r.blobs[dig]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		_, _ = w.Write(blob)
+
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (r *FakeRegistry) serveTags(w http.ResponseWriter, req *http.Request, repo string) {
+	tags, ok := r.tags[repo]
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+
+	var all []string
+	for t := range tags {
+		all = append(all, t)
+	}
+	sort.Strings(all)
+
+	// Paginate with the "last" parameter, like the reference registry.
+	last := req.URL.Query().Get("last")
+	start := sort.SearchStrings(all, last)
+	if last != "" && start < len(all) && all[start] == last {
+		start++
+	}
+	page := all[start:]
+	if r.PageSize > 0 && len(page) > r.PageSize {
+		page = page[:r.PageSize]
+		next := fmt.Sprintf("/v2/%s/tags/list?n=%s&last=%s", repo, strconv.Itoa(r.PageSize), url.QueryEscape(page[len(page)-1]))
+		w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next))
+	}
+
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": repo, "tags": page})
+}
@@ -62,6 +62,15 @@ func NewImageBuildResult(id model.TargetID, localRef, clusterRef reference.Named
 	}
 }
 
+// For image targets whose image status is already known
+// (e.g., an image reused from a previous build).
+func NewImageBuildResultFromStatus(id model.TargetID, status v1alpha1.ImageMapStatus) ImageBuildResult {
+	return ImageBuildResult{
+		id:             id,
+		ImageMapStatus: status,
+	}
+}
+
 // When localRef == ClusterRef
 func NewImageBuildResultSingleRef(id model.TargetID, ref reference.NamedTagged) ImageBuildResult {
 	return NewImageBuildResult(id, ref, ref)
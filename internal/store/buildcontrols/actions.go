@@ -27,6 +27,9 @@ type BuildCompleteAction struct {
 	Result       store.BuildResultSet
 	FinishTime   time.Time
 	Error        error
+
+	// A timeline of the steps of the build, if the builder recorded one.
+	Steps []model.BuildStep
 }
 
 func (BuildCompleteAction) Action() {}
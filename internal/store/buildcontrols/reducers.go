@@ -198,6 +198,7 @@ func HandleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 	bs.Error = err
 	bs.FinishTime = cb.FinishTime
 	bs.BuildTypes = cb.Result.BuildTypes()
+	bs.Steps = cb.Steps
 	if bs.SpanID != "" {
 		bs.WarningCount = len(engineState.LogStore.Warnings(bs.SpanID))
 	}
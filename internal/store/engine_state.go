@@ -89,10 +89,13 @@ type EngineState struct {
 	Token        token.Token
 	TeamID       string
 
-	DockerPruneSettings model.DockerPruneSettings
+	DockerPruneSettings   model.DockerPruneSettings
+	RegistryPruneSettings model.RegistryPruneSettings
 
 	TelemetrySettings model.TelemetrySettings
 
+	NotifySettings model.NotifySettings
+
 	UserConfigState model.UserConfigState
 
 	// The initialization sequence is unfortunate. Currently we have:
@@ -566,6 +569,7 @@ func NewState() *EngineState {
 	ret.ManifestTargets = make(map[model.ManifestName]*ManifestTarget)
 	ret.Secrets = model.SecretSet{}
 	ret.DockerPruneSettings = model.DefaultDockerPruneSettings()
+	ret.RegistryPruneSettings = model.DefaultRegistryPruneSettings()
 	ret.VersionSettings = model.VersionSettings{
 		CheckUpdates: true,
 	}
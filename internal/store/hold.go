@@ -39,4 +39,8 @@ const (
 
 	// We're waiting on the cluster connection to be established.
 	HoldReasonCluster HoldReason = "waiting-for-cluster"
+
+	// We're waiting for other resources with the same label to finish updating,
+	// because of update_settings(max_parallel_updates_by_label=...).
+	HoldReasonLabelConcurrency HoldReason = "waiting-for-label-slot"
 )
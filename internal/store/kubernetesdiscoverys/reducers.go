@@ -1,6 +1,7 @@
 package kubernetesdiscoverys
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
@@ -87,14 +88,46 @@ func RefreshKubernetesResource(state *store.EngineState, name string) {
 
 			krs.FilteredPods = r.FilteredPods
 			krs.Conditions = r.ApplyStatus.Conditions
+			krs.HasReadinessChecks = len(d.Spec.ReadinessChecks) > 0
+			krs.ReadinessCheckResults = d.Status.ReadinessCheckResults
 
 			if krs.RuntimeStatus() == v1alpha1.RuntimeStatusOK {
 				// NOTE(nick): It doesn't seem right to update this timestamp everytime
 				// we get a new event, but it's what the old code did.
 				krs.LastReadyOrSucceededTime = time.Now()
+				recordRolloutStep(ms, &krs, krs.LastReadyOrSucceededTime)
 			}
 
 			ms.RuntimeState = krs
 		}
 	}
 }
+
+// If the most recent build deployed new pods, record how long we waited
+// for them to become ready.
+func recordRolloutStep(ms *store.ManifestState, krs *store.K8sRuntimeState, readyTime time.Time) {
+	lastBuild := ms.LastBuild()
+	if lastBuild.Empty() || lastBuild.Error != nil || !lastBuild.HasBuildType(model.BuildTypeK8s) {
+		return
+	}
+	if krs.Rollout.BuildStartTime.Equal(lastBuild.StartTime) {
+		return
+	}
+
+	// If the pod is older than the build, the build didn't roll out anything
+	// (e.g., the apply didn't change the pod spec).
+	pod := krs.MostRecentPod()
+	if pod.Name == "" || pod.CreatedAt.Time.Before(lastBuild.StartTime) {
+		return
+	}
+
+	krs.Rollout = store.K8sRollout{
+		BuildStartTime: lastBuild.StartTime,
+		Step: model.BuildStep{
+			Name:       fmt.Sprintf("Waiting for pod %s", pod.Name),
+			Type:       model.BuildStepTypeRollout,
+			StartTime:  lastBuild.FinishTime,
+			FinishTime: readyTime,
+		},
+	}
+}
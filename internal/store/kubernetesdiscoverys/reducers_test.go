@@ -86,6 +86,165 @@ func TestReadyJob(t *testing.T) {
 	assert.True(t, ms.K8sRuntimeState().HasEverBeenReadyOrSucceeded())
 }
 
+func TestRolloutStep(t *testing.T) {
+	buildStart := time.Now().Add(-time.Minute)
+	buildFinish := buildStart.Add(10 * time.Second)
+	pod := v1alpha1.Pod{
+		Name:      "pod-a",
+		Namespace: "default",
+		Phase:     string(v1.PodPending),
+		CreatedAt: metav1.NewTime(buildFinish),
+		Containers: []v1alpha1.Container{
+			{Name: "main", Ready: false},
+		},
+	}
+
+	ka := newApply("a")
+	state := store.NewState()
+	mt := store.NewManifestTarget(model.Manifest{Name: "a"})
+	state.UpsertManifestTarget(mt)
+	state.KubernetesApplys[ka.Name] = ka
+
+	ms, ok := state.ManifestState("a")
+	require.True(t, ok)
+	krs := ms.K8sRuntimeState()
+	krs.HasEverDeployedSuccessfully = true
+	ms.RuntimeState = krs
+	ms.AddCompletedBuild(model.BuildRecord{
+		StartTime:  buildStart,
+		FinishTime: buildFinish,
+		BuildTypes: []model.BuildType{model.BuildTypeImage, model.BuildTypeK8s},
+	})
+
+	HandleKubernetesDiscoveryUpsertAction(state, KubernetesDiscoveryUpsertAction{
+		KubernetesDiscovery: newDiscovery("a", []v1alpha1.Pod{pod}),
+	})
+	assert.True(t, ms.K8sRuntimeState().Rollout.Empty())
+
+	pod.Phase = string(v1.PodRunning)
+	pod.Containers[0].Ready = true
+	HandleKubernetesDiscoveryUpsertAction(state, KubernetesDiscoveryUpsertAction{
+		KubernetesDiscovery: newDiscovery("a", []v1alpha1.Pod{pod}),
+	})
+
+	rollout := ms.K8sRuntimeState().Rollout
+	assert.Equal(t, buildStart, rollout.BuildStartTime)
+	assert.Equal(t, model.BuildStepTypeRollout, rollout.Step.Type)
+	assert.Equal(t, "Waiting for pod pod-a", rollout.Step.Name)
+	assert.Equal(t, buildFinish, rollout.Step.StartTime)
+	assert.True(t, rollout.Step.FinishTime.After(buildFinish))
+
+	// The build history is never modified after the build completes.
+	assert.Empty(t, ms.LastBuild().Steps)
+
+	// Later events don't move the rollout step.
+	pod.Containers[0].Restarts = 1
+	HandleKubernetesDiscoveryUpsertAction(state, KubernetesDiscoveryUpsertAction{
+		KubernetesDiscovery: newDiscovery("a", []v1alpha1.Pod{pod}),
+	})
+	assert.Equal(t, rollout, ms.K8sRuntimeState().Rollout)
+}
+
+func TestRolloutStepSkippedForOldPod(t *testing.T) {
+	buildStart := time.Now().Add(-time.Minute)
+	pod := v1alpha1.Pod{
+		Name:      "pod-a",
+		Namespace: "default",
+		Phase:     string(v1.PodRunning),
+		CreatedAt: metav1.NewTime(buildStart.Add(-time.Hour)),
+		Containers: []v1alpha1.Container{
+			{Name: "main", Ready: true},
+		},
+	}
+
+	ka := newApply("a")
+	state := store.NewState()
+	mt := store.NewManifestTarget(model.Manifest{Name: "a"})
+	state.UpsertManifestTarget(mt)
+	state.KubernetesApplys[ka.Name] = ka
+
+	ms, ok := state.ManifestState("a")
+	require.True(t, ok)
+	krs := ms.K8sRuntimeState()
+	krs.HasEverDeployedSuccessfully = true
+	ms.RuntimeState = krs
+	ms.AddCompletedBuild(model.BuildRecord{
+		StartTime:  buildStart,
+		FinishTime: buildStart.Add(10 * time.Second),
+		BuildTypes: []model.BuildType{model.BuildTypeK8s},
+	})
+
+	HandleKubernetesDiscoveryUpsertAction(state, KubernetesDiscoveryUpsertAction{
+		KubernetesDiscovery: newDiscovery("a", []v1alpha1.Pod{pod}),
+	})
+	assert.Equal(t, v1alpha1.RuntimeStatusOK, ms.K8sRuntimeState().RuntimeStatus())
+	assert.True(t, ms.K8sRuntimeState().Rollout.Empty())
+}
+
+func TestReadinessChecks(t *testing.T) {
+	ka := newApply("a")
+	kd := newDiscovery("a", nil)
+	kd.Spec.ReadinessChecks = []v1alpha1.KubernetesReadinessCheck{
+		{Kind: "Database", Condition: "status.phase == Ready"},
+	}
+
+	state := store.NewState()
+	mt := store.NewManifestTarget(model.Manifest{Name: "a"}.WithDeployTarget(model.K8sTarget{PodReadinessMode: model.PodReadinessIgnore}))
+	state.UpsertManifestTarget(mt)
+	state.KubernetesApplys[ka.Name] = ka
+
+	ms, ok := state.ManifestState("a")
+	require.True(t, ok)
+	krs := ms.K8sRuntimeState()
+	krs.HasEverDeployedSuccessfully = true
+	ms.RuntimeState = krs
+
+	HandleKubernetesDiscoveryUpsertAction(state, KubernetesDiscoveryUpsertAction{
+		KubernetesDiscovery: kd,
+	})
+	assert.Equal(t, v1alpha1.RuntimeStatusPending, ms.K8sRuntimeState().RuntimeStatus())
+	assert.False(t, ms.K8sRuntimeState().HasEverBeenReadyOrSucceeded())
+
+	kd = kd.DeepCopy()
+	kd.Status.ReadinessCheckResults = []v1alpha1.KubernetesReadinessCheckResult{
+		{Kind: "Database", Name: "db", Ready: true},
+	}
+	HandleKubernetesDiscoveryUpsertAction(state, KubernetesDiscoveryUpsertAction{
+		KubernetesDiscovery: kd,
+	})
+	assert.Equal(t, v1alpha1.RuntimeStatusOK, ms.K8sRuntimeState().RuntimeStatus())
+	assert.True(t, ms.K8sRuntimeState().HasEverBeenReadyOrSucceeded())
+}
+
+func TestReadinessCheckError(t *testing.T) {
+	ka := newApply("a")
+	kd := newDiscovery("a", nil)
+	kd.Spec.ReadinessChecks = []v1alpha1.KubernetesReadinessCheck{
+		{Kind: "Database", Condition: "status.phase == Ready"},
+	}
+	kd.Status.ReadinessCheckResults = []v1alpha1.KubernetesReadinessCheckResult{
+		{Kind: "Database", Error: "readiness check for kind Database matched no deployed objects"},
+	}
+
+	state := store.NewState()
+	mt := store.NewManifestTarget(model.Manifest{Name: "a"}.WithDeployTarget(model.K8sTarget{PodReadinessMode: model.PodReadinessIgnore}))
+	state.UpsertManifestTarget(mt)
+	state.KubernetesApplys[ka.Name] = ka
+
+	ms, ok := state.ManifestState("a")
+	require.True(t, ok)
+	krs := ms.K8sRuntimeState()
+	krs.HasEverDeployedSuccessfully = true
+	ms.RuntimeState = krs
+
+	HandleKubernetesDiscoveryUpsertAction(state, KubernetesDiscoveryUpsertAction{
+		KubernetesDiscovery: kd,
+	})
+	assert.Equal(t, v1alpha1.RuntimeStatusError, ms.K8sRuntimeState().RuntimeStatus())
+	assert.EqualError(t, ms.K8sRuntimeState().RuntimeStatusError(),
+		"readiness check for kind Database matched no deployed objects")
+}
+
 func TestNewKubernetesApplyFilterOnlyUsesYaml(t *testing.T) {
 	// RefreshKubernetesResource assumes that KubernetesApplyFilters are pure functions of the ResultYAML,
 	// and reuses filters if the yaml hasn't changed, to save time on re-parsing. If we change the signature
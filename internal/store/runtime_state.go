@@ -1,6 +1,7 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"time"
@@ -91,6 +92,27 @@ type K8sRuntimeState struct {
 	UpdateStartTime map[k8s.PodID]time.Time
 
 	PodReadinessMode model.PodReadinessMode
+
+	// Whether the KubernetesDiscovery has readiness checks on deployed objects,
+	// and their most recent results.
+	HasReadinessChecks    bool
+	ReadinessCheckResults []v1alpha1.KubernetesReadinessCheckResult
+
+	// How long we waited for the pods from the most recent deploy to become ready.
+	Rollout K8sRollout
+}
+
+// K8sRollout is the rollout step of one build, keyed by the build's start time.
+//
+// We keep it on the runtime state rather than in the build history,
+// because the pods become ready after the build has finished.
+type K8sRollout struct {
+	BuildStartTime time.Time
+	Step           model.BuildStep
+}
+
+func (r K8sRollout) Empty() bool {
+	return r.BuildStartTime.IsZero()
 }
 
 func (K8sRuntimeState) RuntimeState() {}
@@ -117,6 +139,9 @@ func (s K8sRuntimeState) RuntimeStatusError() error {
 	if status != v1alpha1.RuntimeStatusError {
 		return nil
 	}
+	if err := s.readinessCheckError(); err != nil {
+		return err
+	}
 	pod := s.MostRecentPod()
 	return fmt.Errorf("Pod %s in error state: %s", pod.Name, pod.Status)
 }
@@ -126,6 +151,14 @@ func (s K8sRuntimeState) RuntimeStatus() v1alpha1.RuntimeStatus {
 		return v1alpha1.RuntimeStatusPending
 	}
 
+	if s.readinessCheckError() != nil {
+		return v1alpha1.RuntimeStatusError
+	}
+
+	if s.HasReadinessChecks && !s.ReadinessChecksPassed() {
+		return v1alpha1.RuntimeStatusPending
+	}
+
 	if s.PodReadinessMode == model.PodReadinessIgnore {
 		return v1alpha1.RuntimeStatusOK
 	}
@@ -165,12 +198,41 @@ func (s K8sRuntimeState) HasEverBeenReadyOrSucceeded() bool {
 	if !s.HasEverDeployedSuccessfully {
 		return false
 	}
-	if s.PodReadinessMode == model.PodReadinessIgnore {
+	if s.PodReadinessMode == model.PodReadinessIgnore && !s.HasReadinessChecks {
 		return true
 	}
 	return !s.LastReadyOrSucceededTime.IsZero()
 }
 
+// ReadinessChecksPassed returns true if the readiness checks have been
+// evaluated against the deployed objects, and every result is ready.
+//
+// There are no results until the deploy finishes.
+func (s K8sRuntimeState) ReadinessChecksPassed() bool {
+	if len(s.ReadinessCheckResults) == 0 {
+		return false
+	}
+	for _, r := range s.ReadinessCheckResults {
+		if !r.Ready {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the first readiness check that can never pass, if any.
+func (s K8sRuntimeState) readinessCheckError() error {
+	if !s.HasReadinessChecks {
+		return nil
+	}
+	for _, r := range s.ReadinessCheckResults {
+		if r.Error != "" {
+			return errors.New(r.Error)
+		}
+	}
+	return nil
+}
+
 func (s K8sRuntimeState) PodLen() int {
 	return len(s.FilteredPods)
 }
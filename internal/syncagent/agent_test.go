@@ -0,0 +1,236 @@
+package syncagent
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthy(t *testing.T) {
+	f := newFixture(t)
+	assert.NoError(t, f.client.Healthy(context.Background()))
+}
+
+func TestRejectsWrongToken(t *testing.T) {
+	f := newFixture(t)
+	f.write("app/keep.go", "package keep")
+
+	client := NewClient(f.server.URL, "wrong")
+	err := client.Healthy(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected Tilt's token")
+
+	err = client.Sync(context.Background(), newTar(t, nil), []string{"/app/keep.go"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	f.assertFile("app/keep.go", "package keep")
+
+	_, err = NewClient(f.server.URL, "").Run(context.Background(), []string{"true"}, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
+
+func TestServerWithoutTokenRejectsEverything(t *testing.T) {
+	server := httptest.NewServer(NewServer(t.TempDir(), StaticToken("")).Handler())
+	t.Cleanup(server.Close)
+
+	err := NewClient(server.URL, "").Healthy(context.Background())
+	require.Error(t, err)
+}
+
+func TestSyncWritesFiles(t *testing.T) {
+	f := newFixture(t)
+
+	archive := newTar(t, map[string]string{
+		"app/main.go":        "package main",
+		"app/static/app.css": "body {}",
+	})
+	err := f.client.Sync(context.Background(), archive, nil)
+	require.NoError(t, err)
+
+	f.assertFile("app/main.go", "package main")
+	f.assertFile("app/static/app.css", "body {}")
+
+	// Files are overwritten on the next sync.
+	err = f.client.Sync(context.Background(), newTar(t, map[string]string{"app/main.go": "package app"}), nil)
+	require.NoError(t, err)
+	f.assertFile("app/main.go", "package app")
+}
+
+func TestSyncDeletesFiles(t *testing.T) {
+	f := newFixture(t)
+	f.write("app/old.go", "package old")
+	f.write("app/gone/a.txt", "a")
+	f.write("app/keep.go", "package keep")
+
+	err := f.client.Sync(context.Background(), newTar(t, nil), []string{"/app/old.go", "/app/gone"})
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, filepath.Join(f.root, "app", "old.go"))
+	assert.NoDirExists(t, filepath.Join(f.root, "app", "gone"))
+	f.assertFile("app/keep.go", "package keep")
+}
+
+func TestSyncCannotEscapeRoot(t *testing.T) {
+	f := newFixture(t)
+
+	err := f.client.Sync(context.Background(), newTar(t, map[string]string{"../../escape.txt": "hi"}), nil)
+	require.NoError(t, err)
+	f.assertFile("escape.txt", "hi")
+}
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	f := newFixture(t)
+
+	out := &bytes.Buffer{}
+	code, err := f.client.Run(context.Background(), []string{"sh", "-c", "echo hello; exit 3"}, out)
+	require.NoError(t, err)
+	assert.Equal(t, 3, code)
+	assert.Equal(t, "hello\n", out.String())
+
+	code, err = f.client.Run(context.Background(), []string{"true"}, out)
+	require.NoError(t, err)
+	assert.Equal(t, 0, code)
+}
+
+func TestRunNotFound(t *testing.T) {
+	f := newFixture(t)
+
+	code, err := f.client.Run(context.Background(), []string{"definitely-not-a-real-command"}, &bytes.Buffer{})
+	require.NoError(t, err)
+	assert.Equal(t, ExitCodeNotFound, code)
+}
+
+func TestArchive(t *testing.T) {
+	f := newFixture(t)
+	f.write("app/gen/a.pb.go", "package a")
+	f.write("app/gen/sub/b.pb.go", "package b")
+	f.write("app/main.go", "package main")
+
+	assert.Equal(t, map[string]string{
+		"gen/a.pb.go":     "package a",
+		"gen/sub/b.pb.go": "package b",
+	}, f.archive("/app/gen", nil))
+}
+
+func TestArchiveOnlyFiles(t *testing.T) {
+	f := newFixture(t)
+	f.write("app/gen/a.pb.go", "package a")
+	f.write("app/gen/sub/b.pb.go", "package b")
+
+	assert.Equal(t, map[string]string{
+		"gen/sub/b.pb.go": "package b",
+	}, f.archive("/app/gen", []string{"gen/sub/b.pb.go"}))
+}
+
+func TestHashes(t *testing.T) {
+	f := newFixture(t)
+	f.write("app/gen/a.pb.go", "package a")
+	f.write("app/gen/sub/b.pb.go", "package b")
+
+	hashes, err := f.client.Hashes(context.Background(), "/app/gen")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"gen/a.pb.go":     sha256Hex("package a"),
+		"gen/sub/b.pb.go": sha256Hex("package b"),
+	}, hashes)
+
+	_, err = f.client.Hashes(context.Background(), "/app/missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+func TestArchiveNotFound(t *testing.T) {
+	f := newFixture(t)
+
+	_, err := f.client.Archive(context.Background(), "/app/gen", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+}
+
+type fixture struct {
+	t      *testing.T
+	root   string
+	server *httptest.Server
+	client *Client
+}
+
+func newFixture(t *testing.T) *fixture {
+	root := t.TempDir()
+	server := httptest.NewServer(NewServer(root, StaticToken("secret")).Handler())
+	t.Cleanup(server.Close)
+	return &fixture{t: t, root: root, server: server, client: NewClient(server.URL, "secret")}
+}
+
+// Downloads an archive, and returns the contents of its regular files.
+func (f *fixture) archive(containerPath string, only []string) map[string]string {
+	r, err := f.client.Archive(context.Background(), containerPath, only)
+	require.NoError(f.t, err)
+	defer func() { _ = r.Close() }()
+
+	files := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(f.t, err)
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		contents, err := io.ReadAll(tr)
+		require.NoError(f.t, err)
+		files[hdr.Name] = string(contents)
+	}
+	return files
+}
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func (f *fixture) write(path, contents string) {
+	p := filepath.Join(f.root, filepath.FromSlash(path))
+	require.NoError(f.t, os.MkdirAll(filepath.Dir(p), 0o755))
+	require.NoError(f.t, os.WriteFile(p, []byte(contents), 0o644))
+}
+
+func (f *fixture) assertFile(path, expected string) {
+	contents, err := os.ReadFile(filepath.Join(f.root, filepath.FromSlash(path)))
+	if assert.NoError(f.t, err) {
+		assert.Equal(f.t, expected, string(contents))
+	}
+}
+
+func newTar(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(contents)),
+		}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf
+}
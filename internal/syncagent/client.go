@@ -0,0 +1,182 @@
+package syncagent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client talks to a sync agent.
+//
+// The client reuses its HTTP connections, so one client should
+// be used for all updates to the same agent.
+type Client struct {
+	baseURL string
+	token   Token
+	http    *http.Client
+}
+
+func NewClient(baseURL string, token Token) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{},
+	}
+}
+
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+c.token.String())
+	return c.http.Do(req)
+}
+
+// Healthy checks that the agent is up and speaks our protocol.
+func (c *Client) Healthy(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+PathHealthz, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("sync agent rejected Tilt's token. Is %s set to a different token in the container?", EnvToken)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("sync agent health check: %s", resp.Status)
+	}
+	v := resp.Header.Get(HeaderProtocolVersion)
+	if v != ProtocolVersion {
+		return fmt.Errorf("sync agent speaks protocol version %q, but Tilt needs %q", v, ProtocolVersion)
+	}
+	return nil
+}
+
+// Sync deletes the given container paths, then extracts the archive
+// at the container's filesystem root.
+func (c *Client) Sync(ctx context.Context, archive io.Reader, toDelete []string) error {
+	q := url.Values{}
+	for _, p := range toDelete {
+		q.Add("delete", p)
+	}
+	u := c.baseURL + PathSync
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, archive)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-tar")
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer drain(resp)
+	return checkStatus(resp)
+}
+
+// Run runs a command in the container, streaming its output to out,
+// and returns its exit code.
+func (c *Client) Run(ctx context.Context, argv []string, out io.Writer) (int, error) {
+	body, err := json.Marshal(RunRequest{Argv: argv})
+	if err != nil {
+		return -1, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+PathRun, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.do(req)
+	if err != nil {
+		return -1, err
+	}
+	defer drain(resp)
+	err = checkStatus(resp)
+	if err != nil {
+		return -1, err
+	}
+
+	_, err = io.Copy(out, resp.Body)
+	if err != nil {
+		return -1, err
+	}
+
+	// Trailers are only populated once the body has been read.
+	code, err := strconv.Atoi(resp.Trailer.Get(TrailerExitCode))
+	if err != nil {
+		return -1, fmt.Errorf("sync agent didn't report an exit code")
+	}
+	return code, nil
+}
+
+// Archive returns a tarball of the given container path. Entries are named
+// relative to the path's parent directory. If files is non-empty, only those
+// entries are included. The caller must close the reader.
+func (c *Client) Archive(ctx context.Context, containerPath string, files []string) (io.ReadCloser, error) {
+	u := c.baseURL + PathArchive + "?" + url.Values{"path": {containerPath}, "file": files}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	err = checkStatus(resp)
+	if err != nil {
+		drain(resp)
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Hashes returns the sha256 of every regular file under the given container
+// path, keyed by the entry names that Archive uses.
+func (c *Client) Hashes(ctx context.Context, containerPath string) (map[string]string, error) {
+	u := c.baseURL + PathHashes + "?" + url.Values{"path": {containerPath}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer drain(resp)
+	err = checkStatus(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := map[string]string{}
+	err = json.NewDecoder(resp.Body).Decode(&hashes)
+	if err != nil {
+		return nil, fmt.Errorf("sync agent: reading hashes: %v", err)
+	}
+	return hashes, nil
+}
+
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return fmt.Errorf("sync agent: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// Reads the rest of the body, so that the connection can be reused.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,88 @@
+// Package syncagent implements a small file-sync agent that runs inside
+// a container, and the client that Tilt uses to talk to it.
+//
+// Live update normally copies files by piping a tarball into `tar` over
+// `kubectl exec`. That needs `tar` in the image, and starts a new exec
+// session for every step. The agent instead serves a small HTTP API that
+// Tilt reaches over a long-lived port-forward, so it works in distroless
+// images and skips the per-step exec overhead.
+package syncagent
+
+// The port the agent listens on if none is specified.
+const DefaultPort = 8473
+
+// The protocol version served by the agent.
+//
+// Bump this when making incompatible changes to the API.
+const ProtocolVersion = "1"
+
+const (
+	// Reports the protocol version. Used as a liveness check.
+	PathHealthz = "/healthz"
+
+	// Deletes the paths in the `delete` query params, then extracts
+	// the tarball in the request body at the filesystem root.
+	PathSync = "/sync"
+
+	// Runs the command in the RunRequest body, streaming the combined
+	// stdout and stderr in the response. The exit code is sent in a trailer.
+	PathRun = "/run"
+
+	// Streams a tarball of the container path in the `path` query param.
+	// Entries are named relative to the path's parent directory,
+	// the same as `tar -C dir -c base`. If there are `file` query params,
+	// only the regular files with those entry names are included.
+	PathArchive = "/archive"
+
+	// Returns the sha256 of every regular file under the container path in
+	// the `path` query param, as a JSON object keyed by archive entry name.
+	PathHashes = "/hashes"
+)
+
+const (
+	HeaderProtocolVersion = "Tilt-Sync-Agent-Version"
+	TrailerExitCode       = "Tilt-Exit-Code"
+)
+
+// The env variable that the agent reads its token from, if it isn't
+// given --token or --token-file.
+const EnvToken = "TILT_SYNC_AGENT_TOKEN"
+
+// The image that Tilt copies the agent from, if the Tiltfile doesn't
+// specify one. Built from cmd/tilt-sync-agent/Dockerfile.
+const DefaultImage = "docker.io/tiltdev/tilt-sync-agent:v" + ProtocolVersion
+
+// Where the agent binary lives in DefaultImage.
+const ImageBinaryPath = "/tilt-sync-agent"
+
+const (
+	// The emptyDir that the init container copies the agent into.
+	InstallDir = "/.tilt-sync-agent"
+
+	// The path of the agent binary in the container.
+	InstallPath = InstallDir + "/tilt-sync-agent"
+
+	// Where the Secret with the token is mounted.
+	TokenDir = "/.tilt-sync-agent-token"
+
+	// The key of the token in the Secret.
+	SecretKeyToken = "token"
+
+	// The path of the token file in the container.
+	TokenPath = TokenDir + "/" + SecretKeyToken
+)
+
+// Every request must send the agent's token as a bearer token in the
+// Authorization header. The agent rejects requests without it.
+type Token string
+
+func (t Token) String() string {
+	return string(t)
+}
+
+type RunRequest struct {
+	Argv []string `json:"argv"`
+}
+
+// The exit code the agent reports when a command can't be found.
+const ExitCodeNotFound = 127
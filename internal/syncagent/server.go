@@ -0,0 +1,358 @@
+package syncagent
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+)
+
+// Server serves the sync agent API, writing files relative to a root directory.
+type Server struct {
+	root  string
+	token TokenSource
+
+	// Syncs are applied one at a time, so that two updates
+	// don't interleave their writes.
+	mu sync.Mutex
+}
+
+func NewServer(root string, token TokenSource) *Server {
+	return &Server{root: root, token: token}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+PathHealthz, s.handleHealthz)
+	mux.HandleFunc("POST "+PathSync, s.handleSync)
+	mux.HandleFunc("POST "+PathRun, s.handleRun)
+	mux.HandleFunc("GET "+PathArchive, s.handleArchive)
+	mux.HandleFunc("GET "+PathHashes, s.handleHashes)
+	return s.requireToken(mux)
+}
+
+// The API can write any file and run any command, so every request
+// must prove that it came from Tilt.
+func (s *Server) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := s.token()
+		want := []byte("Bearer " + token.String())
+		got := []byte(r.Header.Get("Authorization"))
+		if token == "" || subtle.ConstantTimeCompare(got, want) != 1 {
+			http.Error(w, "missing or invalid token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(HeaderProtocolVersion, ProtocolVersion)
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range r.URL.Query()["delete"] {
+		err := os.RemoveAll(s.resolve(p))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("removing %s: %v", p, err), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err := s.extract(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("extracting files: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	var req RunRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("decoding request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(req.Argv) == 0 {
+		http.Error(w, "empty command", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Trailer", TrailerExitCode)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	out := &flushWriter{w: w}
+	cmd := exec.CommandContext(r.Context(), req.Argv[0], req.Argv[1:]...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	w.Header().Set(TrailerExitCode, strconv.Itoa(exitCode(cmd.Run())))
+}
+
+func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
+	src, ok := s.resolveExisting(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var only map[string]bool
+	if files := r.URL.Query()["file"]; len(files) > 0 {
+		only = make(map[string]bool, len(files))
+		for _, f := range files {
+			only[f] = true
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/x-tar")
+	tw := tar.NewWriter(w)
+	err := archive(tw, src, only)
+	if err == nil {
+		err = tw.Close()
+	}
+	if err != nil {
+		// The headers are already sent, so the best we can do is
+		// cut the response short. The client will see a truncated tarball.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+func (s *Server) handleHashes(w http.ResponseWriter, r *http.Request) {
+	src, ok := s.resolveExisting(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hashes := map[string]string{}
+	parent := filepath.Dir(src)
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(parent, path)
+		if err != nil {
+			return err
+		}
+		hashes[filepath.ToSlash(rel)], err = hashFile(path)
+		return err
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(hashes)
+}
+
+// Resolves the `path` query param, and writes an error if it doesn't exist.
+func (s *Server) resolveExisting(w http.ResponseWriter, r *http.Request) (string, bool) {
+	p := r.URL.Query().Get("path")
+	if p == "" {
+		http.Error(w, "missing path", http.StatusBadRequest)
+		return "", false
+	}
+
+	src := s.resolve(p)
+	if _, err := os.Lstat(src); err != nil {
+		status := http.StatusInternalServerError
+		if os.IsNotExist(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return "", false
+	}
+	return src, true
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Writes src to the tarball, with entry names relative to src's parent.
+//
+// If only is non-nil, the tarball only includes the regular files named in it.
+func archive(tw *tar.Writer, src string, only map[string]bool) error {
+	parent := filepath.Dir(src)
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if only != nil {
+			rel, err := filepath.Rel(parent, path)
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() || !only[filepath.ToSlash(rel)] {
+				return nil
+			}
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		} else if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(parent, path)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	// The command couldn't start, usually because it doesn't exist.
+	return ExitCodeNotFound
+}
+
+// Resolves a container path against the root, without letting it escape.
+func (s *Server) resolve(p string) string {
+	return filepath.Join(s.root, filepath.FromSlash(filepath.Clean("/"+p)))
+}
+
+func (s *Server) extract(r io.Reader) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dst := s.resolve(hdr.Name)
+		mode := os.FileMode(hdr.Mode).Perm()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(dst, mode|0o700)
+		case tar.TypeReg:
+			err = writeFile(dst, tr, mode)
+		case tar.TypeSymlink:
+			err = replaceWith(dst, func() error { return os.Symlink(hdr.Linkname, dst) })
+		case tar.TypeLink:
+			err = replaceWith(dst, func() error { return os.Link(s.resolve(hdr.Linkname), dst) })
+		default:
+			// Device files, fifos, etc. never show up in live updates.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %v", hdr.Name, err)
+		}
+	}
+}
+
+func writeFile(dst string, r io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	closeErr := f.Close()
+	if err != nil {
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	// OpenFile doesn't change the mode of existing files.
+	return os.Chmod(dst, mode)
+}
+
+func replaceWith(dst string, create func() error) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0o755)
+	if err != nil {
+		return err
+	}
+	err = os.RemoveAll(dst)
+	if err != nil {
+		return err
+	}
+	return create()
+}
+
+// Flushes after every write, so command output streams back as it happens.
+type flushWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	n, err := fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,52 @@
+package syncagent
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strings"
+)
+
+// ProvideToken generates the token that Tilt sends to sync agents.
+//
+// Every Tilt session gets its own token, which is never written to disk.
+// Tilt mounts it into pods from a Secret, so a new session's token reaches
+// running pods without rolling them out.
+func ProvideToken() Token {
+	return NewToken()
+}
+
+// NewToken generates a random token.
+func NewToken() Token {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		panic(err)
+	}
+	return Token(hex.EncodeToString(b))
+}
+
+// TokenSource returns the token that the agent currently accepts.
+//
+// The server asks for the token on every request, so that the token
+// can change while the agent runs.
+type TokenSource func() Token
+
+// StaticToken always accepts the same token.
+func StaticToken(t Token) TokenSource {
+	return func() Token { return t }
+}
+
+// FileToken reads the token from a file on every request.
+//
+// Kubernetes updates a mounted Secret in place when Tilt rotates the
+// token. If the file can't be read, the agent rejects every request.
+func FileToken(path string) TokenSource {
+	return func() Token {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return ""
+		}
+		return Token(strings.TrimSpace(string(contents)))
+	}
+}
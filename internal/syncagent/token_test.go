@@ -0,0 +1,29 @@
+package syncagent
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProvideTokenPerSession(t *testing.T) {
+	t1 := ProvideToken()
+	t2 := ProvideToken()
+	require.NotEmpty(t, t1)
+	assert.NotEqual(t, t1, t2)
+}
+
+func TestFileTokenRereadsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), SecretKeyToken)
+	source := FileToken(path)
+	assert.Equal(t, Token(""), source())
+
+	require.NoError(t, os.WriteFile(path, []byte("a\n"), 0o600))
+	assert.Equal(t, Token("a"), source())
+
+	require.NoError(t, os.WriteFile(path, []byte("b"), 0o600))
+	assert.Equal(t, Token("b"), source())
+}
@@ -154,6 +154,43 @@ def initial_sync() -> LiveUpdateStep:
   """
   pass
 
+def sync_agent(port: int = 8473, image: str = "docker.io/tiltdev/tilt-sync-agent:v1") -> LiveUpdateStep:
+  """Apply live updates through a sync agent running in the container.
+
+  By default, Tilt live-updates Kubernetes containers by piping a tarball into
+  ``tar`` over ``kubectl exec``, and runs each ``run`` step with its own exec.
+  That needs ``tar`` in the image. With ``sync_agent``, Tilt instead talks to
+  a ``tilt-sync-agent`` process in the container over a port-forward that it keeps
+  open between updates. This works in distroless and ``FROM scratch`` images,
+  and is faster on remote clusters.
+
+  Tilt injects the agent when it deploys the container, so the image doesn't
+  need to include it. It adds an init container that copies the agent from
+  ``image`` into an ``emptyDir`` volume, and wraps the container's command with
+  the agent. If the container spec doesn't set a ``command``, Tilt wraps the
+  image's ``ENTRYPOINT`` and ``CMD``. Images that Tilt doesn't load into the
+  local Docker daemon (e.g., ``custom_build`` with ``skips_local_docker=True``)
+  need a ``command`` in the container spec.
+
+  The agent only listens on the container's loopback interface, and requires a
+  token on every request, so other pods can't use it to write files or run
+  commands. Every Tilt session generates a new token, and mounts it into the
+  container from a Secret named ``tilt-sync-agent-<kind>-<name>`` that Tilt
+  applies alongside the workload. Changing the token doesn't restart the pod.
+
+  If Tilt can't reach the agent, it falls back to exec, and tries the agent again later.
+
+  ``sync_agent`` must appear at most once, before any ``sync``, ``run``, or
+  ``restart_container`` steps. It has no effect on Docker Compose services.
+
+  Args:
+    port: The container port that the agent listens on.
+    image: The image to copy the agent from. Must contain the agent binary at
+      ``/tilt-sync-agent``. Build your own from Tilt's ``scripts/tilt-sync-agent.Dockerfile``
+      if your cluster can't pull from Docker Hub.
+  """
+  pass
+
 def sync(local_path: str, remote_path: str) -> LiveUpdateStep:
   """Specify that any changes to `localPath` should be synced to `remotePath`
 
@@ -168,6 +205,42 @@ def sync(local_path: str, remote_path: str) -> LiveUpdateStep:
   """
   pass
 
+def sync_back(remote_path: str, local_path: str) -> LiveUpdateStep:
+  """Copy files from ``remote_path`` in the container back to ``local_path``
+  after each live update.
+
+  Useful when a ``run`` step generates files that you want to check in,
+  like generated code or lockfiles:
+
+  .. code-block:: python
+
+    docker_build('my-image', '.', live_update=[
+      sync('.', '/app'),
+      run('npm install', trigger=['package.json']),
+      sync_back('/app/package-lock.json', 'package-lock.json'),
+    ])
+
+  Only regular files are copied back. Files deleted in the container are
+  not deleted locally. If there are several containers, Tilt copies from
+  the first one it updated.
+
+  If a file changed both locally and in the container since the last
+  sync-back, Tilt keeps the local copy and logs a conflict. Copying a file
+  back doesn't trigger another live update.
+
+  ``sync_back`` runs after the rest of the update, wherever it appears in the list.
+  After the first successful live update, Tilt also polls the container every few
+  seconds, so files that the container changes on its own (e.g., a code generator
+  in watch mode) are copied back without waiting for the next update. Without
+  :meth:`sync_agent`, each poll copies the whole path out of the container. With
+  it, Tilt compares file hashes first, and only downloads the files that changed.
+
+  Args:
+      remote_path: container path to copy from. Must be absolute. Can be a file or a directory.
+      local_path: A path relative to the Tiltfile's directory to copy to.
+  """
+  pass
+
 def run(cmd: Union[str, List[str]], trigger: Union[List[str], str] = [], echo_off: bool = False) -> LiveUpdateStep:
   """Specify that the given `cmd` should be executed when updating an image's container
 
@@ -218,7 +291,9 @@ def docker_build(ref: str,
                  cache_from: Union[str, List[str]] = [],
                  pull: bool = False,
                  platform: str = "",
-                 extra_hosts: Union[str, List[str]] = []) -> None:
+                 extra_hosts: Union[str, List[str]] = [],
+                 attest: Union[bool, str, List[str]] = False,
+                 sign: str = "") -> None:
   """Builds a docker image.
 
   The invocation
@@ -264,6 +339,8 @@ def docker_build(ref: str,
     pull: Force pull the latest version of parent images. Equivalent to the ``docker build --pull`` flag.
     platform: Target platform for build (e.g. ``linux/amd64``). Defaults to the value of the ``DOCKER_DEFAULT_PLATFORM`` environment variable. Equivalent to the ``docker build --platform`` flag.
     extra_hosts: Add a custom host-to-IP mapping (host:ip). Equivalent to the ``docker build --add-host`` flag.
+    attest: Attestations to generate with the image, like an SBOM or build provenance. ``True`` generates an SBOM and minimal provenance. Also accepts attestations in the same syntax as the `docker buildx build --attest <https://docs.docker.com/reference/cli/docker/buildx/build/#attest>`_ flag (e.g., ``['type=sbom', 'type=provenance,mode=max']``). Requires BuildKit and Docker's `containerd image store <https://docs.docker.com/engine/storage/containerd/>`_. On the classic image store, which drops attestations, the build fails.
+    sign: A `cosign <https://docs.sigstore.dev/cosign/signing/signing_with_containers/>`_ key to sign the image with, as a local path or a KMS URI. Tilt signs the image by digest after it's pushed, and before it's deployed. (cosign stores the signature in the registry next to the pushed image, so Tilt can't sign before the push.) Images that aren't pushed to a registry (e.g., on Docker Compose or a local cluster that shares Docker's image store) aren't signed. Requires ``cosign`` on your PATH; for a password-protected key, set ``COSIGN_PASSWORD``.
   """
   pass
 
@@ -279,6 +356,11 @@ def docker_compose(configPaths: Union[str, Blob, List[Union[str, Blob]]], env_fi
 
   Tilt will watch your Docker Compose YAML and reload if it changes.
 
+  Tilt runs the first compose backend it finds: ``docker compose``, ``docker-compose``,
+  ``podman compose``, or ``nerdctl compose``. Set the ``TILT_DOCKER_COMPOSE_CMD``
+  environment variable to choose a different one. Run ``tilt doctor`` to see which
+  backend Tilt is using.
+
   For more info, see `the guide to Tilt with Docker Compose <docker_compose.html>`_.
 
   Examples:
@@ -492,7 +574,8 @@ def k8s_resource(workload: str = "", new_name: str = "",
                  pod_readiness: str = "",
                  links: Union[str, Link, List[Union[str, Link]]]=[],
                  labels: Union[str, List[str]] = [],
-                 discovery_strategy: str = "") -> None:
+                 discovery_strategy: str = "",
+                 readiness_checks: Union[Dict[str, str], List[Dict[str, str]]] = []) -> None:
   """
 
   Configures or creates the specified Kubernetes resource.
@@ -591,6 +674,16 @@ def k8s_resource(workload: str = "", new_name: str = "",
       `Accessing Resource Endpoints <accessing_resource_endpoints.html#arbitrary-links>`_.
     labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed separately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
     discovery_strategy: Possible values: '', 'default', 'selectors-only'. When '' or 'default', Tilt both uses `extra_pod_selectors` and traces k8s owner references to identify this resource's pods. When 'selectors-only', Tilt uses only `extra_pod_selectors`.
+    readiness_checks: one or more checks on the status of objects deployed by this
+      resource, for objects that Tilt doesn't otherwise know how to watch (like a
+      database managed by an operator). Each check is a dict with keys ``kind``,
+      ``condition``, and optionally ``name``. The condition is a JSONPath into the
+      object, optionally compared with ``==`` or ``!=`` to a value (case-insensitive).
+      The shorthand ``[key=value]`` filters a list. The resource isn't ready until every
+      matching object passes, e.g.,
+      ``{'kind': 'Database', 'condition': 'status.conditions[type=Ready].status == True'}``.
+      A check that matches none of the deployed objects (or has an invalid condition)
+      puts the resource in an error state.
   """
   pass
 
@@ -643,6 +736,20 @@ def include(path: str):
 
     include('./frontend/Tiltfile')
     include('./backend/Tiltfile')
+
+  After the main Tiltfile finishes, Tilt automatically includes a per-user
+  overrides file, if it exists: ``Tiltfile.local`` next to the main Tiltfile,
+  or the path in the ``TILT_OVERRIDES`` environment variable. Add ``Tiltfile.local``
+  to your ``.gitignore``, and use it to override the team's defaults
+  without editing the shared Tiltfile. For example ::
+
+    # Tiltfile.local
+    k8s_resource('frontend', port_forwards=9000)
+    config.set_enabled_resources(['frontend', 'api'])
+    update_settings(max_parallel_updates=1)
+
+  The overrides file can't see the main Tiltfile's variables.
+  The path of the loaded overrides file is recorded in the Tiltfile's status.
   """
 
 def load(path: str, *args):
@@ -737,15 +844,25 @@ def watch_file(file_path: str) -> None:
     file_path: Path to the file locally (absolute, or relative to the location of the Tiltfile)."""
 
 
-def kustomize(pathToDir: str, kustomize_bin: str = None, flags: List[str] = []) -> Blob:
+def kustomize(pathToDir: str, kustomize_bin: str = None, flags: List[str] = [], components: List[str] = [], enable_helm: bool = False, enable_alpha_plugins: bool = False, enable_exec: bool = False) -> Blob:
   """Run `kustomize <https://github.com/kubernetes-sigs/kustomize>`_ on a given directory and return the resulting YAML as a Blob
-  Directory is watched (see ``watch_file``). Checks for and uses separately installed kustomize first, if it exists. Otherwise,
+
+  Tilt renders the kustomization with the kustomize library built into Tilt,
+  so you don't need ``kustomize`` or ``kubectl`` installed. Every file that
+  kustomize reads is watched (see ``watch_file``).
+
+  If you pass ``kustomize_bin`` or ``flags``, Tilt runs ``kustomize build`` instead.
+  It checks for and uses separately installed kustomize first, if it exists. Otherwise,
   uses kubectl's kustomize. See `blog post <https://blog.tilt.dev/2020/02/04/are-you-my-kustomize.html>`_.
 
   Args:
     pathToDir: Path to the directory locally (absolute, or relative to the location of the Tiltfile).
     kustomize_bin: Custom path to the ``kustomize`` binary executable. Defaults to searching $PATH for kustomize.
     flags: Additional flags to pass to ``kustomize build``
+    components: Paths to `components <https://kubectl.docs.kubernetes.io/guides/config_management/components/>`_ to apply on top of the kustomization.
+    enable_helm: Inflate ``helmCharts`` with the ``helm`` binary. Equivalent to ``kustomize build --enable-helm``.
+    enable_alpha_plugins: Allow plugins that aren't built into kustomize. Equivalent to ``kustomize build --enable-alpha-plugins``.
+    enable_exec: Allow exec plugins. Equivalent to ``kustomize build --enable-exec``. Requires ``enable_alpha_plugins``.
   """
   pass
 
@@ -948,6 +1065,40 @@ def default_registry(host: str, host_from_cluster: str = None, single_name: str
   """
   pass
 
+def image_tag_policy(template: str, additional_tags: Union[str, List[str]] = []) -> None:
+  """Specifies how Tilt tags the images it builds with ``docker_build`` and ``custom_build``.
+
+  By default, images are tagged ``tilt-<digest>``. A tag policy lets you make images traceable
+  to the commit they were built from, e.g., when pushing to a shared dev registry:
+
+  .. code-block:: python
+
+    image_tag_policy('{git_branch}-{git_sha_short}-{digest8}',
+                     additional_tags=['{git_branch}-latest'])
+
+  Templates may use these variables:
+
+  - ``{digest}``: the image digest (without the ``sha256:``).
+  - ``{digestN}``: the first N characters of the digest, e.g., ``{digest8}``.
+  - ``{git_branch}``: the branch checked out in the repo that contains the build directory.
+  - ``{git_sha}``: the commit checked out in that repo.
+  - ``{git_sha_short}``: the first 7 characters of ``{git_sha}``.
+
+  Git variables are empty if the build directory isn't in a git repo (or, for ``{git_branch}``,
+  if HEAD is detached). Characters that aren't valid in a tag (like the ``/`` in ``feature/login``)
+  are replaced with ``-``. If a tag would be longer than the 128 characters registries accept,
+  Tilt shortens ``{git_branch}`` to fit. The digest is never shortened, so a template that's
+  too long even with an empty branch is an error.
+
+  Can only be called once.
+
+  Args:
+    template: the tag that Tilt deploys. Must contain ``{digest}`` or ``{digestN}``, so that each build gets a unique tag.
+    additional_tags: extra tags to apply to the image. They're pushed alongside the main tag but never deployed,
+      so they don't need to be unique.
+  """
+  pass
+
 def custom_build(
     ref: str,
     command: Union[str, List[str]],
@@ -1070,6 +1221,37 @@ def k8s_namespace() -> str:
 
     if k8s_namespace() == 'default':
       fail("failing early to avoid deploying to 'default' namespace")
+
+  If the Tiltfile calls :meth:`k8s_dev_namespace`, returns the dev namespace.
+  """
+  pass
+
+def k8s_dev_namespace(name: str = 'tilt-{user}', delete_on_down: bool = False) -> str:
+  """Gives each developer their own namespace, so that a team can share one cluster.
+
+  Tilt creates the namespace when it connects to the cluster, before it applies
+  anything. Every object in your Kubernetes YAML that doesn't specify a namespace
+  is deployed to the dev namespace. Objects with an explicit namespace, and
+  cluster-scoped kinds like ``ClusterRole`` (including cluster-scoped custom
+  resources), are left alone. Tilt asks the cluster which kinds are namespaced
+  when it applies the YAML.
+
+  The name is a template. ``{user}`` is replaced with the ``$USER`` environment
+  variable, and ``{branch}`` with the git branch checked out in the Tiltfile's
+  directory. The result is lowercased, and any characters that aren't allowed in
+  a namespace name are replaced with ``-``.
+
+  Example ::
+
+    k8s_dev_namespace(name='{user}-{branch}', delete_on_down=True)
+
+  Args:
+    name: A template for the namespace name.
+    delete_on_down: If True, ``tilt down`` deletes the namespace (and everything
+      in it) after deleting your resources.
+
+  Returns:
+    The name of the namespace.
   """
   pass
 
@@ -1232,6 +1414,40 @@ def docker_prune_settings(disable: bool=False, max_age_mins: int=360,
   """
   pass
 
+def registry_prune_settings(enable: bool=False, max_age_days: int=7,
+                            interval_hrs: int=24, keep_recent: int=5) -> None:
+  """
+  Configures Tilt's Registry Pruner, which deletes images that Tilt pushed to remote registries.
+
+  Off by default, because it deletes images that other people or clusters might be using.
+  When enabled, the pruner runs in the background once something has been built, and then every ``interval_hrs`` hours.
+  You can also run it once with ``tilt registry-prune`` (try ``--dry-run`` first).
+
+  The pruner looks at the registry repositories of the images in this Tiltfile, and deletes tags that:
+    - Tilt created (i.e., tagged ``tilt-<digest>`` or with a tag from an :meth:`image_tag_policy` template
+      that contains a digest, on an image with the labels that Tilt adds when it builds)
+    - are at least ``max_age_days`` days old
+    - are not in the ``keep_recent`` most recent builds for that image name
+
+  Tags that share a digest with a tag the pruner keeps (e.g., an ``extra_tag``) are never deleted.
+  Tags from ``image_tag_policy(additional_tags=...)`` templates without a digest (like ``{git_branch}-latest``)
+  might also match tags that Tilt didn't push, so they're only deleted along with a build that the pruner
+  deletes. Otherwise, they keep the image they point to.
+  If several images share one repository (``default_registry(single_name=...)``), ``keep_recent``
+  applies to all the images with templated tags together.
+  Images on Docker Hub are skipped.
+
+  The registry must allow deletes (for the reference registry, set ``REGISTRY_STORAGE_DELETE_ENABLED=true``).
+  Deleting a tag doesn't free space until the registry runs garbage collection.
+
+  Args:
+    enable: if true, run the Registry Pruner in the background
+    max_age_days: minimum age, in days, of images to delete. Defaults to 7 days
+    interval_hrs: run the Registry Pruner every ``interval_hrs`` hours. Defaults to 24 hours
+    keep_recent: retain at least the ``keep_recent`` most recent images for each image name. Defaults to 5
+  """
+  pass
+
 def analytics_settings(enable: bool) -> None:
   """Overrides Tilt telemetry.
 
@@ -1246,6 +1462,90 @@ def analytics_settings(enable: bool) -> None:
   """
   pass
 
+def trace_export(endpoint: str, protocol: str = "grpc", headers: Dict[str, str] = None) -> None:
+  """Exports traces of Tilt's builds and deploys to an OpenTelemetry collector.
+
+  Each update gets a trace, with spans for each build step, Kubernetes apply,
+  live update sync, and exec. Useful for finding slow steps in your inner
+  loop, e.g., by running Jaeger locally:
+
+  .. code-block:: python
+
+    trace_export('http://localhost:4317')
+
+  The step, apply, sync, and exec spans only go to this collector. They're never
+  sent to ``experimental_telemetry_cmd``.
+
+  Args:
+    endpoint: The URL of the collector. ``http://`` URLs send traces in plaintext;
+      ``https://`` URLs use TLS.
+    protocol: ``'grpc'`` for OTLP/gRPC (usually port 4317), or ``'http'`` for
+      OTLP/HTTP (usually port 4318).
+    headers: Extra headers to send with each export (e.g., for authentication).
+  """
+  pass
+
+def notify(events: Union[str, List[str]] = [], url: str = None, cmd: Union[str, List[str]] = None,
+           cmd_bat: Union[str, List[str]] = None, headers: Dict[str, str] = None,
+           resources: Union[str, List[str]] = []) -> None:
+  """Sends a notification when resources change status.
+
+  Each call adds a notifier that either POSTs to a webhook or runs a local command.
+  For example, to post to a Slack channel when a resource breaks, and to say
+  something when everything is up:
+
+  .. code-block:: python
+
+    notify(events=['error', 'recovered'], url='https://hooks.slack.com/services/...')
+    notify(events='all_ready', cmd=['say', 'Tilt is ready'])
+
+  Webhooks receive a JSON body like
+  ``{"event": "error", "resource": "frontend", "message": "...", "text": "...", "time": "..."}``.
+  ``text`` is a copy of ``message``, for Slack-compatible webhooks.
+
+  Commands receive the event in the environment variables ``TILT_NOTIFY_EVENT``,
+  ``TILT_NOTIFY_RESOURCE``, and ``TILT_NOTIFY_MESSAGE``.
+
+  Args:
+    events: The events to notify on. ``'error'`` fires when a resource's update or
+      runtime fails, ``'recovered'`` fires when a failed resource becomes ready again,
+      and ``'all_ready'`` fires when all resources become ready.
+      Defaults to all events.
+    url: An ``http://`` or ``https://`` URL to POST to.
+    cmd: A command to run. If a string, runs it with ``sh -c``. If a list, runs it directly.
+    cmd_bat: The command to run on Windows, if different from ``cmd``.
+    headers: Extra headers to send with the webhook (e.g., for authentication).
+    resources: If set, only notify on these resources. ``'all_ready'`` then
+      fires when these resources become ready.
+
+  Exactly one of ``url`` and ``cmd`` must be set.
+  """
+  pass
+
+def desktop_notify(enabled: bool = True, fallback_cmd: Union[str, List[str]] = None,
+                   fallback_cmd_bat: Union[str, List[str]] = None) -> None:
+  """Shows a desktop notification when a resource fails or recovers.
+
+  Useful when Tilt is running in a background terminal. Equivalent to
+  ``tilt up --notify``.
+
+  On Linux, Tilt sends notifications over D-Bus, which works with most desktop
+  environments. Elsewhere (or if D-Bus isn't available), Tilt runs the
+  ``fallback_cmd`` instead. For example, on macOS:
+
+  .. code-block:: python
+
+    desktop_notify(fallback_cmd='terminal-notifier -title Tilt -message "$TILT_NOTIFY_MESSAGE"')
+
+  Args:
+    enabled: Whether to show desktop notifications.
+    fallback_cmd: A command to run if Tilt can't show a notification over D-Bus. Receives
+      the environment variables ``TILT_NOTIFY_EVENT``, ``TILT_NOTIFY_RESOURCE``,
+      and ``TILT_NOTIFY_MESSAGE``, like :meth:`notify`.
+    fallback_cmd_bat: The fallback command to run on Windows, if different from ``fallback_cmd``.
+  """
+  pass
+
 def version_settings(check_updates: bool = True, constraint: str = "") -> None:
   """Controls Tilt's behavior with regard to its own version.
 
@@ -1290,7 +1590,10 @@ def update_settings(
     max_parallel_updates: int=3,
     k8s_upsert_timeout_secs: int=30,
     suppress_unused_image_warnings: Union[str, List[str]]=None,
-    k8s_server_side_apply: str="auto") -> None:
+    k8s_server_side_apply: str="auto",
+    k8s_dry_run_diff: bool=False,
+    max_parallel_updates_by_label: Dict[str, int]={},
+    cache_build_inputs: bool=False) -> None:
   """Configures Tilt's updates to your resources. (An update is any execution of or
   change to a resource. Examples of updates include: doing a docker build + deploy to
   Kubernetes; running a live update on an existing container; and executing
@@ -1303,6 +1606,23 @@ def update_settings(
       Accepts a list of image names, or '*' to suppress warnings for all images.
     k8s_server_side_apply: controls whether Kubernetes applies use server-side apply.
       Accepts values ``true``, ``false`` and ``auto``. Default is ``auto``.
+    k8s_dry_run_diff: if True, Tilt runs a server-side dry-run before each Kubernetes apply
+      and records what changed on the KubernetesApply status. View it with ``tilt diff <resource>``.
+    max_parallel_updates_by_label: maximum number of updates Tilt will execute in parallel for resources
+      with each label, e.g., ``{'database': 1}``. Limits must be positive integers. Calling ``update_settings``
+      again adds to the existing limits.
+    cache_build_inputs: if True, Tilt remembers the inputs of each ``docker_build`` (the build context,
+      Dockerfile, build args, etc.) across restarts. On startup, if an image's inputs haven't changed and
+      the image still exists where the cluster pulls it from (the local image store, or the cluster's
+      registry), Tilt reuses it instead of rebuilding it. Images loaded directly into a cluster, like KIND
+      without a registry, are always rebuilt. Tilt reads each file in the build
+      context once, then only re-reads files whose size or modification time changed.
+
+  On startup, Tilt builds the resources at the start of the longest chain of ``resource_deps`` first.
+  Among resources with equally long chains, local resources go first, then Kubernetes resources without
+  images, then the rest. Within each of those, resources whose images are shared with other resources go
+  first, and then resources go in Tiltfile order. Kubernetes YAML that isn't part of any resource
+  (e.g., namespaces) always goes before other cluster resources, because they might need it.
 """
 
 def ci_settings(
@@ -1317,7 +1637,7 @@ def ci_settings(
     readiness_timeout: Timeout for an active resource to become ready before the CI pipeline fails. Measured from the time the resource is started. Defaults to '5m'. Does not affect Kubernetes jobs.
   """
 
-def watch_settings(ignore: Union[str, List[str]]) -> None:
+def watch_settings(ignore: Union[str, List[str]] = [], mode: str = '', interval: str = '') -> None:
   """Configures global watches.
 
   May be called multiple times to add more ignore patterns.
@@ -1326,6 +1646,11 @@ def watch_settings(ignore: Union[str, List[str]]) -> None:
     ignore: A string or list of strings that should not trigger updates. Equivalent to adding
       patterns to .tiltignore. Relative patterns are evaluated relative to the current working dir.
       See `Debugging File Changes <file_changes.html>`_ for more details.
+    mode: How Tilt detects file changes. ``'native'`` (the default) uses the OS's file notifications.
+      ``'poll'`` periodically scans watched files for changes instead. Polling is slower, but works
+      on filesystems that don't deliver notifications, like NFS and some container bind mounts.
+    interval: How often to scan for changes when ``mode='poll'``, as a duration string (e.g., ``'500ms'``).
+      Defaults to ``'2s'``.
   """
 
 
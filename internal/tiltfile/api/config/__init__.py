@@ -88,6 +88,77 @@ def define_bool(name: str, args: bool=False, usage: str="") -> None:
       usage: When arg parsing fails, what to print for this setting's description.
     """
 
+def define_int(name: str, args: bool=False, usage: str="") -> None:
+    """
+    Defines a config setting of type `int`.
+
+    Allows the user invoking Tilt to configure a key named ``name`` to be in the
+    dict returned by :meth:`parse`.
+
+    For instance, at runtime, to set a flag of this type named `replicas` to value 3, run ``tilt up -- --replicas 3``.
+
+    See the `Tiltfile config documentation <tiltfile_config.html>`_ for examples
+    and more information.
+
+    Args:
+      name: The name of the config setting
+      args: If False, the config setting is specified by its name. (e.g., if it's named "foo",
+            ``tilt up -- --foo 3`` this setting would be ``3``.)
+
+            If True, the config setting is specified by unnamed positional args. (e.g.,
+            in ``tilt up -- 3``, this setting would be ``3``.)
+      usage: When arg parsing fails, what to print for this setting's description.
+    """
+
+def define_enum(name: str, choices: List[str], args: bool=False, usage: str="") -> None:
+    """
+    Defines a config setting of type `str` that must be one of the given choices.
+
+    Allows the user invoking Tilt to configure a key named ``name`` to be in the
+    dict returned by :meth:`parse`.
+
+    For instance::
+
+      config.define_enum('env', choices=['dev', 'staging', 'prod'])
+      cfg = config.parse()
+      env = cfg.get('env', 'dev')
+
+    Then ``tilt up -- --env prod`` sets ``env`` to ``"prod"``, and
+    ``tilt up -- --env qa`` is an error.
+
+    See the `Tiltfile config documentation <tiltfile_config.html>`_ for examples
+    and more information.
+
+    Args:
+      name: The name of the config setting
+      choices: The allowed values.
+      args: If False, the config setting is specified by its name. (e.g., if it's named "foo",
+            ``tilt up -- --foo bar`` this setting would be ``"bar"``.)
+
+            If True, the config setting is specified by unnamed positional args. (e.g.,
+            in ``tilt up -- bar``, this setting would be ``"bar"``.)
+      usage: When arg parsing fails, what to print for this setting's description.
+        The choices are appended automatically.
+    """
+
+def define_secret_string(name: str, args: bool=False, usage: str="") -> None:
+    """
+    Defines a config setting of type `str` whose value is a secret, like an API token.
+
+    Works like :meth:`define_string`, but Tilt scrubs the value from logs,
+    the same way it scrubs the contents of Kubernetes Secrets (unless disabled with
+    :meth:`~api.secret_settings`).
+
+    Args:
+      name: The name of the config setting
+      args: If False, the config setting is specified by its name. (e.g., if it's named "foo",
+            ``tilt up -- --foo bar`` this setting would be ``"bar"``.)
+
+            If True, the config setting is specified by unnamed positional args. (e.g.,
+            in ``tilt up -- bar``, this setting would be ``"bar"``.)
+      usage: When arg parsing fails, what to print for this setting's description.
+    """
+
 def parse() -> Dict[str, Any]:
     """
     Loads config settings from tilt_config.json, overlays config settings from
@@ -104,6 +175,8 @@ def parse() -> Dict[str, Any]:
     Tiltfile uses :meth:`parse` and also needs to allow specifying a set
     of resources to run, it needs to call :meth:`set_enabled_resources`.
 
+    To list the settings that a Tiltfile accepts, run ``tilt up -- --help``.
+
     See the `Tiltfile config documentation <tiltfile_config.html>`_ for examples
     and more information.
 
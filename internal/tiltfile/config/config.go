@@ -21,6 +21,9 @@ type Settings struct {
 
 	configParseCalled bool
 
+	// the values of any secret settings, so that we can scrub them from logs
+	secrets model.SecretSet
+
 	// if parse has been called, the directory containing the Tiltfile that called it
 	seenWorkingDirectory string
 }
@@ -75,6 +78,13 @@ func (e *Plugin) OnStart(env *starkit.Environment) error {
 		{"config.define_object", configSettingDefinitionBuiltin(func() configValue {
 			return &objectSetting{}
 		})},
+		{"config.define_int", configSettingDefinitionBuiltin(func() configValue {
+			return &intSetting{}
+		})},
+		{"config.define_secret_string", configSettingDefinitionBuiltin(func() configValue {
+			return &secretStringSetting{}
+		})},
+		{"config.define_enum", defineEnum},
 	} {
 		err := env.AddBuiltin(b.name, b.f)
 		if err != nil {
@@ -145,7 +155,7 @@ func (e *Plugin) parse(thread *starlark.Thread, fn *starlark.Builtin, args starl
 		return starlark.None, err
 	}
 
-	ret, out, err := settings.configDef.parse(userConfigPath, tf.Spec.Args)
+	config, out, err := settings.configDef.parse(userConfigPath, tf.Spec.Args)
 	if out != "" {
 		thread.Print(thread, out)
 	}
@@ -153,5 +163,19 @@ func (e *Plugin) parse(thread *starlark.Thread, fn *starlark.Builtin, args starl
 		return starlark.None, err
 	}
 
-	return ret, nil
+	err = starkit.SetState(thread, func(settings Settings) (Settings, error) {
+		settings.secrets = config.secrets()
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return config.toStarlark()
+}
+
+// Secrets returns the values of any secret settings (from config.define_secret_string),
+// so that they can be scrubbed from logs.
+func (s Settings) Secrets() model.SecretSet {
+	return s.secrets
 }
@@ -12,6 +12,8 @@ import (
 	"go.starlark.net/starlark"
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 type configValue interface {
@@ -23,6 +25,15 @@ type configValue interface {
 
 type configMap map[string]configValue
 
+// Returned when the user asks for help with Tiltfile args (e.g., tilt up -- --help).
+type helpRequestedError struct {
+	usage string
+}
+
+func (e helpRequestedError) Error() string {
+	return "Tiltfile args:\n" + e.usage
+}
+
 type configSetting struct {
 	newValue func() configValue
 	usage    string
@@ -64,6 +75,9 @@ func mergeConfigMaps(settingsFromConfig, settingsFromArgs configMap) configMap {
 func (cd ConfigDef) incorporateArgs(config configMap, args []string) (ret configMap, output string, err error) {
 	var settingsFromArgs configMap
 	settingsFromArgs, output, err = cd.parseArgs(args)
+	if _, ok := err.(helpRequestedError); ok {
+		return nil, output, err
+	}
 	if err != nil {
 		return nil, output, fmt.Errorf("invalid Tiltfile config args: %v", err)
 	}
@@ -73,23 +87,25 @@ func (cd ConfigDef) incorporateArgs(config configMap, args []string) (ret config
 	return config, output, nil
 }
 
-func (cd ConfigDef) parse(configPath string, args []string) (v starlark.Value, output string, err error) {
-	config, err := cd.readFromFile(configPath)
+func (cd ConfigDef) parse(configPath string, args []string) (config configMap, output string, err error) {
+	config, err = cd.readFromFile(configPath)
 	if err != nil {
-		return starlark.None, "", err
+		return nil, "", err
 	}
 
-	config, output, err = cd.incorporateArgs(config, args)
-	if err != nil {
-		return starlark.None, output, err
-	}
+	return cd.incorporateArgs(config, args)
+}
 
-	ret, err := config.toStarlark()
-	if err != nil {
-		return nil, output, err
+// The values of all secret settings, so that we can scrub them from logs.
+func (cm configMap) secrets() model.SecretSet {
+	ret := model.SecretSet{}
+	for k, v := range cm {
+		secret, ok := v.(*secretStringSetting)
+		if ok && secret.value != "" {
+			ret.AddSecret("config", k, []byte(secret.value))
+		}
 	}
-
-	return ret, output, nil
+	return ret
 }
 
 // parse command-line args
@@ -98,6 +114,9 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 	w := &bytes.Buffer{}
 	fs.SetOutput(w)
 
+	// We print our own usage, which includes the positional args.
+	fs.Usage = func() {}
+
 	ret = make(configMap)
 	for name, def := range cd.configSettings {
 		ret[name] = def.newValue()
@@ -112,8 +131,11 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 	}
 
 	err = fs.Parse(args)
+	if err == flag.ErrHelp {
+		return nil, w.String(), helpRequestedError{usage: cd.usage(fs)}
+	}
 	if err != nil {
-		usage := fs.FlagUsagesWrapped(80)
+		usage := cd.usage(fs)
 		if strings.TrimSpace(usage) != "" {
 			usage = "\nUsage:\n" + usage
 		}
@@ -138,6 +160,16 @@ func (cd ConfigDef) parseArgs(args []string) (ret configMap, output string, err
 	return ret, w.String(), nil
 }
 
+// The usage text for the Tiltfile args: the flags, then the positional args (if any).
+func (cd ConfigDef) usage(fs *flag.FlagSet) string {
+	usage := fs.FlagUsagesWrapped(80)
+	if cd.positionalSettingName != "" {
+		def := cd.configSettings[cd.positionalSettingName]
+		usage += fmt.Sprintf("\nPositional args:\n  %s   %s\n", cd.positionalSettingName, def.usage)
+	}
+	return usage
+}
+
 // parse settings from the config file
 func (cd ConfigDef) readFromFile(tiltConfigPath string) (ret configMap, err error) {
 	ret = make(configMap)
@@ -193,38 +225,78 @@ func configSettingDefinitionBuiltin(newConfigValue func() configValue) starkit.F
 			return starlark.None, err
 		}
 
-		if name == "" {
-			return starlark.None, errors.New("'name' is required")
-		}
+		return defineSetting(thread, fn, name, isArgs, usage, newConfigValue)
+	}
+}
 
-		err = starkit.SetState(thread, func(settings Settings) (Settings, error) {
-			if settings.configParseCalled {
-				return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
-			}
+// config.define_enum takes a list of choices, so it gets its own builtin.
+func defineEnum(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var choices value.StringList
+	var isArgs bool
+	var usage string
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"name",
+		&name,
+		"choices",
+		&choices,
+		"args?",
+		&isArgs,
+		"usage?",
+		&usage,
+	)
+	if err != nil {
+		return starlark.None, err
+	}
 
-			if _, ok := settings.configDef.configSettings[name]; ok {
-				return settings, fmt.Errorf("%s defined multiple times", name)
-			}
+	if len(choices) == 0 {
+		return starlark.None, fmt.Errorf("%s: 'choices' must not be empty", fn.Name())
+	}
 
-			if isArgs {
-				if settings.configDef.positionalSettingName != "" {
-					return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
-				}
+	choiceHelp := fmt.Sprintf("(one of: %s)", strings.Join(choices, ", "))
+	if usage == "" {
+		usage = choiceHelp
+	} else {
+		usage = usage + " " + choiceHelp
+	}
 
-				settings.configDef.positionalSettingName = name
-			}
+	return defineSetting(thread, fn, name, isArgs, usage, func() configValue {
+		return &enumSetting{choices: choices}
+	})
+}
+
+func defineSetting(thread *starlark.Thread, fn *starlark.Builtin, name string, isArgs bool, usage string, newConfigValue func() configValue) (starlark.Value, error) {
+	if name == "" {
+		return starlark.None, errors.New("'name' is required")
+	}
+
+	err := starkit.SetState(thread, func(settings Settings) (Settings, error) {
+		if settings.configParseCalled {
+			return settings, fmt.Errorf("%s cannot be called after config.parse is called", fn.Name())
+		}
 
-			settings.configDef.configSettings[name] = configSetting{
-				newValue: newConfigValue,
-				usage:    usage,
+		if _, ok := settings.configDef.configSettings[name]; ok {
+			return settings, fmt.Errorf("%s defined multiple times", name)
+		}
+
+		if isArgs {
+			if settings.configDef.positionalSettingName != "" {
+				return settings, fmt.Errorf("both %s and %s are defined as positional args", name, settings.configDef.positionalSettingName)
 			}
 
-			return settings, nil
-		})
-		if err != nil {
-			return starlark.None, err
+			settings.configDef.positionalSettingName = name
+		}
+
+		settings.configDef.configSettings[name] = configSetting{
+			newValue: newConfigValue,
+			usage:    usage,
 		}
 
-		return starlark.None, nil
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
 	}
+
+	return starlark.None, nil
 }
@@ -281,6 +281,50 @@ Usage:
 	require.EqualError(t, err, expected)
 }
 
+func TestHelp(t *testing.T) {
+	f := NewFixture(t, []string{"--help"}, "")
+
+	f.File("Tiltfile", `
+config.define_string_list('to-run', args=True, usage='resources to run')
+config.define_enum('env', choices=['dev', 'prod'], usage='where to deploy')
+config.define_int('replicas', usage='how many replicas')
+config.define_secret_string('token')
+config.parse()
+`)
+
+	expected := `Tiltfile args:
+      --env string     where to deploy (one of: dev, prod)
+      --replicas int   how many replicas
+      --token string   ` + `
+
+Positional args:
+  to-run   resources to run
+`
+
+	_, err := f.ExecFile("Tiltfile")
+	require.EqualError(t, err, expected)
+	require.Equal(t, "", f.PrintOutput())
+}
+
+func TestSecrets(t *testing.T) {
+	f := NewFixture(t, []string{"--token", "hunter2"}, "")
+
+	f.File("Tiltfile", `
+config.define_secret_string('token')
+config.define_secret_string('unset')
+config.define_string('name')
+config.parse()
+`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	secrets := MustState(result).Secrets()
+	require.Len(t, secrets, 1)
+	require.Equal(t, "token", secrets["hunter2"].Key)
+	require.Equal(t, "config", secrets["hunter2"].Name)
+}
+
 // i.e., tilt up foo bar gets you resources foo and bar
 func TestDefaultTiltBehavior(t *testing.T) {
 	f := NewFixture(t, []string{"foo", "bar"}, "")
@@ -575,6 +619,21 @@ func TestTypes(t *testing.T) {
 		newTypeTestCase("bool defined multiple times", "config.define_bool('foo')").withArgs("--foo", "--foo").withExpectedError("bool settings can only be specified once"),
 		newTypeTestCase("invalid bool from config", "config.define_bool('foo')").withConfigFile(`{"foo": 5}`).withExpectedError("expected bool, found float64"),
 
+		newTypeTestCase("int from args", "config.define_int('foo')").withArgs("--foo", "3").withExpectedVal("3"),
+		newTypeTestCase("int from config", "config.define_int('foo')").withConfigFile(`{"foo": 3}`).withExpectedVal("3"),
+		newTypeTestCase("invalid int from args", "config.define_int('foo')").withArgs("--foo", "three").withExpectedError(`expected int, found "three"`),
+		newTypeTestCase("invalid int from config", "config.define_int('foo')").withConfigFile(`{"foo": "3"}`).withExpectedError("expected int, found string"),
+		newTypeTestCase("fractional int from config", "config.define_int('foo')").withConfigFile(`{"foo": 3.5}`).withExpectedError("expected int, found 3.5"),
+
+		newTypeTestCase("enum from args", "config.define_enum('foo', choices=['dev', 'prod'])").withArgs("--foo", "prod").withExpectedVal("'prod'"),
+		newTypeTestCase("enum from config", "config.define_enum('foo', choices=['dev', 'prod'])").withConfigFile(`{"foo": "dev"}`).withExpectedVal("'dev'"),
+		newTypeTestCase("invalid enum from args", "config.define_enum('foo', choices=['dev', 'prod'])").withArgs("--foo", "staging").withExpectedError(`invalid value "staging". Must be one of: dev, prod`),
+		newTypeTestCase("invalid enum from config", "config.define_enum('foo', choices=['dev', 'prod'])").withConfigFile(`{"foo": "staging"}`).withExpectedError(`invalid value "staging". Must be one of: dev, prod`),
+		newTypeTestCase("enum without choices", "config.define_enum('foo', choices=[])").withExpectedError("config.define_enum: 'choices' must not be empty"),
+
+		newTypeTestCase("secret string from args", "config.define_secret_string('foo')").withArgs("--foo", "hunter2").withExpectedVal("'hunter2'"),
+		newTypeTestCase("secret string from config", "config.define_secret_string('foo')").withConfigFile(`{"foo": "hunter2"}`).withExpectedVal("'hunter2'"),
+
 		newTypeTestCase("obj from args", "config.define_object('foo')").
 			withArgs(`--foo`, `["a", "b", "c"]`).
 			withExpectedVal(`["a", "b", "c"]`),
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+)
+
+// A string setting that must be one of a fixed set of choices.
+type enumSetting struct {
+	choices []string
+	value   string
+	isSet   bool
+}
+
+var _ configValue = &enumSetting{}
+var _ flag.Value = &enumSetting{}
+
+func (s *enumSetting) starlark() starlark.Value {
+	return starlark.String(s.value)
+}
+
+func (s *enumSetting) IsSet() bool {
+	return s.isSet
+}
+
+func (s *enumSetting) Type() string {
+	return "string"
+}
+
+func (s *enumSetting) validate(v string) error {
+	for _, c := range s.choices {
+		if v == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value %q. Must be one of: %s", v, strings.Join(s.choices, ", "))
+}
+
+func (s *enumSetting) setFromInterface(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	v, ok := i.(string)
+	if !ok {
+		return fmt.Errorf("expected %T, found %T", s.value, i)
+	}
+	err := s.validate(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = v
+	s.isSet = true
+
+	return nil
+}
+
+func (s *enumSetting) Set(v string) error {
+	if s.isSet {
+		return fmt.Errorf("enum settings can only be specified once. multiple values found (last value: %s)", v)
+	}
+	err := s.validate(v)
+	if err != nil {
+		return err
+	}
+
+	s.value = v
+	s.isSet = true
+	return nil
+}
+
+func (s *enumSetting) String() string {
+	return s.value
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	flag "github.com/spf13/pflag"
+	"go.starlark.net/starlark"
+)
+
+type intSetting struct {
+	value int
+	isSet bool
+}
+
+var _ configValue = &intSetting{}
+var _ flag.Value = &intSetting{}
+
+func (s *intSetting) starlark() starlark.Value {
+	return starlark.MakeInt(s.value)
+}
+
+func (s *intSetting) IsSet() bool {
+	return s.isSet
+}
+
+func (s *intSetting) Type() string {
+	return "int"
+}
+
+func (s *intSetting) setFromInterface(i interface{}) error {
+	if i == nil {
+		return nil
+	}
+	// JSON numbers are decoded as float64.
+	v, ok := i.(float64)
+	if !ok {
+		return fmt.Errorf("expected %T, found %T", s.value, i)
+	}
+	if v != math.Trunc(v) || v > math.MaxInt || v < math.MinInt {
+		return fmt.Errorf("expected %T, found %v", s.value, v)
+	}
+
+	s.value = int(v)
+	s.isSet = true
+
+	return nil
+}
+
+func (s *intSetting) Set(v string) error {
+	if s.isSet {
+		return fmt.Errorf("int settings can only be specified once. multiple values found (last value: %s)", v)
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("expected int, found %q", v)
+	}
+	s.value = i
+	s.isSet = true
+	return nil
+}
+
+func (s *intSetting) String() string {
+	return strconv.Itoa(s.value)
+}
@@ -0,0 +1,13 @@
+package config
+
+import (
+	flag "github.com/spf13/pflag"
+)
+
+// A string setting whose value is scrubbed from logs, like a Kubernetes secret.
+type secretStringSetting struct {
+	stringSetting
+}
+
+var _ configValue = &secretStringSetting{}
+var _ flag.Value = &secretStringSetting{}
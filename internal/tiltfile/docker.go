@@ -14,6 +14,7 @@ import (
 	"go.starlark.net/starlark"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
@@ -80,6 +81,9 @@ type dockerImage struct {
 	dockerComposeLocalVolumePaths []string
 
 	extraHosts []string
+
+	attestations []string
+	signingKey   string
 }
 
 func (d *dockerImage) ID() model.TargetID {
@@ -112,9 +116,10 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		liveUpdateVal,
 		ignoreVal,
 		onlyVal,
-		entrypoint starlark.Value
+		entrypoint,
+		attestVal starlark.Value
 	var buildArgs value.StringStringMap
-	var network, platform value.Stringable
+	var network, platform, signingKey value.Stringable
 	var ssh, secret, extraTags, cacheFrom, extraHosts value.StringOrStringList
 	var matchInEnvVars, pullParent bool
 	var overrideArgsVal starlark.Sequence
@@ -140,6 +145,8 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		"pull?", &pullParent,
 		"platform?", &platform,
 		"extra_hosts?", &extraHosts,
+		"attest?", &attestVal,
+		"sign?", &signingKey,
 	); err != nil {
 		return nil, err
 	}
@@ -219,6 +226,16 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		}
 	}
 
+	attestations, err := parseAttestations(attestVal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys may be local paths or KMS URIs (e.g., awskms://...).
+	if signingKey.Value != "" && !strings.Contains(signingKey.Value, "://") {
+		signingKey.Value = starkit.AbsPath(thread, signingKey.Value)
+	}
+
 	if platform.Value == "" {
 		// for compatibility with Docker CLI, support the env var fallback
 		// see https://docs.docker.com/engine/reference/commandline/cli/#environment-variables
@@ -258,6 +275,8 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		platform:         platform.Value,
 		tiltfilePath:     starkit.CurrentExecPath(thread),
 		extraHosts:       extraHosts.Values,
+		attestations:     attestations,
+		signingKey:       signingKey.Value,
 	}
 	err = s.buildIndex.addImage(r)
 	if err != nil {
@@ -267,6 +286,35 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 	return starlark.None, nil
 }
 
+// The attestations that attest=True generates.
+var defaultAttestations = []string{"type=sbom", "type=provenance,mode=min"}
+
+// Parses the attest argument, which may be a bool or a list of
+// attestations in the `docker buildx build --attest` format.
+func parseAttestations(val starlark.Value) ([]string, error) {
+	if val == nil || val == starlark.None {
+		return nil, nil
+	}
+
+	if b, ok := val.(starlark.Bool); ok {
+		if !b {
+			return nil, nil
+		}
+		return append([]string{}, defaultAttestations...), nil
+	}
+
+	var attests value.StringOrStringList
+	if err := attests.Unpack(val); err != nil {
+		return nil, fmt.Errorf("Argument attest: must be a bool, string, or list of strings, got %s", val.Type())
+	}
+
+	_, err := docker.ParseAttestations(attests.Values)
+	if err != nil {
+		return nil, fmt.Errorf("Argument attest: %v", err)
+	}
+	return attests.Values, nil
+}
+
 func (s *tiltfileState) parseOnly(val starlark.Value) ([]string, error) {
 	paths, err := parseValuesToStrings(val, "only")
 	if err != nil {
@@ -515,6 +563,35 @@ func (s *tiltfileState) defaultRegistry(t *starlark.Thread, fn *starlark.Builtin
 	return starlark.None, nil
 }
 
+func (s *tiltfileState) imageTagPolicyFn(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if s.imageTagPolicy != nil {
+		return starlark.None, errors.New("image tag policy already defined")
+	}
+
+	var template string
+	var additionalTags value.StringOrStringList
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"template", &template,
+		"additional_tags?", &additionalTags); err != nil {
+		return nil, err
+	}
+
+	if err := container.ValidateTagTemplate(template, true); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	for _, tmpl := range additionalTags.Values {
+		if err := container.ValidateTagTemplate(tmpl, false); err != nil {
+			return nil, fmt.Errorf("%s: additional_tags: %v", fn.Name(), err)
+		}
+	}
+
+	s.imageTagPolicy = &v1alpha1.ImageTagPolicy{
+		Template:       template,
+		AdditionalTags: additionalTags.Values,
+	}
+	return starlark.None, nil
+}
+
 func (s *tiltfileState) dockerignoresFromPathsAndContextFilters(source string, paths []string, ignorePatterns []string, onlys []string, dbDockerfilePath string) ([]model.Dockerignore, error) {
 	var result []model.Dockerignore
 	dupeSet := map[string]bool{}
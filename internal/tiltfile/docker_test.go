@@ -257,3 +257,81 @@ func TestExtraHosts(t *testing.T) {
 		)
 	}
 }
+
+func TestAttest(t *testing.T) {
+	type tc struct {
+		name     string
+		arg      string
+		expected []string
+	}
+	tcs := []tc{
+		{name: "No Attest"},
+		{name: "Attest False", arg: "False"},
+		{name: "Attest True", arg: "True", expected: []string{"type=sbom", "type=provenance,mode=min"}},
+		{name: "One Attestation", arg: "'type=sbom'", expected: []string{"type=sbom"}},
+		{
+			name:     "Two Attestations",
+			arg:      "['type=sbom,generator=docker/scout-sbom-indexer:1', 'type=provenance,mode=max']",
+			expected: []string{"type=sbom,generator=docker/scout-sbom-indexer:1", "type=provenance,mode=max"},
+		},
+	}
+
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			f.yaml("fe.yaml", deployment("fe", image("gcr.io/fe")))
+			f.file("Dockerfile", `FROM alpine`)
+
+			tf := "k8s_yaml('fe.yaml')\ndocker_build('gcr.io/fe', '.'"
+			if tt.arg != "" {
+				tf += ", attest=" + tt.arg
+			}
+			tf += ")"
+			f.file("Tiltfile", tf)
+
+			f.load()
+			m := f.assertNextManifest("fe")
+			require.Equal(t, tt.expected, m.ImageTargetAt(0).DockerBuildInfo().Attestations)
+		})
+	}
+}
+
+func TestAttestInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Dockerfile", `FROM alpine`)
+	f.file("Tiltfile", `docker_build('gcr.io/fe', '.', attest=['mode=max'])`)
+
+	f.loadErrString(`Argument attest: attestation "mode=max" must have a type`)
+}
+
+func TestSign(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("fe.yaml", deployment("fe", image("gcr.io/fe")))
+	f.file("Dockerfile", `FROM alpine`)
+	f.file("Tiltfile", `
+k8s_yaml('fe.yaml')
+docker_build('gcr.io/fe', '.', sign='keys/cosign.key')
+`)
+
+	f.load()
+	m := f.assertNextManifest("fe")
+	assert.Equal(t, f.JoinPath("keys", "cosign.key"), m.ImageTargetAt(0).DockerBuildInfo().SigningKey)
+}
+
+func TestSignKMS(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("fe.yaml", deployment("fe", image("gcr.io/fe")))
+	f.file("Dockerfile", `FROM alpine`)
+	f.file("Tiltfile", `
+k8s_yaml('fe.yaml')
+docker_build('gcr.io/fe', '.', sign='gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k')
+`)
+
+	f.load()
+	m := f.assertNextManifest("fe")
+	assert.Equal(t, "gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k", m.ImageTargetAt(0).DockerBuildInfo().SigningKey)
+}
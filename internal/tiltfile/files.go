@@ -140,20 +140,77 @@ func (s *tiltfileState) execLocalCmd(t *starlark.Thread, cmd model.Cmd, options
 func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	path, kustomizeBin := value.NewLocalPathUnpacker(thread), value.NewLocalPathUnpacker(thread)
 	flags := value.StringList{}
-	err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &path, "kustomize_bin?", &kustomizeBin, "flags?", &flags)
+	components := value.NewLocalPathListUnpacker(thread)
+	var enableHelm, enableAlphaPlugins, enableExec bool
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"paths", &path,
+		"kustomize_bin?", &kustomizeBin,
+		"flags?", &flags,
+		"components?", &components,
+		"enable_helm?", &enableHelm,
+		"enable_alpha_plugins?", &enableAlphaPlugins,
+		"enable_exec?", &enableExec)
 	if err != nil {
 		return nil, err
 	}
 
+	if kustomizeBin.Value != "" || len(flags) > 0 {
+		if len(components.Value) > 0 || enableHelm || enableAlphaPlugins || enableExec {
+			return nil, fmt.Errorf("%s: components, enable_helm, enable_alpha_plugins and enable_exec "+
+				"can't be combined with kustomize_bin or flags", fn.Name())
+		}
+		return s.kustomizeWithBinary(thread, path.Value, kustomizeBin.Value, flags)
+	}
+
+	ctx, err := starkit.ContextFromThread(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := &bytes.Buffer{}
+	yaml, deps, err := kustomize.Render(ctx, path.Value, kustomize.RenderOptions{
+		Components:         components.Value,
+		EnableHelm:         enableHelm,
+		EnableAlphaPlugins: enableAlphaPlugins,
+		EnableExec:         enableExec,
+		Warnings:           warnings,
+	})
+	seenWarnings := make(map[string]bool)
+	for _, line := range strings.Split(warnings.String(), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "# Warning:"))
+		if line != "" && !seenWarnings[line] {
+			seenWarnings[line] = true
+			s.logger.Warnf("%s: %s", fn.Name(), line)
+		}
+	}
+	if err != nil {
+		// Kustomize may have read some files before failing. Watch the directory,
+		// so that fixing the error reloads the Tiltfile.
+		_ = tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchRecursive, path.Value)
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+	for _, d := range deps {
+		err := tiltfile_io.RecordReadPath(thread, tiltfile_io.WatchFileOnly, d)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tiltfile_io.NewBlob(string(yaml), fmt.Sprintf("kustomize: %s", path.Value)), nil
+}
+
+// Renders the kustomization by shelling out to a kustomize binary,
+// for Tiltfiles that pin a binary or pass it flags.
+func (s *tiltfileState) kustomizeWithBinary(thread *starlark.Thread, path string, kustomizeBin string, flags []string) (starlark.Value, error) {
 	kustomizeArgs := []string{"kustomize", "build"}
 
-	if kustomizeBin.Value != "" {
-		kustomizeArgs[0] = kustomizeBin.Value
+	if kustomizeBin != "" {
+		kustomizeArgs[0] = kustomizeBin
 	}
 
-	_, err = exec.LookPath(kustomizeArgs[0])
+	_, err := exec.LookPath(kustomizeArgs[0])
 	if err != nil {
-		if kustomizeBin.Value != "" {
+		if kustomizeBin != "" {
 			return nil, err
 		}
 		s.logger.Infof("Falling back to `kubectl kustomize` since `%s` was not found in PATH", kustomizeArgs[0])
@@ -163,7 +220,7 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 	// NOTE(nick): There's a bug in kustomize where it doesn't properly
 	// handle absolute paths. Convert to relative paths instead:
 	// https://github.com/kubernetes-sigs/kustomize/issues/2789
-	relKustomizePath, err := filepath.Rel(starkit.AbsWorkingDir(thread), path.Value)
+	relKustomizePath, err := filepath.Rel(starkit.AbsWorkingDir(thread), path)
 	if err != nil {
 		return nil, err
 	}
@@ -176,7 +233,7 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 	if err != nil {
 		return nil, err
 	}
-	deps, err := kustomize.Deps(path.Value)
+	deps, err := kustomize.Deps(path)
 	if err != nil {
 		return nil, fmt.Errorf("resolving deps: %v", err)
 	}
@@ -187,7 +244,7 @@ func (s *tiltfileState) kustomize(thread *starlark.Thread, fn *starlark.Builtin,
 		}
 	}
 
-	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("kustomize: %s", path.Value)), nil
+	return tiltfile_io.NewBlob(yaml, fmt.Sprintf("kustomize: %s", path)), nil
 }
 
 func (s *tiltfileState) helm(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
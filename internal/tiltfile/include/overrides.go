@@ -0,0 +1,113 @@
+package include
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The environment variable that points to a per-user overrides file.
+const OverridesEnvVar = "TILT_OVERRIDES"
+
+// The suffix of the default overrides file, e.g., Tiltfile.local
+const overridesSuffix = ".local"
+
+type OverridesState struct {
+	// The absolute path of the overrides file that was loaded, if any.
+	Path string
+}
+
+// Loads a per-user overrides file after the main Tiltfile, as if the main
+// Tiltfile had called include() on it at the end.
+//
+// This lets each developer tweak the team's Tiltfile (e.g., with
+// k8s_resource() or config.set_enabled_resources()) without editing it.
+//
+// By default, we look for Tiltfile.local next to the main Tiltfile, which
+// is optional. $TILT_OVERRIDES points to a different file, which must exist.
+type OverridesPlugin struct{}
+
+func NewOverridesPlugin() OverridesPlugin {
+	return OverridesPlugin{}
+}
+
+func (OverridesPlugin) NewState() interface{} {
+	return OverridesState{}
+}
+
+func (OverridesPlugin) OnStart(e *starkit.Environment) error {
+	return nil
+}
+
+func (OverridesPlugin) OnFinish(t *starlark.Thread) error {
+	tf, err := starkit.StartTiltfileFromThread(t)
+	if err != nil {
+		return err
+	}
+
+	// Only the main Tiltfile can be overridden.
+	if tf.Name != model.MainTiltfileManifestName.String() {
+		return nil
+	}
+
+	mainPath, err := filepath.Abs(tf.Spec.Path)
+	if err != nil {
+		return err
+	}
+
+	path := mainPath + overridesSuffix
+	required := false
+	if envPath := os.Getenv(OverridesEnvVar); envPath != "" {
+		path = envPath
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(filepath.Dir(mainPath), path)
+		}
+		required = true
+	}
+
+	// Watch the file, so that Tilt reloads when you create it.
+	err = io.RecordReadPath(t, io.WatchFileOnly, path)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stat(path)
+	if os.IsNotExist(err) && !required {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("loading Tiltfile overrides: %v", err)
+	}
+
+	_, err = t.Load(t, path)
+	if err != nil {
+		return err
+	}
+
+	return starkit.SetState(t, func(state OverridesState) (OverridesState, error) {
+		state.Path = path
+		return state, nil
+	})
+}
+
+var _ starkit.StatefulPlugin = OverridesPlugin{}
+var _ starkit.OnFinishPlugin = OverridesPlugin{}
+
+func MustOverridesState(m starkit.Model) OverridesState {
+	state, err := GetOverridesState(m)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetOverridesState(m starkit.Model) (OverridesState, error) {
+	var state OverridesState
+	err := m.Load(&state)
+	return state, err
+}
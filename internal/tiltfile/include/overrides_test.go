@@ -0,0 +1,92 @@
+package include
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/io"
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+)
+
+func TestOverridesLoadedAfterTiltfile(t *testing.T) {
+	f := newOverridesFixture(t)
+
+	f.File("Tiltfile", `print('main')`)
+	f.File("Tiltfile.local", `print('local')`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "main\nlocal\n", f.PrintOutput())
+	assert.Equal(t, f.JoinPath("Tiltfile.local"), MustOverridesState(result).Path)
+	assert.Contains(t, io.MustState(result).Paths, f.JoinPath("Tiltfile.local"))
+}
+
+func TestOverridesMissing(t *testing.T) {
+	f := newOverridesFixture(t)
+
+	f.File("Tiltfile", `print('main')`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "main\n", f.PrintOutput())
+	assert.Equal(t, "", MustOverridesState(result).Path)
+
+	// Watched, so that creating the file reloads the Tiltfile.
+	assert.Contains(t, io.MustState(result).Paths, f.JoinPath("Tiltfile.local"))
+}
+
+func TestOverridesFromEnv(t *testing.T) {
+	f := newOverridesFixture(t)
+	t.Setenv(OverridesEnvVar, "my-overrides.tilt")
+
+	f.File("Tiltfile", `print('main')`)
+	f.File("Tiltfile.local", `print('local')`)
+	f.File("my-overrides.tilt", `print('mine')`)
+
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, "main\nmine\n", f.PrintOutput())
+	assert.Equal(t, f.JoinPath("my-overrides.tilt"), MustOverridesState(result).Path)
+}
+
+func TestOverridesFromEnvMissing(t *testing.T) {
+	f := newOverridesFixture(t)
+	t.Setenv(OverridesEnvVar, "my-overrides.tilt")
+
+	f.File("Tiltfile", `print('main')`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "loading Tiltfile overrides")
+	assert.Contains(t, err.Error(), "my-overrides.tilt")
+}
+
+func TestOverridesSkippedOnError(t *testing.T) {
+	f := newOverridesFixture(t)
+
+	f.File("Tiltfile", `fail('oops')`)
+	f.File("Tiltfile.local", `print('local')`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Equal(t, "", f.PrintOutput())
+}
+
+func TestOverridesSeeMainGlobalsOnlyThroughBuiltins(t *testing.T) {
+	f := newOverridesFixture(t)
+
+	f.File("Tiltfile", `x = 1`)
+	f.File("Tiltfile.local", `print(x)`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undefined: x")
+}
+
+func newOverridesFixture(tb testing.TB) *starkit.Fixture {
+	f := starkit.NewFixture(tb, NewOverridesPlugin(), io.NewPlugin())
+	f.UseRealFS()
+	return f
+}
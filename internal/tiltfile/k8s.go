@@ -55,6 +55,9 @@ type k8sResource struct {
 
 	podReadinessMode model.PodReadinessMode
 
+	// checks on the status of deployed objects that must pass before the resource is ready
+	readinessChecks []v1alpha1.KubernetesReadinessCheck
+
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
 
 	imageMapDeps []string
@@ -87,6 +90,7 @@ type k8sResourceOptions struct {
 	objects           []string
 	manuallyGrouped   bool
 	podReadinessMode  model.PodReadinessMode
+	readinessChecks   []v1alpha1.KubernetesReadinessCheck
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
 	links             []model.Link
 	labels            map[string]string
@@ -305,6 +309,7 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 	var autoInit = value.Optional[starlark.Bool]{Value: true}
 	var labels value.LabelSet
 	var discoveryStrategy tiltfile_k8s.DiscoveryStrategy
+	var readinessChecks tiltfile_k8s.ReadinessCheckList
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"workload?", &workload,
@@ -319,6 +324,7 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		"links?", &links,
 		"labels?", &labels,
 		"discovery_strategy?", &discoveryStrategy,
+		"readiness_checks?", &readinessChecks,
 	); err != nil {
 		return nil, err
 	}
@@ -376,6 +382,7 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		objects:           objects,
 		manuallyGrouped:   manuallyGrouped,
 		podReadinessMode:  podReadinessMode.Value,
+		readinessChecks:   readinessChecks.Checks,
 		links:             links.Links,
 		labels:            labelMap,
 		discoveryStrategy: v1alpha1.KubernetesDiscoveryStrategy(discoveryStrategy),
@@ -5,7 +5,9 @@ import (
 
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -34,3 +36,55 @@ func (m *PodReadinessMode) Unpack(v starlark.Value) error {
 
 	return fmt.Errorf("Invalid value. Allowed: {%s, %s}. Got: %s", model.PodReadinessIgnore, model.PodReadinessWait, s)
 }
+
+// Deserializing readiness checks on deployed objects from starlark values.
+//
+// Each check is a dict with keys 'kind', 'condition', and optionally 'name'.
+type ReadinessCheckList struct {
+	Checks []v1alpha1.KubernetesReadinessCheck
+}
+
+func (l *ReadinessCheckList) Unpack(v starlark.Value) error {
+	vals := []starlark.Value{v}
+	if _, isDict := v.(*starlark.Dict); !isDict {
+		vals = value.ValueOrSequenceToSlice(v)
+	}
+
+	for _, val := range vals {
+		d, ok := val.(*starlark.Dict)
+		if !ok {
+			return fmt.Errorf("Want a dict or a sequence of dicts; found %v (type: %s)", val, val.Type())
+		}
+
+		var check v1alpha1.KubernetesReadinessCheck
+		for _, item := range d.Items() {
+			key, ok := value.AsString(item[0])
+			if !ok {
+				return fmt.Errorf("Readiness check keys must be strings. Got: %s", item[0].Type())
+			}
+			s, ok := value.AsString(item[1])
+			if !ok {
+				return fmt.Errorf("Readiness check %q must be a string. Got: %s", key, item[1].Type())
+			}
+			switch key {
+			case "kind":
+				check.Kind = s
+			case "name":
+				check.Name = s
+			case "condition":
+				check.Condition = s
+			default:
+				return fmt.Errorf("Invalid readiness check key %q. Allowed: {kind, name, condition}", key)
+			}
+		}
+
+		if check.Kind == "" {
+			return fmt.Errorf("Readiness check missing 'kind': %v", d)
+		}
+		if _, err := k8s.ParseReadinessCondition(check.Condition); err != nil {
+			return err
+		}
+		l.Checks = append(l.Checks, check)
+	}
+	return nil
+}
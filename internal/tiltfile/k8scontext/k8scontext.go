@@ -2,10 +2,16 @@ package k8scontext
 
 import (
 	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
 
 	"go.starlark.net/starlark"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/git"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
@@ -48,6 +54,11 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+
+	err = env.AddBuiltin("k8s_dev_namespace", e.k8sDevNamespace)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -56,9 +67,100 @@ func (e Plugin) k8sContext(thread *starlark.Thread, fn *starlark.Builtin, args s
 }
 
 func (e Plugin) k8sNamespace(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	model, err := starkit.ModelFromThread(thread)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := GetState(model)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.devNamespace.Name != "" {
+		return starlark.String(state.devNamespace.Name), nil
+	}
 	return starlark.String(e.namespace), nil
 }
 
+func (e Plugin) k8sDevNamespace(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	name := defaultDevNamespaceTemplate
+	var deleteOnDown bool
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"name?", &name,
+		"delete_on_down?", &deleteOnDown,
+	); err != nil {
+		return nil, err
+	}
+
+	ns, err := expandDevNamespace(name, starkit.AbsWorkingDir(thread))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+
+	err = starkit.SetState(thread, func(existing State) State {
+		existing.devNamespace = DevNamespace{
+			Name:         ns,
+			DeleteOnDown: deleteOnDown,
+		}
+		return existing
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return starlark.String(ns), nil
+}
+
+const defaultDevNamespaceTemplate = "tilt-{user}"
+
+var invalidNamespaceChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// Fills in the {user} and {branch} placeholders of a namespace template,
+// and cleans up the result so that it's a valid namespace name.
+func expandDevNamespace(template string, dir string) (k8s.Namespace, error) {
+	result := template
+	if strings.Contains(result, "{user}") {
+		username := currentUsername()
+		if username == "" {
+			return "", fmt.Errorf("could not determine the current user for {user}")
+		}
+		result = strings.ReplaceAll(result, "{user}", username)
+	}
+
+	if strings.Contains(result, "{branch}") {
+		branch := git.CurrentBranch(dir)
+		if branch == "" {
+			return "", fmt.Errorf("could not determine the git branch for {branch}. Is %s in a git repo with a branch checked out?", dir)
+		}
+		result = strings.ReplaceAll(result, "{branch}", branch)
+	}
+
+	result = invalidNamespaceChars.ReplaceAllString(strings.ToLower(result), "-")
+	if len(result) > validation.DNS1123LabelMaxLength {
+		result = result[:validation.DNS1123LabelMaxLength]
+	}
+	result = strings.Trim(result, "-")
+
+	if errs := validation.IsDNS1123Label(result); len(errs) > 0 {
+		return "", fmt.Errorf("invalid namespace %q from template %q: %s", result, template, strings.Join(errs, "; "))
+	}
+	return k8s.Namespace(result), nil
+}
+
+func currentUsername() string {
+	username := os.Getenv("USER")
+	if username != "" {
+		return username
+	}
+
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}
+
 func (e Plugin) allowK8sContexts(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var contexts starlark.Value
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
@@ -79,11 +181,8 @@ func (e Plugin) allowK8sContexts(thread *starlark.Thread, fn *starlark.Builtin,
 	}
 
 	err := starkit.SetState(thread, func(existing State) State {
-		return State{
-			context: existing.context,
-			env:     existing.env,
-			allowed: append(newContexts, existing.allowed...),
-		}
+		existing.allowed = append(newContexts, existing.allowed...)
+		return existing
 	})
 
 	return starlark.None, err
@@ -91,16 +190,30 @@ func (e Plugin) allowK8sContexts(thread *starlark.Thread, fn *starlark.Builtin,
 
 var _ starkit.StatefulPlugin = &Plugin{}
 
+// A namespace that Tilt creates for the current developer,
+// declared with k8s_dev_namespace().
+type DevNamespace struct {
+	Name k8s.Namespace
+
+	// Whether `tilt down` should delete the namespace.
+	DeleteOnDown bool
+}
+
 type State struct {
-	context k8s.KubeContext
-	env     clusterid.Product
-	allowed []k8s.KubeContext
+	context      k8s.KubeContext
+	env          clusterid.Product
+	allowed      []k8s.KubeContext
+	devNamespace DevNamespace
 }
 
 func (s State) KubeContext() k8s.KubeContext {
 	return s.context
 }
 
+func (s State) DevNamespace() DevNamespace {
+	return s.devNamespace
+}
+
 // Returns whether we're allowed to deploy to this kubecontext.
 //
 // Checks against a manually specified list and a baked-in list
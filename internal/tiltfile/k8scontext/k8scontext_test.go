@@ -1,9 +1,11 @@
 package k8scontext
 
 import (
+	"os/exec"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/clusterid"
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -60,6 +62,58 @@ func TestForbidK8sContext(t *testing.T) {
 	assert.True(t, MustState(model).IsAllowed(f.Tiltfile()))
 }
 
+func TestK8sDevNamespace(t *testing.T) {
+	t.Setenv("USER", "Alice.Smith")
+	f := NewFixture(t, "gke-blorg", "default", clusterid.ProductGKE)
+	require.NoError(t, exec.Command("git", "init", f.Path()).Run())
+	require.NoError(t, exec.Command("git", "-C", f.Path(), "checkout", "-b", "feature/Login_Page").Run())
+
+	f.File("Tiltfile", `
+print(k8s_dev_namespace(name='{user}-{branch}', delete_on_down=True))
+print(k8s_namespace())
+`)
+	model, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	assert.Equal(t, "alice-smith-feature-login-page\nalice-smith-feature-login-page\n", f.PrintOutput())
+	assert.Equal(t, DevNamespace{Name: "alice-smith-feature-login-page", DeleteOnDown: true},
+		MustState(model).DevNamespace())
+}
+
+func TestK8sDevNamespaceDefault(t *testing.T) {
+	t.Setenv("USER", "bob")
+	f := NewFixture(t, "gke-blorg", "default", clusterid.ProductGKE)
+	f.File("Tiltfile", `
+allow_k8s_contexts('gke-blorg')
+k8s_dev_namespace()
+`)
+	model, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	assert.Equal(t, DevNamespace{Name: "tilt-bob"}, MustState(model).DevNamespace())
+	assert.True(t, MustState(model).IsAllowed(f.Tiltfile()))
+}
+
+func TestK8sDevNamespaceNoBranch(t *testing.T) {
+	f := NewFixture(t, "gke-blorg", "default", clusterid.ProductGKE)
+	f.File("Tiltfile", `
+k8s_dev_namespace(name='dev-{branch}')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not determine the git branch for {branch}")
+}
+
+func TestK8sDevNamespaceInvalid(t *testing.T) {
+	f := NewFixture(t, "gke-blorg", "default", clusterid.ProductGKE)
+	f.File("Tiltfile", `
+k8s_dev_namespace(name='___')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid namespace "" from template "___"`)
+}
+
 func NewFixture(tb testing.TB, ctx k8s.KubeContext, ns k8s.Namespace, env clusterid.Product) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewPlugin(ctx, ns, env))
 }
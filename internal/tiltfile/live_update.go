@@ -10,6 +10,8 @@ import (
 
 	"go.starlark.net/starlark"
 
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/syncagent"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -80,6 +82,28 @@ func (l liveUpdateSyncStep) Hash() (uint32, error) {
 func (l liveUpdateSyncStep) liveUpdateStep()        {}
 func (l liveUpdateSyncStep) declarationPos() string { return l.position.String() }
 
+type liveUpdateSyncBackStep struct {
+	remotePath, localPath string
+	position              syntax.Position
+}
+
+var _ starlark.Value = liveUpdateSyncBackStep{}
+var _ liveUpdateStep = liveUpdateSyncBackStep{}
+
+func (l liveUpdateSyncBackStep) String() string {
+	return fmt.Sprintf("sync_back step: '%s'->'%s'", l.remotePath, l.localPath)
+}
+func (l liveUpdateSyncBackStep) Type() string { return "live_update_sync_back_step" }
+func (l liveUpdateSyncBackStep) Freeze()      {}
+func (l liveUpdateSyncBackStep) Truth() starlark.Bool {
+	return len(l.remotePath) > 0 || len(l.localPath) > 0
+}
+func (l liveUpdateSyncBackStep) Hash() (uint32, error) {
+	return starlark.Tuple{starlark.String(l.remotePath), starlark.String(l.localPath)}.Hash()
+}
+func (l liveUpdateSyncBackStep) liveUpdateStep()        {}
+func (l liveUpdateSyncBackStep) declarationPos() string { return l.position.String() }
+
 type liveUpdateRunStep struct {
 	command  model.Cmd
 	triggers []string
@@ -146,6 +170,29 @@ func (l liveUpdateInitialSyncStep) Hash() (uint32, error)  { return 0, nil }
 func (l liveUpdateInitialSyncStep) declarationPos() string { return l.position.String() }
 func (l liveUpdateInitialSyncStep) liveUpdateStep()        {}
 
+type liveUpdateSyncAgentStep struct {
+	port     int
+	image    string
+	position syntax.Position
+}
+
+var _ starlark.Value = liveUpdateSyncAgentStep{}
+var _ liveUpdateStep = liveUpdateSyncAgentStep{}
+
+func (l liveUpdateSyncAgentStep) String() string {
+	return fmt.Sprintf("sync_agent step: port=%d image=%s", l.port, l.image)
+}
+func (l liveUpdateSyncAgentStep) Type() string { return "live_update_sync_agent_step" }
+func (l liveUpdateSyncAgentStep) Freeze()      {}
+func (l liveUpdateSyncAgentStep) Truth() starlark.Bool {
+	return true
+}
+func (l liveUpdateSyncAgentStep) Hash() (uint32, error) {
+	return starlark.Tuple{starlark.MakeInt(l.port), starlark.String(l.image)}.Hash()
+}
+func (l liveUpdateSyncAgentStep) declarationPos() string { return l.position.String() }
+func (l liveUpdateSyncAgentStep) liveUpdateStep()        {}
+
 func (s *tiltfileState) recordLiveUpdateStep(step liveUpdateStep) {
 	s.unconsumedLiveUpdateSteps[step.declarationPos()] = step
 }
@@ -163,6 +210,29 @@ func (s *tiltfileState) liveUpdateInitialSync(thread *starlark.Thread, fn *starl
 	return ret, nil
 }
 
+// syncAgent creates a live update step that applies updates through a sync agent in the container.
+func (s *tiltfileState) liveUpdateSyncAgent(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	port := syncagent.DefaultPort
+	image := syncagent.DefaultImage
+	if err := s.unpackArgs(fn.Name(), args, kwargs, "port?", &port, "image?", &image); err != nil {
+		return nil, err
+	}
+	if port < 1 || port > 65535 {
+		return nil, fmt.Errorf("%s: port must be between 1 and 65535, got %d", fn.Name(), port)
+	}
+	if _, err := container.ParseNamed(image); err != nil {
+		return nil, fmt.Errorf("%s: invalid image %q: %v", fn.Name(), image, err)
+	}
+
+	ret := liveUpdateSyncAgentStep{
+		port:     port,
+		image:    image,
+		position: thread.CallFrame(1).Pos,
+	}
+	s.recordLiveUpdateStep(ret)
+	return ret, nil
+}
+
 func (s *tiltfileState) liveUpdateFallBackOn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	files := value.NewLocalPathListUnpacker(thread)
 	if err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &files); err != nil {
@@ -192,6 +262,21 @@ func (s *tiltfileState) liveUpdateSync(thread *starlark.Thread, fn *starlark.Bui
 	return ret, nil
 }
 
+func (s *tiltfileState) liveUpdateSyncBack(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var remotePath, localPath string
+	if err := s.unpackArgs(fn.Name(), args, kwargs, "remote_path", &remotePath, "local_path", &localPath); err != nil {
+		return nil, err
+	}
+
+	ret := liveUpdateSyncBackStep{
+		remotePath: remotePath,
+		localPath:  starkit.AbsPath(thread, localPath),
+		position:   thread.CallFrame(1).Pos,
+	}
+	s.recordLiveUpdateStep(ret)
+	return ret, nil
+}
+
 func (s *tiltfileState) liveUpdateRun(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	var commandVal starlark.Value
 	var triggers starlark.Value
@@ -275,6 +360,13 @@ func (s *tiltfileState) liveUpdateFromSteps(t *starlark.Thread, maybeSteps starl
 
 			spec.InitialSync = &v1alpha1.LiveUpdateInitialSync{}
 
+		case liveUpdateSyncAgentStep:
+			if spec.SyncAgent != nil || noMoreFallbacks {
+				return v1alpha1.LiveUpdateSpec{}, fmt.Errorf("sync_agent must appear at most once, before any sync, run, or restart_container steps")
+			}
+
+			spec.SyncAgent = &v1alpha1.LiveUpdateSyncAgent{Port: int32(x.port), Image: x.image}
+
 		case liveUpdateFallBackOnStep:
 			seenInitialSync = true
 			if noMoreFallbacks {
@@ -313,6 +405,20 @@ func (s *tiltfileState) liveUpdateFromSteps(t *starlark.Thread, maybeSteps starl
 				ContainerPath: x.remotePath,
 			})
 
+		case liveUpdateSyncBackStep:
+			// sync_back runs after the rest of the update, wherever it appears in the list.
+			localPath := x.localPath
+			if filepath.IsAbs(localPath) {
+				localPath, err = filepath.Rel(basePath, x.localPath)
+				if err != nil {
+					return v1alpha1.LiveUpdateSpec{}, err
+				}
+			}
+			spec.SyncBacks = append(spec.SyncBacks, v1alpha1.LiveUpdateSyncBack{
+				ContainerPath: x.remotePath,
+				LocalPath:     localPath,
+			})
+
 		case liveUpdateRunStep:
 			if noMoreRuns {
 				return v1alpha1.LiveUpdateSpec{}, fmt.Errorf("restart container is only valid as the last step")
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/tilt-dev/tilt/internal/syncagent"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -617,3 +618,151 @@ k8s_custom_deploy('foo', 'apply', 'delete', deps=['foo'], container_selector='fo
 	m := f.assertNextManifest("foo", cb(image("k8s_custom_deploy:foo"), lu))
 	assert.True(t, m.ImageTargets[0].IsLiveUpdateOnly)
 }
+
+func TestLiveUpdate_SyncAgent(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync_agent(),
+    sync('foo', '/app'),
+    run('go build ./...'),
+  ]
+)`)
+	f.load()
+
+	lu := v1alpha1.LiveUpdateSpec{
+		BasePath: f.Path(),
+		Syncs: []v1alpha1.LiveUpdateSync{
+			{LocalPath: "foo", ContainerPath: "/app"},
+		},
+		Execs: []v1alpha1.LiveUpdateExec{
+			{Args: []string{"sh", "-c", "go build ./..."}},
+		},
+		SyncAgent: &v1alpha1.LiveUpdateSyncAgent{Port: 8473, Image: syncagent.DefaultImage},
+	}
+
+	m := f.assertNextManifest("foo", db(image("gcr.io/foo"), lu))
+	assert.Equal(t, lu.SyncAgent, m.ImageTargets[0].ImageMapSpec.SyncAgent)
+}
+
+func TestLiveUpdate_SyncAgentPort(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    initial_sync(),
+    sync_agent(port=9000, image='my-registry/tilt-sync-agent:dev'),
+    sync('foo', '/app'),
+  ]
+)`)
+	f.load()
+
+	lu := v1alpha1.LiveUpdateSpec{
+		BasePath: f.Path(),
+		Syncs: []v1alpha1.LiveUpdateSync{
+			{LocalPath: "foo", ContainerPath: "/app"},
+		},
+		InitialSync: &v1alpha1.LiveUpdateInitialSync{},
+		SyncAgent:   &v1alpha1.LiveUpdateSyncAgent{Port: 9000, Image: "my-registry/tilt-sync-agent:dev"},
+	}
+
+	f.assertNextManifest("foo", db(image("gcr.io/foo"), lu))
+}
+
+func TestLiveUpdate_SyncAgentMustPrecedeSyncs(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/app'),
+    sync_agent(),
+  ]
+)`)
+	f.loadErrString("sync_agent must appear at most once, before any sync, run, or restart_container steps")
+}
+
+func TestLiveUpdate_SyncAgentInvalidPort(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync_agent(port=0),
+    sync('foo', '/app'),
+  ]
+)`)
+	f.loadErrString("sync_agent: port must be between 1 and 65535, got 0")
+}
+
+func TestLiveUpdate_SyncAgentInvalidImage(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync_agent(image='Not A Ref'),
+    sync('foo', '/app'),
+  ]
+)`)
+	f.loadErrString(`sync_agent: invalid image "Not A Ref"`)
+}
+
+func TestLiveUpdate_SyncBack(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/app'),
+    run('make generate'),
+    sync_back('/app/gen', 'foo/gen'),
+  ]
+)`)
+	f.load()
+
+	lu := v1alpha1.LiveUpdateSpec{
+		BasePath: f.Path(),
+		Syncs: []v1alpha1.LiveUpdateSync{
+			{LocalPath: "foo", ContainerPath: "/app"},
+		},
+		Execs: []v1alpha1.LiveUpdateExec{
+			{Args: []string{"sh", "-c", "make generate"}},
+		},
+		SyncBacks: []v1alpha1.LiveUpdateSyncBack{
+			{ContainerPath: "/app/gen", LocalPath: filepath.Join("foo", "gen")},
+		},
+	}
+
+	f.assertNextManifest("foo", db(image("gcr.io/foo"), lu))
+}
+
+func TestLiveUpdate_SyncBackRelativeContainerPath(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/app'),
+    sync_back('gen', 'foo/gen'),
+  ]
+)`)
+	f.loadErrString("sync_back source is not absolute")
+}
@@ -0,0 +1,13 @@
+package tiltfile
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tilt-dev/tilt/internal/kustomize"
+)
+
+func TestMain(m *testing.M) {
+	kustomize.RunRendererIfRequested()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,157 @@
+package notify
+
+import (
+	"fmt"
+	"net/url"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type Plugin struct{}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (e Plugin) NewState() interface{} {
+	return model.NotifySettings{}
+}
+
+func (Plugin) OnStart(env *starkit.Environment) error {
+	err := env.AddBuiltin("notify", addNotifier)
+	if err != nil {
+		return err
+	}
+	return env.AddBuiltin("desktop_notify", setDesktopNotify)
+}
+
+func addNotifier(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var events, resources value.StringOrStringList
+	var webhookURL string
+	var cmdVal, cmdBatVal starlark.Value
+	var headers value.StringStringMap
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"events?", &events,
+		"url?", &webhookURL,
+		"cmd?", &cmdVal,
+		"cmd_bat?", &cmdBatVal,
+		"headers?", &headers,
+		"resources?", &resources)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	notifier := model.Notifier{
+		Headers: headers.AsMap(),
+	}
+
+	if len(events.Values) == 0 {
+		notifier.Events = append(notifier.Events, model.AllNotifyEvents...)
+	}
+	for _, e := range events.Values {
+		event := model.NotifyEvent(e)
+		if !isValidEvent(event) {
+			return starlark.None, fmt.Errorf("%s: invalid event %q. Must be one of: %q, %q, %q",
+				fn.Name(), e, model.NotifyEventError, model.NotifyEventRecovered, model.NotifyEventAllReady)
+		}
+		notifier.Events = append(notifier.Events, event)
+	}
+
+	for _, r := range resources.Values {
+		notifier.Resources = append(notifier.Resources, model.ManifestName(r))
+	}
+
+	if cmdVal != nil || cmdBatVal != nil {
+		notifier.Cmd, err = value.ValueGroupToCmdHelper(thread, cmdVal, cmdBatVal, nil, nil)
+		if err != nil {
+			return starlark.None, fmt.Errorf("%s: %v", fn.Name(), err)
+		}
+	}
+
+	if webhookURL != "" {
+		u, err := url.Parse(webhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return starlark.None, fmt.Errorf("%s: invalid url %q. Must be an http:// or https:// URL", fn.Name(), webhookURL)
+		}
+		notifier.URL = webhookURL
+	}
+
+	if notifier.URL == "" && notifier.Cmd.Empty() {
+		return starlark.None, fmt.Errorf("%s: must specify a url or a cmd", fn.Name())
+	}
+	if notifier.URL != "" && !notifier.Cmd.Empty() {
+		return starlark.None, fmt.Errorf("%s: cannot specify both a url and a cmd", fn.Name())
+	}
+	if len(notifier.Headers) > 0 && notifier.URL == "" {
+		return starlark.None, fmt.Errorf("%s: headers are only supported with a url", fn.Name())
+	}
+
+	err = starkit.SetState(thread, func(settings model.NotifySettings) (model.NotifySettings, error) {
+		settings.Notifiers = append(settings.Notifiers, notifier)
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
+
+func setDesktopNotify(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	enabled := true
+	var cmdVal, cmdBatVal starlark.Value
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"enabled?", &enabled,
+		"fallback_cmd?", &cmdVal,
+		"fallback_cmd_bat?", &cmdBatVal)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	desktop := model.DesktopNotifySettings{Enabled: enabled}
+	if cmdVal != nil || cmdBatVal != nil {
+		desktop.FallbackCmd, err = value.ValueGroupToCmdHelper(thread, cmdVal, cmdBatVal, nil, nil)
+		if err != nil {
+			return starlark.None, fmt.Errorf("%s: %v", fn.Name(), err)
+		}
+	}
+
+	err = starkit.SetState(thread, func(settings model.NotifySettings) (model.NotifySettings, error) {
+		settings.Desktop = desktop
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
+
+func isValidEvent(e model.NotifyEvent) bool {
+	for _, valid := range model.AllNotifyEvents {
+		if e == valid {
+			return true
+		}
+	}
+	return false
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) model.NotifySettings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.NotifySettings, error) {
+	var state model.NotifySettings
+	err := m.Load(&state)
+	return state, err
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestNotifyURL(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+notify(url='https://hooks.slack.com/services/xyz', headers={'Authorization': 'Bearer abc'})
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	assert.Equal(t, model.NotifySettings{
+		Notifiers: []model.Notifier{
+			{
+				Events:  model.AllNotifyEvents,
+				URL:     "https://hooks.slack.com/services/xyz",
+				Headers: map[string]string{"Authorization": "Bearer abc"},
+			},
+		},
+	}, MustState(result))
+}
+
+func TestNotifyCmd(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+notify(events=['error', 'recovered'], cmd='notify-send "$TILT_NOTIFY_MESSAGE"', resources=['frontend'])
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	notifiers := MustState(result).Notifiers
+	require.Len(t, notifiers, 1)
+	assert.Equal(t, []model.NotifyEvent{model.NotifyEventError, model.NotifyEventRecovered}, notifiers[0].Events)
+	assert.Equal(t, []model.ManifestName{"frontend"}, notifiers[0].Resources)
+	assert.Equal(t, model.ToHostCmdInDir(`notify-send "$TILT_NOTIFY_MESSAGE"`, f.Path()), notifiers[0].Cmd)
+	assert.Equal(t, "", notifiers[0].URL)
+}
+
+func TestNotifyMultiple(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+notify(events='error', url='http://localhost:8080/hook')
+notify(events='all_ready', cmd=['say', 'ready'])
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	notifiers := MustState(result).Notifiers
+	require.Len(t, notifiers, 2)
+	assert.Equal(t, []model.NotifyEvent{model.NotifyEventError}, notifiers[0].Events)
+	assert.Equal(t, []model.NotifyEvent{model.NotifyEventAllReady}, notifiers[1].Events)
+	assert.Equal(t, []string{"say", "ready"}, notifiers[1].Cmd.Argv)
+}
+
+func TestNotifyInvalidEvent(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "notify(events=['broken'], url='http://localhost:8080')")
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, `notify: invalid event "broken". Must be one of: "error", "recovered", "all_ready"`)
+}
+
+func TestNotifyInvalidURL(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "notify(url='localhost:8080')")
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, `notify: invalid url "localhost:8080". Must be an http:// or https:// URL`)
+}
+
+func TestNotifyNoTarget(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "notify(events=['error'])")
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, "notify: must specify a url or a cmd")
+}
+
+func TestNotifyURLAndCmd(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "notify(url='http://localhost:8080', cmd='echo hi')")
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, "notify: cannot specify both a url and a cmd")
+}
+
+func TestNotifyHeadersWithCmd(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "notify(cmd='echo hi', headers={'X-Foo': 'bar'})")
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, "notify: headers are only supported with a url")
+}
+
+func TestDesktopNotify(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "desktop_notify()")
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	assert.Equal(t, model.DesktopNotifySettings{Enabled: true}, MustState(result).Desktop)
+}
+
+func TestDesktopNotifyFallbackCmd(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `desktop_notify(fallback_cmd=['terminal-notifier', '-title', 'Tilt'])`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	desktop := MustState(result).Desktop
+	assert.True(t, desktop.Enabled)
+	assert.Equal(t, []string{"terminal-notifier", "-title", "Tilt"}, desktop.FallbackCmd.Argv)
+}
+
+func TestDesktopNotifyDisabled(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+desktop_notify()
+desktop_notify(enabled=False)
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	assert.False(t, MustState(result).Desktop.Enabled)
+}
+
+func newFixture(tb testing.TB) *starkit.Fixture {
+	return starkit.NewFixture(tb, NewPlugin())
+}
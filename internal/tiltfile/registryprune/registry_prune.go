@@ -0,0 +1,83 @@
+package registryprune
+
+import (
+	"fmt"
+	"time"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Implements functions for dealing with Registry Prune settings.
+type Plugin struct {
+}
+
+func NewPlugin() Plugin {
+	return Plugin{}
+}
+
+func (e Plugin) NewState() interface{} {
+	return model.DefaultRegistryPruneSettings()
+}
+
+func (e Plugin) OnStart(env *starkit.Environment) error {
+	return env.AddBuiltin("registry_prune_settings", e.registryPruneSettings)
+}
+
+func (e Plugin) registryPruneSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var enable bool
+	var keepRecent starlark.Value
+	var maxAgeDays, intervalHrs int
+	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"enable?", &enable,
+		"max_age_days?", &maxAgeDays,
+		"interval_hrs?", &intervalHrs,
+		"keep_recent?", &keepRecent); err != nil {
+		return nil, err
+	}
+
+	if maxAgeDays < 0 || intervalHrs < 0 {
+		return nil, fmt.Errorf("%s: max_age_days and interval_hrs must not be negative", fn.Name())
+	}
+
+	err := starkit.SetState(thread, func(settings model.RegistryPruneSettings) (model.RegistryPruneSettings, error) {
+		settings.Enabled = enable
+		if maxAgeDays != 0 {
+			settings.MaxAge = time.Duration(maxAgeDays) * 24 * time.Hour
+		}
+		if intervalHrs != 0 {
+			settings.Interval = time.Duration(intervalHrs) * time.Hour
+		}
+		if keepRecent != nil {
+			recent, err := starlark.AsInt32(keepRecent)
+			if err != nil {
+				return settings, err
+			}
+			if recent < 0 {
+				return settings, fmt.Errorf("%s: keep_recent must not be negative", fn.Name())
+			}
+			settings.KeepRecent = recent
+		}
+		return settings, nil
+	})
+
+	return starlark.None, err
+}
+
+var _ starkit.StatefulPlugin = Plugin{}
+
+func MustState(model starkit.Model) model.RegistryPruneSettings {
+	state, err := GetState(model)
+	if err != nil {
+		panic(err)
+	}
+	return state
+}
+
+func GetState(m starkit.Model) (model.RegistryPruneSettings, error) {
+	var state model.RegistryPruneSettings
+	err := m.Load(&state)
+	return state, err
+}
@@ -0,0 +1,51 @@
+package registryprune
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestRegistryPruneDefault(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", "")
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, model.DefaultRegistryPruneSettings(), MustState(result))
+	assert.False(t, MustState(result).Enabled)
+}
+
+func TestRegistryPrune(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+registry_prune_settings(enable=True, max_age_days=3, interval_hrs=6, keep_recent=0)
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	assert.Equal(t, model.RegistryPruneSettings{
+		Enabled:    true,
+		MaxAge:     3 * 24 * time.Hour,
+		Interval:   6 * time.Hour,
+		KeepRecent: 0,
+	}, MustState(result))
+}
+
+func TestRegistryPruneNegativeKeepRecent(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+registry_prune_settings(enable=True, keep_recent=-1)
+`)
+	_, err := f.ExecFile("Tiltfile")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "keep_recent must not be negative")
+	}
+}
+
+func NewFixture(tb testing.TB) *starkit.Fixture {
+	return starkit.NewFixture(tb, NewPlugin())
+}
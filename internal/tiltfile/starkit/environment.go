@@ -204,6 +204,9 @@ func (e *Environment) start(tf *v1alpha1.Tiltfile) (Model, error) {
 
 	t := e.newThread(model)
 	_, err = e.exec(t, path)
+	if err == nil {
+		err = e.finish(t)
+	}
 	model.BuiltinCalls = e.builtinCalls
 	if errors.Is(err, ErrStopExecution) {
 		return model, nil
@@ -211,6 +214,19 @@ func (e *Environment) start(tf *v1alpha1.Tiltfile) (Model, error) {
 	return model, err
 }
 
+func (e *Environment) finish(t *starlark.Thread) error {
+	for _, ext := range e.plugins {
+		onFinishExt, ok := ext.(OnFinishPlugin)
+		if ok {
+			err := onFinishExt.OnFinish(t)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (e *Environment) load(t *starlark.Thread, path string) (starlark.StringDict, error) {
 	return e.exec(t, path)
 }
@@ -15,6 +15,14 @@ type OnExecPlugin interface {
 	OnExec(t *starlark.Thread, path string, contents []byte) error
 }
 
+type OnFinishPlugin interface {
+	Plugin
+
+	// Called after the main file finishes executing successfully.
+	// Plugins may load more files on the thread.
+	OnFinish(t *starlark.Thread) error
+}
+
 type OnBuiltinCallPlugin interface {
 	Plugin
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -24,7 +25,11 @@ func (e Plugin) NewState() interface{} {
 }
 
 func (Plugin) OnStart(env *starkit.Environment) error {
-	return env.AddBuiltin("experimental_telemetry_cmd", setTelemetryCmd)
+	err := env.AddBuiltin("experimental_telemetry_cmd", setTelemetryCmd)
+	if err != nil {
+		return err
+	}
+	return env.AddBuiltin("trace_export", setTraceExport)
 }
 
 func setTelemetryCmd(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -69,6 +74,51 @@ func setTelemetryCmd(thread *starlark.Thread, fn *starlark.Builtin, args starlar
 	return starlark.None, nil
 }
 
+func setTraceExport(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var endpoint, protocol string
+	var headers value.StringStringMap
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"endpoint", &endpoint,
+		"protocol?", &protocol,
+		"headers?", &headers)
+	if err != nil {
+		return starlark.None, err
+	}
+
+	otlpProtocol := model.OTLPProtocol(protocol)
+	switch otlpProtocol {
+	case "":
+		otlpProtocol = model.OTLPProtocolGRPC
+	case model.OTLPProtocolGRPC, model.OTLPProtocolHTTP:
+	default:
+		return starlark.None, fmt.Errorf("%s: protocol must be one of %q or %q, got %q",
+			fn.Name(), model.OTLPProtocolGRPC, model.OTLPProtocolHTTP, protocol)
+	}
+
+	err = tracer.ValidateOTLPEndpoint(endpoint)
+	if err != nil {
+		return starlark.None, fmt.Errorf("%s: %v", fn.Name(), err)
+	}
+
+	err = starkit.SetState(thread, func(settings model.TelemetrySettings) (model.TelemetrySettings, error) {
+		if !settings.OTLP.Empty() {
+			return settings, fmt.Errorf("%v called multiple times; already set to %v", fn.Name(), settings.OTLP.Endpoint)
+		}
+
+		settings.OTLP = model.OTLPSettings{
+			Endpoint: endpoint,
+			Protocol: otlpProtocol,
+			Headers:  headers.AsMap(),
+		}
+		return settings, nil
+	})
+	if err != nil {
+		return starlark.None, err
+	}
+
+	return starlark.None, nil
+}
+
 var _ starkit.StatefulPlugin = Plugin{}
 
 func MustState(model starkit.Model) model.TelemetrySettings {
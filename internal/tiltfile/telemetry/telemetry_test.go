@@ -57,6 +57,61 @@ experimental_telemetry_cmd('bar.sh')
 	assert.EqualError(t, err, "experimental_telemetry_cmd called multiple times; already set to foo.sh")
 }
 
+func TestTraceExport(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "trace_export('http://localhost:4317')")
+	result, err := f.ExecFile("Tiltfile")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.OTLPSettings{
+		Endpoint: "http://localhost:4317",
+		Protocol: model.OTLPProtocolGRPC,
+	}, MustState(result).OTLP)
+}
+
+func TestTraceExportHTTPWithHeaders(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+trace_export('https://otel.example.com', protocol='http', headers={'Authorization': 'Bearer xyz'})
+`)
+	result, err := f.ExecFile("Tiltfile")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.OTLPSettings{
+		Endpoint: "https://otel.example.com",
+		Protocol: model.OTLPProtocolHTTP,
+		Headers:  map[string]string{"Authorization": "Bearer xyz"},
+	}, MustState(result).OTLP)
+}
+
+func TestTraceExportBadProtocol(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "trace_export('http://localhost:4317', protocol='udp')")
+	_, err := f.ExecFile("Tiltfile")
+
+	assert.EqualError(t, err, `trace_export: protocol must be one of "grpc" or "http", got "udp"`)
+}
+
+func TestTraceExportBadEndpoint(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "trace_export('localhost:4317')")
+	_, err := f.ExecFile("Tiltfile")
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "must be a URL like http://localhost:4317")
+	}
+}
+
+func TestTraceExportMultiple(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+trace_export('http://localhost:4317')
+trace_export('http://localhost:4318', protocol='http')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, "trace_export called multiple times; already set to http://localhost:4317")
+}
+
 func newFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewPlugin())
 }
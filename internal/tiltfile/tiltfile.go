@@ -24,8 +24,11 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/config"
 	"github.com/tilt-dev/tilt/internal/tiltfile/dockerprune"
 	"github.com/tilt-dev/tilt/internal/tiltfile/hasher"
+	"github.com/tilt-dev/tilt/internal/tiltfile/include"
 	"github.com/tilt-dev/tilt/internal/tiltfile/io"
 	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
+	"github.com/tilt-dev/tilt/internal/tiltfile/notify"
+	"github.com/tilt-dev/tilt/internal/tiltfile/registryprune"
 	"github.com/tilt-dev/tilt/internal/tiltfile/secretsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/telemetry"
@@ -43,24 +46,31 @@ import (
 const FileName = "Tiltfile"
 
 type TiltfileLoadResult struct {
-	Manifests           []model.Manifest
-	EnabledManifests    []model.ManifestName
-	Tiltignore          model.Dockerignore
-	ConfigFiles         []string
-	FeatureFlags        map[string]bool
-	TeamID              string
-	TelemetrySettings   model.TelemetrySettings
-	Secrets             model.SecretSet
-	Error               error
-	DockerPruneSettings model.DockerPruneSettings
-	AnalyticsOpt        wmanalytics.Opt
-	VersionSettings     model.VersionSettings
-	UpdateSettings      model.UpdateSettings
-	WatchSettings       model.WatchSettings
-	DefaultRegistry     *corev1alpha1.RegistryHosting
-	ObjectSet           apiset.ObjectSet
-	Hashes              hasher.Hashes
-	CISettings          *corev1alpha1.SessionCISpec
+	Manifests             []model.Manifest
+	EnabledManifests      []model.ManifestName
+	Tiltignore            model.Dockerignore
+	ConfigFiles           []string
+	FeatureFlags          map[string]bool
+	TeamID                string
+	TelemetrySettings     model.TelemetrySettings
+	NotifySettings        model.NotifySettings
+	Secrets               model.SecretSet
+	Error                 error
+	DockerPruneSettings   model.DockerPruneSettings
+	RegistryPruneSettings model.RegistryPruneSettings
+	AnalyticsOpt          wmanalytics.Opt
+	VersionSettings       model.VersionSettings
+	UpdateSettings        model.UpdateSettings
+	WatchSettings         model.WatchSettings
+	DefaultRegistry       *corev1alpha1.RegistryHosting
+	ObjectSet             apiset.ObjectSet
+	Hashes                hasher.Hashes
+	CISettings            *corev1alpha1.SessionCISpec
+	DevNamespace          k8scontext.DevNamespace
+
+	// The per-user overrides file (e.g., Tiltfile.local) that was loaded
+	// after the main Tiltfile, if any.
+	OverridesPath string
 
 	// For diagnostic purposes only
 	BuiltinCalls []starkit.BuiltinCall `json:"-"`
@@ -204,6 +214,9 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	dps, _ := dockerprune.GetState(result)
 	tlr.DockerPruneSettings = dps
 
+	rps, _ := registryprune.GetState(result)
+	tlr.RegistryPruneSettings = rps
+
 	aSettings, _ := tiltfileanalytics.GetState(result)
 	tlr.AnalyticsOpt = aSettings.Opt
 
@@ -222,13 +235,28 @@ func (tfl tiltfileLoader) Load(ctx context.Context, tf *corev1alpha1.Tiltfile, p
 	telemetrySettings, _ := telemetry.GetState(result)
 	tlr.TelemetrySettings = telemetrySettings
 
+	notifySettings, _ := notify.GetState(result)
+	tlr.NotifySettings = notifySettings
+
+	overrides, _ := include.GetOverridesState(result)
+	tlr.OverridesPath = overrides.Path
+	if overrides.Path != "" {
+		s.logger.Infof("Applied Tiltfile overrides from: %s", overrides.Path)
+	}
+
 	us, _ := updatesettings.GetState(result)
 	tlr.UpdateSettings = us
 
 	ci, _ := cisettings.GetState(result)
 	tlr.CISettings = ci
 
+	k8sContextState, _ := k8scontext.GetState(result)
+	tlr.DevNamespace = k8sContextState.DevNamespace()
+
 	configSettings, _ := config.GetState(result)
+	if s.secretSettings.ScrubSecrets {
+		tlr.Secrets.AddAll(configSettings.Secrets())
+	}
 	if tlr.Error == nil {
 		tlr.EnabledManifests, tlr.Error = configSettings.EnabledResources(tf, manifests)
 	}
@@ -171,6 +171,7 @@ func TestDockerComposeManifest(t *testing.T) {
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		"Tiltfile.local",
 		"docker-compose.yml",
 		f.JoinPath("foo", ".dockerignore"),
 	}
@@ -195,6 +196,7 @@ func TestDockerComposeEnvFile(t *testing.T) {
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		"Tiltfile.local",
 		"local.env",
 		"docker-compose.yml",
 	}
@@ -219,6 +221,7 @@ func TestDockerComposeServiceEnvFile(t *testing.T) {
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		"Tiltfile.local",
 		"docker-compose.yml",
 		"bar.env",
 	}
@@ -267,6 +270,7 @@ func TestDockerComposeYAMLBlob(t *testing.T) {
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		"Tiltfile.local",
 		"docker-compose.yml",
 		f.JoinPath("foo", ".dockerignore"),
 	}
@@ -321,7 +325,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "Tiltfile.local", "docker-compose.yml"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -352,7 +356,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml", "baz/.dockerignore"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "Tiltfile.local", "docker-compose.yml", "baz/.dockerignore"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -385,7 +389,7 @@ networks:
 		// TODO(maia): assert m.tiltFilename
 	)
 
-	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "docker-compose.yml", "baz/.dockerignore"}
+	expectedConfFiles := []string{"Tiltfile", ".tiltignore", "Tiltfile.local", "docker-compose.yml", "baz/.dockerignore"}
 	f.assertConfigFiles(expectedConfFiles...)
 }
 
@@ -420,6 +424,7 @@ networks:
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		"Tiltfile.local",
 		"docker-compose.yml",
 		"baz/alternate-Dockerfile.dockerignore",
 	}
@@ -634,6 +639,7 @@ services:
 	expectedConfFiles := []string{
 		"Tiltfile",
 		".tiltignore",
+		"Tiltfile.local",
 		filepath.Join("foo", "docker-compose.yml"),
 		filepath.Join("foo", ".dockerignore"),
 	}
@@ -44,7 +44,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/k8scontext"
 	"github.com/tilt-dev/tilt/internal/tiltfile/loaddynamic"
 	"github.com/tilt-dev/tilt/internal/tiltfile/metrics"
+	"github.com/tilt-dev/tilt/internal/tiltfile/notify"
 	"github.com/tilt-dev/tilt/internal/tiltfile/os"
+	"github.com/tilt-dev/tilt/internal/tiltfile/registryprune"
 	"github.com/tilt-dev/tilt/internal/tiltfile/secretsettings"
 	"github.com/tilt-dev/tilt/internal/tiltfile/shlex"
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
@@ -110,6 +112,9 @@ type tiltfileState struct {
 	// ensure that any images are pushed to/pulled from this registry, rewriting names if needed
 	defaultReg *v1alpha1.RegistryHosting
 
+	// how to tag the images that Tilt builds, from image_tag_policy()
+	imageTagPolicy *v1alpha1.ImageTagPolicy
+
 	k8sKinds map[k8s.ObjectSelector]*tiltfile_k8s.KindInfo
 
 	workloadToResourceFunction workloadToResourceFunction
@@ -213,17 +218,20 @@ func (s *tiltfileState) loadManifests(tf *v1alpha1.Tiltfile) ([]model.Manifest,
 	result, err := starkit.ExecFile(tf,
 		s,
 		include.IncludeFn{},
+		include.NewOverridesPlugin(),
 		git.NewPlugin(),
 		os.NewPlugin(),
 		sys.NewPlugin(),
 		io.NewPlugin(),
 		s.k8sContextPlugin,
 		dockerprune.NewPlugin(),
+		registryprune.NewPlugin(),
 		analytics.NewPlugin(),
 		s.versionPlugin,
 		s.configPlugin,
 		starlarkstruct.NewPlugin(),
 		telemetry.NewPlugin(),
+		notify.NewPlugin(),
 		metrics.NewPlugin(),
 		updatesettings.NewPlugin(),
 		s.ciSettingsPlugin,
@@ -359,6 +367,7 @@ const (
 	dockerBuildN     = "docker_build"
 	customBuildN     = "custom_build"
 	defaultRegistryN = "default_registry"
+	imageTagPolicyN  = "image_tag_policy"
 
 	// docker compose functions
 	dockerComposeN = "docker_compose"
@@ -385,6 +394,8 @@ const (
 
 	// live update functions
 	initialSyncN      = "initial_sync"
+	syncAgentN        = "sync_agent"
+	syncBackN         = "sync_back"
 	fallBackOnN       = "fall_back_on"
 	syncN             = "sync"
 	runN              = "run"
@@ -550,6 +561,7 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{dockerBuildN, s.dockerBuild},
 		{customBuildN, s.customBuild},
 		{defaultRegistryN, s.defaultRegistry},
+		{imageTagPolicyN, s.imageTagPolicyFn},
 		{dockerComposeN, s.dockerCompose},
 		{dcResourceN, s.dcResource},
 		{k8sYamlN, s.k8sYaml},
@@ -566,6 +578,8 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{helmN, s.helm},
 		{triggerModeN, s.triggerModeFn},
 		{initialSyncN, s.liveUpdateInitialSync},
+		{syncAgentN, s.liveUpdateSyncAgent},
+		{syncBackN, s.liveUpdateSyncBack},
 		{fallBackOnN, s.liveUpdateFallBackOn},
 		{syncN, s.liveUpdateSync},
 		{runN, s.liveUpdateRun},
@@ -804,6 +818,7 @@ func (s *tiltfileState) assembleK8s() error {
 			if opts.podReadinessMode != model.PodReadinessNone {
 				r.podReadinessMode = opts.podReadinessMode
 			}
+			r.readinessChecks = append(r.readinessChecks, opts.readinessChecks...)
 			if opts.discoveryStrategy != "" {
 				r.discoveryStrategy = opts.discoveryStrategy
 			}
@@ -1172,9 +1187,12 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource, updateSettings mo
 
 func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResource, imageTargets []model.ImageTarget, updateSettings model.UpdateSettings) (model.K8sTarget, error) {
 	var kdTemplateSpec *v1alpha1.KubernetesDiscoveryTemplateSpec
-	if len(r.extraPodSelectors) != 0 {
+	if len(r.extraPodSelectors) != 0 || len(r.readinessChecks) != 0 {
 		kdTemplateSpec = &v1alpha1.KubernetesDiscoveryTemplateSpec{
-			ExtraSelectors: k8s.SetsAsLabelSelectors(r.extraPodSelectors),
+			ReadinessChecks: r.readinessChecks,
+		}
+		if len(r.extraPodSelectors) != 0 {
+			kdTemplateSpec.ExtraSelectors = k8s.SetsAsLabelSelectors(r.extraPodSelectors)
 		}
 	}
 
@@ -1185,6 +1203,7 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 		PortForwardTemplateSpec:         k8s.PortForwardTemplateSpec(s.defaultedPortForwards(r.portForwards)),
 		DiscoveryStrategy:               r.discoveryStrategy,
 		KubernetesDiscoveryTemplateSpec: kdTemplateSpec,
+		DryRunDiff:                      updateSettings.K8sDryRunDiff(),
 		PodLogStreamTemplateSpec: &v1alpha1.PodLogStreamTemplateSpec{
 			SinceTime: &sinceTime,
 			IgnoreContainers: []string{
@@ -1433,6 +1452,7 @@ func (s *tiltfileState) imgTargetsForDepsHelper(mn model.ManifestName, imageMapD
 				MatchExact:      image.configurationRef.MatchExact(),
 				OverrideCommand: overrideCommand,
 				OverrideArgs:    image.overrideArgs,
+				SyncAgent:       image.liveUpdate.SyncAgent.DeepCopy(),
 			},
 			LiveUpdateSpec: image.liveUpdate,
 		}
@@ -1463,6 +1483,9 @@ func (s *tiltfileState) imgTargetsForDepsHelper(mn model.ManifestName, imageMapD
 				ExtraTags:          image.extraTags,
 				ContextIgnores:     contextIgnores,
 				ExtraHosts:         image.extraHosts,
+				TagPolicy:          s.imageTagPolicy,
+				Attestations:       image.attestations,
+				SigningKey:         image.signingKey,
 			}
 			iTarget = iTarget.WithBuildDetails(model.DockerBuild{DockerImageSpec: spec})
 		case CustomBuild:
@@ -1474,6 +1497,7 @@ func (s *tiltfileState) imgTargetsForDepsHelper(mn model.ManifestName, imageMapD
 				Env:               image.customCommand.Env,
 				OutputTag:         image.customTag,
 				OutputsImageRefTo: image.outputsImageRefTo,
+				TagPolicy:         s.imageTagPolicy,
 			}
 			if image.skipsLocalDocker {
 				spec.OutputMode = v1alpha1.CmdImageOutputRemote
@@ -113,7 +113,7 @@ k8s_yaml('foo.yaml')
 	m := f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 
 	iTarget := m.ImageTargetAt(0)
 
@@ -140,7 +140,7 @@ k8s_yaml('foo.yaml')
 	f.assertNextManifest("foo",
 		db(image("fooimage")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
 func TestExplicitDockerfileIsConfigFile(t *testing.T) {
@@ -152,7 +152,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile='other/Dockerfile')
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
 }
 
 func TestDockerfileNone(t *testing.T) {
@@ -163,7 +163,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile=None)
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 func TestExplicitDockerfileAsLocalPath(t *testing.T) {
@@ -176,7 +176,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile=r.paths('other/Dockerfile'))
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
 }
 
 func TestExplicitDockerfileContents(t *testing.T) {
@@ -187,7 +187,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile_contents='FROM alpine')
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "foo/.dockerignore")
 	f.assertNextManifest("foo", db(image("gcr.io/foo")))
 }
 
@@ -201,7 +201,7 @@ docker_build('gcr.io/foo', 'foo', dockerfile_contents=df)
 k8s_yaml('foo.yaml')
 `)
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "other/Dockerfile", "foo/.dockerignore")
 	f.assertNextManifest("foo", db(image("gcr.io/foo")))
 }
 
@@ -514,9 +514,13 @@ k8s_yaml(yaml)
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
+// The in-process kustomize passes along its warnings about deprecated fields.
+const kustomizeCommonLabelsWarning = "kustomize: 'commonLabels' is deprecated. Please use 'labels' instead. " +
+	"Run 'kustomize edit fix' to update your Kustomization automatically."
+
 func TestKustomize(t *testing.T) {
 	f := newFixture(t)
 
@@ -531,9 +535,9 @@ docker_build("gcr.io/foo", "foo")
 k8s_yaml(kustomize("."))
 k8s_resource("the-deployment", "foo")
 `)
-	f.load()
+	f.loadAssertWarnings(kustomizeCommonLabelsWarning)
 	f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
 }
 
 func TestKustomizeFlags(t *testing.T) {
@@ -552,7 +556,7 @@ k8s_resource("the-deployment", "foo")
 `)
 	f.load()
 	f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml")
 	assert.Contains(t, f.out.String(), "kustomize build --enable-helm")
 }
 
@@ -589,6 +593,38 @@ k8s_resource("the-deployment", "foo")
 	assert.EqualValues(t, "build .", strings.Trim(string(sentinelContents), " \r\n"))
 }
 
+func TestKustomizeComponents(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("kustomization.yaml", kustomizeFileText)
+	f.file("configMap.yaml", kustomizeConfigMapText)
+	f.file("deployment.yaml", kustomizeDeploymentText)
+	f.file("service.yaml", kustomizeServiceText)
+	f.file("debug/kustomization.yaml", `apiVersion: kustomize.config.k8s.io/v1alpha1
+kind: Component
+commonLabels:
+  debug: "true"
+`)
+	f.file("Tiltfile", `
+
+docker_build("gcr.io/foo", "foo")
+k8s_yaml(kustomize(".", components=["debug"]))
+k8s_resource("the-deployment", "foo")
+`)
+	f.loadAssertWarnings(kustomizeCommonLabelsWarning)
+	m := f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
+	assert.Contains(t, m.K8sTarget().KubernetesApplySpec.YAML, `debug: "true"`)
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "kustomization.yaml", "service.yaml", "debug/kustomization.yaml")
+}
+
+func TestKustomizeComponentsWithFlags(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `kustomize(".", flags=["--enable-helm"], components=["debug"])`)
+	f.loadErrString("components, enable_helm, enable_alpha_plugins and enable_exec can't be combined with kustomize_bin or flags")
+}
+
 func TestKustomizeError(t *testing.T) {
 	f := newFixture(t)
 
@@ -610,9 +646,9 @@ docker_build("gcr.io/foo", "foo")
 k8s_yaml(kustomize("."))
 k8s_resource("the-deployment", "foo")
 `)
-	f.load()
+	f.loadAssertWarnings(kustomizeCommonLabelsWarning)
 	f.assertNextManifest("foo", deployment("the-deployment"), numEntities(2))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "Kustomization", "service.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "configMap.yaml", "deployment.yaml", "Kustomization", "service.yaml")
 }
 
 func TestDockerBuildTarget(t *testing.T) {
@@ -732,6 +768,66 @@ docker_build("gcr.io/foo", "foo", extra_tag='cherry bomb')
 	f.loadErrString("Argument extra_tag=\"cherry bomb\" not a valid image reference: invalid reference format")
 }
 
+func TestImageTagPolicy(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+image_tag_policy('{git_branch}-{git_sha_short}-{digest8}', additional_tags=['{git_branch}-latest'])
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo")
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, &v1alpha1.ImageTagPolicy{
+		Template:       "{git_branch}-{git_sha_short}-{digest8}",
+		AdditionalTags: []string{"{git_branch}-latest"},
+	}, m.ImageTargets[0].BuildDetails.(model.DockerBuild).TagPolicy)
+}
+
+func TestImageTagPolicyCustomBuild(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+image_tag_policy('{git_sha}-{digest}')
+k8s_yaml('foo.yaml')
+custom_build('gcr.io/foo', 'docker build -t $EXPECTED_REF foo', ['foo'])
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, &v1alpha1.ImageTagPolicy{Template: "{git_sha}-{digest}"},
+		m.ImageTargets[0].BuildDetails.(model.CustomBuild).TagPolicy)
+}
+
+func TestImageTagPolicyRequiresDigest(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+image_tag_policy('{git_branch}')
+`)
+	f.loadErrString(`image_tag_policy: tag template "{git_branch}" must contain {digest} or {digestN}`)
+}
+
+func TestImageTagPolicyUnknownVariable(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+image_tag_policy('{digest8}', additional_tags=['{user}-latest'])
+`)
+	f.loadErrString("image_tag_policy: additional_tags: tag template \"{user}-latest\": unknown variable {user}")
+}
+
+func TestImageTagPolicyTwice(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+image_tag_policy('{digest8}')
+image_tag_policy('{digest16}')
+`)
+	f.loadErrString("image tag policy already defined")
+}
+
 func TestDockerBuildCache(t *testing.T) {
 	f := newFixture(t)
 
@@ -1066,7 +1162,7 @@ docker_build('gcr.io/d', 'd')
 	f.assertNextManifest("c", db(image("gcr.io/c")), deployment("c"))
 	f.assertNextManifest("d", db(image("gcr.io/d")), deployment("d"))
 	f.assertNoMoreManifests() // should be no unresourced yaml remaining
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "all.yaml", "a/Dockerfile", "a/.dockerignore", "b/Dockerfile", "b/.dockerignore", "c/Dockerfile", "c/.dockerignore", "d/Dockerfile", "d/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "all.yaml", "a/Dockerfile", "a/.dockerignore", "b/Dockerfile", "b/.dockerignore", "c/Dockerfile", "c/.dockerignore", "d/Dockerfile", "d/.dockerignore")
 }
 
 func TestExpandUnresourced(t *testing.T) {
@@ -1267,7 +1363,54 @@ k8s_yaml('bar.yaml')
 	f.load("foo")
 	require.Equal(t, []model.ManifestName{"foo"}, f.loadResult.EnabledManifests)
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml")
+}
+
+func TestTiltfileOverrides(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFooAndBar()
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+
+docker_build('gcr.io/bar', 'bar')
+k8s_yaml('bar.yaml')
+`)
+	f.file("Tiltfile.local", `
+k8s_resource('foo', port_forwards=8000)
+local_resource('scratch', 'echo hi')
+config.set_enabled_resources(['foo', 'scratch'])
+update_settings(max_parallel_updates=5)
+`)
+
+	f.load()
+	require.Equal(t, []model.ManifestName{"foo", "scratch"}, f.loadResult.EnabledManifests)
+	require.Equal(t, 5, f.loadResult.UpdateSettings.MaxParallelUpdates())
+	require.Equal(t, f.JoinPath("Tiltfile.local"), f.loadResult.OverridesPath)
+
+	f.assertNextManifest("foo",
+		[]model.PortForward{{LocalPort: 8000}},
+		db(image("gcr.io/foo")),
+		deployment("foo"))
+	f.assertNextManifest("bar", db(image("gcr.io/bar")), deployment("bar"))
+	f.assertNextManifest("scratch", localTarget(updateCmd(f.Path(), "echo hi", nil)))
+}
+
+func TestTiltfileOverridesFromEnv(t *testing.T) {
+	f := newFixture(t)
+	t.Setenv("TILT_OVERRIDES", "dev.tilt")
+
+	f.file("Tiltfile", `local_resource('a', 'echo a')`)
+	f.file("Tiltfile.local", `local_resource('b', 'echo b')`)
+	f.file("dev.tilt", `local_resource('c', 'echo c')`)
+
+	f.load()
+	require.Equal(t, f.JoinPath("dev.tilt"), f.loadResult.OverridesPath)
+	f.assertNextManifest("a")
+	f.assertNextManifest("c")
+	f.assertNoMoreManifests()
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "dev.tilt")
 }
 
 func TestUncategorizedEnabledEvenIfNotSpecified(t *testing.T) {
@@ -1672,7 +1815,7 @@ if True:
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
 func TestTopLevelForLoop(t *testing.T) {
@@ -1957,6 +2100,43 @@ k8s_resource(new_name='config', objects=['config'], pod_readiness='w')
 	f.loadErrString("Invalid value. Allowed: {ignore, wait}. Got: w")
 }
 
+func TestReadinessChecks(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("db.yaml", `apiVersion: db.example.com/v1
+kind: Database
+metadata:
+  name: db
+`)
+	f.file("Tiltfile", `
+k8s_yaml('db.yaml')
+k8s_resource(new_name='db', objects=['db'],
+             readiness_checks={'kind': 'Database', 'condition': 'status.conditions[type=Ready].status == True'})
+`)
+
+	f.load("db")
+	m := f.assertNextManifest("db", podReadiness(model.PodReadinessIgnore))
+	assert.Equal(t, []v1alpha1.KubernetesReadinessCheck{
+		{Kind: "Database", Condition: "status.conditions[type=Ready].status == True"},
+	}, m.K8sTarget().KubernetesApplySpec.KubernetesDiscoveryTemplateSpec.ReadinessChecks)
+}
+
+func TestReadinessChecksInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("db.yaml", `apiVersion: db.example.com/v1
+kind: Database
+metadata:
+  name: db
+`)
+	f.file("Tiltfile", `
+k8s_yaml('db.yaml')
+k8s_resource(new_name='db', objects=['db'], readiness_checks=[{'kind': 'Database', 'condition': '== True'}])
+`)
+
+	f.loadErrString(`invalid readiness condition "== True": missing JSONPath`)
+}
+
 func TestDockerBuildMatchingTag(t *testing.T) {
 	f := newFixture(t)
 
@@ -2310,7 +2490,7 @@ func TestDir(t *testing.T) {
 
 	f.load("foo", "bar")
 	f.assertNumManifests(2)
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "config/foo.yaml", "config/bar.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "config/foo.yaml", "config/bar.yaml")
 }
 
 func TestDirRecursive(t *testing.T) {
@@ -2326,7 +2506,7 @@ for f in files:
 `)
 
 	f.load()
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo", "foo/bar", "foo/baz/qux")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo", "foo/bar", "foo/baz/qux")
 }
 
 func TestCallCounts(t *testing.T) {
@@ -2467,7 +2647,7 @@ custom_build(
 
 	f.load("foo")
 	f.assertNumManifests(1)
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "foo/.dockerignore")
 	m := f.assertNextManifest("foo",
 		cb(
 			image("gcr.io/foo"),
@@ -2495,7 +2675,7 @@ hfb = custom_build(
 
 	f.load("foo")
 	f.assertNumManifests(1)
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "foo/.dockerignore")
 	f.assertNextManifest("foo",
 		cb(
 			image("gcr.io/foo"),
@@ -3034,7 +3214,7 @@ default_registry('bar.com')
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo").withLocalRef("bar.com/gcr.io_foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 }
 
 func TestDefaultRegistryTwoImagesOnlyDifferByTag(t *testing.T) {
@@ -3064,7 +3244,7 @@ default_registry('example.com')
 	f.assertNextManifest("baz",
 		db(image("gcr.io/foo:baz").withLocalRef("example.com/gcr.io_foo")),
 		deployment("baz"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml", "baz/Dockerfile", "baz/.dockerignore", "baz.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "bar/Dockerfile", "bar/.dockerignore", "bar.yaml", "baz/Dockerfile", "baz/.dockerignore", "baz.yaml")
 }
 
 func TestDefaultRegistrySingleName(t *testing.T) {
@@ -3128,7 +3308,7 @@ k8s_yaml(str(result) + '.yaml')
 		db(image("gcr.io/foo")),
 		deployment("foo"))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "this_file_does_not_exist", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "this_file_does_not_exist", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 func TestWatchFile(t *testing.T) {
@@ -3148,7 +3328,7 @@ k8s_yaml('foo.yaml')
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "hello")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml", "hello")
 }
 
 func TestAssemblyBasic(t *testing.T) {
@@ -3167,7 +3347,7 @@ k8s_yaml('foo.yaml')
 		db(image("gcr.io/foo")),
 		deployment("foo"))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 func TestAssemblyTwoWorkloadsSameImage(t *testing.T) {
@@ -3191,7 +3371,7 @@ k8s_yaml(['foo.yaml', 'bar.yaml'])
 		db(image("gcr.io/foo")),
 		deployment("bar"))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo.yaml", "bar.yaml", "foo/Dockerfile", "foo/.dockerignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo.yaml", "bar.yaml", "foo/Dockerfile", "foo/.dockerignore")
 }
 
 // Fix a bug where a service with no selectors trivially matched all pods, so Tilt grouped
@@ -3678,7 +3858,7 @@ docker_build('gcr.io/foo', 'foo')
 	f.assertNextManifest("foo",
 		db(image("gcr.io/foo")),
 		deployment("foo"))
-	f.assertConfigFiles("Tiltfile", ".tiltignore", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local", "foo/Dockerfile", "foo/.dockerignore", "foo.yaml")
 
 }
 
@@ -4347,7 +4527,7 @@ local_resource("test", "echo hi", deps=["foo/bar", "foo/a.txt"])
 		{BasePath: f.JoinPath(".git")},
 	}, lt.GetFileWatchIgnores())
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local")
 }
 
 func TestLocalResourceOnlyServeCmd(t *testing.T) {
@@ -4362,7 +4542,7 @@ local_resource("test", serve_cmd="sleep 1000")
 	f.assertNumManifests(1)
 	f.assertNextManifest("test", localTarget(serveCmd(f.Path(), "sleep 1000", nil)))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local")
 }
 
 func TestLocalResourceUpdateAndServeCmd(t *testing.T) {
@@ -4380,7 +4560,7 @@ local_resource("test", cmd="echo hi", serve_cmd="sleep 1000")
 		serveCmd(f.Path(), "sleep 1000", nil),
 	))
 
-	f.assertConfigFiles("Tiltfile", ".tiltignore")
+	f.assertConfigFiles("Tiltfile", ".tiltignore", "Tiltfile.local")
 }
 
 func TestLocalResourceNeitherUpdateOrServeCmd(t *testing.T) {
@@ -4681,6 +4861,36 @@ secret_settings(disable_scrub=True)
 	assert.Empty(t, secrets, "expect no secrets to be collected if scrubbing secrets is disabled")
 }
 
+func TestSecretConfigSetting(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+config.define_secret_string('token')
+cfg = config.parse()
+`)
+
+	f.load("--token", "hunter2")
+
+	secrets := f.loadResult.Secrets
+	assert.Equal(t, 1, len(secrets))
+	assert.Equal(t, "token", secrets["hunter2"].Key)
+	assert.Equal(t, "[redacted secret config:token]", string(secrets["hunter2"].Replacement))
+}
+
+func TestSecretConfigSettingDisableScrub(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+config.define_secret_string('token')
+cfg = config.parse()
+secret_settings(disable_scrub=True)
+`)
+
+	f.load("--token", "hunter2")
+
+	assert.Empty(t, f.loadResult.Secrets)
+}
+
 func TestDockerPruneSettings(t *testing.T) {
 	f := newFixture(t)
 
@@ -4931,6 +5141,24 @@ func TestMaxParallelUpdates(t *testing.T) {
 	}
 }
 
+func TestMaxParallelUpdatesByLabel(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `update_settings(max_parallel_updates_by_label={'db': 1, 'web': 2})
+update_settings(max_parallel_updates_by_label={'web': 3})`)
+
+	f.load()
+	assert.Equal(t, map[string]int{"db": 1, "web": 3}, f.loadResult.UpdateSettings.MaxParallelUpdatesByLabel())
+}
+
+func TestMaxParallelUpdatesByLabelInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `update_settings(max_parallel_updates_by_label={'db': 0})`)
+
+	f.loadErrString(`max number of parallel updates for label "db" must be >= 1 (got: 0)`)
+}
+
 func TestK8sUpsertTimeout(t *testing.T) {
 	for _, tc := range []struct {
 		name                string
@@ -4992,6 +5220,57 @@ update_settings(k8s_upsert_timeout_secs=456)`)
 	assert.Equal(t, 456*time.Second, f.loadResult.UpdateSettings.K8sUpsertTimeout(), "expected vs. actual k8sUpsertTimeout")
 }
 
+func TestCacheBuildInputs(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `update_settings(cache_build_inputs=True)`)
+
+	f.load()
+	assert.True(t, f.loadResult.UpdateSettings.CacheBuildInputs())
+}
+
+func TestK8sDryRunDiff(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+update_settings(k8s_dry_run_diff=True)
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+`)
+
+	f.load()
+	assert.True(t, f.loadResult.UpdateSettings.K8sDryRunDiff())
+	m := f.assertNextManifest("foo")
+	assert.True(t, m.K8sTarget().KubernetesApplySpec.DryRunDiff)
+}
+
+func TestK8sDryRunDiffNotBool(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", "update_settings(k8s_dry_run_diff='yes')")
+	f.loadErrString("got starlark.String, want bool")
+}
+
+func TestK8sDevNamespace(t *testing.T) {
+	t.Setenv("USER", "alice")
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo")))
+	f.file("Tiltfile", `
+k8s_dev_namespace(delete_on_down=True)
+k8s_yaml('foo.yaml')
+`)
+
+	f.load()
+	assert.Equal(t, k8scontext.DevNamespace{Name: "tilt-alice", DeleteOnDown: true}, f.loadResult.DevNamespace)
+
+	// The namespace is injected when the YAML is applied, once we can ask
+	// the cluster which kinds are namespaced.
+	foo := f.assertNextManifest("foo")
+	assert.NotContains(t, foo.K8sTarget().KubernetesApplySpec.YAML, "tilt-alice")
+}
+
 // recursion is disabled by default in Starlark. Make sure we've enabled it for Tiltfiles.
 func TestRecursionEnabled(t *testing.T) {
 	f := newFixture(t)
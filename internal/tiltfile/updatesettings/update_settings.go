@@ -32,11 +32,16 @@ func (e *Plugin) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, a
 	var maxParallelUpdates, k8sUpsertTimeoutSecs starlark.Value
 	var unusedImageWarnings value.StringOrStringList
 	var k8sServerSideApply string
+	var k8sDryRunDiff, cacheBuildInputs starlark.Value
+	var maxParallelByLabel value.StringIntMap
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 		"max_parallel_updates?", &maxParallelUpdates,
 		"k8s_upsert_timeout_secs?", &k8sUpsertTimeoutSecs,
 		"suppress_unused_image_warnings?", &unusedImageWarnings,
-		"k8s_server_side_apply?", &k8sServerSideApply); err != nil {
+		"k8s_server_side_apply?", &k8sServerSideApply,
+		"k8s_dry_run_diff?", &k8sDryRunDiff,
+		"max_parallel_updates_by_label?", &maxParallelByLabel,
+		"cache_build_inputs?", &cacheBuildInputs); err != nil {
 		return nil, err
 	}
 
@@ -49,6 +54,12 @@ func (e *Plugin) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, a
 			maxParallelUpdates)
 	}
 
+	for label, n := range maxParallelByLabel.AsMap() {
+		if n < 1 {
+			return nil, fmt.Errorf("update_settings: max number of parallel updates for label %q must be >= 1 (got: %d)", label, n)
+		}
+	}
+
 	kuts, kutsPassed, err := valueToInt(k8sUpsertTimeoutSecs)
 	if err != nil {
 		return nil, errors.Wrap(err, "update_settings: for parameter \"k8s_upsert_timeout_secs\"")
@@ -62,6 +73,16 @@ func (e *Plugin) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, a
 		return nil, fmt.Errorf("update_settings: k8s_server_side_apply must be \"true\", \"false\", or \"auto\"; got %q", k8sServerSideApply)
 	}
 
+	dryRunDiff, dryRunDiffPassed, err := valueToBool(k8sDryRunDiff)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"k8s_dry_run_diff\"")
+	}
+
+	cbi, cbiPassed, err := valueToBool(cacheBuildInputs)
+	if err != nil {
+		return nil, errors.Wrap(err, "update_settings: for parameter \"cache_build_inputs\"")
+	}
+
 	err = starkit.SetState(thread, func(settings model.UpdateSettings) model.UpdateSettings {
 		if mpuPassed {
 			settings = settings.WithMaxParallelUpdates(mpu)
@@ -73,6 +94,15 @@ func (e *Plugin) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, a
 		if k8sServerSideApply != "" {
 			settings = settings.WithK8sServerSideApply(k8sServerSideApply)
 		}
+		if dryRunDiffPassed {
+			settings = settings.WithK8sDryRunDiff(dryRunDiff)
+		}
+		if cbiPassed {
+			settings = settings.WithCacheBuildInputs(cbi)
+		}
+		if len(maxParallelByLabel.AsMap()) > 0 {
+			settings = settings.WithMaxParallelUpdatesByLabel(maxParallelByLabel.AsMap())
+		}
 		return settings
 	})
 
@@ -91,6 +121,17 @@ func valueToInt(v starlark.Value) (val int, wasPassed bool, err error) {
 	}
 }
 
+func valueToBool(v starlark.Value) (val bool, wasPassed bool, err error) {
+	switch x := v.(type) {
+	case nil, starlark.NoneType:
+		return false, false, nil
+	case starlark.Bool:
+		return bool(x), true, nil
+	default:
+		return false, true, fmt.Errorf("got %T, want bool", x)
+	}
+}
+
 var _ starkit.StatefulPlugin = Plugin{}
 
 func MustState(model starkit.Model) model.UpdateSettings {
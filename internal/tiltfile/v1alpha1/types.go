@@ -367,6 +367,7 @@ func (p Plugin) kubernetesApply(t *starlark.Thread, fn *starlark.Builtin, args s
 		"delete_cmd?", &deleteCmd,
 		"cluster?", &obj.Spec.Cluster,
 		"server_side_apply?", &obj.Spec.ServerSideApply,
+		"dry_run_diff?", &obj.Spec.DryRunDiff,
 	)
 	if err != nil {
 		return nil, err
@@ -39,3 +39,37 @@ func (s *StringStringMap) Unpack(v starlark.Value) error {
 func (s *StringStringMap) AsMap() map[string]string {
 	return *s
 }
+
+type StringIntMap map[string]int
+
+var _ starlark.Unpacker = &StringIntMap{}
+
+func (s *StringIntMap) Unpack(v starlark.Value) error {
+	*s = make(map[string]int)
+	if v != nil && v != starlark.None {
+		d, ok := v.(*starlark.Dict)
+		if !ok {
+			return fmt.Errorf("expected dict, got %T", v)
+		}
+
+		for _, tuple := range d.Items() {
+			k, ok := AsString(tuple[0])
+			if !ok {
+				return fmt.Errorf("key is not a string: %T (%v)", tuple[0], tuple[0])
+			}
+
+			v, err := starlark.AsInt32(tuple[1])
+			if err != nil {
+				return fmt.Errorf("value is not an int: %T (%v)", tuple[1], tuple[1])
+			}
+
+			(*s)[k] = v
+		}
+	}
+
+	return nil
+}
+
+func (s *StringIntMap) AsMap() map[string]int {
+	return *s
+}
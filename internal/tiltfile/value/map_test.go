@@ -74,3 +74,31 @@ func TestStringStringMapUnpackClearsExistingData(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 0, len(v))
 }
+
+func TestStringIntMap(t *testing.T) {
+	sv := starlark.NewDict(2)
+	err := sv.SetKey(starlark.String("a"), starlark.MakeInt(1))
+	require.NoError(t, err)
+	err = sv.SetKey(starlark.String("b"), starlark.MakeInt(2))
+	require.NoError(t, err)
+
+	v := StringIntMap{}
+
+	err = v.Unpack(sv)
+	require.NoError(t, err)
+
+	expected := StringIntMap{"a": 1, "b": 2}
+	require.Equal(t, expected, v)
+}
+
+func TestStringIntMapValueNotInt(t *testing.T) {
+	sv := starlark.NewDict(1)
+	err := sv.SetKey(starlark.String("a"), starlark.String("b"))
+	require.NoError(t, err)
+
+	v := StringIntMap{}
+
+	err = v.Unpack(sv)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "value is not an int: starlark.String (\"b\")")
+}
@@ -1,13 +1,22 @@
 package watch
 
 import (
+	"fmt"
+
 	"go.starlark.net/starlark"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
 	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
+const (
+	modeNative = "native"
+	modePoll   = "poll"
+)
+
 type Plugin struct {
 }
 
@@ -26,12 +35,41 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 func (e Plugin) setWatchSettings(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	err := starkit.SetState(thread, func(settings model.WatchSettings) (model.WatchSettings, error) {
 		var ignores value.StringOrStringList
+		var mode string
+		var interval value.Duration
 		if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 			"ignore?", &ignores,
+			"mode?", &mode,
+			"interval?", &interval,
 		); err != nil {
 			return settings, err
 		}
 
+		switch mode {
+		case "":
+		case modeNative:
+			settings.Poll = nil
+		case modePoll:
+			if settings.Poll == nil {
+				settings.Poll = &v1alpha1.FileWatchPoll{}
+			}
+		default:
+			return settings, fmt.Errorf("%s: invalid mode %q. Must be one of: %q, %q",
+				fn.Name(), mode, modeNative, modePoll)
+		}
+
+		if !interval.IsZero() {
+			if settings.Poll == nil {
+				return settings, fmt.Errorf("%s: interval is only supported with mode=%q", fn.Name(), modePoll)
+			}
+			if interval.AsDuration() < 0 {
+				return settings, fmt.Errorf("%s: interval cannot be negative", fn.Name())
+			}
+			settings.Poll = &v1alpha1.FileWatchPoll{
+				Interval: metav1.Duration{Duration: interval.AsDuration()},
+			}
+		}
+
 		if len(ignores.Values) != 0 {
 			settings.Ignores = append(settings.Ignores, model.Dockerignore{
 				LocalPath: starkit.AbsWorkingDir(thread),
@@ -2,10 +2,13 @@ package watch
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -49,6 +52,60 @@ load('./foo/Tiltfile', 'x')
 	}, MustState(result))
 }
 
+func TestPoll(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+watch_settings(mode='poll', interval='500ms')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, model.WatchSettings{
+		Poll: &v1alpha1.FileWatchPoll{
+			Interval: metav1.Duration{Duration: 500 * time.Millisecond},
+		},
+	}, MustState(result))
+}
+
+func TestPollDefaultInterval(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+watch_settings(mode='poll')
+watch_settings(ignore=['foo'])
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Equal(t, &v1alpha1.FileWatchPoll{}, MustState(result).Poll)
+}
+
+func TestNativeMode(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+watch_settings(mode='poll')
+watch_settings(mode='native')
+`)
+	result, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+	require.Nil(t, MustState(result).Poll)
+}
+
+func TestInvalidMode(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+watch_settings(mode='fanotify')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.EqualError(t, err, `watch_settings: invalid mode "fanotify". Must be one of: "native", "poll"`)
+}
+
+func TestIntervalWithoutPoll(t *testing.T) {
+	f := NewFixture(t)
+	f.File("Tiltfile", `
+watch_settings(interval='1s')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	require.EqualError(t, err, `watch_settings: interval is only supported with mode="poll"`)
+}
+
 func NewFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewPlugin())
 }
@@ -0,0 +1,175 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/tilt-dev/wmclient/pkg/dirs"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Share tokens live in a file per Tilt API server, so that a token minted
+// for one Tilt instance isn't valid on another instance on the same machine.
+const shareTokensDir = "share_tokens"
+
+// Scope determines what a token is allowed to do.
+type Scope string
+
+const (
+	// Can view the environment, trigger updates, and modify resources.
+	ScopeFull Scope = "full"
+
+	// Can only view the environment.
+	ScopeReadOnly Scope = "read-only"
+)
+
+// Allows returns true if a token with this scope may be used
+// where the required scope is needed.
+func (s Scope) Allows(required Scope) bool {
+	return s == ScopeFull || s == required
+}
+
+// A token with a limited scope and lifetime, for sharing access to Tilt
+// with other people.
+type ScopedToken struct {
+	Token   Token     `json:"token"`
+	Scope   Scope     `json:"scope"`
+	Expires time.Time `json:"expires"`
+}
+
+func (t ScopedToken) Expired(now time.Time) bool {
+	return !t.Expires.IsZero() && !now.Before(t.Expires)
+}
+
+// ShareTokens stores scoped tokens in the Tilt dev dir.
+//
+// Tokens are minted by `tilt share`, in a different process than the Tilt
+// server that checks them, so we read the file on every lookup.
+type ShareTokens struct {
+	dir  *dirs.TiltDevDir
+	file string
+	now  func() time.Time
+	mu   sync.Mutex
+}
+
+func NewShareTokens(dir *dirs.TiltDevDir, apiServerName model.APIServerName) *ShareTokens {
+	return &ShareTokens{
+		dir:  dir,
+		file: filepath.Join(shareTokensDir, fmt.Sprintf("%s.json", apiServerName)),
+		now:  time.Now,
+	}
+}
+
+// Mint creates a new token with the given scope that expires after ttl.
+func (s *ShareTokens) Mint(scope Scope, ttl time.Duration) (ScopedToken, error) {
+	if ttl <= 0 {
+		return ScopedToken{}, fmt.Errorf("token lifetime must be positive, got %s", ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.readLocked()
+	if err != nil {
+		return ScopedToken{}, err
+	}
+
+	t := ScopedToken{
+		Token:   Token(uuid.New().String()),
+		Scope:   scope,
+		Expires: s.now().Add(ttl),
+	}
+	tokens = append(tokens, t)
+	err = s.writeLocked(tokens)
+	if err != nil {
+		return ScopedToken{}, err
+	}
+	return t, nil
+}
+
+// Revoke deletes a token before it expires.
+func (s *ShareTokens) Revoke(t Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+
+	result := tokens[:0]
+	for _, existing := range tokens {
+		if existing.Token != t {
+			result = append(result, existing)
+		}
+	}
+	return s.writeLocked(result)
+}
+
+// Lookup returns the unexpired token matching t, if any.
+//
+// A nil ShareTokens never matches.
+func (s *ShareTokens) Lookup(t Token) (ScopedToken, bool) {
+	if s == nil || t == "" {
+		return ScopedToken{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.readLocked()
+	if err != nil {
+		return ScopedToken{}, false
+	}
+
+	now := s.now()
+	for _, existing := range tokens {
+		if existing.Token == t && !existing.Expired(now) {
+			return existing, true
+		}
+	}
+	return ScopedToken{}, false
+}
+
+// Reads all unexpired tokens.
+func (s *ShareTokens) readLocked() ([]ScopedToken, error) {
+	contents, err := s.dir.ReadFile(s.file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var tokens []ScopedToken
+	err = json.Unmarshal([]byte(contents), &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", s.file, err)
+	}
+
+	now := s.now()
+	result := tokens[:0]
+	for _, t := range tokens {
+		if !t.Expired(now) {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+func (s *ShareTokens) writeLocked(tokens []ScopedToken) error {
+	if tokens == nil {
+		tokens = []ScopedToken{}
+	}
+	contents, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	return s.dir.WriteFile(s.file, string(contents))
+}
@@ -2,6 +2,7 @@ package token
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -41,3 +42,62 @@ func newFixture(t *testing.T) *fixture {
 		dir:            temp,
 	}
 }
+
+func TestShareTokens(t *testing.T) {
+	f := newFixture(t)
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tokens := NewShareTokens(f.dir, "tilt-default")
+	tokens.now = func() time.Time { return now }
+
+	readOnly, err := tokens.Mint(ScopeReadOnly, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, ScopeReadOnly, readOnly.Scope)
+
+	// Tokens minted in one process are visible in another.
+	other := NewShareTokens(f.dir, "tilt-default")
+	other.now = tokens.now
+	found, ok := other.Lookup(readOnly.Token)
+	require.True(t, ok)
+	require.Equal(t, readOnly, found)
+
+	_, ok = other.Lookup("not-a-token")
+	require.False(t, ok)
+
+	now = now.Add(2 * time.Hour)
+	_, ok = other.Lookup(readOnly.Token)
+	require.False(t, ok, "token should have expired")
+}
+
+func TestShareTokensRevoke(t *testing.T) {
+	f := newFixture(t)
+	tokens := NewShareTokens(f.dir, "tilt-default")
+
+	t1, err := tokens.Mint(ScopeReadOnly, time.Hour)
+	require.NoError(t, err)
+	t2, err := tokens.Mint(ScopeReadOnly, time.Hour)
+	require.NoError(t, err)
+
+	require.NoError(t, tokens.Revoke(t1.Token))
+	_, ok := tokens.Lookup(t1.Token)
+	require.False(t, ok)
+	_, ok = tokens.Lookup(t2.Token)
+	require.True(t, ok)
+}
+
+func TestShareTokensArePerAPIServer(t *testing.T) {
+	f := newFixture(t)
+	tokens := NewShareTokens(f.dir, "tilt-default")
+	t1, err := tokens.Mint(ScopeReadOnly, time.Hour)
+	require.NoError(t, err)
+
+	other := NewShareTokens(f.dir, "tilt-10351")
+	_, ok := other.Lookup(t1.Token)
+	require.False(t, ok, "token should only be valid on the Tilt instance it was minted for")
+}
+
+func TestScopeAllows(t *testing.T) {
+	require.True(t, ScopeFull.Allows(ScopeReadOnly))
+	require.True(t, ScopeFull.Allows(ScopeFull))
+	require.True(t, ScopeReadOnly.Allows(ScopeReadOnly))
+	require.False(t, ScopeReadOnly.Allows(ScopeFull))
+}
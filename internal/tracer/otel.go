@@ -1,16 +1,96 @@
 package tracer
 
 import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 )
 
 const tracerName = "tilt.dev/usage"
 
-func InitOpenTelemetry(exporter sdktrace.SpanExporter) trace.Tracer {
-	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
-	sp := sdktrace.NewBatchSpanProcessor(exporter)
-	tp.RegisterSpanProcessor(sp)
-	tracer := tp.Tracer(tracerName)
-	return tracer
+// The tracer for spans that only go to the OTLP collector
+// (e.g., individual build steps), not to experimental_telemetry_cmd.
+const traceExportTracerName = "tilt.dev/trace"
+
+// ProvideTracerProvider sends spans to the exporter for experimental_telemetry_cmd,
+// and to the OTLP collector configured in the Tiltfile (if any).
+//
+// Spans from the trace export tracer (e.g., individual build steps) only
+// go to the OTLP collector, so that configuring one doesn't change what
+// experimental_telemetry_cmd receives.
+func ProvideTracerProvider(exporter sdktrace.SpanExporter, otlp *OTLPExporter) trace.TracerProvider {
+	return newTracerProvider(exporter, otlp)
+}
+
+// ProvideTracer returns the tracer for spans that go to both
+// experimental_telemetry_cmd and the OTLP collector.
+func ProvideTracer(tp trace.TracerProvider) trace.Tracer {
+	return tp.Tracer(tracerName)
+}
+
+func newTracerProvider(exporter sdktrace.SpanExporter, otlp sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "tilt"))))
+	tp.RegisterSpanProcessor(scopeFilter{
+		scope: tracerName,
+		next:  sdktrace.NewBatchSpanProcessor(exporter),
+	})
+	tp.RegisterSpanProcessor(sdktrace.NewBatchSpanProcessor(otlp))
+	return tp
+}
+
+// scopeFilter only passes along spans from one tracer.
+type scopeFilter struct {
+	scope string
+	next  sdktrace.SpanProcessor
+}
+
+func (f scopeFilter) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if s.InstrumentationScope().Name == f.scope {
+		f.next.OnStart(parent, s)
+	}
+}
+
+func (f scopeFilter) OnEnd(s sdktrace.ReadOnlySpan) {
+	if s.InstrumentationScope().Name == f.scope {
+		f.next.OnEnd(s)
+	}
+}
+
+func (f scopeFilter) Shutdown(ctx context.Context) error {
+	return f.next.Shutdown(ctx)
+}
+
+func (f scopeFilter) ForceFlush(ctx context.Context) error {
+	return f.next.ForceFlush(ctx)
+}
+
+// Start starts a child of the span in ctx (e.g., the span for the current
+// update), using the same tracer provider. The span is only exported to
+// the OTLP collector.
+//
+// If ctx has no span, the new span isn't recorded.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return StartWithProvider(ctx, trace.SpanFromContext(ctx).TracerProvider(), name, attrs...)
+}
+
+// StartWithProvider starts a span from the given tracer provider, for code that
+// may run outside of any other span (e.g., a reconciler). The span is only
+// exported to the OTLP collector.
+func StartWithProvider(ctx context.Context, tp trace.TracerProvider, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tp.Tracer(traceExportTracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndWithError ends the span, recording the error (if any).
+func EndWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
@@ -0,0 +1,54 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTraceExportSpansDontReachTelemetryCmd(t *testing.T) {
+	ctx := context.Background()
+	telemetry := tracetest.NewInMemoryExporter()
+	otlp := tracetest.NewInMemoryExporter()
+	tp := newTracerProvider(telemetry, otlp)
+
+	ctx, update := tp.Tracer(tracerName).Start(ctx, "update")
+	_, step := tp.Tracer(traceExportTracerName).Start(ctx, "pipeline.step")
+	step.End()
+	update.End()
+	require.NoError(t, tp.ForceFlush(ctx))
+
+	telemetrySpans := telemetry.GetSpans()
+	require.Len(t, telemetrySpans, 1)
+	assert.Equal(t, "update", telemetrySpans[0].Name)
+
+	otlpSpans := otlp.GetSpans()
+	require.Len(t, otlpSpans, 2)
+	assert.Equal(t, "pipeline.step", otlpSpans[0].Name)
+	assert.Equal(t, otlpSpans[1].SpanContext.SpanID(), otlpSpans[0].Parent.SpanID())
+}
+
+func TestStartUsesProviderFromContext(t *testing.T) {
+	ctx := context.Background()
+	telemetry := tracetest.NewInMemoryExporter()
+	otlp := tracetest.NewInMemoryExporter()
+	tp := newTracerProvider(telemetry, otlp)
+
+	_, orphan := Start(ctx, "orphan")
+	assert.False(t, orphan.IsRecording())
+	orphan.End()
+
+	ctx, update := ProvideTracer(tp).Start(ctx, "update")
+	_, step := Start(ctx, "pipeline.step")
+	step.End()
+	update.End()
+	require.NoError(t, tp.ForceFlush(ctx))
+
+	otlpSpans := otlp.GetSpans()
+	require.Len(t, otlpSpans, 2)
+	assert.Equal(t, "pipeline.step", otlpSpans[0].Name)
+	assert.Equal(t, otlpSpans[1].SpanContext.SpanID(), otlpSpans[0].Parent.SpanID())
+}
@@ -0,0 +1,129 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The path that OTLP/HTTP collectors receive traces on.
+const otlpHTTPTracesPath = "/v1/traces"
+
+// OTLPExporter sends spans to an OpenTelemetry collector (e.g., a local Jaeger).
+//
+// The collector is configured in the Tiltfile, so it can change while Tilt is
+// running. Until a collector is configured, spans are dropped.
+type OTLPExporter struct {
+	mu       sync.Mutex
+	settings model.OTLPSettings
+	exporter sdktrace.SpanExporter
+}
+
+var _ sdktrace.SpanExporter = &OTLPExporter{}
+
+func NewOTLPExporter() *OTLPExporter {
+	return &OTLPExporter{}
+}
+
+// Configure points the exporter at a new collector, shutting down the
+// connection to the old collector (if any).
+//
+// Empty settings stop exporting.
+func (e *OTLPExporter) Configure(ctx context.Context, settings model.OTLPSettings) error {
+	e.mu.Lock()
+	if otlpSettingsEqual(e.settings, settings) {
+		e.mu.Unlock()
+		return nil
+	}
+
+	// If the new collector is invalid, we still remember its settings,
+	// so that we only report the error once.
+	var newExporter sdktrace.SpanExporter
+	var err error
+	if !settings.Empty() {
+		newExporter, err = newOTLPSpanExporter(ctx, settings)
+	}
+
+	oldExporter := e.exporter
+	e.exporter = newExporter
+	e.settings = settings
+	e.mu.Unlock()
+
+	if oldExporter != nil {
+		shutdownErr := oldExporter.Shutdown(ctx)
+		if err == nil {
+			err = shutdownErr
+		}
+	}
+	return err
+}
+
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	exporter := e.exporter
+	e.mu.Unlock()
+
+	if exporter == nil {
+		return nil
+	}
+	return exporter.ExportSpans(ctx, spans)
+}
+
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	return e.Configure(ctx, model.OTLPSettings{})
+}
+
+func otlpSettingsEqual(a, b model.OTLPSettings) bool {
+	return a.Endpoint == b.Endpoint && a.Protocol == b.Protocol && maps.Equal(a.Headers, b.Headers)
+}
+
+func newOTLPSpanExporter(ctx context.Context, settings model.OTLPSettings) (sdktrace.SpanExporter, error) {
+	err := ValidateOTLPEndpoint(settings.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	switch settings.Protocol {
+	case model.OTLPProtocolGRPC, "":
+		return otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpointURL(settings.Endpoint),
+			otlptracegrpc.WithHeaders(settings.Headers))
+	case model.OTLPProtocolHTTP:
+		return otlptracehttp.New(ctx,
+			otlptracehttp.WithEndpointURL(otlpHTTPTracesURL(settings.Endpoint)),
+			otlptracehttp.WithHeaders(settings.Headers))
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q", settings.Protocol)
+	}
+}
+
+// ValidateOTLPEndpoint checks that the endpoint is an http:// or https:// URL.
+func ValidateOTLPEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid OTLP endpoint %q: %v", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid OTLP endpoint %q: must be a URL like http://localhost:4317", endpoint)
+	}
+	return nil
+}
+
+// The OTLP/HTTP exporter uses the endpoint's path as-is, so we add the
+// standard traces path if the user only gave us the collector's address.
+func otlpHTTPTracesURL(endpoint string) string {
+	u := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(u, otlpHTTPTracesPath) {
+		u += otlpHTTPTracesPath
+	}
+	return u
+}
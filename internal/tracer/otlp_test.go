@@ -0,0 +1,172 @@
+package tracer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestOTLPExporterUnconfiguredDropsSpans(t *testing.T) {
+	e := NewOTLPExporter()
+	assert.NoError(t, e.ExportSpans(context.Background(), []sdktrace.ReadOnlySpan{sd(1)}))
+	assert.NoError(t, e.Shutdown(context.Background()))
+}
+
+func TestOTLPExporterHTTP(t *testing.T) {
+	c := newFakeCollector(t)
+	ctx := context.Background()
+	e := NewOTLPExporter()
+	defer func() { _ = e.Shutdown(ctx) }()
+
+	err := e.Configure(ctx, model.OTLPSettings{
+		Endpoint: c.server.URL,
+		Protocol: model.OTLPProtocolHTTP,
+		Headers:  map[string]string{"Authorization": "Bearer xyz"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, e.ExportSpans(ctx, []sdktrace.ReadOnlySpan{namedSpan(1, "pipeline.step")}))
+
+	req := c.lastRequest(t)
+	assert.Equal(t, "/v1/traces", req.path)
+	assert.Equal(t, "Bearer xyz", req.header.Get("Authorization"))
+	assert.Equal(t, "application/x-protobuf", req.header.Get("Content-Type"))
+	require.Len(t, req.body.ResourceSpans, 1)
+	require.Len(t, req.body.ResourceSpans[0].ScopeSpans, 1)
+	spans := req.body.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	assert.Equal(t, "pipeline.step", spans[0].Name)
+}
+
+func TestOTLPExporterReconfigure(t *testing.T) {
+	c1 := newFakeCollector(t)
+	c2 := newFakeCollector(t)
+	ctx := context.Background()
+	e := NewOTLPExporter()
+	defer func() { _ = e.Shutdown(ctx) }()
+
+	require.NoError(t, e.Configure(ctx, model.OTLPSettings{Endpoint: c1.server.URL, Protocol: model.OTLPProtocolHTTP}))
+	require.NoError(t, e.ExportSpans(ctx, []sdktrace.ReadOnlySpan{sd(1)}))
+	require.NoError(t, e.Configure(ctx, model.OTLPSettings{Endpoint: c2.server.URL, Protocol: model.OTLPProtocolHTTP}))
+	require.NoError(t, e.ExportSpans(ctx, []sdktrace.ReadOnlySpan{sd(2)}))
+
+	assert.Equal(t, 1, c1.requestCount())
+	assert.Equal(t, 1, c2.requestCount())
+
+	// Removing the collector stops exporting.
+	require.NoError(t, e.Configure(ctx, model.OTLPSettings{}))
+	require.NoError(t, e.ExportSpans(ctx, []sdktrace.ReadOnlySpan{sd(3)}))
+	assert.Equal(t, 1, c2.requestCount())
+}
+
+func TestOTLPExporterHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	e := NewOTLPExporter()
+	defer func() { _ = e.Shutdown(ctx) }()
+
+	require.NoError(t, e.Configure(ctx, model.OTLPSettings{Endpoint: server.URL, Protocol: model.OTLPProtocolHTTP}))
+	err := e.ExportSpans(ctx, []sdktrace.ReadOnlySpan{sd(1)})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "403 Forbidden")
+	}
+}
+
+func TestOTLPExporterInvalidEndpoint(t *testing.T) {
+	ctx := context.Background()
+	e := NewOTLPExporter()
+	settings := model.OTLPSettings{Endpoint: "localhost:4317", Protocol: model.OTLPProtocolGRPC}
+
+	err := e.Configure(ctx, settings)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "invalid OTLP endpoint")
+	}
+
+	// We only report the error once.
+	assert.NoError(t, e.Configure(ctx, settings))
+}
+
+func TestValidateOTLPEndpoint(t *testing.T) {
+	assert.NoError(t, ValidateOTLPEndpoint("http://localhost:4317"))
+	assert.NoError(t, ValidateOTLPEndpoint("https://otel.example.com/v1/traces"))
+	assert.Error(t, ValidateOTLPEndpoint("localhost:4317"))
+	assert.Error(t, ValidateOTLPEndpoint("grpc://localhost:4317"))
+	assert.Error(t, ValidateOTLPEndpoint("http://"))
+}
+
+type collectorRequest struct {
+	path   string
+	header http.Header
+	body   *coltracepb.ExportTraceServiceRequest
+}
+
+type fakeCollector struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	requests []collectorRequest
+}
+
+func newFakeCollector(t *testing.T) *fakeCollector {
+	c := &fakeCollector{}
+	c.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contents, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body := &coltracepb.ExportTraceServiceRequest{}
+		err = proto.Unmarshal(contents, body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		c.mu.Lock()
+		c.requests = append(c.requests, collectorRequest{path: r.URL.Path, header: r.Header, body: body})
+		c.mu.Unlock()
+	}))
+	t.Cleanup(c.server.Close)
+	return c
+}
+
+func (c *fakeCollector) requestCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.requests)
+}
+
+func (c *fakeCollector) lastRequest(t *testing.T) collectorRequest {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	require.NotEmpty(t, c.requests)
+	return c.requests[len(c.requests)-1]
+}
+
+func namedSpan(id int, name string) sdktrace.ReadOnlySpan {
+	return tracetest.SpanStub{
+		Name: name,
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1},
+			SpanID:  idFromInt(id),
+		}),
+	}.Snapshot()
+}
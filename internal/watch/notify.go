@@ -45,6 +45,23 @@ type Notify interface {
 	Errors() chan error
 }
 
+// WatchStats describes the OS resources that a Notify is using.
+type WatchStats struct {
+	// The number of OS-level watches (e.g., one inotify watch per directory on Linux).
+	Watches int
+
+	// The number of directories that are polled for changes instead of watched,
+	// because we hit the OS limit on watches.
+	PolledDirs int
+}
+
+// A Notify that can report its resource usage.
+type StatsNotify interface {
+	Notify
+
+	Stats() WatchStats
+}
+
 // When we specify directories to watch, we often want to
 // ignore some subset of the files under those directories.
 //
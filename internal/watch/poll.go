@@ -0,0 +1,88 @@
+package watch
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileState records enough about a file to tell whether it changed
+// between two scans.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	mode    fs.FileMode
+}
+
+// fileSnapshot is the state of every file in a set of directory trees, by path.
+type fileSnapshot map[string]fileState
+
+// snapshotTree walks dir and records the state of everything under it
+// (including dir itself), skipping directories for which skipDir returns true.
+//
+// Files that disappear during the walk are silently skipped.
+func snapshotTree(dir string, skipDir func(path string) (bool, error), into fileSnapshot) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() && path != dir {
+			skip, err := skipDir(path)
+			if err != nil {
+				return err
+			}
+			if skip {
+				return filepath.SkipDir
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		into[path] = fileState{modTime: info.ModTime(), size: info.Size(), mode: info.Mode()}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// changedPaths returns the paths that were created, modified, or deleted
+// between two snapshots, in sorted order.
+//
+// Directories whose only change is their modtime are not included,
+// because that's already covered by the files inside them.
+func changedPaths(old, new fileSnapshot) []string {
+	var result []string
+	for path, newState := range new {
+		oldState, ok := old[path]
+		if !ok {
+			result = append(result, path)
+			continue
+		}
+		if newState.mode.IsDir() && oldState.mode.IsDir() {
+			continue
+		}
+		if !newState.modTime.Equal(oldState.modTime) || newState.size != oldState.size || newState.mode != oldState.mode {
+			result = append(result, path)
+		}
+	}
+	for path := range old {
+		if _, ok := new[path]; !ok {
+			result = append(result, path)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
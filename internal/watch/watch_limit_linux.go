@@ -0,0 +1,17 @@
+//go:build linux
+// +build linux
+
+package watch
+
+import (
+	"errors"
+	"syscall"
+)
+
+const watchLimitHelp = "Run 'sysctl fs.inotify.max_user_watches' to check your inotify limits.\n" +
+	"To raise them, run 'sudo sysctl fs.inotify.max_user_watches=524288'"
+
+// Inotify returns ENOSPC when adding a watch would exceed fs.inotify.max_user_watches.
+func isWatchLimitError(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package watch
+
+const watchLimitHelp = ""
+
+// Only Linux has a per-user limit on watches that we know how to detect.
+func isWatchLimitError(err error) bool {
+	return false
+}
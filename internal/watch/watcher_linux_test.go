@@ -0,0 +1,165 @@
+package watch
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/fsnotify"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestWatchLimitFallsBackToPolling(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	root := f.TempDir("root")
+	f.WriteFile(f.JoinPath(root, "a", "a.txt"), "a")
+	f.WriteFile(f.JoinPath(root, "b", "b.txt"), "b")
+
+	out := bytes.NewBuffer(nil)
+	d, err := newWatcher([]string{root}, EmptyMatcher{}, logger.NewTestLogger(out))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = d.Close() })
+
+	// Only allow a watch on the root, so that its children are polled.
+	add := d.addWatch
+	d.addWatch = func(name string) error {
+		if name != root {
+			return syscall.ENOSPC
+		}
+		return add(name)
+	}
+	d.pollInterval = 10 * time.Millisecond
+
+	require.NoError(t, d.Start())
+	assert.Equal(t, WatchStats{Watches: 1, PolledDirs: 2}, d.Stats())
+	assert.Contains(t, out.String(), "fs.inotify.max_user_watches")
+
+	f.WriteFile(f.JoinPath(root, "b", "b.txt"), "changed")
+	requireEvent(t, d, f.JoinPath(root, "b", "b.txt"))
+
+	f.WriteFile(f.JoinPath(root, "a", "nested", "new.txt"), "new")
+	requireEvent(t, d, f.JoinPath(root, "a", "nested", "new.txt"))
+
+	require.NoError(t, os.Remove(f.JoinPath(root, "a", "a.txt")))
+	requireEvent(t, d, f.JoinPath(root, "a", "a.txt"))
+}
+
+func TestPollScanDoesNotBlockIsPolled(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	root := f.TempDir("root")
+	f.WriteFile(f.JoinPath(root, "a", "a.txt"), "a")
+	f.WriteFile(f.JoinPath(root, "a", "nested", "b.txt"), "b")
+
+	matcher := &blockingMatcher{scanning: make(chan struct{}), release: make(chan struct{})}
+	d, err := newWatcher([]string{root}, matcher, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = d.Close() })
+
+	add := d.addWatch
+	d.addWatch = func(name string) error {
+		if name != root {
+			return syscall.ENOSPC
+		}
+		return add(name)
+	}
+	d.pollInterval = 10 * time.Millisecond
+
+	require.NoError(t, d.Start())
+
+	// Stall the poll loop in the middle of a scan.
+	matcher.armed.Store(true)
+	select {
+	case <-matcher.scanning:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for the poll loop to scan")
+	}
+	defer close(matcher.release)
+
+	polled := make(chan bool)
+	go func() {
+		polled <- d.isPolled(f.JoinPath(root, "a", "a.txt"))
+	}()
+	select {
+	case isPolled := <-polled:
+		assert.True(t, isPolled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("isPolled blocked on the poll scan")
+	}
+}
+
+// A matcher that stalls the first directory scan after it's armed.
+type blockingMatcher struct {
+	EmptyMatcher
+	armed    atomic.Bool
+	once     sync.Once
+	scanning chan struct{}
+	release  chan struct{}
+}
+
+func (m *blockingMatcher) MatchesEntireDir(f string) (bool, error) {
+	if m.armed.Load() {
+		m.once.Do(func() {
+			close(m.scanning)
+			<-m.release
+		})
+	}
+	return false, nil
+}
+
+func TestOverflowRescan(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	root := f.TempDir("root")
+	missed := f.JoinPath(root, "missed")
+	require.NoError(t, os.MkdirAll(missed, 0755))
+
+	out := bytes.NewBuffer(nil)
+	d, err := newWatcher([]string{root}, EmptyMatcher{}, logger.NewTestLogger(out))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = d.Close() })
+
+	require.NoError(t, d.Start())
+	assert.Equal(t, 2, d.Stats().Watches)
+
+	// Simulate a dropped create event, by removing the watch on a directory.
+	require.NoError(t, d.watcher.Remove(missed))
+	d.mu.Lock()
+	delete(d.watched, missed)
+	d.numWatches--
+	d.mu.Unlock()
+
+	f.WriteFile(f.JoinPath(missed, "file.txt"), "hello")
+	d.watcher.Errors <- fsnotify.ErrEventOverflow
+
+	// The rescan finds the file we missed, and re-watches the directory.
+	requireEvent(t, d, f.JoinPath(missed, "file.txt"))
+	assert.Contains(t, out.String(), "overflowed")
+
+	f.WriteFile(f.JoinPath(missed, "file2.txt"), "hello")
+	requireEvent(t, d, f.JoinPath(missed, "file2.txt"))
+	assert.Equal(t, 2, d.Stats().Watches)
+}
+
+func requireEvent(t *testing.T, d *naiveNotify, path string) {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case e := <-d.Events():
+			if e.Path() == path {
+				return
+			}
+		case err := <-d.Errors():
+			t.Fatalf("Unexpected error: %v", err)
+		case <-timeout:
+			t.Fatalf("Timed out waiting for event on %s", path)
+		}
+	}
+}
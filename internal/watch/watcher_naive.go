@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -18,10 +20,20 @@ import (
 	"github.com/tilt-dev/tilt/pkg/logger"
 )
 
+// How often we poll directories that we couldn't watch.
+const defaultPollInterval = 2 * time.Second
+
+// When the OS event queue overflows, we rescan for files modified since
+// a little before the last event we saw, in case the clocks don't quite agree.
+const overflowRescanSlack = time.Second
+
 // A naive file watcher that uses the plain fsnotify API.
 // Used on all non-Darwin systems (including Windows & Linux).
 //
-// All OS-specific codepaths are handled by fsnotify.
+// Most OS-specific codepaths are handled by fsnotify. On top of that, we:
+//   - Rescan the watched trees if the OS event queue overflows.
+//   - Fall back to polling directories if we hit the OS limit on watches
+//     (fs.inotify.max_user_watches on Linux).
 type naiveNotify struct {
 	// Paths that we're watching that should be passed up to the caller.
 	// Note that we may have to watch ancestors of these paths
@@ -40,11 +52,38 @@ type naiveNotify struct {
 	events             chan fsnotify.Event
 	wrappedEvents      chan FileEvent
 	errors             chan error
-	numWatches         int64
+	done               chan struct{}
+	closeOnce          sync.Once
+
+	// Adds an OS-level watch. Replaced in tests to simulate OS limits.
+	addWatch     func(name string) error
+	pollInterval time.Duration
+
+	// The paths passed to the OS watcher at start, so that we can rescan them.
+	watchRoots []string
+
+	// When we last saw an event. Only accessed on the event loop.
+	lastEventTime time.Time
+
+	// Serializes scans of the polled directories, so that a scan for a newly
+	// polled path doesn't race with the poll loop's scan. Walking a tree can
+	// be slow, so we don't hold mu while we do it.
+	pollMu sync.Mutex
+
+	// mu protects everything below, which is shared with the poll loop.
+	mu            sync.Mutex
+	numWatches    int64
+	watched       map[string]bool
+	polledDirs    map[string]bool
+	pollSnapshot  fileSnapshot
+	pollWG        sync.WaitGroup
+	hitWatchLimit bool
 }
 
 func (d *naiveNotify) Start() error {
+	d.lastEventTime = time.Now()
 	if len(d.notifyList) == 0 {
+		go d.loop()
 		return nil
 	}
 
@@ -60,6 +99,7 @@ func (d *naiveNotify) Start() error {
 	if d.isWatcherRecursive {
 		pathsToWatch = dedupePathsForRecursiveWatcher(pathsToWatch)
 	}
+	d.watchRoots = pathsToWatch
 
 	for _, name := range pathsToWatch {
 		fi, err := os.Stat(name)
@@ -123,13 +163,21 @@ func (d *naiveNotify) watchRecursively(dir string) error {
 			}
 			return errors.Wrapf(err, "watcher.Add(%q)", path)
 		}
+		if d.isPolled(path) {
+			// The poller covers everything under this directory.
+			return filepath.SkipDir
+		}
 		return nil
 	})
 }
 
 func (d *naiveNotify) Close() error {
+	d.mu.Lock()
 	numberOfWatches.Add(-d.numWatches)
 	d.numWatches = 0
+	d.mu.Unlock()
+
+	d.closeOnce.Do(func() { close(d.done) })
 	return d.watcher.Close()
 }
 
@@ -141,91 +189,133 @@ func (d *naiveNotify) Errors() chan error {
 	return d.errors
 }
 
+func (d *naiveNotify) Stats() WatchStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return WatchStats{
+		Watches:    int(d.numWatches),
+		PolledDirs: len(d.polledDirs),
+	}
+}
+
 func (d *naiveNotify) loop() {
-	defer close(d.wrappedEvents)
-	for e := range d.events {
-		// The Windows fsnotify event stream sometimes gets events with empty names
-		// that are also sent to the error stream. Hmmmm...
-		if e.Name == "" {
-			continue
-		}
+	defer func() {
+		// Wait for the poller, so that it doesn't send on a closed channel.
+		d.pollWG.Wait()
+		close(d.wrappedEvents)
+		close(d.errors)
+	}()
+
+	for {
+		select {
+		case e, ok := <-d.events:
+			if !ok {
+				return
+			}
+			d.lastEventTime = time.Now()
+			d.handleEvent(e)
 
-		if e.Op&fsnotify.Create != fsnotify.Create {
-			if !d.shouldNotify(e.Name) {
-				continue
+		case err, ok := <-d.watcher.Errors:
+			if !ok {
+				return
 			}
 
-			// Don't send events for directories when the modtime is being changed.
-			//
-			// This is a bit of a hack because every OS represents modtime updates
-			// a bit differently and they don't map well to fsnotify events.
-			//
-			// On Windows, updating the modtime of a directory is a fsnotify.Write.
-			// On Linux, it's a fsnotify.Chmod.
-			isDirUpdateOnly := (e.Op == fsnotify.Write || e.Op == fsnotify.Chmod) &&
-				ospath.IsDir(e.Name)
-			if isDirUpdateOnly {
+			if err == fsnotify.ErrEventOverflow {
+				d.rescan()
 				continue
 			}
 
-			d.wrappedEvents <- FileEvent{e.Name}
-			continue
+			select {
+			case d.errors <- err:
+			case <-d.done:
+				return
+			}
 		}
+	}
+}
 
-		if d.isWatcherRecursive {
-			if !d.shouldNotify(e.Name) {
-				continue
-			}
-			d.wrappedEvents <- FileEvent{e.Name}
-			continue
+func (d *naiveNotify) handleEvent(e fsnotify.Event) {
+	// The Windows fsnotify event stream sometimes gets events with empty names
+	// that are also sent to the error stream. Hmmmm...
+	if e.Name == "" {
+		return
+	}
+
+	if e.Op&fsnotify.Create != fsnotify.Create {
+		if !d.shouldNotify(e.Name) {
+			return
 		}
 
-		// If the watcher is not recursive, we have to walk the tree
-		// and add watches manually. We fire the event while we're walking the tree.
-		// because it's a bit more elegant that way.
+		// Don't send events for directories when the modtime is being changed.
+		//
+		// This is a bit of a hack because every OS represents modtime updates
+		// a bit differently and they don't map well to fsnotify events.
 		//
-		// TODO(dbentley): if there's a delete should we call d.watcher.Remove to prevent leaking?
-		err := filepath.WalkDir(e.Name, func(path string, info fs.DirEntry, err error) error {
+		// On Windows, updating the modtime of a directory is a fsnotify.Write.
+		// On Linux, it's a fsnotify.Chmod.
+		isDirUpdateOnly := (e.Op == fsnotify.Write || e.Op == fsnotify.Chmod) &&
+			ospath.IsDir(e.Name)
+		if isDirUpdateOnly {
+			return
+		}
+
+		d.wrappedEvents <- FileEvent{e.Name}
+		return
+	}
+
+	if d.isWatcherRecursive {
+		if !d.shouldNotify(e.Name) {
+			return
+		}
+		d.wrappedEvents <- FileEvent{e.Name}
+		return
+	}
+
+	// If the watcher is not recursive, we have to walk the tree
+	// and add watches manually. We fire the event while we're walking the tree.
+	// because it's a bit more elegant that way.
+	//
+	// TODO(dbentley): if there's a delete should we call d.watcher.Remove to prevent leaking?
+	err := filepath.WalkDir(e.Name, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.shouldNotify(path) {
+			d.wrappedEvents <- FileEvent{path}
+		}
+
+		// TODO(dmiller): symlinks 😭
+
+		shouldWatch := false
+		if info.IsDir() {
+			// watch directories unless we can skip them entirely
+			shouldSkipDir, err := d.shouldSkipDir(path)
 			if err != nil {
 				return err
 			}
-
-			if d.shouldNotify(path) {
-				d.wrappedEvents <- FileEvent{path}
+			if shouldSkipDir {
+				return filepath.SkipDir
 			}
 
-			// TODO(dmiller): symlinks 😭
-
-			shouldWatch := false
-			if info.IsDir() {
-				// watch directories unless we can skip them entirely
-				shouldSkipDir, err := d.shouldSkipDir(path)
-				if err != nil {
-					return err
-				}
-				if shouldSkipDir {
-					return filepath.SkipDir
-				}
-
+			shouldWatch = true
+		} else {
+			// watch files that are explicitly named, but don't watch others
+			_, ok := d.notifyList[path]
+			if ok {
 				shouldWatch = true
-			} else {
-				// watch files that are explicitly named, but don't watch others
-				_, ok := d.notifyList[path]
-				if ok {
-					shouldWatch = true
-				}
 			}
-			if shouldWatch {
-				err := d.add(path)
-				if err != nil && !os.IsNotExist(err) {
-					d.log.Infof("Error watching path %s: %s", e.Name, err)
-				}
+		}
+		if shouldWatch && !d.isPolled(path) {
+			err := d.add(path)
+			if err != nil && !os.IsNotExist(err) {
+				d.log.Infof("Error watching path %s: %s", e.Name, err)
 			}
-			return nil
-		})
-		if err != nil && !os.IsNotExist(err) {
-			d.log.Infof("Error walking directory %s: %s", e.Name, err)
 		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		d.log.Infof("Error walking directory %s: %s", e.Name, err)
 	}
 }
 
@@ -289,16 +379,202 @@ func (d *naiveNotify) shouldSkipDir(path string) (bool, error) {
 	return true, nil
 }
 
+// add watches the given path.
+//
+// If we've hit the OS limit on watches, we poll the path instead.
 func (d *naiveNotify) add(path string) error {
-	err := d.watcher.Add(path)
+	err := d.addWatch(path)
 	if err != nil {
+		if isWatchLimitError(err) {
+			d.pollInstead(path)
+			return nil
+		}
 		return err
 	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
 	d.numWatches++
+	d.watched[path] = true
 	numberOfWatches.Add(1)
 	return nil
 }
 
+// pollInstead polls the given path (and everything under it) for changes.
+func (d *naiveNotify) pollInstead(path string) {
+	d.pollMu.Lock()
+	defer d.pollMu.Unlock()
+
+	snapshot := make(fileSnapshot)
+	err := snapshotTree(path, d.shouldSkipDir, snapshot)
+	if err != nil {
+		d.log.Debugf("Error scanning %s: %v", path, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.hitWatchLimit {
+		d.hitWatchLimit = true
+		d.log.Infof("Hit OS limits watching %s. Polling for changes instead, which may be slow.\n%s",
+			path, watchLimitHelp)
+		d.pollWG.Add(1)
+		go d.pollLoop()
+	}
+
+	d.polledDirs[path] = true
+	for p, info := range snapshot {
+		d.pollSnapshot[p] = info
+	}
+}
+
+// isPolled returns true if the path or any of its ancestors is being polled.
+func (d *naiveNotify) isPolled(path string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.polledDirs) == 0 {
+		return false
+	}
+
+	for {
+		if d.polledDirs[path] {
+			return true
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return false
+		}
+		path = parent
+	}
+}
+
+func (d *naiveNotify) pollLoop() {
+	defer d.pollWG.Done()
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+		}
+
+		for _, path := range d.poll() {
+			select {
+			case d.wrappedEvents <- FileEvent{path}:
+			case <-d.done:
+				return
+			}
+		}
+	}
+}
+
+// poll rescans the polled directories, and returns the paths that changed
+// since the last scan.
+func (d *naiveNotify) poll() []string {
+	d.pollMu.Lock()
+	defer d.pollMu.Unlock()
+
+	d.mu.Lock()
+	dirs := make([]string, 0, len(d.polledDirs))
+	for dir := range d.polledDirs {
+		dirs = append(dirs, dir)
+	}
+	size := len(d.pollSnapshot)
+	d.mu.Unlock()
+
+	snapshot := make(fileSnapshot, size)
+	for _, dir := range dirs {
+		err := snapshotTree(dir, d.shouldSkipDir, snapshot)
+		if err != nil {
+			d.log.Debugf("Error scanning %s: %v", dir, err)
+		}
+	}
+
+	d.mu.Lock()
+	old := d.pollSnapshot
+	d.pollSnapshot = snapshot
+	d.mu.Unlock()
+
+	var result []string
+	for _, path := range changedPaths(old, snapshot) {
+		if d.shouldNotify(path) {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+// rescan recovers from an overflow of the OS event queue, where we've
+// dropped an unknown number of events.
+//
+// We walk everything we're watching, add watches for any directories whose
+// creation we missed, and send events for any files modified since shortly
+// before the last event we saw. (We can't detect deletes this way,
+// but the next build will notice them.)
+func (d *naiveNotify) rescan() {
+	since := d.lastEventTime.Add(-overflowRescanSlack)
+	d.log.Infof("File event queue overflowed. Rescanning for changes")
+
+	for _, root := range d.watchRoots {
+		err := filepath.WalkDir(root, func(path string, info fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if info.IsDir() {
+				if path != root {
+					shouldSkipDir, err := d.shouldSkipDir(path)
+					if err != nil {
+						return err
+					}
+					if shouldSkipDir {
+						return filepath.SkipDir
+					}
+				}
+
+				if d.isPolled(path) {
+					// The poller will catch any changes here.
+					return filepath.SkipDir
+				}
+
+				if !d.isWatcherRecursive && !d.isWatched(path) {
+					err := d.add(path)
+					if err != nil && !os.IsNotExist(err) {
+						d.log.Infof("Error watching path %s: %s", path, err)
+					}
+				}
+				return nil
+			}
+
+			fi, err := info.Info()
+			if err != nil {
+				return nil
+			}
+			if fi.ModTime().After(since) && d.shouldNotify(path) {
+				select {
+				case d.wrappedEvents <- FileEvent{path}:
+				case <-d.done:
+					return filepath.SkipAll
+				}
+			}
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			d.log.Infof("Error rescanning %s: %s", root, err)
+		}
+	}
+	d.lastEventTime = time.Now()
+}
+
+func (d *naiveNotify) isWatched(path string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.watched[path]
+}
+
 func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNotify, error) {
 	if ignore == nil {
 		return nil, fmt.Errorf("newWatcher: ignore is nil")
@@ -338,14 +614,20 @@ func newWatcher(paths []string, ignore PathMatcher, l logger.Logger) (*naiveNoti
 		watcher:            fsw,
 		events:             fsw.Events,
 		wrappedEvents:      wrappedEvents,
-		errors:             fsw.Errors,
+		errors:             make(chan error),
+		done:               make(chan struct{}),
+		addWatch:           fsw.Add,
+		pollInterval:       defaultPollInterval,
 		isWatcherRecursive: isWatcherRecursive,
+		watched:            make(map[string]bool),
+		polledDirs:         make(map[string]bool),
+		pollSnapshot:       make(fileSnapshot),
 	}
 
 	return wmw, nil
 }
 
-var _ Notify = &naiveNotify{}
+var _ StatsNotify = &naiveNotify{}
 
 func greatestExistingAncestors(paths []string) ([]string, error) {
 	result := []string{}
@@ -0,0 +1,205 @@
+package watch
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// A watcher that scans the filesystem for changes on an interval,
+// rather than relying on OS notifications.
+//
+// Slower and more expensive than a native watcher, but works on filesystems
+// that never deliver notifications (e.g., NFS, or some container bind mounts).
+type pollNotify struct {
+	notifyList map[string]bool
+	ignore     PathMatcher
+	log        logger.Logger
+	interval   time.Duration
+
+	events    chan FileEvent
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+
+	mu       sync.Mutex
+	snapshot fileSnapshot
+}
+
+var _ StatsNotify = &pollNotify{}
+
+// NewPollWatcher creates a watcher that scans paths for changes every interval.
+//
+// If interval is zero, a default is used.
+func NewPollWatcher(paths []string, ignore PathMatcher, interval time.Duration, l logger.Logger) (Notify, error) {
+	return newPollWatcher(paths, ignore, interval, l)
+}
+
+func newPollWatcher(paths []string, ignore PathMatcher, interval time.Duration, l logger.Logger) (*pollNotify, error) {
+	if ignore == nil {
+		return nil, fmt.Errorf("newPollWatcher: ignore is nil")
+	}
+	if interval < 0 {
+		return nil, fmt.Errorf("newPollWatcher: interval cannot be negative")
+	}
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	notifyList := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "newPollWatcher")
+		}
+		notifyList[path] = true
+	}
+
+	return &pollNotify{
+		notifyList: notifyList,
+		ignore:     ignore,
+		log:        l,
+		interval:   interval,
+		events:     make(chan FileEvent),
+		errors:     make(chan error),
+		done:       make(chan struct{}),
+		snapshot:   make(fileSnapshot),
+	}, nil
+}
+
+func (d *pollNotify) Start() error {
+	snapshot, err := d.scan()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.snapshot = snapshot
+	d.mu.Unlock()
+
+	d.wg.Add(1)
+	go d.loop()
+	return nil
+}
+
+func (d *pollNotify) Close() error {
+	d.closeOnce.Do(func() {
+		close(d.done)
+	})
+	return nil
+}
+
+func (d *pollNotify) Events() chan FileEvent {
+	return d.events
+}
+
+func (d *pollNotify) Errors() chan error {
+	return d.errors
+}
+
+func (d *pollNotify) Stats() WatchStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	polledDirs := 0
+	for _, state := range d.snapshot {
+		if state.mode.IsDir() {
+			polledDirs++
+		}
+	}
+	return WatchStats{PolledDirs: polledDirs}
+}
+
+func (d *pollNotify) loop() {
+	defer func() {
+		d.wg.Done()
+		close(d.events)
+		close(d.errors)
+	}()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+		}
+
+		for _, path := range d.poll() {
+			select {
+			case d.events <- FileEvent{path}:
+			case <-d.done:
+				return
+			}
+		}
+	}
+}
+
+// poll rescans the watched paths, and returns the paths that changed
+// since the last scan.
+func (d *pollNotify) poll() []string {
+	snapshot, err := d.scan()
+	if err != nil {
+		d.log.Debugf("Error scanning for changes: %v", err)
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var result []string
+	for _, path := range changedPaths(d.snapshot, snapshot) {
+		if d.shouldNotify(path) {
+			result = append(result, path)
+		}
+	}
+	d.snapshot = snapshot
+	return result
+}
+
+func (d *pollNotify) scan() (fileSnapshot, error) {
+	snapshot := make(fileSnapshot)
+	for path := range d.notifyList {
+		err := snapshotTree(path, d.shouldSkipDir, snapshot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "scanning %s", path)
+		}
+	}
+	return snapshot, nil
+}
+
+func (d *pollNotify) shouldSkipDir(path string) (bool, error) {
+	if d.notifyList[path] {
+		return false, nil
+	}
+	return d.ignore.MatchesEntireDir(path)
+}
+
+func (d *pollNotify) shouldNotify(path string) bool {
+	ignore, err := d.ignore.Matches(path)
+	if err != nil {
+		d.log.Infof("Error matching path %q: %v", path, err)
+	} else if ignore {
+		return false
+	}
+
+	if _, ok := d.notifyList[path]; ok {
+		// We generally don't care when directories change at the root of an ADD
+		return !ospath.IsDirLstat(path)
+	}
+
+	for root := range d.notifyList {
+		if ospath.IsChild(root, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package watch
+
+import (
+	"bytes"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/dockerignore"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+func TestPollWatcher(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	root := f.TempDir("root")
+	f.WriteFile(f.JoinPath(root, "a.txt"), "a")
+	f.WriteFile(f.JoinPath(root, "sub", "b.txt"), "b")
+
+	d := newTestPollWatcher(t, []string{root}, EmptyMatcher{})
+	assert.Equal(t, WatchStats{PolledDirs: 2}, d.Stats())
+
+	f.WriteFile(f.JoinPath(root, "sub", "b.txt"), "changed")
+	assert.Equal(t, []string{f.JoinPath(root, "sub", "b.txt")}, d.poll())
+
+	f.WriteFile(f.JoinPath(root, "new", "c.txt"), "c")
+	require.NoError(t, os.Remove(f.JoinPath(root, "a.txt")))
+	assert.Equal(t, []string{
+		f.JoinPath(root, "a.txt"),
+		f.JoinPath(root, "new"),
+		f.JoinPath(root, "new", "c.txt"),
+	}, d.poll())
+}
+
+func TestPollWatcherIgnores(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	root := f.TempDir("root")
+	f.WriteFile(f.JoinPath(root, "node_modules", "a.js"), "a")
+
+	ignore, err := dockerignore.NewDockerPatternMatcher(root, []string{"node_modules", "*.log"})
+	require.NoError(t, err)
+	d := newTestPollWatcher(t, []string{root}, ignore)
+	assert.Equal(t, WatchStats{PolledDirs: 1}, d.Stats())
+
+	f.WriteFile(f.JoinPath(root, "node_modules", "a.js"), "changed")
+	f.WriteFile(f.JoinPath(root, "debug.log"), "log")
+	f.WriteFile(f.JoinPath(root, "main.go"), "main")
+	assert.Equal(t, []string{f.JoinPath(root, "main.go")}, d.poll())
+}
+
+func TestPollWatcherMissingPath(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	file := f.JoinPath("dir", "file.txt")
+
+	d := newTestPollWatcher(t, []string{file}, EmptyMatcher{})
+
+	f.WriteFile(file, "hello")
+	assert.Equal(t, []string{file}, d.poll())
+}
+
+func TestPollWatcherEvents(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	root := f.TempDir("root")
+
+	d, err := newPollWatcher([]string{root}, EmptyMatcher{}, 10*time.Millisecond, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	require.NoError(t, d.Start())
+
+	f.WriteFile(f.JoinPath(root, "a.txt"), "a")
+	select {
+	case e := <-d.Events():
+		assert.Equal(t, f.JoinPath(root, "a.txt"), e.Path())
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+
+	require.NoError(t, d.Close())
+	_, ok := <-d.Events()
+	assert.False(t, ok, "Events channel should be closed")
+}
+
+func newTestPollWatcher(t *testing.T, paths []string, ignore PathMatcher) *pollNotify {
+	// Use a long interval, and call poll() directly to scan for changes.
+	d, err := newPollWatcher(paths, ignore, time.Hour, logger.NewTestLogger(bytes.NewBuffer(nil)))
+	require.NoError(t, err)
+	require.NoError(t, d.Start())
+	t.Cleanup(func() { _ = d.Close() })
+	return d
+}
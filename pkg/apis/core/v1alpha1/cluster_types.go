@@ -101,6 +101,14 @@ type KubernetesClusterConnection struct {
 	//
 	// +optional
 	Namespace string `json:"namespace,omitempty" protobuf:"bytes,2,opt,name=namespace"`
+
+	// Whether to create the default namespace if it doesn't exist.
+	//
+	// The namespace is created when Tilt connects to the cluster,
+	// before any objects are applied.
+	//
+	// +optional
+	CreateNamespace bool `json:"createNamespace,omitempty" protobuf:"varint,3,opt,name=createNamespace"`
 }
 
 type DockerClusterConnection struct {
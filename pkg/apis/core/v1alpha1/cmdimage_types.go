@@ -79,6 +79,14 @@ type CmdImageSpec struct {
 	// +optional
 	Env []string `json:"env,omitempty" protobuf:"bytes,10,rep,name=env"`
 
+	// How to tag the built image.
+	//
+	// If not specified, the image is tagged with a prefix and the image digest.
+	// Ignored if the command writes its own image ref.
+	//
+	// +optional
+	TagPolicy *ImageTagPolicy `json:"tagPolicy,omitempty" protobuf:"bytes,11,opt,name=tagPolicy"`
+
 	// Names of image maps that this build depends on.
 	//
 	// The controller will watch all the image maps, and rebuild the image
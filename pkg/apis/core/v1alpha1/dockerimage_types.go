@@ -181,6 +181,58 @@ type DockerImageSpec struct {
 	//
 	// Equivalent to `--add-host` in the Docker CLI.
 	ExtraHosts []string `json:"extraHosts,omitempty" protobuf:"bytes,17,opt,name=extraHosts"`
+
+	// How to tag the built image.
+	//
+	// If not specified, the image is tagged with a prefix and the image digest.
+	//
+	// +optional
+	TagPolicy *ImageTagPolicy `json:"tagPolicy,omitempty" protobuf:"bytes,18,opt,name=tagPolicy"`
+
+	// Attestations to generate with the image, like an SBOM or build provenance.
+	//
+	// https://docs.docker.com/build/metadata/attestations/
+	//
+	// Equivalent to `--attest` in the Docker Buildx CLI
+	// (e.g., "type=sbom", "type=provenance,mode=max").
+	//
+	// Requires BuildKit and Docker's containerd image store. The build fails
+	// on the classic image store, which drops attestations.
+	//
+	// +optional
+	Attestations []string `json:"attestations,omitempty" protobuf:"bytes,19,rep,name=attestations"`
+
+	// A cosign key to sign the image with after it's pushed, and before it's deployed.
+	//
+	// cosign stores the signature in the registry, next to the pushed
+	// digest, so the image can't be signed before the push.
+	//
+	// May be a path to a local key, or a KMS URI that cosign understands.
+	// Requires the cosign CLI.
+	//
+	// +optional
+	SigningKey string `json:"signingKey,omitempty" protobuf:"bytes,20,opt,name=signingKey"`
+}
+
+// ImageTagPolicy describes how to tag a built image.
+//
+// Templates may reference the variables {digest} (the full image digest),
+// {digestN} (the first N characters of the digest), {git_branch}, {git_sha},
+// and {git_sha_short}. Git variables are read from the repo that
+// contains the build directory.
+type ImageTagPolicy struct {
+	// The template for the tag that Tilt deploys.
+	//
+	// Must contain {digest} or {digestN}, so that every build has a unique tag.
+	Template string `json:"template" protobuf:"bytes,1,opt,name=template"`
+
+	// Templates for extra tags to apply to the image.
+	//
+	// These tags are pushed alongside the main tag, but aren't deployed.
+	// They don't need to be unique (e.g., "{git_branch}-latest").
+	//
+	// +optional
+	AdditionalTags []string `json:"additionalTags,omitempty" protobuf:"bytes,2,rep,name=additionalTags"`
 }
 
 var _ resource.Object = &DockerImage{}
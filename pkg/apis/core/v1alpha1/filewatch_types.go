@@ -66,6 +66,25 @@ type FileWatchSpec struct {
 	//
 	// +optional
 	DisableSource *DisableSource `json:"disableSource,omitempty" protobuf:"bytes,3,opt,name=disableSource"`
+
+	// Poll makes the FileWatch scan WatchedPaths for changes periodically,
+	// rather than relying on OS file notifications.
+	//
+	// Useful on filesystems that don't deliver notifications, like network
+	// filesystems and some container bind mounts.
+	//
+	// +optional
+	Poll *FileWatchPoll `json:"poll,omitempty" protobuf:"bytes,4,opt,name=poll"`
+}
+
+// Describes how to poll for file changes.
+type FileWatchPoll struct {
+	// Interval is how often to scan for changes.
+	//
+	// If not specified, defaults to 2s.
+	//
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty" protobuf:"bytes,1,opt,name=interval"`
 }
 
 // Describes sets of file paths that the FileWatch should ignore.
@@ -135,6 +154,12 @@ func (in *FileWatch) Validate(_ context.Context) field.ErrorList {
 			field.NewPath("spec", "watchedPaths"),
 			"cannot be an empty list"))
 	}
+	if in.Spec.Poll != nil && in.Spec.Poll.Interval.Duration < 0 {
+		fieldErrors = append(fieldErrors, field.Invalid(
+			field.NewPath("spec", "poll", "interval"),
+			in.Spec.Poll.Interval.Duration.String(),
+			"cannot be negative"))
+	}
 	return fieldErrors
 }
 
@@ -163,6 +188,14 @@ type FileWatchStatus struct {
 	// Details about whether/why this is disabled.
 	// +optional
 	DisableStatus *DisableStatus `json:"disableStatus,omitempty" protobuf:"bytes,5,opt,name=disableStatus"`
+	// WatchCount is the number of OS-level watches (e.g., inotify watches on Linux) used to monitor the
+	// watched paths. OS watch limits are shared by all processes for a user, so large counts can starve other tools.
+	// +optional
+	WatchCount int32 `json:"watchCount,omitempty" protobuf:"varint,6,opt,name=watchCount"`
+	// PolledDirCount is the number of directories that are polled for changes instead of watched, because the
+	// OS limit on watches was reached. Changes in these directories may take a few seconds to be seen.
+	// +optional
+	PolledDirCount int32 `json:"polledDirCount,omitempty" protobuf:"varint,7,opt,name=polledDirCount"`
 }
 
 type FileEvent struct {
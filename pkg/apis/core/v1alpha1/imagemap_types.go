@@ -105,6 +105,16 @@ type ImageMapSpec struct {
 	//
 	// +optional
 	OverrideArgs *ImageMapOverrideArgs `json:"overrideArgs,omitempty" protobuf:"bytes,5,opt,name=overrideArgs"`
+
+	// If specified, the injector will inject a live update sync agent into
+	// containers that run the image.
+	//
+	// The injector adds an init container that copies the agent binary into an
+	// emptyDir volume, wraps the container's command with the agent, and mounts
+	// the token that the agent requires from a Secret.
+	//
+	// +optional
+	SyncAgent *LiveUpdateSyncAgent `json:"syncAgent,omitempty" protobuf:"bytes,6,opt,name=syncAgent"`
 }
 
 // ImageMapCommandOverride defines a command to inject when the image
@@ -219,6 +229,31 @@ type ImageMapStatus struct {
 	// may not be included in the image.
 	BuildStartTime *metav1.MicroTime `json:"buildStartTime,omitempty" protobuf:"bytes,2,opt,name=buildStartTime"`
 
+	// The ENTRYPOINT from the image config.
+	//
+	// Only recorded when the spec injects a sync agent, which needs to know
+	// the command to wrap if the container doesn't set one.
+	//
+	// +optional
+	Entrypoint []string `json:"entrypoint,omitempty" protobuf:"bytes,5,rep,name=entrypoint"`
+
+	// The CMD from the image config.
+	//
+	// Only recorded when the spec injects a sync agent.
+	//
+	// +optional
+	Cmd []string `json:"cmd,omitempty" protobuf:"bytes,6,rep,name=cmd"`
+
+	// A hash of everything that went into building the image
+	// (the build context, the Dockerfile, the build args, and so on).
+	//
+	// Only recorded for docker_build() images, when Tilt is saving its state
+	// across restarts or caching build inputs. On startup, Tilt skips
+	// building an image whose inputs still have this hash.
+	//
+	// +optional
+	BuildInputHash string `json:"buildInputHash,omitempty" protobuf:"bytes,7,opt,name=buildInputHash"`
+
 	// TODO(nick): I'm not totally sure how we should model registries in this system.
 	//
 	// We need to be able to support an image existing at multiple URLs in
@@ -191,6 +191,17 @@ type KubernetesApplySpec struct {
 	//
 	// +optional
 	ServerSideApply bool `json:"serverSideApply,omitempty" protobuf:"bytes,14,opt,name=serverSideApply"`
+
+	// DryRunDiff enables a server-side dry-run before each apply.
+	//
+	// When true, the controller asks the apiserver what each object would look
+	// like after the apply, compares it against the live object, and records
+	// the result in the Diff status field. The dry-run never blocks the apply.
+	//
+	// Only supported for YAML applies.
+	//
+	// +optional
+	DryRunDiff bool `json:"dryRunDiff,omitempty" protobuf:"bytes,15,opt,name=dryRunDiff"`
 }
 
 var _ resource.Object = &KubernetesApply{}
@@ -335,6 +346,14 @@ type KubernetesApplyStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,7,rep,name=conditions"`
 
+	// The changes that the most recent apply made to the cluster,
+	// as predicted by a server-side dry-run.
+	//
+	// Only populated when DryRunDiff is enabled on the spec.
+	//
+	// +optional
+	Diff *KubernetesApplyDiff `json:"diff,omitempty" protobuf:"bytes,8,opt,name=diff"`
+
 	// TODO(nick): We should also add some sort of status field to this
 	// status (like waiting, active, done).
 }
@@ -351,6 +370,73 @@ const (
 	ApplyConditionJobComplete string = "JobComplete"
 )
 
+// KubernetesApplyDiff describes how an apply changed the objects in the cluster.
+type KubernetesApplyDiff struct {
+	// Timestamp of when the dry-run was performed.
+	//
+	// +optional
+	DryRunTime metav1.MicroTime `json:"dryRunTime,omitempty" protobuf:"bytes,1,opt,name=dryRunTime"`
+
+	// An error performing the dry-run.
+	//
+	// A dry-run error does not stop the apply. For example, the dry-run
+	// may fail on a custom resource whose CRD is created by the same apply.
+	//
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,2,opt,name=error"`
+
+	// Per-object changes, in the order the objects were applied.
+	//
+	// +optional
+	Objects []KubernetesApplyObjectDiff `json:"objects,omitempty" protobuf:"bytes,3,rep,name=objects"`
+}
+
+// KubernetesApplyObjectDiff describes the change to a single object.
+type KubernetesApplyObjectDiff struct {
+	// The API version of the object.
+	APIVersion string `json:"apiVersion" protobuf:"bytes,1,opt,name=apiVersion"`
+
+	// The kind of the object.
+	Kind string `json:"kind" protobuf:"bytes,2,opt,name=kind"`
+
+	// The namespace of the object. Empty for cluster-scoped objects.
+	//
+	// +optional
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,3,opt,name=namespace"`
+
+	// The name of the object.
+	Name string `json:"name" protobuf:"bytes,4,opt,name=name"`
+
+	// What the apply does to the object.
+	Operation KubernetesApplyDiffOperation `json:"operation" protobuf:"bytes,5,opt,name=operation,casttype=KubernetesApplyDiffOperation"`
+
+	// Dot-separated paths of the fields that changed, e.g.,
+	// "spec.template.spec.containers[0].image".
+	//
+	// Server-managed fields (like metadata.resourceVersion and status) are excluded.
+	//
+	// +optional
+	ChangedFields []string `json:"changedFields,omitempty" protobuf:"bytes,6,rep,name=changedFields"`
+
+	// A unified diff of the live object against the dry-run object, in YAML.
+	//
+	// +optional
+	Diff string `json:"diff,omitempty" protobuf:"bytes,7,opt,name=diff"`
+}
+
+type KubernetesApplyDiffOperation string
+
+const (
+	// The object does not exist yet, and the apply will create it.
+	KubernetesApplyDiffOperationCreate KubernetesApplyDiffOperation = "create"
+
+	// The object exists, and the apply will modify it.
+	KubernetesApplyDiffOperationUpdate KubernetesApplyDiffOperation = "update"
+
+	// The object exists, and the apply will leave it as-is.
+	KubernetesApplyDiffOperationUnchanged KubernetesApplyDiffOperation = "unchanged"
+)
+
 // KubernetesApply implements ObjectWithStatusSubResource interface.
 var _ resource.ObjectWithStatusSubResource = &KubernetesApply{}
 
@@ -400,6 +486,12 @@ type KubernetesDiscoveryTemplateSpec struct {
 	// This should only be necessary in the event that a CRD creates Pods but does
 	// not set an owner reference to itself.
 	ExtraSelectors []metav1.LabelSelector `json:"extraSelectors,omitempty" protobuf:"bytes,1,rep,name=extraSelectors"`
+
+	// ReadinessChecks evaluate conditions on the applied objects themselves,
+	// rather than on the pods that they own.
+	//
+	// +optional
+	ReadinessChecks []KubernetesReadinessCheck `json:"readinessChecks,omitempty" protobuf:"bytes,2,rep,name=readinessChecks"`
 }
 
 type KubernetesDiscoveryStrategy string
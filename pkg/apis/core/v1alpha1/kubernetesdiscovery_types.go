@@ -97,6 +97,44 @@ type KubernetesDiscoverySpec struct {
 	//
 	// +optional
 	Cluster string `json:"cluster" protobuf:"bytes,5,opt,name=cluster"`
+
+	// ReadinessChecks evaluate conditions on the watched objects themselves,
+	// rather than on the pods that they own.
+	//
+	// Useful for custom resources managed by an operator, which report
+	// readiness in their status.
+	//
+	// +optional
+	ReadinessChecks []KubernetesReadinessCheck `json:"readinessChecks,omitempty" protobuf:"bytes,6,rep,name=readinessChecks"`
+}
+
+// KubernetesReadinessCheck is a condition on an object that must hold
+// for the object to be considered ready.
+type KubernetesReadinessCheck struct {
+	// The kind of object to check (e.g., "Database"). Required.
+	//
+	// Matched case-insensitively against the kind of each watched object.
+	Kind string `json:"kind" protobuf:"bytes,1,opt,name=kind"`
+
+	// The name of the object to check.
+	//
+	// If not specified, checks every watched object of this kind.
+	//
+	// +optional
+	Name string `json:"name,omitempty" protobuf:"bytes,2,opt,name=name"`
+
+	// A JSONPath into the object, optionally compared to a value with == or !=.
+	// Required.
+	//
+	// Without a comparison, the object is ready when the path matches a value
+	// that isn't empty, false, or 0.
+	//
+	// The braces and leading dot of JSONPath are optional, and a list can be
+	// filtered with [key=value]. For example, these are equivalent:
+	//
+	//   status.conditions[type=Ready].status == True
+	//   {.status.conditions[?(@.type=="Ready")].status} == True
+	Condition string `json:"condition" protobuf:"bytes,3,opt,name=condition"`
 }
 
 // KubernetesWatchRef is similar to v1.ObjectReference from the Kubernetes API and is used to determine
@@ -118,6 +156,22 @@ type KubernetesWatchRef struct {
 	//
 	// +optional
 	Name string `json:"name,omitempty" protobuf:"bytes,3,opt,name=name"`
+
+	// APIVersion of the object.
+	//
+	// This is not directly used in discovery; it is extra metadata
+	// for readiness checks.
+	//
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty" protobuf:"bytes,4,opt,name=apiVersion"`
+
+	// Kind of the object.
+	//
+	// This is not directly used in discovery; it is extra metadata
+	// for readiness checks.
+	//
+	// +optional
+	Kind string `json:"kind,omitempty" protobuf:"bytes,5,opt,name=kind"`
 }
 
 // PortForwardTemplateSpec describes common attributes for PortForwards
@@ -247,6 +301,42 @@ type KubernetesDiscoveryStatus struct {
 	//
 	// +optional
 	Running *KubernetesDiscoveryStateRunning `json:"running,omitempty" protobuf:"bytes,4,opt,name=running"`
+
+	// Results of the readiness checks in the spec, one for each checked object.
+	//
+	// +optional
+	ReadinessCheckResults []KubernetesReadinessCheckResult `json:"readinessCheckResults,omitempty" protobuf:"bytes,5,rep,name=readinessCheckResults"`
+}
+
+// KubernetesReadinessCheckResult is the outcome of evaluating
+// a readiness check against one object.
+type KubernetesReadinessCheckResult struct {
+	// APIVersion of the checked object.
+	APIVersion string `json:"apiVersion" protobuf:"bytes,1,opt,name=apiVersion"`
+
+	// Kind of the checked object.
+	Kind string `json:"kind" protobuf:"bytes,2,opt,name=kind"`
+
+	// Namespace of the checked object.
+	Namespace string `json:"namespace" protobuf:"bytes,3,opt,name=namespace"`
+
+	// Name of the checked object.
+	Name string `json:"name" protobuf:"bytes,4,opt,name=name"`
+
+	// Whether the object satisfies the check.
+	Ready bool `json:"ready" protobuf:"varint,5,opt,name=ready"`
+
+	// A human-readable explanation of why the object isn't ready.
+	//
+	// +optional
+	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
+
+	// A human-readable explanation of why the check can never pass
+	// (e.g., it matched none of the deployed objects). The resource
+	// is reported as an error until the check or the objects change.
+	//
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,7,opt,name=error"`
 }
 
 type KubernetesDiscoveryStateWaiting struct {
@@ -117,6 +117,33 @@ type LiveUpdateSpec struct {
 	//
 	// +optional
 	InitialSync *LiveUpdateInitialSync `json:"initialSync,omitempty" protobuf:"bytes,8,opt,name=initialSync"`
+
+	// SyncAgent configures Tilt to apply updates through a sync agent running
+	// in the container, instead of running `tar` over `kubectl exec`.
+	//
+	// Only applies to Kubernetes containers. If the agent can't be reached,
+	// Tilt falls back to exec.
+	//
+	// Tilt injects the agent into the pod with an init container, so the
+	// image doesn't need to include it.
+	//
+	// +optional
+	SyncAgent *LiveUpdateSyncAgent `json:"syncAgent,omitempty" protobuf:"bytes,10,opt,name=syncAgent"`
+
+	// Specify container paths that Tilt should copy back to the host
+	// after each update (e.g., generated code or lockfiles).
+	//
+	// Files are only copied back if they changed in the container. If a file
+	// also changed locally, the local copy wins and Tilt logs a conflict.
+	//
+	// Tilt copies files back after each successful live update, and then
+	// polls the container every few seconds, so that files the container
+	// changes on its own (e.g., from a code generator running in watch mode)
+	// are copied back too. With a sync agent, Tilt compares file hashes
+	// first, and only downloads the files that changed.
+	//
+	// +optional
+	SyncBacks []LiveUpdateSyncBack `json:"syncBacks,omitempty" protobuf:"bytes,11,rep,name=syncBacks"`
 }
 
 var _ resource.Object = &LiveUpdate{}
@@ -182,6 +209,24 @@ func (in *LiveUpdate) Validate(ctx context.Context) field.ErrorList {
 		}
 	}
 
+	for i, syncBack := range in.Spec.SyncBacks {
+		if !path.IsAbs(syncBack.ContainerPath) {
+			errors = append(errors,
+				field.Invalid(
+					field.NewPath("spec.syncBacks").Index(i),
+					syncBack.ContainerPath,
+					"sync_back source is not absolute"))
+		}
+	}
+
+	if in.Spec.SyncAgent != nil && (in.Spec.SyncAgent.Port < 1 || in.Spec.SyncAgent.Port > 65535) {
+		errors = append(errors,
+			field.Invalid(
+				field.NewPath("spec.syncAgent.port"),
+				in.Spec.SyncAgent.Port,
+				"must be between 1 and 65535"))
+	}
+
 	selectorPath := field.NewPath("spec.selector")
 	kSelector := in.Spec.Selector.Kubernetes
 	dcSelector := in.Spec.Selector.DockerCompose
@@ -344,6 +389,17 @@ type LiveUpdateSync struct {
 	ContainerPath string `json:"containerPath" protobuf:"bytes,2,opt,name=containerPath"`
 }
 
+// Determines how a container path maps back onto local files.
+type LiveUpdateSyncBack struct {
+	// An absolute path inside the container. Required.
+	ContainerPath string `json:"containerPath" protobuf:"bytes,1,opt,name=containerPath"`
+
+	// A relative path to local files. Required.
+	//
+	// Computed relative to the live-update BasePath.
+	LocalPath string `json:"localPath" protobuf:"bytes,2,opt,name=localPath"`
+}
+
 // Runs a remote command after files have been synced to the container.
 // Commonly used for small in-container changes (like moving files
 // around, or restart processes).
@@ -388,6 +444,18 @@ var (
 // LiveUpdateInitialSync enables full file sync on container start/restart.
 type LiveUpdateInitialSync struct{}
 
+// LiveUpdateSyncAgent describes how to reach the sync agent in the container.
+type LiveUpdateSyncAgent struct {
+	// The container port the sync agent listens on.
+	Port int32 `json:"port" protobuf:"varint,1,opt,name=port"`
+
+	// The image that Tilt copies the agent binary from when it
+	// injects the agent into the pod.
+	//
+	// +optional
+	Image string `json:"image,omitempty" protobuf:"bytes,2,opt,name=image"`
+}
+
 // LiveUpdateContainerStatus defines the observed state of
 // the live-update syncer for a particular container.
 type LiveUpdateContainerStatus struct {
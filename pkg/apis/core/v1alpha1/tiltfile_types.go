@@ -199,4 +199,9 @@ type TiltfileStateTerminated struct {
 	// (brief) reason the process is terminated
 	// +optional
 	WarningCount int32 `json:"warningCount,omitempty" protobuf:"varint,5,opt,name=warningCount"`
+
+	// The per-user overrides file (e.g., Tiltfile.local or $TILT_OVERRIDES)
+	// that was loaded after this Tiltfile, if any.
+	// +optional
+	OverridesPath string `json:"overridesPath,omitempty" protobuf:"bytes,6,opt,name=overridesPath"`
 }
@@ -321,6 +321,45 @@ type UIBuildTerminated struct {
 	// build+deploy to reset the pod state to what's on disk.
 	// +optional
 	IsCrashRebuild bool `json:"isCrashRebuild,omitempty" protobuf:"varint,6,opt,name=isCrashRebuild"`
+
+	// A timeline of the steps of the build, in the order they started.
+	//
+	// Useful for figuring out which step of the build is slow.
+	// +optional
+	Steps []UIBuildStep `json:"steps,omitempty" protobuf:"bytes,7,rep,name=steps"`
+}
+
+// UIBuildStep is one step in the timeline of a build.
+type UIBuildStep struct {
+	// A human-readable name of the step.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+
+	// The kind of step. One of:
+	//
+	// - queue: waiting for a build slot after a change
+	// - pipeline: one of the numbered steps printed in the build log
+	// - image-stage: a stage of an image build (e.g., a Dockerfile instruction)
+	// - apply: applying objects to the cluster
+	// - rollout: waiting for the new pods to become ready
+	//
+	// +optional
+	Type string `json:"type,omitempty" protobuf:"bytes,2,opt,name=type"`
+
+	// The time when the step started.
+	// +optional
+	StartTime metav1.MicroTime `json:"startTime,omitempty" protobuf:"bytes,3,opt,name=startTime"`
+
+	// The time when the step finished.
+	// +optional
+	FinishTime metav1.MicroTime `json:"finishTime,omitempty" protobuf:"bytes,4,opt,name=finishTime"`
+
+	// Whether the step was skipped because its result was cached.
+	// +optional
+	Cached bool `json:"cached,omitempty" protobuf:"varint,5,opt,name=cached"`
+
+	// Error message if the step failed. If empty, the step succeeded.
+	// +optional
+	Error string `json:"error,omitempty" protobuf:"bytes,6,opt,name=error"`
 }
 
 // UIResourceKubernetes contains status information specific to Kubernetes.
@@ -29,6 +29,40 @@ type BuildRecord struct {
 	// We count the warnings by looking up all the logs with Level=WARNING
 	// in the logstore. We store this number separately for ease of use.
 	WarningCount int
+
+	// A timeline of the steps of the build, in the order they started.
+	Steps []BuildStep
+}
+
+type BuildStepType string
+
+// Waiting for a build slot after a change.
+const BuildStepTypeQueue BuildStepType = "queue"
+
+// One of the numbered steps printed in the build log.
+const BuildStepTypePipeline BuildStepType = "pipeline"
+
+// A stage of an image build (e.g., a Dockerfile instruction).
+const BuildStepTypeImageStage BuildStepType = "image-stage"
+
+// Applying objects to the cluster.
+const BuildStepTypeApply BuildStepType = "apply"
+
+// Waiting for the new pods to become ready.
+const BuildStepTypeRollout BuildStepType = "rollout"
+
+// BuildStep is one step in the timeline of a build.
+type BuildStep struct {
+	Name       string
+	Type       BuildStepType
+	StartTime  time.Time
+	FinishTime time.Time
+
+	// Whether the step was skipped because its result was cached.
+	Cached bool
+
+	// Empty if the step succeeded.
+	Error string
 }
 
 func (bs BuildRecord) Empty() bool {
@@ -184,6 +184,11 @@ func NewLogStore() *LogStore {
 	}
 }
 
+// The number of bytes of logs in the store.
+func (s *LogStore) Len() int {
+	return s.len
+}
+
 func (s *LogStore) Checkpoint() Checkpoint {
 	return s.checkpointFromIndex(len(s.segments))
 }
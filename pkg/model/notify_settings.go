@@ -0,0 +1,74 @@
+package model
+
+// NotifyEvent is a change in resource status that a Notifier can fire on.
+type NotifyEvent string
+
+const (
+	// A resource's update or runtime went into an error state.
+	NotifyEventError NotifyEvent = "error"
+
+	// A resource that was in an error state became ready again.
+	NotifyEventRecovered NotifyEvent = "recovered"
+
+	// All resources became ready.
+	NotifyEventAllReady NotifyEvent = "all_ready"
+)
+
+var AllNotifyEvents = []NotifyEvent{NotifyEventError, NotifyEventRecovered, NotifyEventAllReady}
+
+// Notifier sends an HTTP webhook or runs a local command when resources
+// change status.
+//
+// Exactly one of URL and Cmd is set.
+type Notifier struct {
+	Events []NotifyEvent
+
+	// If set, only fire for these resources. Otherwise, fire for all resources.
+	Resources []ManifestName
+
+	// The URL to POST a JSON payload to.
+	URL string
+
+	// Extra headers to send with the webhook (e.g., for auth).
+	Headers map[string]string
+
+	// The command to run. The event is passed in environment variables.
+	Cmd Cmd
+}
+
+func (n Notifier) HasEvent(e NotifyEvent) bool {
+	for _, existing := range n.Events {
+		if existing == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether this notifier fires for the given resource.
+func (n Notifier) MatchesResource(name ManifestName) bool {
+	if len(n.Resources) == 0 {
+		return true
+	}
+	for _, r := range n.Resources {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DesktopNotifySettings controls the desktop notifications that Tilt shows
+// when a resource fails or recovers.
+type DesktopNotifySettings struct {
+	Enabled bool
+
+	// The command to run when Tilt can't show a notification over D-Bus
+	// (e.g., on macOS). The event is passed in environment variables.
+	FallbackCmd Cmd
+}
+
+type NotifySettings struct {
+	Notifiers []Notifier
+	Desktop   DesktopNotifySettings
+}
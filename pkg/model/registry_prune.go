@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Delete Tilt-built images from remote registries when they're older than this
+const RegistryPruneDefaultMaxAge = 7 * 24 * time.Hour
+
+// How often to prune remote registries while Tilt is running
+const RegistryPruneDefaultInterval = 24 * time.Hour
+
+// Keep the last 5 builds of each image
+const RegistryPruneDefaultKeepRecent = 5
+
+type RegistryPruneSettings struct {
+	Enabled    bool
+	MaxAge     time.Duration // "delete images older than X"
+	Interval   time.Duration // "prune every Y hours"
+	KeepRecent int           // Keep the most recent N builds of each image.
+}
+
+func DefaultRegistryPruneSettings() RegistryPruneSettings {
+	// Unlike Docker Prune, registry pruning deletes images that other
+	// people might be using, so it's off unless the Tiltfile turns it on.
+	return RegistryPruneSettings{
+		Enabled:    false,
+		MaxAge:     RegistryPruneDefaultMaxAge,
+		Interval:   RegistryPruneDefaultInterval,
+		KeepRecent: RegistryPruneDefaultKeepRecent,
+	}
+}
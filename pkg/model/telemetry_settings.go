@@ -10,4 +10,32 @@ type TelemetrySettings struct {
 
 	// How often to send the trace data.
 	Period time.Duration
+
+	// Where to export traces with OTLP, if anywhere.
+	OTLP OTLPSettings
+}
+
+type OTLPProtocol string
+
+const (
+	OTLPProtocolGRPC OTLPProtocol = "grpc"
+	OTLPProtocolHTTP OTLPProtocol = "http"
+)
+
+// Settings for exporting traces to an OpenTelemetry collector
+// (e.g., a local Jaeger).
+type OTLPSettings struct {
+	// The URL of the collector, e.g., http://localhost:4317.
+	//
+	// An http:// URL sends traces in plaintext; https:// uses TLS.
+	Endpoint string
+
+	Protocol OTLPProtocol
+
+	// Extra headers to send with each export (e.g., for auth).
+	Headers map[string]string
+}
+
+func (s OTLPSettings) Empty() bool {
+	return s.Endpoint == ""
 }
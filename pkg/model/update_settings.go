@@ -11,12 +11,20 @@ const (
 )
 
 type UpdateSettings struct {
-	maxParallelUpdates int           // max number of updates to run concurrently
-	k8sUpsertTimeout   time.Duration // timeout for k8s upsert operations
+	maxParallelUpdates int            // max number of updates to run concurrently
+	maxParallelByLabel map[string]int // max number of concurrent updates of resources with a label
+	k8sUpsertTimeout   time.Duration  // timeout for k8s upsert operations
 
 	// "true", "false", or "auto".
 	k8sServerSideApply string
 
+	// Whether to compute a server-side dry-run diff before each Kubernetes apply.
+	k8sDryRunDiff bool
+
+	// Whether to remember the inputs of each image build, so that Tilt can
+	// reuse the image on restart instead of rebuilding it.
+	cacheBuildInputs bool
+
 	// A list of images to suppress the warning for.
 	SuppressUnusedImageWarnings []string
 }
@@ -38,6 +46,29 @@ func (us UpdateSettings) WithMaxParallelUpdates(n int) UpdateSettings {
 	return us
 }
 
+// MaxParallelUpdatesByLabel returns the max number of resources with each
+// label that may update at once. Labels without a limit are only limited by
+// MaxParallelUpdates.
+func (us UpdateSettings) MaxParallelUpdatesByLabel() map[string]int {
+	return us.maxParallelByLabel
+}
+
+func (us UpdateSettings) WithMaxParallelUpdatesByLabel(limits map[string]int) UpdateSettings {
+	result := make(map[string]int, len(us.maxParallelByLabel)+len(limits))
+	for label, n := range us.maxParallelByLabel {
+		result[label] = n
+	}
+	for label, n := range limits {
+		// Min. value is 1
+		if n < 1 {
+			n = 1
+		}
+		result[label] = n
+	}
+	us.maxParallelByLabel = result
+	return us
+}
+
 func (us UpdateSettings) K8sServerSideApply() string {
 	return us.k8sServerSideApply
 }
@@ -47,6 +78,24 @@ func (us UpdateSettings) WithK8sServerSideApply(v string) UpdateSettings {
 	return us
 }
 
+func (us UpdateSettings) K8sDryRunDiff() bool {
+	return us.k8sDryRunDiff
+}
+
+func (us UpdateSettings) WithK8sDryRunDiff(v bool) UpdateSettings {
+	us.k8sDryRunDiff = v
+	return us
+}
+
+func (us UpdateSettings) CacheBuildInputs() bool {
+	return us.cacheBuildInputs
+}
+
+func (us UpdateSettings) WithCacheBuildInputs(v bool) UpdateSettings {
+	us.cacheBuildInputs = v
+	return us
+}
+
 func (us UpdateSettings) K8sUpsertTimeout() time.Duration {
 	// Min. value is 1s
 	if us.k8sUpsertTimeout < time.Second {
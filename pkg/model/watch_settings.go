@@ -4,10 +4,14 @@ import "github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 
 type WatchSettings struct {
 	Ignores []Dockerignore
+
+	// If set, watch for file changes by polling rather than with
+	// OS file notifications.
+	Poll *v1alpha1.FileWatchPoll
 }
 
 func (ws WatchSettings) Empty() bool {
-	return len(ws.Ignores) == 0
+	return len(ws.Ignores) == 0 && ws.Poll == nil
 }
 
 type Dockerignore struct {
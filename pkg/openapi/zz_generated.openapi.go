@@ -99,6 +99,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.FileEvent{}.OpenAPIModelName():                         schema_pkg_apis_core_v1alpha1_FileEvent(ref),
 		v1alpha1.FileWatch{}.OpenAPIModelName():                         schema_pkg_apis_core_v1alpha1_FileWatch(ref),
 		v1alpha1.FileWatchList{}.OpenAPIModelName():                     schema_pkg_apis_core_v1alpha1_FileWatchList(ref),
+		v1alpha1.FileWatchPoll{}.OpenAPIModelName():                     schema_pkg_apis_core_v1alpha1_FileWatchPoll(ref),
 		v1alpha1.FileWatchSpec{}.OpenAPIModelName():                     schema_pkg_apis_core_v1alpha1_FileWatchSpec(ref),
 		v1alpha1.FileWatchStatus{}.OpenAPIModelName():                   schema_pkg_apis_core_v1alpha1_FileWatchStatus(ref),
 		v1alpha1.Forward{}.OpenAPIModelName():                           schema_pkg_apis_core_v1alpha1_Forward(ref),
@@ -113,9 +114,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.ImageMapOverrideCommand{}.OpenAPIModelName():           schema_pkg_apis_core_v1alpha1_ImageMapOverrideCommand(ref),
 		v1alpha1.ImageMapSpec{}.OpenAPIModelName():                      schema_pkg_apis_core_v1alpha1_ImageMapSpec(ref),
 		v1alpha1.ImageMapStatus{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_ImageMapStatus(ref),
+		v1alpha1.ImageTagPolicy{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_ImageTagPolicy(ref),
 		v1alpha1.KubernetesApply{}.OpenAPIModelName():                   schema_pkg_apis_core_v1alpha1_KubernetesApply(ref),
 		v1alpha1.KubernetesApplyCmd{}.OpenAPIModelName():                schema_pkg_apis_core_v1alpha1_KubernetesApplyCmd(ref),
+		v1alpha1.KubernetesApplyDiff{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_KubernetesApplyDiff(ref),
 		v1alpha1.KubernetesApplyList{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_KubernetesApplyList(ref),
+		v1alpha1.KubernetesApplyObjectDiff{}.OpenAPIModelName():         schema_pkg_apis_core_v1alpha1_KubernetesApplyObjectDiff(ref),
 		v1alpha1.KubernetesApplySpec{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_KubernetesApplySpec(ref),
 		v1alpha1.KubernetesApplyStatus{}.OpenAPIModelName():             schema_pkg_apis_core_v1alpha1_KubernetesApplyStatus(ref),
 		v1alpha1.KubernetesClusterConnection{}.OpenAPIModelName():       schema_pkg_apis_core_v1alpha1_KubernetesClusterConnection(ref),
@@ -129,6 +133,8 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.KubernetesDiscoveryTemplateSpec{}.OpenAPIModelName():   schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryTemplateSpec(ref),
 		v1alpha1.KubernetesImageLocator{}.OpenAPIModelName():            schema_pkg_apis_core_v1alpha1_KubernetesImageLocator(ref),
 		v1alpha1.KubernetesImageObjectDescriptor{}.OpenAPIModelName():   schema_pkg_apis_core_v1alpha1_KubernetesImageObjectDescriptor(ref),
+		v1alpha1.KubernetesReadinessCheck{}.OpenAPIModelName():          schema_pkg_apis_core_v1alpha1_KubernetesReadinessCheck(ref),
+		v1alpha1.KubernetesReadinessCheckResult{}.OpenAPIModelName():    schema_pkg_apis_core_v1alpha1_KubernetesReadinessCheckResult(ref),
 		v1alpha1.KubernetesWatchRef{}.OpenAPIModelName():                schema_pkg_apis_core_v1alpha1_KubernetesWatchRef(ref),
 		v1alpha1.LiveUpdate{}.OpenAPIModelName():                        schema_pkg_apis_core_v1alpha1_LiveUpdate(ref),
 		v1alpha1.LiveUpdateContainerStateWaiting{}.OpenAPIModelName():   schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStateWaiting(ref),
@@ -144,6 +150,8 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.LiveUpdateStateFailed{}.OpenAPIModelName():             schema_pkg_apis_core_v1alpha1_LiveUpdateStateFailed(ref),
 		v1alpha1.LiveUpdateStatus{}.OpenAPIModelName():                  schema_pkg_apis_core_v1alpha1_LiveUpdateStatus(ref),
 		v1alpha1.LiveUpdateSync{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref),
+		v1alpha1.LiveUpdateSyncAgent{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_LiveUpdateSyncAgent(ref),
+		v1alpha1.LiveUpdateSyncBack{}.OpenAPIModelName():                schema_pkg_apis_core_v1alpha1_LiveUpdateSyncBack(ref),
 		v1alpha1.ObjectSelector{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_ObjectSelector(ref),
 		v1alpha1.Pod{}.OpenAPIModelName():                               schema_pkg_apis_core_v1alpha1_Pod(ref),
 		v1alpha1.PodCondition{}.OpenAPIModelName():                      schema_pkg_apis_core_v1alpha1_PodCondition(ref),
@@ -192,6 +200,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.UIBoolInputSpec{}.OpenAPIModelName():                   schema_pkg_apis_core_v1alpha1_UIBoolInputSpec(ref),
 		v1alpha1.UIBoolInputStatus{}.OpenAPIModelName():                 schema_pkg_apis_core_v1alpha1_UIBoolInputStatus(ref),
 		v1alpha1.UIBuildRunning{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_UIBuildRunning(ref),
+		v1alpha1.UIBuildStep{}.OpenAPIModelName():                       schema_pkg_apis_core_v1alpha1_UIBuildStep(ref),
 		v1alpha1.UIBuildTerminated{}.OpenAPIModelName():                 schema_pkg_apis_core_v1alpha1_UIBuildTerminated(ref),
 		v1alpha1.UIButton{}.OpenAPIModelName():                          schema_pkg_apis_core_v1alpha1_UIButton(ref),
 		v1alpha1.UIButtonList{}.OpenAPIModelName():                      schema_pkg_apis_core_v1alpha1_UIButtonList(ref),
@@ -703,6 +712,12 @@ func schema_pkg_apis_core_v1alpha1_CmdImageSpec(ref common.ReferenceCallback) co
 							},
 						},
 					},
+					"tagPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How to tag the built image.\n\nIf not specified, the image is tagged with a prefix and the image digest. Ignored if the command writes its own image ref.",
+							Ref:         ref(v1alpha1.ImageTagPolicy{}.OpenAPIModelName()),
+						},
+					},
 					"imageMaps": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Names of image maps that this build depends on.\n\nThe controller will watch all the image maps, and rebuild the image if any of the maps resolve to a new image.",
@@ -757,6 +772,8 @@ func schema_pkg_apis_core_v1alpha1_CmdImageSpec(ref common.ReferenceCallback) co
 				Required: []string{"ref"},
 			},
 		},
+		Dependencies: []string{
+			v1alpha1.ImageTagPolicy{}.OpenAPIModelName()},
 	}
 }
 
@@ -2536,12 +2553,40 @@ func schema_pkg_apis_core_v1alpha1_DockerImageSpec(ref common.ReferenceCallback)
 							},
 						},
 					},
+					"tagPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How to tag the built image.\n\nIf not specified, the image is tagged with a prefix and the image digest.",
+							Ref:         ref(v1alpha1.ImageTagPolicy{}.OpenAPIModelName()),
+						},
+					},
+					"attestations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Attestations to generate with the image, like an SBOM or build provenance.\n\nhttps://docs.docker.com/build/metadata/attestations/\n\nEquivalent to `--attest` in the Docker Buildx CLI (e.g., \"type=sbom\", \"type=provenance,mode=max\").\n\nRequires BuildKit and Docker's containerd image store. The build fails on the classic image store, which drops attestations.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"signingKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A cosign key to sign the image with after it's pushed, and before it's deployed.\n\ncosign stores the signature in the registry, next to the pushed digest, so the image can't be signed before the push.\n\nMay be a path to a local key, or a KMS URI that cosign understands. Requires the cosign CLI.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"ref"},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.IgnoreDef{}.OpenAPIModelName()},
+			v1alpha1.IgnoreDef{}.OpenAPIModelName(), v1alpha1.ImageTagPolicy{}.OpenAPIModelName()},
 	}
 }
 
@@ -3288,6 +3333,27 @@ func schema_pkg_apis_core_v1alpha1_FileWatchList(ref common.ReferenceCallback) c
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_FileWatchPoll(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Describes how to poll for file changes.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"interval": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Interval is how often to scan for changes.\n\nIf not specified, defaults to 2s.",
+							Ref:         ref(v1.Duration{}.OpenAPIModelName()),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1.Duration{}.OpenAPIModelName()},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_FileWatchSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3330,12 +3396,18 @@ func schema_pkg_apis_core_v1alpha1_FileWatchSpec(ref common.ReferenceCallback) c
 							Ref:         ref(v1alpha1.DisableSource{}.OpenAPIModelName()),
 						},
 					},
+					"poll": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Poll makes the FileWatch scan WatchedPaths for changes periodically, rather than relying on OS file notifications.\n\nUseful on filesystems that don't deliver notifications, like network filesystems and some container bind mounts.",
+							Ref:         ref(v1alpha1.FileWatchPoll{}.OpenAPIModelName()),
+						},
+					},
 				},
 				Required: []string{"watchedPaths"},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.DisableSource{}.OpenAPIModelName(), v1alpha1.IgnoreDef{}.OpenAPIModelName()},
+			v1alpha1.DisableSource{}.OpenAPIModelName(), v1alpha1.FileWatchPoll{}.OpenAPIModelName(), v1alpha1.IgnoreDef{}.OpenAPIModelName()},
 	}
 }
 
@@ -3385,6 +3457,20 @@ func schema_pkg_apis_core_v1alpha1_FileWatchStatus(ref common.ReferenceCallback)
 							Ref:         ref(v1alpha1.DisableStatus{}.OpenAPIModelName()),
 						},
 					},
+					"watchCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WatchCount is the number of OS-level watches (e.g., inotify watches on Linux) used to monitor the watched paths. OS watch limits are shared by all processes for a user, so large counts can starve other tools.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"polledDirCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PolledDirCount is the number of directories that are polled for changes instead of watched, because the OS limit on watches was reached. Changes in these directories may take a few seconds to be seen.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
 				},
 			},
 		},
@@ -3857,12 +3943,18 @@ func schema_pkg_apis_core_v1alpha1_ImageMapSpec(ref common.ReferenceCallback) co
 							Ref:         ref(v1alpha1.ImageMapOverrideArgs{}.OpenAPIModelName()),
 						},
 					},
+					"syncAgent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "If specified, the injector will inject a live update sync agent into containers that run the image.\n\nThe injector adds an init container that copies the agent binary into an emptyDir volume, wraps the container's command with the agent, and mounts the token that the agent requires from a Secret.",
+							Ref:         ref(v1alpha1.LiveUpdateSyncAgent{}.OpenAPIModelName()),
+						},
+					},
 				},
 				Required: []string{"selector"},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.ImageMapOverrideArgs{}.OpenAPIModelName(), v1alpha1.ImageMapOverrideCommand{}.OpenAPIModelName()},
+			v1alpha1.ImageMapOverrideArgs{}.OpenAPIModelName(), v1alpha1.ImageMapOverrideCommand{}.OpenAPIModelName(), v1alpha1.LiveUpdateSyncAgent{}.OpenAPIModelName()},
 	}
 }
 
@@ -3900,6 +3992,43 @@ func schema_pkg_apis_core_v1alpha1_ImageMapStatus(ref common.ReferenceCallback)
 							Ref:         ref(v1.MicroTime{}.OpenAPIModelName()),
 						},
 					},
+					"entrypoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The ENTRYPOINT from the image config.\n\nOnly recorded when the spec injects a sync agent, which needs to know the command to wrap if the container doesn't set one.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"cmd": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The CMD from the image config.\n\nOnly recorded when the spec injects a sync agent.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"buildInputHash": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A hash of everything that went into building the image (the build context, the Dockerfile, the build args, and so on).\n\nOnly recorded for docker_build() images, when Tilt is saving its state across restarts or caching build inputs. On startup, Tilt skips building an image whose inputs still have this hash.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -3908,6 +4037,43 @@ func schema_pkg_apis_core_v1alpha1_ImageMapStatus(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_ImageTagPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ImageTagPolicy describes how to tag a built image.\n\nTemplates may reference the variables {digest} (the full image digest), {digestN} (the first N characters of the digest), {git_branch}, {git_sha}, and {git_sha_short}. Git variables are read from the repo that contains the build directory.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"template": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The template for the tag that Tilt deploys.\n\nMust contain {digest} or {digestN}, so that every build has a unique tag.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"additionalTags": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Templates for extra tags to apply to the image.\n\nThese tags are pushed alongside the main tag, but aren't deployed. They don't need to be unique (e.g., \"{git_branch}-latest\").",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"template"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_KubernetesApply(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4006,6 +4172,48 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApplyCmd(ref common.ReferenceCallba
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_KubernetesApplyDiff(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubernetesApplyDiff describes how an apply changed the objects in the cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"dryRunTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timestamp of when the dry-run was performed.",
+							Ref:         ref(v1.MicroTime{}.OpenAPIModelName()),
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "An error performing the dry-run.\n\nA dry-run error does not stop the apply. For example, the dry-run may fail on a custom resource whose CRD is created by the same apply.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"objects": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Per-object changes, in the order the objects were applied.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.KubernetesApplyObjectDiff{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1alpha1.KubernetesApplyObjectDiff{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_KubernetesApplyList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4055,6 +4263,81 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApplyList(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_KubernetesApplyObjectDiff(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubernetesApplyObjectDiff describes the change to a single object.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The API version of the object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The kind of the object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The namespace of the object. Empty for cluster-scoped objects.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"operation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "What the apply does to the object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"changedFields": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Dot-separated paths of the fields that changed, e.g., \"spec.template.spec.containers[0].image\".\n\nServer-managed fields (like metadata.resourceVersion and status) are excluded.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"diff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A unified diff of the live object against the dry-run object, in YAML.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"apiVersion", "kind", "name", "operation"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_KubernetesApplySpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4168,6 +4451,13 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApplySpec(ref common.ReferenceCallb
 							Format:      "",
 						},
 					},
+					"dryRunDiff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DryRunDiff enables a server-side dry-run before each apply.\n\nWhen true, the controller asks the apiserver what each object would look like after the apply, compares it against the live object, and records the result in the Diff status field. The dry-run never blocks the apply.\n\nOnly supported for YAML applies.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -4236,11 +4526,17 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApplyStatus(ref common.ReferenceCal
 							},
 						},
 					},
+					"diff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The changes that the most recent apply made to the cluster, as predicted by a server-side dry-run.\n\nOnly populated when DryRunDiff is enabled on the spec.",
+							Ref:         ref(v1alpha1.KubernetesApplyDiff{}.OpenAPIModelName()),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.DisableStatus{}.OpenAPIModelName(), v1.Condition{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
+			v1alpha1.DisableStatus{}.OpenAPIModelName(), v1alpha1.KubernetesApplyDiff{}.OpenAPIModelName(), v1.Condition{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
 	}
 }
 
@@ -4264,6 +4560,13 @@ func schema_pkg_apis_core_v1alpha1_KubernetesClusterConnection(ref common.Refere
 							Format:      "",
 						},
 					},
+					"createNamespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to create the default namespace if it doesn't exist.\n\nThe namespace is created when Tilt connects to the cluster, before any objects are applied.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -4473,12 +4776,26 @@ func schema_pkg_apis_core_v1alpha1_KubernetesDiscoverySpec(ref common.ReferenceC
 							Format:      "",
 						},
 					},
+					"readinessChecks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadinessChecks evaluate conditions on the watched objects themselves, rather than on the pods that they own.\n\nUseful for custom resources managed by an operator, which report readiness in their status.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.KubernetesReadinessCheck{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"watches"},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.KubernetesWatchRef{}.OpenAPIModelName(), v1alpha1.PodLogStreamTemplateSpec{}.OpenAPIModelName(), v1alpha1.PortForwardTemplateSpec{}.OpenAPIModelName(), v1.LabelSelector{}.OpenAPIModelName()},
+			v1alpha1.KubernetesReadinessCheck{}.OpenAPIModelName(), v1alpha1.KubernetesWatchRef{}.OpenAPIModelName(), v1alpha1.PodLogStreamTemplateSpec{}.OpenAPIModelName(), v1alpha1.PortForwardTemplateSpec{}.OpenAPIModelName(), v1.LabelSelector{}.OpenAPIModelName()},
 	}
 }
 
@@ -4563,12 +4880,26 @@ func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryStatus(ref common.Referenc
 							Ref:         ref(v1alpha1.KubernetesDiscoveryStateRunning{}.OpenAPIModelName()),
 						},
 					},
+					"readinessCheckResults": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Results of the readiness checks in the spec, one for each checked object.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.KubernetesReadinessCheckResult{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"pods"},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.KubernetesDiscoveryStateRunning{}.OpenAPIModelName(), v1alpha1.KubernetesDiscoveryStateWaiting{}.OpenAPIModelName(), v1alpha1.Pod{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
+			v1alpha1.KubernetesDiscoveryStateRunning{}.OpenAPIModelName(), v1alpha1.KubernetesDiscoveryStateWaiting{}.OpenAPIModelName(), v1alpha1.KubernetesReadinessCheckResult{}.OpenAPIModelName(), v1alpha1.Pod{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
 	}
 }
 
@@ -4592,11 +4923,25 @@ func schema_pkg_apis_core_v1alpha1_KubernetesDiscoveryTemplateSpec(ref common.Re
 							},
 						},
 					},
+					"readinessChecks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ReadinessChecks evaluate conditions on the applied objects themselves, rather than on the pods that they own.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.KubernetesReadinessCheck{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1.LabelSelector{}.OpenAPIModelName()},
+			v1alpha1.KubernetesReadinessCheck{}.OpenAPIModelName(), v1.LabelSelector{}.OpenAPIModelName()},
 	}
 }
 
@@ -4666,6 +5011,111 @@ func schema_pkg_apis_core_v1alpha1_KubernetesImageObjectDescriptor(ref common.Re
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_KubernetesReadinessCheck(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubernetesReadinessCheck is a condition on an object that must hold for the object to be considered ready.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The kind of object to check (e.g., \"Database\"). Required.\n\nMatched case-insensitively against the kind of each watched object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the object to check.\n\nIf not specified, checks every watched object of this kind.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"condition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A JSONPath into the object, optionally compared to a value with == or !=. Required.\n\nWithout a comparison, the object is ready when the path matches a value that isn't empty, false, or 0.\n\nThe braces and leading dot of JSONPath are optional, and a list can be filtered with [key=value]. For example, these are equivalent:\n\n  status.conditions[type=Ready].status == True\n  {.status.conditions[?(@.type==\"Ready\")].status} == True",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"kind", "condition"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesReadinessCheckResult(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "KubernetesReadinessCheckResult is the outcome of evaluating a readiness check against one object.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion of the checked object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind of the checked object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace of the checked object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name of the checked object.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ready": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the object satisfies the check.",
+							Default:     false,
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human-readable explanation of why the object isn't ready.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human-readable explanation of why the check can never pass (e.g., it matched none of the deployed objects). The resource is reported as an error until the check or the objects change.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"apiVersion", "kind", "namespace", "name", "ready"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_KubernetesWatchRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4695,6 +5145,20 @@ func schema_pkg_apis_core_v1alpha1_KubernetesWatchRef(ref common.ReferenceCallba
 							Format:      "",
 						},
 					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion of the object.\n\nThis is not directly used in discovery; it is extra metadata for readiness checks.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind of the object.\n\nThis is not directly used in discovery; it is extra metadata for readiness checks.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"namespace"},
 			},
@@ -5170,12 +5634,32 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSpec(ref common.ReferenceCallback)
 							Ref:         ref(v1alpha1.LiveUpdateInitialSync{}.OpenAPIModelName()),
 						},
 					},
+					"syncAgent": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncAgent configures Tilt to apply updates through a sync agent running in the container, instead of running `tar` over `kubectl exec`.\n\nOnly applies to Kubernetes containers. If the agent can't be reached, Tilt falls back to exec.\n\nTilt injects the agent into the pod with an init container, so the image doesn't need to include it.",
+							Ref:         ref(v1alpha1.LiveUpdateSyncAgent{}.OpenAPIModelName()),
+						},
+					},
+					"syncBacks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specify container paths that Tilt should copy back to the host after each update (e.g., generated code or lockfiles).\n\nFiles are only copied back if they changed in the container. If a file also changed locally, the local copy wins and Tilt logs a conflict.\n\nTilt copies files back after each successful live update, and then polls the container every few seconds, so that files the container changes on its own (e.g., from a code generator running in watch mode) are copied back too. With a sync agent, Tilt compares file hashes first, and only downloads the files that changed.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.LiveUpdateSyncBack{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"basePath", "selector"},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.LiveUpdateExec{}.OpenAPIModelName(), v1alpha1.LiveUpdateInitialSync{}.OpenAPIModelName(), v1alpha1.LiveUpdateSelector{}.OpenAPIModelName(), v1alpha1.LiveUpdateSource{}.OpenAPIModelName(), v1alpha1.LiveUpdateSync{}.OpenAPIModelName()},
+			v1alpha1.LiveUpdateExec{}.OpenAPIModelName(), v1alpha1.LiveUpdateInitialSync{}.OpenAPIModelName(), v1alpha1.LiveUpdateSelector{}.OpenAPIModelName(), v1alpha1.LiveUpdateSource{}.OpenAPIModelName(), v1alpha1.LiveUpdateSync{}.OpenAPIModelName(), v1alpha1.LiveUpdateSyncAgent{}.OpenAPIModelName(), v1alpha1.LiveUpdateSyncBack{}.OpenAPIModelName()},
 	}
 }
 
@@ -5279,6 +5763,65 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_LiveUpdateSyncAgent(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LiveUpdateSyncAgent describes how to reach the sync agent in the container.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The container port the sync agent listens on.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The image that Tilt copies the agent binary from when it injects the agent into the pod.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"port"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_LiveUpdateSyncBack(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "Determines how a container path maps back onto local files.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"containerPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "An absolute path inside the container. Required.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"localPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A relative path to local files. Required.\n\nComputed relative to the live-update BasePath.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"containerPath", "localPath"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_ObjectSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -7020,6 +7563,13 @@ func schema_pkg_apis_core_v1alpha1_TiltfileStateTerminated(ref common.ReferenceC
 							Format:      "int32",
 						},
 					},
+					"overridesPath": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The per-user overrides file (e.g., Tiltfile.local or $TILT_OVERRIDES) that was loaded after this Tiltfile, if any.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -7375,6 +7925,63 @@ func schema_pkg_apis_core_v1alpha1_UIBuildRunning(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_UIBuildStep(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UIBuildStep is one step in the timeline of a build.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A human-readable name of the step.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The kind of step. One of:\n\n- queue: waiting for a build slot after a change - pipeline: one of the numbered steps printed in the build log - image-stage: a stage of an image build (e.g., a Dockerfile instruction) - apply: applying objects to the cluster - rollout: waiting for the new pods to become ready",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"startTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The time when the step started.",
+							Ref:         ref(v1.MicroTime{}.OpenAPIModelName()),
+						},
+					},
+					"finishTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The time when the step finished.",
+							Ref:         ref(v1.MicroTime{}.OpenAPIModelName()),
+						},
+					},
+					"cached": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether the step was skipped because its result was cached.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Error message if the step failed. If empty, the step succeeded.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			v1.MicroTime{}.OpenAPIModelName()},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_UIBuildTerminated(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -7430,11 +8037,25 @@ func schema_pkg_apis_core_v1alpha1_UIBuildTerminated(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
+					"steps": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A timeline of the steps of the build, in the order they started.\n\nUseful for figuring out which step of the build is slow.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.UIBuildStep{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1.MicroTime{}.OpenAPIModelName()},
+			v1alpha1.UIBuildStep{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
 	}
 }
 
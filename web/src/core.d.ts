@@ -81,6 +81,13 @@ export interface KubernetesClusterConnection {
    * +optional
    */
   namespace?: string
+  /**
+   * Whether to create the default namespace if it doesn't exist.
+   * The namespace is created when Tilt connects to the cluster,
+   * before any objects are applied.
+   * +optional
+   */
+  createNamespace?: boolean
 }
 export interface DockerClusterConnection {
   /**
@@ -396,6 +403,13 @@ export interface CmdImageSpec {
    * +optional
    */
   env?: string[]
+  /**
+   * How to tag the built image.
+   * If not specified, the image is tagged with a prefix and the image digest.
+   * Ignored if the command writes its own image ref.
+   * +optional
+   */
+  tagPolicy?: ImageTagPolicy
   /**
    * Names of image maps that this build depends on.
    * The controller will watch all the image maps, and rebuild the image
@@ -1006,6 +1020,31 @@ export interface DockerImageSpec {
    * Equivalent to `--add-host` in the Docker CLI.
    */
   extraHosts?: string[]
+  /**
+   * How to tag the built image.
+   * If not specified, the image is tagged with a prefix and the image digest.
+   * +optional
+   */
+  tagPolicy?: ImageTagPolicy
+  /**
+   * Attestations to generate with the image, like an SBOM or build provenance.
+   * https://docs.docker.com/build/metadata/attestations/
+   * Equivalent to `--attest` in the Docker Buildx CLI
+   * (e.g., "type=sbom", "type=provenance,mode=max").
+   * Requires BuildKit and Docker's containerd image store. The build fails
+   * on the classic image store, which drops attestations.
+   * +optional
+   */
+  attestations?: string[]
+  /**
+   * A cosign key to sign the image with after it's pushed, and before it's deployed.
+   * cosign stores the signature in the registry, next to the pushed
+   * digest, so the image can't be signed before the push.
+   * May be a path to a local key, or a KMS URI that cosign understands.
+   * Requires the cosign CLI.
+   * +optional
+   */
+  signingKey?: string
 }
 /**
  * DockerImageStatus defines the observed state of DockerImage
@@ -1312,6 +1351,25 @@ export interface FileWatchSpec {
    * +optional
    */
   disableSource?: DisableSource
+  /**
+   * Poll makes the FileWatch scan WatchedPaths for changes periodically,
+   * rather than relying on OS file notifications.
+   * Useful on filesystems that don't deliver notifications, like network
+   * filesystems and some container bind mounts.
+   * +optional
+   */
+  poll?: FileWatchPoll
+}
+/**
+ * Describes how to poll for file changes.
+ */
+export interface FileWatchPoll {
+  /**
+   * Interval is how often to scan for changes.
+   * If not specified, defaults to 2s.
+   * +optional
+   */
+  interval?: any /* metav1.Duration */
 }
 /**
  * Describes sets of file paths that the FileWatch should ignore.
@@ -1359,6 +1417,18 @@ export interface FileWatchStatus {
    * +optional
    */
   disableStatus?: DisableStatus
+  /**
+   * WatchCount is the number of OS-level watches (e.g., inotify watches on Linux) used to monitor the
+   * watched paths. OS watch limits are shared by all processes for a user, so large counts can starve other tools.
+   * +optional
+   */
+  watchCount?: number /* int32 */
+  /**
+   * PolledDirCount is the number of directories that are polled for changes instead of watched, because the
+   * OS limit on watches was reached. Changes in these directories may take a few seconds to be seen.
+   * +optional
+   */
+  polledDirCount?: number /* int32 */
 }
 export interface FileEvent {
   /**
@@ -1443,6 +1513,15 @@ export interface ImageMapSpec {
    * +optional
    */
   overrideArgs?: ImageMapOverrideArgs
+  /**
+   * If specified, the injector will inject a live update sync agent into
+   * containers that run the image.
+   * The injector adds an init container that copies the agent binary into an
+   * emptyDir volume, wraps the container's command with the agent, and mounts
+   * the token that the agent requires from a Secret.
+   * +optional
+   */
+  syncAgent?: LiveUpdateSyncAgent
 }
 /**
  * ImageMapCommandOverride defines a command to inject when the image
@@ -1505,6 +1584,49 @@ export interface ImageMapStatus {
    * may not be included in the image.
    */
   buildStartTime?: string
+  /**
+   * The ENTRYPOINT from the image config.
+   * Only recorded when the spec injects a sync agent, which needs to know
+   * the command to wrap if the container doesn't set one.
+   * +optional
+   */
+  entrypoint?: string[]
+  /**
+   * The CMD from the image config.
+   * Only recorded when the spec injects a sync agent.
+   * +optional
+   */
+  cmd?: string[]
+  /**
+   * A hash of everything that went into building the image
+   * (the build context, the Dockerfile, the build args, and so on).
+   * Only recorded for docker_build() images, when Tilt is saving its state
+   * across restarts or caching build inputs. On startup, Tilt skips
+   * building an image whose inputs still have this hash.
+   * +optional
+   */
+  buildInputHash?: string
+}
+/**
+ * ImageTagPolicy describes how to tag a built image.
+ * Templates may reference the variables {digest} (the full image digest),
+ * {digestN} (the first N characters of the digest), {git_branch}, {git_sha},
+ * and {git_sha_short}. Git variables are read from the repo that
+ * contains the build directory.
+ */
+export interface ImageTagPolicy {
+  /**
+   * The template for the tag that Tilt deploys.
+   * Must contain {digest} or {digestN}, so that every build has a unique tag.
+   */
+  template: string
+  /**
+   * Templates for extra tags to apply to the image.
+   * These tags are pushed alongside the main tag, but aren't deployed.
+   * They don't need to be unique (e.g., "{git_branch}-latest").
+   * +optional
+   */
+  additionalTags?: string[]
 }
 
 //////////
@@ -1646,6 +1768,15 @@ export interface KubernetesApplySpec {
    * +optional
    */
   serverSideApply?: boolean
+  /**
+   * DryRunDiff enables a server-side dry-run before each apply.
+   * When true, the controller asks the apiserver what each object would look
+   * like after the apply, compares it against the live object, and records
+   * the result in the Diff status field. The dry-run never blocks the apply.
+   * Only supported for YAML applies.
+   * +optional
+   */
+  dryRunDiff?: boolean
 }
 /**
  * KubernetesApplyStatus defines the observed state of KubernetesApply
@@ -1696,6 +1827,13 @@ export interface KubernetesApplyStatus {
    * +optional
    */
   conditions?: any /* metav1.Condition */[]
+  /**
+   * The changes that the most recent apply made to the cluster,
+   * as predicted by a server-side dry-run.
+   * Only populated when DryRunDiff is enabled on the spec.
+   * +optional
+   */
+  diff?: KubernetesApplyDiff
 }
 /**
  * ApplyConditionJobComplete means the apply was for a batch/v1.Job that has already
@@ -1707,6 +1845,79 @@ export interface KubernetesApplyStatus {
  * bypass Pod monitoring for this resource.
  */
 export const ApplyConditionJobComplete: string = "JobComplete"
+/**
+ * KubernetesApplyDiff describes how an apply changed the objects in the cluster.
+ */
+export interface KubernetesApplyDiff {
+  /**
+   * Timestamp of when the dry-run was performed.
+   * +optional
+   */
+  dryRunTime?: string
+  /**
+   * An error performing the dry-run.
+   * A dry-run error does not stop the apply. For example, the dry-run
+   * may fail on a custom resource whose CRD is created by the same apply.
+   * +optional
+   */
+  error?: string
+  /**
+   * Per-object changes, in the order the objects were applied.
+   * +optional
+   */
+  objects?: KubernetesApplyObjectDiff[]
+}
+/**
+ * KubernetesApplyObjectDiff describes the change to a single object.
+ */
+export interface KubernetesApplyObjectDiff {
+  /**
+   * The API version of the object.
+   */
+  apiVersion: string
+  /**
+   * The kind of the object.
+   */
+  kind: string
+  /**
+   * The namespace of the object. Empty for cluster-scoped objects.
+   * +optional
+   */
+  namespace?: string
+  /**
+   * The name of the object.
+   */
+  name: string
+  /**
+   * What the apply does to the object.
+   */
+  operation: KubernetesApplyDiffOperation
+  /**
+   * Dot-separated paths of the fields that changed, e.g.,
+   * "spec.template.spec.containers[0].image".
+   * Server-managed fields (like metadata.resourceVersion and status) are excluded.
+   * +optional
+   */
+  changedFields?: string[]
+  /**
+   * A unified diff of the live object against the dry-run object, in YAML.
+   * +optional
+   */
+  diff?: string
+}
+export type KubernetesApplyDiffOperation = string
+/**
+ * The object does not exist yet, and the apply will create it.
+ */
+export const KubernetesApplyDiffOperationCreate: KubernetesApplyDiffOperation = "create"
+/**
+ * The object exists, and the apply will modify it.
+ */
+export const KubernetesApplyDiffOperationUpdate: KubernetesApplyDiffOperation = "update"
+/**
+ * The object exists, and the apply will leave it as-is.
+ */
+export const KubernetesApplyDiffOperationUnchanged: KubernetesApplyDiffOperation = "unchanged"
 /**
  * Finds image references in Kubernetes YAML.
  */
@@ -1747,6 +1958,12 @@ export interface KubernetesDiscoveryTemplateSpec {
    * not set an owner reference to itself.
    */
   extraSelectors?: any /* metav1.LabelSelector */[]
+  /**
+   * ReadinessChecks evaluate conditions on the applied objects themselves,
+   * rather than on the pods that they own.
+   * +optional
+   */
+  readinessChecks?: KubernetesReadinessCheck[]
 }
 export type KubernetesDiscoveryStrategy = string
 export interface KubernetesApplyCmd {
@@ -1833,6 +2050,42 @@ export interface KubernetesDiscoverySpec {
    * +optional
    */
   cluster: string
+  /**
+   * ReadinessChecks evaluate conditions on the watched objects themselves,
+   * rather than on the pods that they own.
+   * Useful for custom resources managed by an operator, which report
+   * readiness in their status.
+   * +optional
+   */
+  readinessChecks?: KubernetesReadinessCheck[]
+}
+/**
+ * KubernetesReadinessCheck is a condition on an object that must hold
+ * for the object to be considered ready.
+ */
+export interface KubernetesReadinessCheck {
+  /**
+   * The kind of object to check (e.g., "Database"). Required.
+   * Matched case-insensitively against the kind of each watched object.
+   */
+  kind: string
+  /**
+   * The name of the object to check.
+   * If not specified, checks every watched object of this kind.
+   * +optional
+   */
+  name?: string
+  /**
+   * A JSONPath into the object, optionally compared to a value with == or !=.
+   * Required.
+   * Without a comparison, the object is ready when the path matches a value
+   * that isn't empty, false, or 0.
+   * The braces and leading dot of JSONPath are optional, and a list can be
+   * filtered with [key=value]. For example, these are equivalent:
+   *   status.conditions[type=Ready].status == True
+   *   {.status.conditions[?(@.type=="Ready")].status} == True
+   */
+  condition: string
 }
 /**
  * KubernetesWatchRef is similar to v1.ObjectReference from the Kubernetes API and is used to determine
@@ -1855,6 +2108,20 @@ export interface KubernetesWatchRef {
    * +optional
    */
   name?: string
+  /**
+   * APIVersion of the object.
+   * This is not directly used in discovery; it is extra metadata
+   * for readiness checks.
+   * +optional
+   */
+  apiVersion?: string
+  /**
+   * Kind of the object.
+   * This is not directly used in discovery; it is extra metadata
+   * for readiness checks.
+   * +optional
+   */
+  kind?: string
 }
 /**
  * PortForwardTemplateSpec describes common attributes for PortForwards
@@ -1917,6 +2184,49 @@ export interface KubernetesDiscoveryStatus {
    * +optional
    */
   running?: KubernetesDiscoveryStateRunning
+  /**
+   * Results of the readiness checks in the spec, one for each checked object.
+   * +optional
+   */
+  readinessCheckResults?: KubernetesReadinessCheckResult[]
+}
+/**
+ * KubernetesReadinessCheckResult is the outcome of evaluating
+ * a readiness check against one object.
+ */
+export interface KubernetesReadinessCheckResult {
+  /**
+   * APIVersion of the checked object.
+   */
+  apiVersion: string
+  /**
+   * Kind of the checked object.
+   */
+  kind: string
+  /**
+   * Namespace of the checked object.
+   */
+  namespace: string
+  /**
+   * Name of the checked object.
+   */
+  name: string
+  /**
+   * Whether the object satisfies the check.
+   */
+  ready: boolean
+  /**
+   * A human-readable explanation of why the object isn't ready.
+   * +optional
+   */
+  message?: string
+  /**
+   * A human-readable explanation of why the check can never pass
+   * (e.g., it matched none of the deployed objects). The resource
+   * is reported as an error until the check or the objects change.
+   * +optional
+   */
+  error?: string
 }
 export interface KubernetesDiscoveryStateWaiting {
   /**
@@ -2234,6 +2544,29 @@ export interface LiveUpdateSpec {
    * +optional
    */
   initialSync?: LiveUpdateInitialSync
+  /**
+   * SyncAgent configures Tilt to apply updates through a sync agent running
+   * in the container, instead of running `tar` over `kubectl exec`.
+   * Only applies to Kubernetes containers. If the agent can't be reached,
+   * Tilt falls back to exec.
+   * Tilt injects the agent into the pod with an init container, so the
+   * image doesn't need to include it.
+   * +optional
+   */
+  syncAgent?: LiveUpdateSyncAgent
+  /**
+   * Specify container paths that Tilt should copy back to the host
+   * after each update (e.g., generated code or lockfiles).
+   * Files are only copied back if they changed in the container. If a file
+   * also changed locally, the local copy wins and Tilt logs a conflict.
+   * Tilt copies files back after each successful live update, and then
+   * polls the container every few seconds, so that files the container
+   * changes on its own (e.g., from a code generator running in watch mode)
+   * are copied back too. With a sync agent, Tilt compares file hashes
+   * first, and only downloads the files that changed.
+   * +optional
+   */
+  syncBacks?: LiveUpdateSyncBack[]
 }
 /**
  * LiveUpdateStatus defines the observed state of LiveUpdate
@@ -2350,6 +2683,20 @@ export interface LiveUpdateSync {
    */
   containerPath: string
 }
+/**
+ * Determines how a container path maps back onto local files.
+ */
+export interface LiveUpdateSyncBack {
+  /**
+   * An absolute path inside the container. Required.
+   */
+  containerPath: string
+  /**
+   * A relative path to local files. Required.
+   * Computed relative to the live-update BasePath.
+   */
+  localPath: string
+}
 /**
  * Runs a remote command after files have been synced to the container.
  * Commonly used for small in-container changes (like moving files
@@ -2383,6 +2730,21 @@ export type LiveUpdateRestartStrategy = string
  * LiveUpdateInitialSync enables full file sync on container start/restart.
  */
 export interface LiveUpdateInitialSync {}
+/**
+ * LiveUpdateSyncAgent describes how to reach the sync agent in the container.
+ */
+export interface LiveUpdateSyncAgent {
+  /**
+   * The container port the sync agent listens on.
+   */
+  port: number
+  /**
+   * The image that Tilt copies the agent binary from when it
+   * injects the agent into the pod.
+   * +optional
+   */
+  image?: string
+}
 /**
  * LiveUpdateContainerStatus defines the observed state of
  * the live-update syncer for a particular container.
@@ -3357,6 +3719,12 @@ export interface TiltfileStateTerminated {
    * +optional
    */
   warningCount?: number /* int32 */
+  /**
+   * The per-user overrides file (e.g., Tiltfile.local or $TILT_OVERRIDES)
+   * that was loaded after this Tiltfile, if any.
+   * +optional
+   */
+  overridesPath?: string
 }
 
 //////////
@@ -4034,6 +4402,51 @@ export interface UIBuildTerminated {
    * +optional
    */
   isCrashRebuild?: boolean
+  /**
+   * A timeline of the steps of the build, in the order they started.
+   * Useful for figuring out which step of the build is slow.
+   * +optional
+   */
+  steps?: UIBuildStep[]
+}
+/**
+ * UIBuildStep is one step in the timeline of a build.
+ */
+export interface UIBuildStep {
+  /**
+   * A human-readable name of the step.
+   */
+  name: string
+  /**
+   * The kind of step. One of:
+   * - queue: waiting for a build slot after a change
+   * - pipeline: one of the numbered steps printed in the build log
+   * - image-stage: a stage of an image build (e.g., a Dockerfile instruction)
+   * - apply: applying objects to the cluster
+   * - rollout: waiting for the new pods to become ready
+   * +optional
+   */
+  type?: string
+  /**
+   * The time when the step started.
+   * +optional
+   */
+  startTime?: string
+  /**
+   * The time when the step finished.
+   * +optional
+   */
+  finishTime?: string
+  /**
+   * Whether the step was skipped because its result was cached.
+   * +optional
+   */
+  cached?: boolean
+  /**
+   * Error message if the step failed. If empty, the step succeeded.
+   * +optional
+   */
+  error?: string
 }
 /**
  * UIResourceKubernetes contains status information specific to Kubernetes.